@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +23,7 @@ func runListingStatusCmd(status domain.ListingStatus, action string) func(*cobra
 		listing, err := repo.FindByID(context.Background(), args[0])
 		exitOnErr(err, "Listing not found")
 		listing.Status = status
-		exitOnErr(repo.Save(context.Background(), listing), fmt.Sprintf("Failed to %s listing", action))
+		exitOnErr(newAuditService(repo).Save(context.Background(), domain.ActorCLI, domain.ListingAction(action), listing), fmt.Sprintf("Failed to %s listing", action))
 		fmt.Printf("Listing %sd: %s\n", action, args[0])
 	}
 }
@@ -51,7 +52,7 @@ var adminFeaturedCmd = &cobra.Command{
 		exitOnErr(err, "Listing not found")
 
 		listing.Featured = !listing.Featured
-		exitOnErr(repo.Save(context.Background(), listing), domain.MsgFailedToUpdateListing)
+		exitOnErr(newAuditService(repo).SetFeatured(context.Background(), domain.ActorCLI, listing.ID, listing.Featured), domain.MsgFailedToUpdateListing)
 
 		status := "featured"
 		if !listing.Featured {
@@ -82,6 +83,87 @@ var adminPendingClaimsCmd = &cobra.Command{
 	},
 }
 
+var adminPendingChangesCmd = &cobra.Command{
+	Use:   "pending-changes",
+	Short: "List listing changes awaiting moderation",
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+
+		changes, err := listing.NewModerationService(repo).Pending(context.Background())
+		exitOnErr(err, "Failed to get pending changes")
+
+		if printListResponse(cmd, changes, len(changes), "No listing changes awaiting review") {
+			return
+		}
+
+		cmd.Printf("Found %d listing changes awaiting review:\n\n", len(changes))
+		for _, pc := range changes {
+			kind := "edit"
+			if pc.IsNew {
+				kind = "new listing"
+			}
+			cmd.Printf("[%s] Listing: %s | %s by %s | %s\n",
+				pc.ID, pc.ListingTitle, kind, pc.ActorName, pc.CreatedAt.Format("2006-01-02"))
+			if !pc.IsNew {
+				for _, ch := range pc.Changes {
+					cmd.Printf("    %s: %q -> %q\n", ch.Field, ch.Before, ch.After)
+				}
+			}
+		}
+	},
+}
+
+var adminApproveChangeCmd = &cobra.Command{
+	Use:   "approve-change [id]",
+	Short: "Publish a listing change held for moderation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+		_, err := listing.NewModerationService(repo).Approve(context.Background(), domain.ActorCLI, args[0])
+		exitOnErr(err, "Failed to approve change")
+		fmt.Printf("Change approved: %s\n", args[0])
+	},
+}
+
+var adminRejectChangeCmd = &cobra.Command{
+	Use:   "reject-change [id]",
+	Short: "Discard a listing change held for moderation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+		exitOnErr(listing.NewModerationService(repo).Reject(context.Background(), domain.ActorCLI, args[0]), "Failed to reject change")
+		fmt.Printf("Change rejected: %s\n", args[0])
+	},
+}
+
+var adminHistoryCmd = &cobra.Command{
+	Use:   "history [id]",
+	Short: "Show the change history of a listing",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+
+		events, err := newAuditService(repo).History(context.Background(), args[0])
+		exitOnErr(err, "Failed to get listing history")
+
+		if printListResponse(cmd, events, len(events), "No history recorded for this listing") {
+			return
+		}
+
+		cmd.Printf("Found %d events for listing %s:\n\n", len(events), args[0])
+		for _, e := range events {
+			actor := e.ActorName
+			if actor == "" {
+				actor = e.ActorID
+			}
+			cmd.Printf("%s | %s | %s\n", e.CreatedAt.Format(layoutDateTime), e.Action, actor)
+			for _, ch := range e.Changes {
+				cmd.Printf("    %s: %q -> %q\n", ch.Field, ch.Before, ch.After)
+			}
+		}
+	},
+}
+
 var adminUsersCmd = &cobra.Command{
 	Use:   "users",
 	Short: "List all users",
@@ -108,18 +190,26 @@ var adminUsersCmd = &cobra.Command{
 
 var adminPromoteCmd = &cobra.Command{
 	Use:   "promote [user-id]",
-	Short: "Promote a user to admin by user ID",
-	Args:  cobra.ExactArgs(1),
+	Short: "Give a user a role by user ID (super admin by default)",
+	Long: `Give a user a role. Roles are User, Analyst, CategoryCurator, Moderator,
+Admin and SuperAdmin, and --role defaults to SuperAdmin. This is how the first
+super admin is created in production, where the shared admin code no longer
+grants access; only a super admin can grant the admin roles from the dashboard.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		roleName, _ := cmd.Flags().GetString("role")
+		role, err := domain.ParseUserRole(roleName)
+		exitOnErr(err, "Invalid role")
+
 		repo := initRepo()
 
 		user, err := repo.FindUserByID(context.Background(), args[0])
 		exitOnErr(err, "User not found")
 
-		user.Role = domain.UserRoleAdmin
+		user.Role = role
 		exitOnErr(repo.SaveUser(context.Background(), user), "Failed to promote user")
 
-		fmt.Printf("User promoted to admin: %s\n", args[0])
+		fmt.Printf("User %s is now %s\n", args[0], role)
 	},
 }
 
@@ -128,8 +218,13 @@ func init() {
 	adminCmd.AddCommand(adminRejectCmd)
 	adminCmd.AddCommand(adminFeaturedCmd)
 	adminCmd.AddCommand(adminPendingClaimsCmd)
+	adminCmd.AddCommand(adminPendingChangesCmd)
+	adminCmd.AddCommand(adminApproveChangeCmd)
+	adminCmd.AddCommand(adminRejectChangeCmd)
+	adminCmd.AddCommand(adminHistoryCmd)
 	adminCmd.AddCommand(adminUsersCmd)
 	adminCmd.AddCommand(adminPromoteCmd)
+	adminPromoteCmd.Flags().String("role", string(domain.UserRoleSuperAdmin), "Role to assign")
 
 	rootCmd.AddCommand(adminCmd)
 }
@@ -35,29 +35,38 @@ var benchmarkCmd = &cobra.Command{
 		_, _ = fmt.Fprintln(w, "Scenario\tExecution Time (ms)\tResults Count")
 		_, _ = fmt.Fprintln(w, "--------\t-------------------\t-------------")
 
+		lagos := radiusQuery(6.5244, 3.3792, 10)
+		london := radiusQuery(51.5074, -0.1278, 10)
+
+		// The radius scenarios run twice: through the latitude/longitude B-tree index
+		// and through the listings_rtree spatial index.
 		scenarios := []struct {
-			name       string
-			filterType string
-			queryText  string
-			sortField  string
-			sortOrder  string
-			limit      int
-			offset     int
+			name           string
+			query          domain.ListingQuery
+			noSpatialIndex bool
 		}{
-			{"Page 1 (No Filters)", "", "", "", "", 20, 0},
-			{"Page 500 (Deep Pagination)", "", "", "", "", 20, 10000},
-			{"Category Filter ('Business')", "Business", "", "", "", 20, 0},
+			{"Page 1 (No Filters)", domain.ListingQuery{Limit: 20}, false},
+			{"Page 500 (Deep Pagination)", domain.ListingQuery{Limit: 20, Offset: 10000}, false},
+			{"Page 500 (Keyset Cursor, No Count)", domain.ListingQuery{Limit: 20, Cursor: cursorAt(ctx, repo, 10000), SkipCount: true}, false},
+			{"Category Filter ('Business')", domain.ListingQuery{Types: []domain.Category{domain.Business}, Limit: 20}, false},
+			{"Search 'stress' (Feed Order)", domain.ListingQuery{QueryText: "stress", Limit: 20}, false},
+			{"Search 'stress' (Relevance)", domain.ListingQuery{QueryText: "stress", Sort: domain.SortRelevance, Limit: 20}, false},
+			{"10 mi of Lagos (B-tree)", lagos, true},
+			{"10 mi of Lagos (R*Tree)", lagos, false},
+			{"10 mi of London (B-tree)", london, true},
+			{"10 mi of London (R*Tree)", london, false},
 		}
 
 		for _, s := range scenarios {
+			repo.SetSpatialIndex(!s.noSpatialIndex)
 			if warmup {
 				for i := 0; i < 5; i++ {
-					_, _, _ = repo.FindAll(ctx, s.filterType, s.queryText, "", 0.0, 0.0, 0.0, s.sortField, s.sortOrder, false, s.limit, s.offset)
+					_, _ = repo.Search(ctx, s.query)
 				}
 			}
 
 			start := time.Now()
-			listings, _, err := repo.FindAll(ctx, s.filterType, s.queryText, "", 0.0, 0.0, 0.0, s.sortField, s.sortOrder, false, s.limit, s.offset)
+			page, err := repo.Search(ctx, s.query)
 			duration := time.Since(start)
 
 			if err != nil {
@@ -65,13 +74,30 @@ var benchmarkCmd = &cobra.Command{
 				continue
 			}
 
-			_, _ = fmt.Fprintf(w, "%s\t%.2f\t%d\n", s.name, float64(duration.Microseconds())/1000.0, len(listings))
+			_, _ = fmt.Fprintf(w, "%s\t%.2f\t%d\n", s.name, float64(duration.Microseconds())/1000.0, len(page.Listings))
 		}
 
 		_ = w.Flush()
 	},
 }
 
+// cursorAt returns the cursor a client would hold after paging to offset, so the
+// keyset scenario can be compared directly with the OFFSET one.
+func cursorAt(ctx context.Context, repo domain.ListingReader, offset int) string {
+	page, err := repo.Search(ctx, domain.ListingQuery{Limit: 1, Offset: offset - 1, SkipCount: true})
+	if err != nil {
+		slog.Error("Failed to resolve cursor", "offset", offset, "error", err)
+		return ""
+	}
+	return page.NextCursor
+}
+
+// radiusQuery is a near-me search: the first page of listings within miles of a
+// point, nearest first.
+func radiusQuery(lat, lng, miles float64) domain.ListingQuery {
+	return domain.ListingQuery{Latitude: lat, Longitude: lng, RadiusMiles: miles, Sort: domain.SortDistance, Limit: 20}
+}
+
 func init() {
 	benchmarkCmd.Flags().BoolVar(&warmup, "warmup", false, "Execute queries 5 times in a loop before measuring time")
 	rootCmd.AddCommand(benchmarkCmd)
@@ -21,19 +21,24 @@ var categoryAddCmd = &cobra.Command{
 	Long: `Add a new category to the agbalumo system. Categories are used to 
 properly classify and filter listings.`,
 	Example: `  # Add a new claimable category
-  agbalumo category add "Professional Services" --claimable`,
+  agbalumo category add "Professional Services" --claimable
+
+  # Add a category whose new listings and material edits are reviewed first
+  agbalumo category add "Real Estate" --moderated`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		repo := initRepo()
 
 		name := args[0]
 		claimable, _ := cmd.Flags().GetBool("claimable")
+		moderated, _ := cmd.Flags().GetBool("moderated")
 
 		cat := domain.CategoryData{
-			Name:      name,
-			Claimable: claimable,
-			IsSystem:  false, // user-added are not system categories
-			Active:    true,  // active by default
+			Name:               name,
+			Claimable:          claimable,
+			RequiresModeration: moderated,
+			IsSystem:           false, // user-added are not system categories
+			Active:             true,  // active by default
 		}
 
 		exitOnErr(repo.SaveCategory(context.Background(), cat), "Failed to save category")
@@ -55,10 +60,10 @@ var categoryListCmd = &cobra.Command{
 			return
 		}
 
-		cmd.Printf("\n%-20s %-10s %-15s %-10s\n", "NAME", "ACTIVE", "CLAIMABLE", "SYSTEM")
-		cmd.Printf("------------------------------------------------------------\n")
+		cmd.Printf("\n%-20s %-10s %-15s %-10s %-10s\n", "NAME", "ACTIVE", "CLAIMABLE", "SYSTEM", "MODERATED")
+		cmd.Printf("----------------------------------------------------------------------\n")
 		for _, cat := range categories {
-			cmd.Printf("%-20s %-10t %-15t %-10t\n", cat.Name, cat.Active, cat.Claimable, cat.IsSystem)
+			cmd.Printf("%-20s %-10t %-15t %-10t %-10t\n", cat.Name, cat.Active, cat.Claimable, cat.IsSystem, cat.RequiresModeration)
 		}
 		cmd.Println()
 	},
@@ -66,6 +71,7 @@ var categoryListCmd = &cobra.Command{
 
 func init() {
 	categoryAddCmd.Flags().BoolP("claimable", "c", false, "Is this category claimable?")
+	categoryAddCmd.Flags().BoolP("moderated", "m", false, "Hold new listings and material edits for review?")
 	categoryCmd.AddCommand(categoryAddCmd)
 	categoryCmd.AddCommand(categoryListCmd)
 
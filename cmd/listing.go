@@ -25,12 +25,15 @@ func init() {
 	listingCmd.AddCommand(listingGetCmd)
 	listingCmd.AddCommand(listingUpdateCmd)
 	listingCmd.AddCommand(listingDeleteCmd)
+	listingCmd.AddCommand(listingRevisionsCmd)
+	listingCmd.AddCommand(listingRestoreCmd)
 	listingCmd.AddCommand(listingBackfillCitiesCmd)
 
 	rootCmd.AddCommand(listingCmd)
 
 	bindListingFlags(listingCreateCmd, false)
 	bindListingFlags(listingUpdateCmd, true)
+	bindListingListFlags(listingListCmd)
 
 	_ = listingCreateCmd.MarkFlagRequired(domain.FieldTitle)
 }
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 
 	"github.com/jadecobra/agbalumo/internal/config"
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/service"
 	"github.com/spf13/cobra"
 )
@@ -15,24 +17,27 @@ var listingBackfillCitiesCmd = &cobra.Command{
 	Use:   "backfill-cities",
 	Short: "Backfill missing city data for listings using geocoding",
 	Long: `Iterates through all listings that have an empty city field but have an address,
-and uses the Google Geocoding API to attempt to populate the city.`,
+and uses the configured geocoding providers (GEOCODING_PROVIDERS) to attempt to
+populate the city. Without GOOGLE_MAPS_API_KEY only the offline gazetteer is used.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := config.LoadConfig()
-		if cfg.GoogleMapsAPIKey == "" {
-			slog.Error("GOOGLE_MAPS_API_KEY is not set. Cannot perform geocoding.")
+		geocoders, err := service.NewGeocodingService(cfg.GeocodingProviders, cfg.GoogleMapsAPIKey, cfg.GazetteerPaths)
+		if err != nil {
+			slog.Error("Cannot perform geocoding", "error", err)
 			os.Exit(1)
 		}
 
 		repo := initRepo()
-		geocodingSvc := service.NewGoogleGeocodingService(cfg.GoogleMapsAPIKey)
+		geocodingSvc := service.NewCachingGeocodingService(geocoders, repo, nil)
 		ctx := context.Background()
 
 		// Get all listings
-		listings, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", false, 0, 0)
+		page, err := repo.Search(ctx, domain.ListingQuery{})
 		if err != nil {
 			slog.Error("Failed to fetch listings", "error", err)
 			os.Exit(1)
 		}
+		listings := page.Listings
 
 		updatedCount := 0
 		errorCount := 0
@@ -41,7 +46,7 @@ and uses the Google Geocoding API to attempt to populate the city.`,
 			if l.City == "" && l.Address != "" {
 				slog.Info("Backfilling city for listing", "id", l.ID, "address", l.Address)
 				city, err := geocodingSvc.GetCity(ctx, l.Address)
-				if err != nil {
+				if err != nil && !errors.Is(err, domain.ErrLocationNotFound) {
 					slog.Error("Failed to geocode address", "id", l.ID, "address", l.Address, "error", err)
 					errorCount++
 					continue
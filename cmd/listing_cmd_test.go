@@ -9,6 +9,7 @@ import (
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestListingCommands_RunCoverage(t *testing.T) {
@@ -22,7 +23,8 @@ func TestListingCommands_RunCoverage(t *testing.T) {
 	repo := initRepo()
 
 	// Ensure the repo is clean
-	listings, _, _ := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 100, 0)
+	page, _ := repo.Search(context.Background(), domain.ListingQuery{Limit: 100})
+	listings := page.Listings
 	for _, l := range listings {
 		_ = repo.Delete(context.Background(), l.ID)
 	}
@@ -59,7 +61,8 @@ func runListingCommandsTestsPart1(t *testing.T, repo domain.ListingRepository) {
 
 		time.Sleep(100 * time.Millisecond) // Give SQLite a moment if needed, though Save is sync
 
-		allListings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+		page, err := repo.Search(context.Background(), domain.ListingQuery{Limit: 10})
+		allListings := page.Listings
 		assert.NoError(t, err)
 
 		var found domain.Listing
@@ -89,7 +92,8 @@ func runListingCommandsTestsPart1(t *testing.T, repo domain.ListingRepository) {
 	})
 
 	t.Run("Get", func(t *testing.T) {
-		allListings, _, _ := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+		page, _ := repo.Search(context.Background(), domain.ListingQuery{Limit: 10})
+		allListings := page.Listings
 		if len(allListings) > 0 {
 			targetID := allListings[0].ID
 
@@ -106,7 +110,8 @@ func runListingCommandsTestsPart1(t *testing.T, repo domain.ListingRepository) {
 
 func runListingCommandsTestsPart2(t *testing.T, repo domain.ListingRepository) {
 	t.Run("Update", func(t *testing.T) {
-		allListings, _, _ := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+		page, _ := repo.Search(context.Background(), domain.ListingQuery{Limit: 10})
+		allListings := page.Listings
 		if len(allListings) > 0 {
 			targetID := allListings[0].ID
 
@@ -148,6 +153,32 @@ func runListingCommandsTestsPart2(t *testing.T, repo domain.ListingRepository) {
 		}
 	})
 
+	t.Run("RevisionsAndRestore", func(t *testing.T) {
+		page, _ := repo.Search(context.Background(), domain.ListingQuery{Limit: 10})
+		if len(page.Listings) == 0 {
+			return
+		}
+		targetID := page.Listings[0].ID
+
+		revisions, err := repo.ListListingRevisions(context.Background(), targetID)
+		assert.NoError(t, err)
+		if !assert.Len(t, revisions, 2, "each CLI update snapshots the previous version") {
+			return
+		}
+
+		flagText = true
+		listingRevisionsCmd.Run(listingRevisionsCmd, []string{targetID})
+		flagText = false
+		listingRevisionsCmd.Run(listingRevisionsCmd, []string{targetID})
+
+		listingRestoreCmd.Run(listingRestoreCmd, []string{targetID, "1"})
+
+		restored, err := repo.FindByID(context.Background(), targetID)
+		assert.NoError(t, err)
+		assert.Equal(t, revisions[0].Snapshot.Title, restored.Title)
+		assert.Equal(t, revisions[0].Snapshot.City, restored.City)
+	})
+
 	t.Run("Backfill", func(t *testing.T) {
 		// Ensure we don't crash the test runner with os.Exit(1)
 		_ = os.Setenv("GOOGLE_MAPS_API_KEY", "dummy_key_for_test")
@@ -169,8 +200,26 @@ func runListingCommandsTestsPart2(t *testing.T, repo domain.ListingRepository) {
 		// checking if errorCount increments properly and branch coverage.
 	})
 
+	t.Run("Backfill offline", func(t *testing.T) {
+		t.Setenv("GEOCODING_PROVIDERS", "gazetteer")
+		_ = repo.Save(context.Background(), domain.Listing{
+			ID:       "backfill-test-2",
+			Title:    "Backfill Offline Test",
+			Address:  "9000 Bellaire Blvd, Houston, TX 77036",
+			IsActive: true,
+			Status:   domain.ListingStatusApproved,
+		})
+
+		listingBackfillCitiesCmd.Run(listingBackfillCitiesCmd, nil)
+
+		l, err := repo.FindByID(context.Background(), "backfill-test-2")
+		require.NoError(t, err)
+		assert.Equal(t, "Houston", l.City)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
-		allListings, _, _ := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+		page, _ := repo.Search(context.Background(), domain.ListingQuery{Limit: 10})
+		allListings := page.Listings
 		for _, l := range allListings {
 			listingDeleteCmd.Run(listingDeleteCmd, []string{l.ID})
 
@@ -35,7 +35,7 @@ can be specified via flags.`,
 
 		applyListingUpdates(&listing)
 
-		exitOnErr(repo.Save(context.Background(), listing), domain.MsgFailedToCreateListing)
+		exitOnErr(newAuditService(repo).Save(context.Background(), domain.ActorCLI, domain.ListingActionCreate, listing), domain.MsgFailedToCreateListing)
 
 		if !flagText {
 			data, _ := json.MarshalIndent(listing, "", "  ")
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/spf13/cobra"
 )
 
@@ -16,7 +17,7 @@ var listingDeleteCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		repo := initRepo()
 
-		if err := repo.Delete(context.Background(), args[0]); err != nil {
+		if err := newAuditService(repo).Delete(context.Background(), domain.ActorCLI, args[0]); err != nil {
 			slog.Error("Failed to delete listing", "error", err)
 			os.Exit(1)
 		}
@@ -2,10 +2,30 @@ package cmd
 
 import (
 	"context"
+	"time"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/spf13/cobra"
 )
 
+var (
+	flagListTypes        []string
+	flagListCities       []string
+	flagListStatuses     []string
+	flagListOrigin       string
+	flagListQuery        string
+	flagListCreatedAfter string
+	flagListCursor       string
+	flagListOpenAt       string
+	flagListMinRating    float64
+	flagListHeatLevel    int
+	flagListLimit        int
+	flagListFeatured     bool
+	flagListOpenNow      bool
+	flagListHasImage     bool
+	flagListAll          bool
+)
+
 var listingListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all listings",
@@ -15,12 +35,19 @@ supports filtering and can output the results in a machine-readable JSON format.
   agbalumo listing list
 
   # List all listings in JSON format
-  agbalumo listing list --json`,
+  agbalumo listing list --json
+
+  # Food and services in Houston or Dallas rated 4 and up
+  agbalumo listing list --type Food --type Service --city Houston --city Dallas --min-rating 4`,
 	Run: func(cmd *cobra.Command, args []string) {
 		repo := initRepo()
 
-		listings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 100, 0)
+		q, err := listingListQuery()
+		exitOnErr(err, "Invalid filter")
+
+		page, err := repo.Search(context.Background(), q)
 		exitOnErr(err, "Failed to list listings")
+		listings := page.Listings
 
 		if printListResponse(cmd, listings, len(listings), "No listings found") {
 			return
@@ -30,9 +57,68 @@ supports filtering and can output the results in a machine-readable JSON format.
 		for _, l := range listings {
 			printListingSummary(cmd, l)
 		}
+		if page.NextCursor != "" {
+			cmd.Printf("\nMore results: --cursor %s\n", page.NextCursor)
+		}
 	},
 }
 
+// listingListQuery builds the search from the list command's filter flags.
+func listingListQuery() (domain.ListingQuery, error) {
+	q := domain.ListingQuery{
+		Cities:          flagListCities,
+		OwnerOrigin:     flagListOrigin,
+		QueryText:       flagListQuery,
+		Cursor:          flagListCursor,
+		MinRating:       flagListMinRating,
+		MinHeatLevel:    flagListHeatLevel,
+		Limit:           flagListLimit,
+		FeaturedOnly:    flagListFeatured,
+		OpenNow:         flagListOpenNow,
+		HasImage:        flagListHasImage,
+		IncludeInactive: flagListAll,
+	}
+	for _, t := range flagListTypes {
+		q.Types = append(q.Types, domain.Category(t))
+	}
+	for _, s := range flagListStatuses {
+		q.Statuses = append(q.Statuses, domain.ListingStatus(s))
+	}
+	if flagListCreatedAfter != "" {
+		t, err := time.Parse(layoutDate, flagListCreatedAfter)
+		if err != nil {
+			return q, err
+		}
+		q.CreatedAfter = t
+	}
+	if flagListOpenAt != "" {
+		var err error
+		if q.OpenAt, q.OpenAtWallClock, err = domain.ParseOpenAt(flagListOpenAt); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
+
+func bindListingListFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.StringArrayVar(&flagListTypes, domain.FieldType, nil, "Only these types (repeatable)")
+	f.StringArrayVar(&flagListCities, domain.FieldCity, nil, "Only these cities (repeatable)")
+	f.StringArrayVar(&flagListStatuses, domain.FieldStatus, nil, "Only these statuses (repeatable; combine with --all for non-approved)")
+	f.StringVar(&flagListOrigin, "origin", "", "Owner origin country")
+	f.StringVarP(&flagListQuery, "query", "q", "", "Full-text search")
+	f.StringVar(&flagListCreatedAfter, "created-after", "", "Only listings created after this date (YYYY-MM-DD)")
+	f.StringVar(&flagListCursor, "cursor", "", "Resume from a previous page")
+	f.Float64Var(&flagListMinRating, "min-rating", 0, "Minimum rating")
+	f.IntVar(&flagListHeatLevel, "heat-level", 0, "Minimum heat level (1-5)")
+	f.IntVar(&flagListLimit, "limit", 100, "Maximum listings to return")
+	f.BoolVar(&flagListFeatured, domain.FieldFeatured, false, "Only featured listings")
+	f.BoolVar(&flagListOpenNow, "open-now", false, "Only listings whose structured hours say they are open")
+	f.StringVar(&flagListOpenAt, "open-at", "", "Only listings open at this time (YYYY-MM-DDTHH:MM on each listing's clock, or RFC 3339)")
+	f.BoolVar(&flagListHasImage, "has-image", false, "Only listings with an image")
+	f.BoolVar(&flagListAll, "all", false, "Include inactive and unapproved listings")
+}
+
 var listingGetCmd = &cobra.Command{
 	Use:   "get [id]",
 	Short: "Get a listing by ID",
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var listingRevisionsCmd = &cobra.Command{
+	Use:   "revisions [id]",
+	Short: "List earlier revisions of a listing",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+
+		revisions, err := newAuditService(repo).Revisions(context.Background(), args[0])
+		exitOnErr(err, "Failed to get listing revisions")
+
+		if printListResponse(cmd, revisions, len(revisions), "No revisions recorded for this listing") {
+			return
+		}
+
+		cmd.Printf("Found %d revisions of listing %s:\n\n", len(revisions), args[0])
+		for _, r := range revisions {
+			actor := r.ActorName
+			if actor == "" {
+				actor = r.ActorID
+			}
+			cmd.Printf("#%d | replaced %s by %s\n", r.Number, r.CreatedAt.Format(layoutDateTime), actor)
+			for _, ch := range r.Changes {
+				cmd.Printf("    %s: %q -> %q\n", ch.Field, ch.Before, ch.After)
+			}
+		}
+	},
+}
+
+var listingRestoreCmd = &cobra.Command{
+	Use:   "restore [id] [rev]",
+	Short: "Restore a listing's content from an earlier revision",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		number, err := strconv.Atoi(args[1])
+		exitOnErr(err, "Revision must be a number")
+
+		repo := initRepo()
+		listing, err := newAuditService(repo).Restore(context.Background(), domain.ActorCLI, args[0], number)
+		exitOnErr(err, "Failed to restore revision")
+
+		if !flagText {
+			data, _ := json.MarshalIndent(listing, "", "  ")
+			cmd.Println(string(data))
+			return
+		}
+
+		cmd.Printf("Listing %s restored to revision %d\n", listing.ID, number)
+		printListing(cmd, listing)
+	},
+}
@@ -20,7 +20,7 @@ var listingUpdateCmd = &cobra.Command{
 
 		applyListingUpdates(&listing)
 
-		exitOnErr(repo.Save(context.Background(), listing), domain.MsgFailedToUpdateListing)
+		exitOnErr(newAuditService(repo).Save(context.Background(), domain.ActorCLI, domain.ListingActionUpdate, listing), domain.MsgFailedToUpdateListing)
 
 		if !flagText {
 			data, _ := json.MarshalIndent(listing, "", "  ")
@@ -29,10 +29,11 @@ func main() {
 
 	ctx := context.Background()
 
-	listings, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", false, 2000, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Limit: 2000})
 	if err != nil {
 		log.Fatalf("failed to get listings: %v", err)
 	}
+	listings := page.Listings
 
 	fmt.Printf("Processing %d listings...\n", len(listings))
 	updated := 0
@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/jadecobra/agbalumo/internal/repository/sqlite"
 	"github.com/spf13/cobra"
 
@@ -55,6 +56,11 @@ func initRepo() *sqlite.SQLiteRepository {
 	return repo
 }
 
+// newAuditService records CLI listing mutations in the listing history.
+func newAuditService(repo *sqlite.SQLiteRepository) *listing.AuditService {
+	return listing.NewAuditService(repo)
+}
+
 func getDatabaseURL() string {
 	if dbURL := os.Getenv(domain.EnvKeyDatabaseURL); dbURL != "" {
 		return dbURL
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/repository/sqlite"
 )
 
@@ -37,7 +38,8 @@ func TestStressCmd_Success(t *testing.T) {
 		t.Fatalf("failed to open test database: %v", err)
 	}
 
-	listings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 100, 0)
+	page, err := repo.Search(context.Background(), domain.ListingQuery{Limit: 100})
+	listings := page.Listings
 	if err != nil {
 		t.Fatalf("failed to query test database: %v", err)
 	}
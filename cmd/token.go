@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagTokenName   string
+	flagTokenScopes string
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage personal API tokens",
+	Long: `The token command mints, lists and revokes personal access tokens that
+let scripts call the JSON API with an "Authorization: Bearer" header.`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create [user-id]",
+	Short: "Mint a personal API token for a user",
+	Long: `Mint a personal API token for a user. The plaintext token is printed once
+and cannot be recovered later; only its hash is stored.`,
+	Example: `  # Read-only token for a sync script
+  agbalumo token create user-12345 --name "sync" --scopes listings:read`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+		ctx := context.Background()
+
+		user, err := repo.FindUserByID(ctx, args[0])
+		exitOnErr(err, "User not found")
+
+		scopes, err := domain.ParseTokenScopes(flagTokenScopes)
+		exitOnErr(err, "Invalid scopes")
+
+		raw, token, err := auth.NewTokenService(repo).Mint(ctx, user, flagTokenName, scopes)
+		exitOnErr(err, "Failed to create token")
+
+		if !flagText {
+			data, _ := json.MarshalIndent(struct {
+				domain.APIToken
+				Token string `json:"token"`
+			}{token, raw}, "", "  ")
+			cmd.Println(string(data))
+			return
+		}
+
+		cmd.Printf("Token created: %s (%s)\n", token.Name, domain.JoinTokenScopes(token.Scopes))
+		cmd.Printf("\n  %s\n\nCopy it now; it will not be shown again.\n", raw)
+	},
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list [user-id]",
+	Short: "List a user's API tokens",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+
+		tokens, err := repo.ListAPITokens(context.Background(), args[0])
+		exitOnErr(err, "Failed to list tokens")
+
+		if printListResponse(cmd, tokens, len(tokens), "No tokens found") {
+			return
+		}
+
+		cmd.Printf("Found %d tokens:\n\n", len(tokens))
+		for _, t := range tokens {
+			status := "active"
+			if t.IsRevoked() {
+				status = "revoked"
+			}
+			cmd.Printf("[%s] %s %s… %s (%s)\n", t.ID, t.Name, t.Prefix, domain.JoinTokenScopes(t.Scopes), status)
+		}
+	},
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke [user-id] [token-id]",
+	Short: "Revoke a user's API token",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := initRepo()
+
+		exitOnErr(auth.NewTokenService(repo).Revoke(context.Background(), args[0], args[1]), "Failed to revoke token")
+
+		cmd.Printf("Token revoked: %s\n", args[1])
+	},
+}
+
+func init() {
+	tokenCreateCmd.Flags().StringVarP(&flagTokenName, domain.FieldName, "n", "", "Token name shown on the profile page")
+	tokenCreateCmd.Flags().StringVarP(&flagTokenScopes, domain.FieldScopes, "s", string(domain.ScopeListingsRead), "Comma-separated scopes (listings:read, listings:write, admin)")
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	rootCmd.AddCommand(tokenCmd)
+}
@@ -27,10 +27,11 @@ var locationBackfillCmd = &cobra.Command{
 		defer func() { _ = repo.Close() }()
 
 		ctx := cmd.Context()
-		listings, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", true, 10000, 0)
+		page, err := repo.Search(ctx, domain.ListingQuery{IncludeInactive: true, Limit: 10000})
 		if err != nil {
 			return err
 		}
+		listings := page.Listings
 
 		count := 0
 		for _, l := range listings {
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"cmp"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 )
@@ -12,6 +15,8 @@ import (
 const EnvBaseURL = "BASE_URL"
 
 type Config struct {
+	// SessionPreviousUntil is when SessionPreviousSecrets stop being accepted.
+	SessionPreviousUntil time.Time
 	Env                  string
 	DatabaseURL          string
 	SessionSecret        string
@@ -19,6 +24,33 @@ type Config struct {
 	DevAuthEmail         string
 	UploadDir            string
 	GoogleMapsAPIKey     string
+	BaseURL              string
+	SMTPHost             string
+	SMTPUsername         string
+	SMTPPassword         string
+	MailFrom             string
+	// MailDir is where outgoing mail is written when no SMTP host is configured.
+	MailDir          string
+	NotifyAdminEmail string
+	// TwilioAccountSID, TwilioAuthToken and SMSFrom configure text messages; SMSFrom is
+	// a phone number or messaging service SID. Without them, phone verification is off
+	// outside development.
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	SMSFrom          string
+	// GeocodingProviders are the geocoders tried in order: "google" and "gazetteer".
+	GeocodingProviders []string
+	// GazetteerPaths are GeoNames-style files for the offline geocoder; the bundled
+	// gazetteer is used when there are none.
+	GazetteerPaths []string
+	// OIDCProviders are the OpenID Connect providers offered on the login page.
+	OIDCProviders []OIDCProvider
+	// SessionPreviousSecrets are retired session secrets whose cookies are still
+	// accepted, so rotating SessionSecret signs nobody out at once.
+	SessionPreviousSecrets []string
+	SMTPPort               int
+	// ExpiryReminderDays is how many days before expiry owners are reminded.
+	ExpiryReminderDays   int
 	RateLimitRate        int
 	RateLimitBurst       int
 	SlowQueryThresholdMs int
@@ -41,7 +73,7 @@ func LoadConfig() *Config {
 	hasGoogleAuth := clientID != "" && clientSecret != ""
 	MockAuth := os.Getenv(domain.EnvKeyMockAuth) == "true"
 
-	return &Config{
+	cfg := &Config{
 		Env:                  env,
 		DatabaseURL:          getEnv(domain.EnvKeyDatabaseURL, domain.DefaultDatabaseURL),
 		SessionSecret:        getEnv(domain.EnvKeySessionSecret, "dev-secret-key"),
@@ -54,7 +86,115 @@ func LoadConfig() *Config {
 		HasGoogleAuth:        hasGoogleAuth || MockAuth,
 		MockAuth:             MockAuth,
 		SlowQueryThresholdMs: getEnvAsInt(domain.EnvKeySlowQueryThreshold, 50),
+		BaseURL:              getBaseURL(env),
+		SMTPHost:             getEnv(domain.EnvKeySMTPHost, ""),
+		SMTPPort:             getEnvAsInt(domain.EnvKeySMTPPort, 587),
+		SMTPUsername:         getEnv(domain.EnvKeySMTPUsername, ""),
+		SMTPPassword:         getEnv(domain.EnvKeySMTPPassword, ""),
+		MailFrom:             getEnv(domain.EnvKeyMailFrom, "agbalumo <no-reply@agbalumo.com>"),
+		MailDir:              getEnv(domain.EnvKeyMailDir, ""),
+		NotifyAdminEmail:     getEnv(domain.EnvKeyNotifyAdminEmail, ""),
+		TwilioAccountSID:     getEnv(domain.EnvKeyTwilioAccountSID, ""),
+		TwilioAuthToken:      getEnv(domain.EnvKeyTwilioAuthToken, ""),
+		SMSFrom:              getEnv(domain.EnvKeySMSFrom, ""),
+		ExpiryReminderDays:   getEnvAsInt(domain.EnvKeyExpiryReminderDays, 3),
+		GeocodingProviders:   getEnvAsList(domain.EnvKeyGeocodingProviders, []string{"google", "gazetteer"}),
+		GazetteerPaths:       getEnvAsList(domain.EnvKeyGazetteerPath, nil),
+		OIDCProviders:        getOIDCProviders(),
 	}
+	cfg.SessionPreviousSecrets, cfg.SessionPreviousUntil = getPreviousSessionSecrets()
+	return cfg
+}
+
+// getPreviousSessionSecrets reads the retired session secrets and when they stop being
+// accepted. Without a valid end time they are accepted for one session lifetime from
+// startup, which signs nobody out but does not survive restarts.
+func getPreviousSessionSecrets() ([]string, time.Time) {
+	secrets := getEnvAsList(domain.EnvKeySessionSecretPrevious, nil)
+	if len(secrets) == 0 {
+		return nil, time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, os.Getenv(domain.EnvKeySessionSecretPreviousUntil))
+	if err != nil {
+		slog.Warn("Set " + domain.EnvKeySessionSecretPreviousUntil + " to an RFC 3339 time; accepting previous session secrets for one session lifetime")
+		until = time.Now().Add(domain.SessionMaxAge)
+	}
+	return secrets, until
+}
+
+// OIDCProvider is an OpenID Connect provider users can sign in with.
+type OIDCProvider struct {
+	// ID names the provider in URLs and linked identities, e.g. "microsoft".
+	ID string
+	// Name is shown on the login page.
+	Name string
+	// Issuer is the provider's issuer URL, where its discovery document is found.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes are requested besides "openid".
+	Scopes []string
+}
+
+// getOIDCProviders reads the providers listed in OIDC_PROVIDERS, skipping any
+// without an issuer and client credentials.
+func getOIDCProviders() []OIDCProvider {
+	var providers []OIDCProvider
+	seen := make(map[string]bool)
+	for _, id := range getEnvAsList(domain.EnvKeyOIDCProviders, nil) {
+		id = strings.ToLower(id)
+		if !validProviderID(id) || id == domain.IdentityProviderGoogle || seen[id] {
+			slog.Warn("Skipping OpenID Connect provider with an invalid or duplicate ID", "provider", id)
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(id) + "_"
+		p := OIDCProvider{
+			ID:           id,
+			Name:         getEnv(prefix+"NAME", id),
+			Issuer:       strings.TrimSuffix(getEnv(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       getEnvAsList(prefix+"SCOPES", []string{"email", "profile"}),
+		}
+		if p.Issuer == "" || p.ClientID == "" || p.ClientSecret == "" {
+			slog.Warn("Skipping OpenID Connect provider without "+prefix+"ISSUER, CLIENT_ID and CLIENT_SECRET", "provider", id)
+			continue
+		}
+		seen[id] = true
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// validProviderID reports whether id is lower case letters, digits and underscores,
+// so it fits both a URL path and an environment variable name.
+func validProviderID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// getBaseURL is the public origin of the site, which links in email and provider
+// redirects are built from. It is never taken from requests, whose Host header a
+// client can forge, so it is required in production and empty when unset anywhere but
+// development.
+func getBaseURL(env string) string {
+	base := strings.TrimSuffix(os.Getenv(domain.EnvKeyBaseURL), "/")
+	if base != "" || env == domain.EnvDevelopment {
+		return cmp.Or(base, "http://localhost:8080")
+	}
+	if env == domain.EnvProduction {
+		slog.Error(domain.EnvKeyBaseURL + " environment variable is required in production")
+		os.Exit(1)
+	}
+	slog.Warn(domain.EnvKeyBaseURL + " is not set; sign-in links will not be sent")
+	return ""
 }
 
 func getAdminCode(env string) string {
@@ -80,6 +220,20 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvAsList reads a comma separated list, dropping empty entries.
+func getEnvAsList(key string, fallback []string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	if len(list) == 0 {
+		return fallback
+	}
+	return list
+}
+
 func getEnvAsInt(key string, fallback int) int {
 	valStr := getEnv(key, "")
 	if valStr == "" {
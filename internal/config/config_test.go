@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/jadecobra/agbalumo/internal/config"
 	"github.com/stretchr/testify/require"
@@ -10,7 +11,7 @@ import (
 
 func TestLoadConfig(t *testing.T) {
 	// Clean env before testing
-	keys := []string{"AGBALUMO_ENV", "DATABASE_URL", "SESSION_SECRET", "ADMIN_CODE", "DEV_AUTH_EMAIL", "RATE_LIMIT_RATE", "RATE_LIMIT_BURST"}
+	keys := []string{"AGBALUMO_ENV", "DATABASE_URL", "SESSION_SECRET", "ADMIN_CODE", "DEV_AUTH_EMAIL", "RATE_LIMIT_RATE", "RATE_LIMIT_BURST", "BASE_URL"}
 	for _, k := range keys {
 		_ = os.Unsetenv(k)
 	}
@@ -24,6 +25,7 @@ func TestLoadConfig(t *testing.T) {
 		require.Equal(t, "dev@agbalumo.com", cfg.DevAuthEmail)
 		require.Equal(t, 20, cfg.RateLimitRate)
 		require.Equal(t, 40, cfg.RateLimitBurst)
+		require.Equal(t, "http://localhost:8080", cfg.BaseURL)
 	})
 
 	t.Run("overrides", func(t *testing.T) {
@@ -34,6 +36,7 @@ func TestLoadConfig(t *testing.T) {
 		_ = os.Setenv("DEV_AUTH_EMAIL", "test@example.com")
 		_ = os.Setenv("RATE_LIMIT_RATE", "50")
 		_ = os.Setenv("RATE_LIMIT_BURST", "100")
+		_ = os.Setenv("BASE_URL", "https://agbalumo.example.com/")
 		defer func() {
 			for _, k := range keys {
 				_ = os.Unsetenv(k)
@@ -48,6 +51,12 @@ func TestLoadConfig(t *testing.T) {
 		require.Equal(t, "test@example.com", cfg.DevAuthEmail)
 		require.Equal(t, 50, cfg.RateLimitRate)
 		require.Equal(t, 100, cfg.RateLimitBurst)
+		require.Equal(t, "https://agbalumo.example.com", cfg.BaseURL)
+	})
+
+	t.Run("base URL is never defaulted outside development", func(t *testing.T) {
+		t.Setenv("AGBALUMO_ENV", "staging")
+		require.Empty(t, config.LoadConfig().BaseURL)
 	})
 
 	t.Run("invalid int fallback", func(t *testing.T) {
@@ -57,4 +66,55 @@ func TestLoadConfig(t *testing.T) {
 		cfg := config.LoadConfig()
 		require.Equal(t, 20, cfg.RateLimitRate) // Default fallback
 	})
+
+	t.Run("geocoding providers", func(t *testing.T) {
+		require.Equal(t, []string{"google", "gazetteer"}, config.LoadConfig().GeocodingProviders)
+
+		t.Setenv("GEOCODING_PROVIDERS", "gazetteer, ,google")
+		t.Setenv("GAZETTEER_PATH", "cities.tsv,zips.tsv")
+		cfg := config.LoadConfig()
+		require.Equal(t, []string{"gazetteer", "google"}, cfg.GeocodingProviders)
+		require.Equal(t, []string{"cities.tsv", "zips.tsv"}, cfg.GazetteerPaths)
+	})
+}
+
+func TestLoadConfig_OIDCProviders(t *testing.T) {
+	require.Empty(t, config.LoadConfig().OIDCProviders)
+
+	t.Setenv("OIDC_PROVIDERS", "Microsoft, apple, bad-id, google, okta")
+	t.Setenv("OIDC_MICROSOFT_ISSUER", "https://login.example.com/tenant/v2.0/")
+	t.Setenv("OIDC_MICROSOFT_CLIENT_ID", "ms-client")
+	t.Setenv("OIDC_MICROSOFT_CLIENT_SECRET", "ms-secret")
+	t.Setenv("OIDC_MICROSOFT_NAME", "Microsoft")
+	t.Setenv("OIDC_APPLE_ISSUER", "https://appleid.example.com")
+	t.Setenv("OIDC_APPLE_CLIENT_ID", "apple-client")
+	t.Setenv("OIDC_APPLE_CLIENT_SECRET", "apple-secret")
+	t.Setenv("OIDC_APPLE_SCOPES", "email")
+	t.Setenv("OIDC_OKTA_ISSUER", "https://okta.example.com")
+
+	providers := config.LoadConfig().OIDCProviders
+	require.Len(t, providers, 2, "invalid IDs, google and providers missing credentials are skipped")
+	require.Equal(t, config.OIDCProvider{
+		ID: "microsoft", Name: "Microsoft", Issuer: "https://login.example.com/tenant/v2.0",
+		ClientID: "ms-client", ClientSecret: "ms-secret", Scopes: []string{"email", "profile"},
+	}, providers[0])
+	require.Equal(t, "apple", providers[1].Name, "the name defaults to the ID")
+	require.Equal(t, []string{"email"}, providers[1].Scopes)
+}
+
+func TestLoadConfig_PreviousSessionSecrets(t *testing.T) {
+	cfg := config.LoadConfig()
+	require.Empty(t, cfg.SessionPreviousSecrets)
+	require.True(t, cfg.SessionPreviousUntil.IsZero())
+
+	t.Setenv("SESSION_SECRET_PREVIOUS", "old-1, old-2")
+	t.Setenv("SESSION_SECRET_PREVIOUS_UNTIL", "2030-01-02T03:04:05Z")
+	cfg = config.LoadConfig()
+	require.Equal(t, []string{"old-1", "old-2"}, cfg.SessionPreviousSecrets)
+	require.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), cfg.SessionPreviousUntil.UTC())
+
+	t.Setenv("SESSION_SECRET_PREVIOUS_UNTIL", "next week")
+	cfg = config.LoadConfig()
+	require.WithinDuration(t, time.Now().Add(7*24*time.Hour), cfg.SessionPreviousUntil, time.Minute,
+		"without a valid end time the secrets last one session lifetime")
 }
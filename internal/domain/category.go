@@ -29,6 +29,8 @@ type CategoryData struct {
 	IsSystem                  bool      `json:"is_system"`
 	Active                    bool      `json:"active"`
 	RequiresSpecialValidation bool      `json:"requires_special_validation"`
+	// RequiresModeration holds new listings and material edits by non-admins for review.
+	RequiresModeration bool `json:"requires_moderation"`
 }
 
 // CategoryFilter options for querying categories
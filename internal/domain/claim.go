@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
 
 // ClaimStatus represents the state of a user's claim request on a listing.
 type ClaimStatus string
@@ -11,14 +16,163 @@ const (
 	ClaimStatusRejected ClaimStatus = "Rejected"
 )
 
+const (
+	// ClaimCooldown is how long after a rejection the same user may claim the listing again.
+	ClaimCooldown = 14 * 24 * time.Hour
+	// ClaimPhoneCodeTTL is how long a phone verification code stays valid.
+	ClaimPhoneCodeTTL = 15 * time.Minute
+	// ClaimPhoneCodeResendInterval is the minimum wait before another code is sent.
+	ClaimPhoneCodeResendInterval = time.Minute
+)
+
 // ClaimRequest represents a user's request to claim ownership of an admin-created listing.
 type ClaimRequest struct {
-	CreatedAt    time.Time   `json:"created_at"`
-	ID           string      `json:"id"`
-	ListingID    string      `json:"listing_id"`
-	ListingTitle string      `json:"listing_title"`
-	UserID       string      `json:"user_id"`
-	UserName     string      `json:"user_name"`
-	UserEmail    string      `json:"user_email"`
-	Status       ClaimStatus `json:"status"`
+	CreatedAt       time.Time `json:"created_at"`
+	ReviewedAt      time.Time `json:"reviewed_at"`
+	PhoneCodeSentAt time.Time `json:"phone_code_sent_at"`
+	PhoneVerifiedAt time.Time `json:"phone_verified_at"`
+	ID              string    `json:"id"`
+	ListingID       string    `json:"listing_id"`
+	ListingTitle    string    `json:"listing_title"`
+	UserID          string    `json:"user_id"`
+	UserName        string    `json:"user_name"`
+	UserEmail       string    `json:"user_email"`
+	// EvidenceEmail is a business address the claimant says they use. Unlike
+	// UserEmail it has not been verified.
+	EvidenceEmail string `json:"evidence_email"`
+	// DocumentURL is an uploaded image of a document linking the claimant to the business.
+	DocumentURL string `json:"document_url"`
+	// PhoneCodeHash is the SHA-256 of the outstanding phone verification code.
+	PhoneCodeHash   string      `json:"-"`
+	ReviewerID      string      `json:"reviewer_id"`
+	ReviewerNotes   string      `json:"reviewer_notes"`
+	RejectionReason string      `json:"rejection_reason"`
+	Status          ClaimStatus `json:"status"`
+}
+
+// ClaimEvidence is what a claimant submits to support a claim.
+type ClaimEvidence struct {
+	Email       string
+	DocumentURL string
+}
+
+// ClaimReview is a moderator's decision on a claim. Notes are internal; the
+// rejection reason is shown to the claimant.
+type ClaimReview struct {
+	ReviewedAt      time.Time
+	Status          ClaimStatus
+	ReviewerID      string
+	Notes           string
+	RejectionReason string
+}
+
+// PhoneVerified reports whether the claimant entered the code sent to the listing's phone.
+func (cr ClaimRequest) PhoneVerified() bool {
+	return !cr.PhoneVerifiedAt.IsZero()
+}
+
+// ResubmittableAt returns when a rejected claim's user may claim the listing again,
+// or the zero time for claims that were not rejected.
+func (cr ClaimRequest) ResubmittableAt() time.Time {
+	if cr.Status != ClaimStatusRejected {
+		return time.Time{}
+	}
+	reviewed := cr.ReviewedAt
+	if reviewed.IsZero() {
+		reviewed = cr.CreatedAt
+	}
+	return reviewed.Add(ClaimCooldown)
+}
+
+// ClaimSignal is one piece of evidence considered by ScoreClaim. A SelfReported
+// signal rests on unverified claimant input, so it is shown to moderators but scores
+// no points.
+type ClaimSignal struct {
+	Label        string
+	Points       int
+	Met          bool
+	SelfReported bool
+}
+
+// ClaimScore is an automatic estimate, from 0 to 100, of how likely a claim is genuine.
+// It guides moderators and never approves a claim on its own.
+type ClaimScore struct {
+	Signals []ClaimSignal
+	Score   int
+}
+
+// Level buckets the score as "high", "medium" or "low".
+func (s ClaimScore) Level() string {
+	switch {
+	case s.Score >= 70:
+		return "high"
+	case s.Score >= 40:
+		return "medium"
+	}
+	return "low"
+}
+
+// freeMailDomains are consumer providers whose addresses say nothing about who runs a business.
+var freeMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "hotmail.com": true,
+	"outlook.com": true, "live.com": true, "icloud.com": true, "aol.com": true, "proton.me": true,
+}
+
+// ScoreClaim scores cr against the listing being claimed.
+func ScoreClaim(cr ClaimRequest, l Listing) ClaimScore {
+	site := websiteDomain(l.WebsiteURL)
+	// The stated business email is never verified, so it only informs the moderator.
+	stated := cr.EvidenceEmail != "" && !strings.EqualFold(cr.EvidenceEmail, cr.UserEmail)
+	signals := []ClaimSignal{
+		{Label: "Account email matches website domain", Points: 40, Met: emailMatchesDomain(cr.UserEmail, site)},
+		{Label: "Account email matches listing contact email", Points: 20, Met: l.ContactEmail != "" && strings.EqualFold(cr.UserEmail, l.ContactEmail)},
+		{Label: "Listing phone verified by code", Points: 30, Met: cr.PhoneVerified()},
+		{Label: "Supporting document attached", Points: 10, Met: cr.DocumentURL != ""},
+		{Label: "Stated business email matches website domain", SelfReported: true, Met: stated && emailMatchesDomain(cr.EvidenceEmail, site)},
+		{Label: "Stated business email matches listing contact email", SelfReported: true, Met: stated && l.ContactEmail != "" && strings.EqualFold(cr.EvidenceEmail, l.ContactEmail)},
+	}
+
+	score := ClaimScore{Signals: signals}
+	for _, s := range signals {
+		if s.Met {
+			score.Score += s.Points
+		}
+	}
+	if score.Score > 100 {
+		score.Score = 100
+	}
+	return score
+}
+
+func websiteDomain(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// emailMatchesDomain reports whether email's domain is site or one of its subdomains,
+// ignoring free mail providers.
+func emailMatchesDomain(email, site string) bool {
+	at := strings.LastIndex(email, "@")
+	if site == "" || at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	if freeMailDomains[domain] {
+		return false
+	}
+	return domain == site || strings.HasSuffix(domain, "."+site) || strings.HasSuffix(site, "."+domain)
+}
+
+// SMSSender delivers a text message, such as a phone verification code.
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, body string) error
 }
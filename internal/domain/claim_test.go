@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreClaim(t *testing.T) {
+	t.Parallel()
+	l := Listing{WebsiteURL: "https://www.mamaput.example", ContactEmail: "hello@mamaput.example"}
+
+	tests := []struct {
+		name  string
+		claim ClaimRequest
+		want  int
+	}{
+		{"Nothing", ClaimRequest{UserEmail: "ada@gmail.com"}, 0},
+		{"AccountDomain", ClaimRequest{UserEmail: "ada@mamaput.example"}, 40},
+		{"AccountIsContact", ClaimRequest{UserEmail: "Hello@mamaput.example"}, 60},
+		{"StatedEmailIsNotScored", ClaimRequest{UserEmail: "ada@gmail.com", EvidenceEmail: "hello@mamaput.example"}, 0},
+		{"Everything", ClaimRequest{UserEmail: "hello@mamaput.example", PhoneVerifiedAt: time.Now(), DocumentURL: "/doc.webp"}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ScoreClaim(tt.claim, l).Score)
+		})
+	}
+}
+
+func TestScoreClaim_ShowsStatedEmailAsSelfReported(t *testing.T) {
+	t.Parallel()
+	l := Listing{WebsiteURL: "https://www.mamaput.example", ContactEmail: "hello@mamaput.example"}
+	score := ScoreClaim(ClaimRequest{UserEmail: "ada@gmail.com", EvidenceEmail: "hello@mamaput.example"}, l)
+
+	var stated []ClaimSignal
+	for _, s := range score.Signals {
+		if s.SelfReported {
+			stated = append(stated, s)
+		}
+	}
+	assert.Len(t, stated, 2)
+	for _, s := range stated {
+		assert.True(t, s.Met, s.Label)
+		assert.Zero(t, s.Points, s.Label)
+	}
+	assert.Equal(t, "low", score.Level())
+}
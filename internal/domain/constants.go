@@ -21,6 +21,26 @@ const (
 	EnvKeyRateLimitRate      = "RATE_LIMIT_RATE"
 	EnvKeyRateLimitBurst     = "RATE_LIMIT_BURST"
 	EnvKeySlowQueryThreshold = "SLOW_QUERY_THRESHOLD_MS"
+	EnvKeySMTPHost           = "SMTP_HOST"
+	EnvKeySMTPPort           = "SMTP_PORT"
+	EnvKeySMTPUsername       = "SMTP_USERNAME"
+	EnvKeySMTPPassword       = "SMTP_PASSWORD" // #nosec G101 - This is an env var name, not a credential
+	EnvKeyMailFrom           = "MAIL_FROM"
+	EnvKeyMailDir            = "MAIL_DIR"
+	EnvKeyNotifyAdminEmail   = "NOTIFY_ADMIN_EMAIL"
+	EnvKeyTwilioAccountSID   = "TWILIO_ACCOUNT_SID"
+	EnvKeyTwilioAuthToken    = "TWILIO_AUTH_TOKEN" // #nosec G101 - This is an env var name, not a credential
+	EnvKeySMSFrom            = "SMS_FROM"
+	EnvKeyExpiryReminderDays = "EXPIRY_REMINDER_DAYS"
+	EnvKeyGeocodingProviders = "GEOCODING_PROVIDERS"
+	EnvKeyGazetteerPath      = "GAZETTEER_PATH"
+	// EnvKeyOIDCProviders lists the IDs of OpenID Connect providers; each is set up
+	// by OIDC_<ID>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and optionally _NAME and _SCOPES.
+	EnvKeyOIDCProviders = "OIDC_PROVIDERS"
+	// EnvKeySessionSecretPrevious lists retired session secrets whose cookies are still
+	// accepted until EnvKeySessionSecretPreviousUntil (RFC 3339).
+	EnvKeySessionSecretPrevious      = "SESSION_SECRET_PREVIOUS"
+	EnvKeySessionSecretPreviousUntil = "SESSION_SECRET_PREVIOUS_UNTIL"
 
 	// Audit
 	SeparatorLine = "--------------------------------"
@@ -96,24 +116,41 @@ const (
 	FieldRemoveImage       = "remove_image"
 
 	// Context Keys
-	CtxKeyUser = "User"
+	CtxKeyUser     = "User"
+	CtxKeyAPIToken = "APIToken"
 
 	// Sessions
 	SessionName          = "auth_session"
 	SessionKeyOAuthState = "oauth_state"
+	SessionKeyOIDCState  = "oidc_state"
 
 	// Fields (Additional)
 	FieldStatus      = "status"
 	FieldFeatured    = "featured"
+	FieldClosed      = "closed"
 	FieldCreatedAt   = "created_at"
 	FieldAction      = "action"
 	FieldNewCategory = "new_category"
 	FieldClaimable   = "claimable"
+	FieldModerated   = "moderated"
 	FieldAdminCode   = "admin_code"
 	FieldCode        = "code"
 	FieldName        = "name"
 	FieldCSVFile     = "csv_file"
 	FieldContent     = "content"
+	FieldScopes      = "scopes"
+	FieldRole        = "role"
+
+	// Fields (Claims)
+	FieldEvidenceEmail   = "evidence_email"
+	FieldClaimDocument   = "document"
+	FieldReviewerNotes   = "notes"
+	FieldRejectionReason = "reason"
+
+	// Fields (User moderation)
+	FieldStatusReason       = "reason"
+	FieldSuspendedUntil     = "until"
+	FieldDeactivateListings = "deactivate_listings"
 
 	// Headers
 	HeaderHXTrigger = "HX-Trigger"
@@ -139,11 +176,26 @@ const (
 	ParamQuery       = "q"
 	ParamID          = "id"
 	ParamPage        = "page"
+	ParamCursor      = "cursor"
+	ParamRevision    = "rev"
 	ParamTarget      = "target"
 	ParamState       = "state"
 	ParamCode        = "code"
+	ParamProvider    = "provider"
+	ParamError       = "error"
 	ParamCSVFile     = "csv_file"
 	ParamListingIDs  = "selectedListings"
+	ParamToken       = "token"
+	ParamOpenNow     = "open_now"
+	ParamOpenAt      = "open_at"
+	ParamRadius      = "radius"
+	ParamLat         = "lat"
+	ParamLng         = "lng"
+
+	// SortDistance orders listings nearest first from the query's Latitude and Longitude.
+	SortDistance = "distance"
+	// SortRelevance orders listings best match first for the query's QueryText.
+	SortRelevance = "relevance"
 
 	SessionKeyUserID = "user_id"
 	FlashMessageKey  = "message"
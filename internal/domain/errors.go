@@ -5,6 +5,30 @@ import "errors"
 var (
 	// ErrUserNotFound is returned when a user is not found in the repository.
 	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidEmail is returned when an email address is malformed.
+	ErrInvalidEmail = errors.New("enter a valid email address")
+	// ErrInvalidLoginLink is returned when an emailed sign-in link is unknown, used, or expired.
+	ErrInvalidLoginLink = errors.New("this sign-in link is invalid, already used or has expired")
+	// ErrIdentityLinked is returned when a sign-in account is already linked to another user.
+	ErrIdentityLinked = errors.New("this sign-in is already linked to another account")
+	// ErrInvalidRole is returned when a role name is not one of the known roles.
+	ErrInvalidRole = errors.New("unknown role")
+	// ErrRoleNotAssignable is returned when a user may not give another user a role.
+	ErrRoleNotAssignable = errors.New("you cannot assign this role")
+	// ErrInvalidUserStatus is returned when a status name is not one of the known statuses.
+	ErrInvalidUserStatus = errors.New("unknown user status")
+	// ErrUserNotRestrictable is returned when a staff member may not change a user's status.
+	ErrUserNotRestrictable = errors.New("you cannot change this user's status")
+	// ErrUserNotSignOutable is returned when a staff member may not sign a user out.
+	ErrUserNotSignOutable = errors.New("you cannot sign this user out")
+	// ErrStatusReasonRequired is returned when a user is shadowed, suspended or banned without a reason.
+	ErrStatusReasonRequired = errors.New("a reason is required to shadow, suspend or ban a user")
+	// ErrInvalidSuspension is returned when a suspension does not end in the future.
+	ErrInvalidSuspension = errors.New("a suspension must end in the future")
+	// ErrUserSuspended is returned when a suspended user tries to sign in or make a request.
+	ErrUserSuspended = errors.New("this account is suspended")
+	// ErrUserBanned is returned when a banned user tries to sign in or make a request.
+	ErrUserBanned = errors.New("this account is banned")
 	// ErrListingNotFound is returned when a listing is not found.
 	ErrListingNotFound = errors.New("listing not found")
 	// ErrCategoryNotFound is returned when a category is not found.
@@ -27,4 +51,50 @@ var (
 	ErrPendingClaimExists = errors.New("you already have a pending claim for this listing")
 	// ErrFailedToSaveClaim is returned when a claim record cannot be persisted.
 	ErrFailedToSaveClaim = errors.New("failed to save claim request")
+	// ErrInvalidCursor is returned when a listing pagination cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	// ErrLocationNotFound is returned when a geocoding provider does not recognise an address.
+	ErrLocationNotFound = errors.New("location not found")
+	// ErrGeocodeNotCached is returned when the geocode cache has no unexpired entry.
+	ErrGeocodeNotCached = errors.New("geocode not cached")
+	// ErrRevisionNotFound is returned when a listing has no revision with the requested number.
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrPendingChangeNotFound is returned when a pending change does not exist or was already resolved.
+	ErrPendingChangeNotFound = errors.New("pending change not found")
+	// ErrSessionNotFound is returned when a browser session does not exist or has expired.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrTokenNotFound is returned when a personal access token does not exist.
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrInvalidToken is returned when a bearer token is malformed, unknown, or revoked.
+	ErrInvalidToken = errors.New("invalid or revoked token")
+	// ErrTokenScopeRequired is returned when a token is minted without any scope.
+	ErrTokenScopeRequired = errors.New("at least one token scope is required")
+	// ErrInvalidTokenScope is returned when a token scope is not recognised.
+	ErrInvalidTokenScope = errors.New("invalid token scope")
+	// ErrScopeNotGrantable is returned when a user requests a scope their role does not allow.
+	ErrScopeNotGrantable = errors.New("you are not allowed to mint a token with this scope")
+	// ErrInsufficientScope is returned when a token lacks the scope a request needs.
+	ErrInsufficientScope = errors.New("token does not grant the required scope")
+	// ErrClaimCooldown is returned when a user re-claims a listing too soon after a rejection.
+	ErrClaimCooldown = errors.New("your previous claim was rejected recently")
+	// ErrInvalidEvidenceEmail is returned when a claim's business email is not a valid address.
+	ErrInvalidEvidenceEmail = errors.New("business email is not a valid address")
+	// ErrRejectionReasonRequired is returned when a claim is rejected without a reason for the claimant.
+	ErrRejectionReasonRequired = errors.New("a rejection reason is required")
+	// ErrNoClaimPhone is returned when phone verification is requested for a listing without a phone number.
+	ErrNoClaimPhone = errors.New("this listing has no phone number to verify")
+	// ErrPhoneCodeRecentlySent is returned when a new verification code is requested too soon.
+	ErrPhoneCodeRecentlySent = errors.New("a code was sent recently; please wait a minute")
+	// ErrPhoneVerificationUnavailable is returned when no SMS provider is configured.
+	ErrPhoneVerificationUnavailable = errors.New("phone verification is not available")
+	// ErrInvalidPhoneCode is returned when a phone verification code is wrong or expired.
+	ErrInvalidPhoneCode = errors.New("the verification code is invalid or has expired")
+	// ErrClaimNotPending is returned when a claim has already been approved or rejected.
+	ErrClaimNotPending = errors.New("this claim has already been reviewed")
+	// ErrPhoneAlreadyVerified is returned when a claim's phone has already been verified.
+	ErrPhoneAlreadyVerified = errors.New("the listing's phone is already verified for this claim")
+	// ErrListingNotExtendable is returned when extending a listing whose expiry cannot be moved.
+	ErrListingNotExtendable = errors.New("only open requests can be extended")
+	// ErrInvalidExtensionLink is returned when a listing extension link is forged, stale, or expired.
+	ErrInvalidExtensionLink = errors.New("this extension link is invalid or has expired")
 )
@@ -1,9 +1,87 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
 
 // GeocodingService defines the contract for converting addresses to location metadata.
 type GeocodingService interface {
 	GetCity(ctx context.Context, address string) (string, error)
 	Geocode(ctx context.Context, address string) (lat, lng float64, err error)
+	// ReverseGeocode names the area around a point, such as "Katy, TX".
+	ReverseGeocode(ctx context.Context, lat, lng float64) (string, error)
+}
+
+// EarthRadiusMiles is the mean radius of the Earth used for distances.
+const EarthRadiusMiles = 3959.0
+
+// ErrInvalidCoordinates is returned for a latitude or longitude out of range.
+var ErrInvalidCoordinates = errors.New("lat and lng must be a latitude and longitude in degrees")
+
+// ErrInvalidRadius is returned for a search radius that is not a finite number.
+var ErrInvalidRadius = errors.New("radius must be a number")
+
+// ParseCoordinates reads "lat" and "lng" request parameters. It reports false when
+// both are empty. ParseFloat accepts "NaN" and "Inf", which no range check catches,
+// so those are refused explicitly.
+func ParseCoordinates(latStr, lngStr string) (lat, lng float64, ok bool, err error) {
+	if latStr == "" && lngStr == "" {
+		return 0, 0, false, nil
+	}
+	lat, latErr := strconv.ParseFloat(latStr, 64)
+	lng, lngErr := strconv.ParseFloat(lngStr, 64)
+	if latErr != nil || lngErr != nil || !isFinite(lat) || !isFinite(lng) ||
+		math.Abs(lat) > 90 || math.Abs(lng) > 180 || (lat == 0 && lng == 0) {
+		return 0, 0, false, ErrInvalidCoordinates
+	}
+	return lat, lng, true, nil
+}
+
+// ParseRadius reads a "radius" request parameter in miles.
+func ParseRadius(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil || !isFinite(r) {
+		return 0, ErrInvalidRadius
+	}
+	return r, nil
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// DistanceMiles is the great-circle distance between two points in miles.
+func DistanceMiles(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusMiles * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// GeocodeLookup names the GeocodingService method a cached result answers.
+type GeocodeLookup string
+
+const (
+	GeocodeLookupCity   GeocodeLookup = "city"
+	GeocodeLookupCoords GeocodeLookup = "coords"
+	// GeocodeLookupPlace caches ReverseGeocode, keyed by rounded coordinates.
+	GeocodeLookupPlace GeocodeLookup = "place"
+)
+
+// GeocodeCacheEntry is a stored geocoding result for a normalized address. An entry
+// that is not Found records a failed lookup, so it is not retried until it expires.
+type GeocodeCacheEntry struct {
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	Address   string
+	Lookup    GeocodeLookup
+	City      string
+	Latitude  float64
+	Longitude float64
+	Found     bool
 }
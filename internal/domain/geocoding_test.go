@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	t.Parallel()
+
+	lat, lng, ok, err := ParseCoordinates("29.7858", "-95.8245")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 29.7858, lat)
+	assert.Equal(t, -95.8245, lng)
+
+	_, _, ok, err = ParseCoordinates("", "")
+	require.NoError(t, err)
+	assert.False(t, ok, "no coordinates is not an error")
+
+	for _, tc := range [][2]string{{"29.7", ""}, {"north", "-95"}, {"91", "0"}, {"0", "-181"}, {"0", "0"}, {"NaN", "10"}, {"10", "nan"}, {"Inf", "10"}, {"10", "-Infinity"}} {
+		_, _, _, err = ParseCoordinates(tc[0], tc[1])
+		assert.ErrorIs(t, err, ErrInvalidCoordinates, "lat=%q lng=%q", tc[0], tc[1])
+	}
+}
+
+func TestParseRadius(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParseRadius("25")
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, r)
+	for _, s := range []string{"far", "NaN", "+Inf"} {
+		_, err = ParseRadius(s)
+		assert.ErrorIs(t, err, ErrInvalidRadius, s)
+	}
+}
+
+func TestDistanceMiles(t *testing.T) {
+	t.Parallel()
+
+	assert.Zero(t, DistanceMiles(29.76, -95.37, 29.76, -95.37))
+	// Houston to Dallas is about 225 miles as the crow flies.
+	assert.InDelta(t, 225, DistanceMiles(29.7604, -95.3698, 32.7767, -96.7970), 5)
+	assert.Equal(t, DistanceMiles(1, 2, 3, 4), DistanceMiles(3, 4, 1, 2))
+}
+
+func TestListing_DistanceLabel(t *testing.T) {
+	t.Parallel()
+
+	for miles, want := range map[float64]string{0: "", 0.04: "< 0.1 mi", 0.44: "0.4 mi", 9.96: "10.0 mi", 12.6: "13 mi"} {
+		assert.Equal(t, want, Listing{DistanceMiles: miles}.DistanceLabel(), "%v miles", miles)
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"math"
+	"strconv"
 	"time"
 )
 
@@ -55,18 +57,60 @@ type Listing struct {
 	OwnerID               string        `json:"owner_id" form:"owner_id"`
 	ID                    string        `json:"id" form:"id"`
 	StructuredHours       string        `json:"structured_hours" form:"structured_hours"`
+	HoursExceptions       string        `json:"hours_exceptions" form:"hours_exceptions"`
 	PayRange              string        `json:"pay_range" form:"pay_range"`
 	WebsiteURL            string        `json:"website_url" form:"website_url"`
+	Timezone              string        `json:"timezone" form:"timezone"`
+	OpenState             OpenState     `json:"open_state,omitempty" form:"-"`
 	Latitude              float64       `json:"latitude" form:"latitude"`
 	Longitude             float64       `json:"longitude" form:"longitude"`
 	Rating                float64       `json:"rating" form:"rating"`
-	HeatLevel             int           `json:"heat_level" form:"heat_level"`
-	ReviewCount           int           `json:"review_count" form:"review_count"`
-	IsActive              bool          `json:"is_active" form:"is_active"`
-	Featured              bool          `json:"featured" form:"featured"`
-	IsCurrentlyOpen       bool          `json:"is_currently_open" form:"is_currently_open"`
+	// DistanceMiles is how far the listing is from the point a search was centred on.
+	// Like OpenState it is computed for display and never stored; zero means unknown.
+	DistanceMiles     float64 `json:"distance_miles,omitempty" form:"-"`
+	HeatLevel         int     `json:"heat_level" form:"heat_level"`
+	ReviewCount       int     `json:"review_count" form:"review_count"`
+	IsActive          bool    `json:"is_active" form:"is_active"`
+	Featured          bool    `json:"featured" form:"featured"`
+	PermanentlyClosed bool    `json:"permanently_closed" form:"permanently_closed"`
+	IsCurrentlyOpen   bool    `json:"is_currently_open" form:"is_currently_open"`
 }
 
+// OpenState refines Listing.IsCurrentlyOpen with whether the listing opens or closes
+// soon. Like IsCurrentlyOpen it is computed for display and never stored.
+type OpenState string
+
+const (
+	// OpenStateUnknown means the listing's hours could not be evaluated.
+	OpenStateUnknown    OpenState = ""
+	OpenStateOpen       OpenState = "open"
+	OpenStateClosesSoon OpenState = "closes_soon"
+	OpenStateOpensSoon  OpenState = "opens_soon"
+	OpenStateClosed     OpenState = "closed"
+)
+
+// DistanceLabel formats DistanceMiles for a listing card, such as "0.4 mi" or "12 mi",
+// or returns "" when the distance is unknown.
+func (l Listing) DistanceLabel() string {
+	switch {
+	case l.DistanceMiles <= 0:
+		return ""
+	case l.DistanceMiles < 0.1:
+		return "< 0.1 mi"
+	case l.DistanceMiles < 10:
+		return strconv.FormatFloat(l.DistanceMiles, 'f', 1, 64) + " mi"
+	}
+	return strconv.FormatFloat(math.Round(l.DistanceMiles), 'f', 0, 64) + " mi"
+}
+
+// IsOpen reports whether the state is one in which the listing is open.
+func (s OpenState) IsOpen() bool {
+	return s == OpenStateOpen || s == OpenStateClosesSoon
+}
+
+// OpenSoonWindow is how far ahead a listing is considered to open or close soon.
+const OpenSoonWindow = 30 * time.Minute
+
 // ListingStatus represents the moderation state of a listing.
 type ListingStatus string
 
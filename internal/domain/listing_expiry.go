@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+const (
+	// MaxRequestDeadline is how long after creation a request may stay open.
+	MaxRequestDeadline = 90 * 24 * time.Hour
+	// JobListingLifetime is how long after its start date a job stays listed.
+	JobListingLifetime = 90 * 24 * time.Hour
+	// ListingExtensionPeriod is how far one extension pushes back a request's deadline.
+	ListingExtensionPeriod = 30 * 24 * time.Hour
+)
+
+// ExpiresAt returns when ExpireListings will deactivate l, or the zero time for
+// listing types that never expire.
+func (l Listing) ExpiresAt() time.Time {
+	switch l.Type {
+	case Request:
+		return l.Deadline
+	case Event:
+		return l.EventEnd
+	case Job:
+		if l.JobStartDate.IsZero() {
+			return time.Time{}
+		}
+		return l.JobStartDate.Add(JobListingLifetime)
+	}
+	return time.Time{}
+}
+
+// Extendable reports whether an owner can push back l's expiry. Only requests have a
+// deadline of their own; events and jobs expire on dates that describe the real world.
+func (l Listing) Extendable() bool {
+	return l.Type == Request && !l.Deadline.IsZero() && l.Status != ListingStatusRejected
+}
+
+// ExtendDeadline returns l with its deadline ListingExtensionPeriod past the later of
+// the current deadline and now, capped at MaxRequestDeadline after creation so the
+// result still validates. A request that expired is reactivated, but one taken offline
+// for any other reason, as told by history (oldest first), is not extendable. It returns
+// ErrInvalidDeadline when the deadline cannot move any further.
+func (l Listing) ExtendDeadline(now time.Time, history []ListingEvent) (Listing, error) {
+	if !l.Extendable() {
+		return l, ErrListingNotExtendable
+	}
+	if !l.IsActive && (l.Deadline.After(now) || deactivatedInHistory(history)) {
+		return l, ErrListingNotExtendable
+	}
+
+	from := l.Deadline
+	if now.After(from) {
+		from = now
+	}
+	next := from.Add(ListingExtensionPeriod)
+	if limit := l.CreatedAt.Add(MaxRequestDeadline); !l.CreatedAt.IsZero() && next.After(limit) {
+		next = limit
+	}
+	if !next.After(l.Deadline) || !next.After(now) {
+		return l, ErrInvalidDeadline
+	}
+
+	l.Deadline = next
+	l.IsActive = true
+	return l, nil
+}
+
+// deactivatedInHistory reports whether the latest recorded change to a listing's active
+// flag took it offline. Expiry records no event, so a listing that went inactive after
+// its last recorded activation expired on its own.
+func deactivatedInHistory(history []ListingEvent) bool {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Action == ListingActionDeactivate {
+			return true
+		}
+		for _, ch := range history[i].Changes {
+			if ch.Field == "is_active" {
+				return ch.After == "false"
+			}
+		}
+	}
+	return false
+}
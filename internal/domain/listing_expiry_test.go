@@ -0,0 +1,115 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+func TestListing_ExpiresAt(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		l    domain.Listing
+		want time.Time
+	}{
+		"Request":   {domain.Listing{Type: domain.Request, Deadline: at}, at},
+		"Event":     {domain.Listing{Type: domain.Event, EventEnd: at}, at},
+		"Job":       {domain.Listing{Type: domain.Job, JobStartDate: at}, at.Add(domain.JobListingLifetime)},
+		"JobNoDate": {domain.Listing{Type: domain.Job}, time.Time{}},
+		"Business":  {domain.Listing{Type: domain.Business, Deadline: at}, time.Time{}},
+	}
+	for name, tc := range cases {
+		if got := tc.l.ExpiresAt(); !got.Equal(tc.want) {
+			t.Errorf("%s: ExpiresAt() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestListing_ExtendDeadline(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	t.Run("AddsPeriodToCurrentDeadline", func(t *testing.T) {
+		l := domain.Listing{Type: domain.Request, CreatedAt: now.Add(-10 * day), Deadline: now.Add(2 * day), IsActive: true}
+		got, err := l.ExtendDeadline(now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := now.Add(2*day + domain.ListingExtensionPeriod); !got.Deadline.Equal(want) {
+			t.Errorf("Deadline = %v, want %v", got.Deadline, want)
+		}
+	})
+
+	t.Run("ExpiredRequestRestartsFromNowAndReactivates", func(t *testing.T) {
+		l := domain.Listing{Type: domain.Request, CreatedAt: now.Add(-20 * day), Deadline: now.Add(-day), IsActive: false}
+		got, err := l.ExtendDeadline(now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := now.Add(domain.ListingExtensionPeriod); !got.Deadline.Equal(want) || !got.IsActive {
+			t.Errorf("got deadline %v active %v, want %v and active", got.Deadline, got.IsActive, want)
+		}
+	})
+
+	t.Run("CappedAtNinetyDaysAfterCreation", func(t *testing.T) {
+		created := now.Add(-80 * day)
+		l := domain.Listing{Type: domain.Request, CreatedAt: created, Deadline: now.Add(day), IsActive: true}
+		got, err := l.ExtendDeadline(now, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := created.Add(domain.MaxRequestDeadline); !got.Deadline.Equal(want) {
+			t.Errorf("Deadline = %v, want %v", got.Deadline, want)
+		}
+		if err := got.Validate(); errors.Is(err, domain.ErrInvalidDeadline) {
+			t.Errorf("extended listing fails the deadline rule: %v", err)
+		}
+	})
+
+	t.Run("AtLimit", func(t *testing.T) {
+		created := now.Add(-85 * day)
+		l := domain.Listing{Type: domain.Request, CreatedAt: created, Deadline: created.Add(domain.MaxRequestDeadline), IsActive: true}
+		if _, err := l.ExtendDeadline(now, nil); !errors.Is(err, domain.ErrInvalidDeadline) {
+			t.Errorf("err = %v, want ErrInvalidDeadline", err)
+		}
+	})
+
+	t.Run("DeactivatedRequestIsNotReactivated", func(t *testing.T) {
+		expired := domain.Listing{Type: domain.Request, CreatedAt: now.Add(-20 * day), Deadline: now.Add(-day)}
+		offline := []domain.ListingEvent{
+			{Action: domain.ListingActionCreate},
+			{Action: domain.ListingActionDeactivate, Changes: []domain.FieldChange{{Field: "is_active", Before: "true", After: "false"}}},
+		}
+		if _, err := expired.ExtendDeadline(now, offline); !errors.Is(err, domain.ErrListingNotExtendable) {
+			t.Errorf("deactivated by staff: err = %v, want ErrListingNotExtendable", err)
+		}
+
+		reactivated := append(offline, domain.ListingEvent{Action: domain.ListingActionUpdate,
+			Changes: []domain.FieldChange{{Field: "is_active", Before: "false", After: "true"}}})
+		if _, err := expired.ExtendDeadline(now, reactivated); err != nil {
+			t.Errorf("expired after reactivation: err = %v", err)
+		}
+
+		notDue := domain.Listing{Type: domain.Request, CreatedAt: now.Add(-20 * day), Deadline: now.Add(day)}
+		if _, err := notDue.ExtendDeadline(now, nil); !errors.Is(err, domain.ErrListingNotExtendable) {
+			t.Errorf("inactive before its deadline: err = %v, want ErrListingNotExtendable", err)
+		}
+	})
+
+	t.Run("NotExtendable", func(t *testing.T) {
+		for _, l := range []domain.Listing{
+			{Type: domain.Event, EventEnd: now.Add(day)},
+			{Type: domain.Request},
+			{Type: domain.Request, Deadline: now.Add(day), Status: domain.ListingStatusRejected},
+		} {
+			if _, err := l.ExtendDeadline(now, nil); !errors.Is(err, domain.ErrListingNotExtendable) {
+				t.Errorf("%+v: err = %v, want ErrListingNotExtendable", l.Type, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ListingAction names the kind of mutation recorded in a ListingEvent.
+type ListingAction string
+
+const (
+	ListingActionCreate         ListingAction = "create"
+	ListingActionUpdate         ListingAction = "update"
+	ListingActionDelete         ListingAction = "delete"
+	ListingActionApprove        ListingAction = "approve"
+	ListingActionReject         ListingAction = "reject"
+	ListingActionChangeCategory ListingAction = "change_category"
+	ListingActionFeature        ListingAction = "feature"
+	ListingActionUnfeature      ListingAction = "unfeature"
+	ListingActionClaimApprove   ListingAction = "claim_approve"
+	ListingActionRestore        ListingAction = "restore"
+	ListingActionExtend         ListingAction = "extend"
+	ListingActionCloseForGood   ListingAction = "close_for_good"
+	ListingActionReopen         ListingAction = "reopen"
+	ListingActionDeactivate     ListingAction = "deactivate"
+)
+
+// Actor identifies who performed a listing mutation.
+type Actor struct {
+	ID   string
+	Name string
+}
+
+// ActorCLI is recorded for mutations made through the agbalumo command line.
+var ActorCLI = Actor{ID: "cli", Name: "CLI"}
+
+// ActorFromUser returns the actor for a signed-in user.
+func ActorFromUser(u *User) Actor {
+	if u == nil {
+		return Actor{}
+	}
+	return Actor{ID: u.ID, Name: u.Name}
+}
+
+// FieldChange is the before and after value of one listing field, keyed by its JSON name.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ListingEvent is one append-only entry in a listing's history.
+type ListingEvent struct {
+	CreatedAt time.Time     `json:"created_at"`
+	ID        string        `json:"id"`
+	ListingID string        `json:"listing_id"`
+	ActorID   string        `json:"actor_id"`
+	ActorName string        `json:"actor_name"`
+	Action    ListingAction `json:"action"`
+	// Reason is the staff member's explanation, for actions that take one.
+	Reason  string        `json:"reason,omitempty"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// untrackedListingFields are computed at read time and never persisted.
+var untrackedListingFields = map[string]bool{
+	"is_currently_open": true,
+	"open_state":        true,
+}
+
+// DiffListings returns the fields that differ between before and after, sorted by
+// field name. Pass a zero Listing as before for a creation or as after for a deletion.
+func DiffListings(before, after Listing) []FieldChange {
+	b, a := listingFields(before), listingFields(after)
+
+	var changes []FieldChange
+	for field, av := range a {
+		if bv := b[field]; bv != av {
+			changes = append(changes, FieldChange{Field: field, Before: bv, After: av})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// listingFields flattens a listing into display strings keyed by JSON field name.
+func listingFields(l Listing) map[string]string {
+	raw, _ := json.Marshal(l)
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(raw, &decoded)
+
+	fields := make(map[string]string, len(decoded))
+	for k, v := range decoded {
+		if untrackedListingFields[k] {
+			continue
+		}
+		fields[k] = formatFieldValue(v)
+	}
+	return fields
+}
+
+func formatFieldValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if x == zeroTimeJSON {
+			return ""
+		}
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// zeroTimeJSON is how encoding/json renders an unset time.Time.
+const zeroTimeJSON = "0001-01-01T00:00:00Z"
+
+// ListingRevision is a snapshot of a listing as it stood before a save replaced it.
+// Numbers start at 1 and increase with each save of the same listing.
+type ListingRevision struct {
+	CreatedAt time.Time `json:"created_at"`
+	ListingID string    `json:"listing_id"`
+	// ActorID and ActorName identify who made the save that superseded this revision.
+	ActorID   string  `json:"actor_id"`
+	ActorName string  `json:"actor_name"`
+	Snapshot  Listing `json:"snapshot"`
+	Number    int     `json:"number"`
+}
+
+// RestoreOnto returns the revision's content applied to current. Ownership and
+// moderation state are kept from current so that restoring an old revision cannot
+// undo a claim, a rejection or a featured slot.
+func (r ListingRevision) RestoreOnto(current Listing) Listing {
+	restored := r.Snapshot
+	restored.ID = current.ID
+	restored.OwnerID = current.OwnerID
+	restored.Status = current.Status
+	restored.IsActive = current.IsActive
+	restored.Featured = current.Featured
+	restored.PermanentlyClosed = current.PermanentlyClosed
+	restored.CreatedAt = current.CreatedAt
+	return restored
+}
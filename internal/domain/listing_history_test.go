@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffListings(t *testing.T) {
+	t.Parallel()
+	before := Listing{ID: "l1", Title: "Suya Spot", Type: Food, Rating: 4.5, Featured: true, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	t.Run("changed fields only", func(t *testing.T) {
+		after := before
+		after.Title, after.Featured, after.IsCurrentlyOpen = "Suya Palace", false, true
+		assert.Equal(t, []FieldChange{
+			{Field: "featured", Before: "true", After: "false"},
+			{Field: "title", Before: "Suya Spot", After: "Suya Palace"},
+		}, DiffListings(before, after))
+	})
+
+	t.Run("identical listings", func(t *testing.T) {
+		assert.Empty(t, DiffListings(before, before))
+	})
+
+	t.Run("creation lists populated fields", func(t *testing.T) {
+		changes := DiffListings(Listing{}, before)
+		fields := make(map[string]FieldChange)
+		for _, c := range changes {
+			fields[c.Field] = c
+		}
+		assert.Equal(t, FieldChange{Field: "rating", Before: "0", After: "4.5"}, fields["rating"])
+		assert.Equal(t, "2026-01-02T03:04:05Z", fields["created_at"].After)
+		assert.Empty(t, fields["created_at"].Before, "zero time renders as empty")
+		assert.NotContains(t, fields, "deadline")
+	})
+}
+
+func TestListingRevision_RestoreOnto(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	rev := ListingRevision{Number: 1, Snapshot: Listing{ID: "l1", Title: "Old Title", City: "Lagos", OwnerID: "", Status: ListingStatusPending}}
+	current := Listing{ID: "l1", Title: "Vandalized", City: "Nowhere", OwnerID: "owner-1", Status: ListingStatusRejected, Featured: true, CreatedAt: created}
+
+	got := rev.RestoreOnto(current)
+
+	assert.Equal(t, "Old Title", got.Title)
+	assert.Equal(t, "Lagos", got.City)
+	assert.Equal(t, "owner-1", got.OwnerID)
+	assert.Equal(t, ListingStatusRejected, got.Status)
+	assert.True(t, got.Featured)
+	assert.Equal(t, created, got.CreatedAt)
+}
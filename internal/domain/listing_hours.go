@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HoursExceptionKind says how an HoursException changes a listing's regular hours.
+type HoursExceptionKind string
+
+const (
+	// HoursExceptionClosed closes the listing for every day in the range.
+	HoursExceptionClosed HoursExceptionKind = "closed"
+	// HoursExceptionCustom replaces the regular hours with Hours for every day in the range.
+	HoursExceptionCustom HoursExceptionKind = "custom"
+	// HoursExceptionPermanentlyClosed closes the listing for good from Start onwards.
+	HoursExceptionPermanentlyClosed HoursExceptionKind = "permanently_closed"
+)
+
+// MaxHoursExceptions bounds how many exceptions a listing may record.
+const MaxHoursExceptions = 30
+
+// HoursNoticeWindow is how far ahead an upcoming exception is announced on a listing.
+const HoursNoticeWindow = 14 * 24 * time.Hour
+
+// HoursException overrides a listing's regular hours for a range of dates, such as a
+// holiday closure, Ramadan hours or a renovation. Dates are "YYYY-MM-DD" on the
+// listing's own calendar and End is inclusive. A permanent closure has no End.
+type HoursException struct {
+	Start string             `json:"start"`
+	End   string             `json:"end,omitempty"`
+	Kind  HoursExceptionKind `json:"kind"`
+	// Hours are "HH:MM-HH:MM" ranges for a custom exception.
+	Hours []string `json:"hours,omitempty"`
+	Note  string   `json:"note,omitempty"`
+}
+
+var (
+	ErrInvalidHoursException = errors.New("special hours need a valid start date and kind")
+	ErrHoursExceptionRange   = errors.New("special hours cannot end before they start")
+	ErrHoursExceptionHours   = errors.New(`special hours must be given as ranges like "10:00-14:00"`)
+	ErrTooManyHoursException = fmt.Errorf("a listing can have at most %d special hours entries", MaxHoursExceptions)
+)
+
+var hoursRangeRe = regexp.MustCompile(`^([01]\d|2[0-4]):[0-5]\d-([01]\d|2[0-4]):[0-5]\d$`)
+
+// Covers reports whether the exception applies on date, a "YYYY-MM-DD" string.
+func (e HoursException) Covers(date string) bool {
+	if date < e.Start {
+		return false
+	}
+	switch {
+	case e.Kind == HoursExceptionPermanentlyClosed:
+		return true
+	case e.End == "":
+		return date == e.Start
+	}
+	return date <= e.End
+}
+
+// Ranges returns the hours the listing keeps on a day the exception covers; an empty
+// list means closed all day.
+func (e HoursException) Ranges() []string {
+	if e.Kind != HoursExceptionCustom {
+		return []string{}
+	}
+	return e.Hours
+}
+
+// HoursText returns Hours as the comma separated text the listing form edits.
+func (e HoursException) HoursText() string {
+	return strings.Join(e.Hours, ", ")
+}
+
+// Period describes the dates the exception covers, such as "Dec 24", "Dec 24 – Jan 2"
+// or, for a permanent closure, "from Dec 24, 2026".
+func (e HoursException) Period() string {
+	start, err := time.Parse(time.DateOnly, e.Start)
+	if err != nil {
+		return e.Start
+	}
+	if e.Kind == HoursExceptionPermanentlyClosed {
+		return "from " + start.Format("Jan 2, 2006")
+	}
+	end, err := time.Parse(time.DateOnly, e.End)
+	if err != nil || !end.After(start) {
+		return start.Format("Jan 2")
+	}
+	return start.Format("Jan 2") + " – " + end.Format("Jan 2")
+}
+
+// Validate checks the exception's dates, kind and hours.
+func (e HoursException) Validate() error {
+	if _, err := time.Parse(time.DateOnly, e.Start); err != nil {
+		return ErrInvalidHoursException
+	}
+	switch e.Kind {
+	case HoursExceptionClosed, HoursExceptionPermanentlyClosed:
+	case HoursExceptionCustom:
+		if len(e.Hours) == 0 {
+			return ErrHoursExceptionHours
+		}
+		for _, r := range e.Hours {
+			if !hoursRangeRe.MatchString(r) {
+				return ErrHoursExceptionHours
+			}
+		}
+	default:
+		return ErrInvalidHoursException
+	}
+	if e.End == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.DateOnly, e.End); err != nil {
+		return ErrInvalidHoursException
+	}
+	if e.End < e.Start {
+		return ErrHoursExceptionRange
+	}
+	return nil
+}
+
+// NewHoursException builds an exception from form input. Hours are comma separated
+// ranges and are ignored unless kind is custom.
+func NewHoursException(start, end, kind, hours, note string) HoursException {
+	e := HoursException{
+		Start: strings.TrimSpace(start),
+		End:   strings.TrimSpace(end),
+		Kind:  HoursExceptionKind(strings.TrimSpace(kind)),
+		Note:  strings.TrimSpace(note),
+	}
+	if e.Kind == HoursExceptionCustom {
+		for _, r := range strings.Split(hours, ",") {
+			if r = strings.ReplaceAll(strings.TrimSpace(r), " ", ""); r != "" {
+				e.Hours = append(e.Hours, r)
+			}
+		}
+	}
+	if e.Kind == HoursExceptionPermanentlyClosed || e.End == e.Start {
+		e.End = ""
+	}
+	return e
+}
+
+// ParseHoursExceptions decodes stored exceptions, returning none for malformed JSON.
+func ParseHoursExceptions(raw string) []HoursException {
+	if raw == "" {
+		return nil
+	}
+	var exceptions []HoursException
+	if err := json.Unmarshal([]byte(raw), &exceptions); err != nil {
+		return nil
+	}
+	return exceptions
+}
+
+// EncodeHoursExceptions validates exceptions and encodes them sorted by start date for
+// storage. No exceptions encode as "".
+func EncodeHoursExceptions(exceptions []HoursException) (string, error) {
+	if len(exceptions) == 0 {
+		return "", nil
+	}
+	if len(exceptions) > MaxHoursExceptions {
+		return "", ErrTooManyHoursException
+	}
+	for _, e := range exceptions {
+		if err := e.Validate(); err != nil {
+			return "", err
+		}
+	}
+	sorted := append([]HoursException(nil), exceptions...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	b, err := json.Marshal(sorted)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// HoursExceptionList returns the listing's special hours, oldest first.
+func (l Listing) HoursExceptionList() []HoursException {
+	return ParseHoursExceptions(l.HoursExceptions)
+}
+
+// HoursExceptionCovering returns the exception covering date, a "YYYY-MM-DD" string.
+// When several do, the one starting latest wins, so a custom day inside a longer
+// closure still applies.
+func HoursExceptionCovering(exceptions []HoursException, date string) (HoursException, bool) {
+	var found HoursException
+	ok := false
+	for _, e := range exceptions {
+		if e.Covers(date) && (!ok || e.Start >= found.Start) {
+			found, ok = e, true
+		}
+	}
+	return found, ok
+}
+
+// IsClosedForGood reports whether the listing has closed permanently, either as marked
+// by an admin or as announced by its owner, as of now.
+func (l Listing) IsClosedForGood(now time.Time) bool {
+	if l.PermanentlyClosed {
+		return true
+	}
+	today := l.LocalTime(now).Format(time.DateOnly)
+	for _, e := range l.HoursExceptionList() {
+		if e.Kind == HoursExceptionPermanentlyClosed && e.Covers(today) {
+			return true
+		}
+	}
+	return false
+}
+
+// HoursNotice is what the listing's detail view announces about its special hours.
+type HoursNotice struct {
+	Exception HoursException
+	// Current is true when the exception applies today rather than in the future.
+	Current bool
+}
+
+// HoursNoticeAt returns the exception in effect on the listing's calendar at now, or
+// else the next one to start within HoursNoticeWindow.
+func (l Listing) HoursNoticeAt(now time.Time) (HoursNotice, bool) {
+	exceptions := l.HoursExceptionList()
+	local := l.LocalTime(now)
+	if e, ok := HoursExceptionCovering(exceptions, local.Format(time.DateOnly)); ok {
+		return HoursNotice{Exception: e, Current: true}, true
+	}
+	today, horizon := local.Format(time.DateOnly), local.Add(HoursNoticeWindow).Format(time.DateOnly)
+	for _, e := range exceptions { // sorted by start when saved
+		if e.Start > today && e.Start <= horizon {
+			return HoursNotice{Exception: e}, true
+		}
+	}
+	return HoursNotice{}, false
+}
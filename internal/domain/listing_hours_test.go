@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoursException_Covers(t *testing.T) {
+	t.Parallel()
+	day := HoursException{Start: "2026-12-25", Kind: HoursExceptionClosed}
+	span := HoursException{Start: "2026-12-24", End: "2026-12-26", Kind: HoursExceptionClosed}
+	gone := HoursException{Start: "2026-12-24", Kind: HoursExceptionPermanentlyClosed}
+
+	assert.True(t, day.Covers("2026-12-25"))
+	assert.False(t, day.Covers("2026-12-26"))
+	assert.False(t, span.Covers("2026-12-23"))
+	assert.True(t, span.Covers("2026-12-26"))
+	assert.False(t, span.Covers("2026-12-27"))
+	assert.True(t, gone.Covers("2030-01-01"))
+	assert.False(t, gone.Covers("2026-12-23"))
+}
+
+func TestEncodeHoursExceptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		in   HoursException
+		err  error
+	}{
+		{"closed day", NewHoursException("2026-12-25", "", "closed", "", ""), nil},
+		{"custom", NewHoursException("2026-03-01", "2026-03-30", "custom", "18:00-23:00", "Ramadan"), nil},
+		{"custom without hours", NewHoursException("2026-03-01", "", "custom", "", ""), ErrHoursExceptionHours},
+		{"custom with text hours", NewHoursException("2026-03-01", "", "custom", "6pm-11pm", ""), ErrHoursExceptionHours},
+		{"bad start", NewHoursException("25/12/2026", "", "closed", "", ""), ErrInvalidHoursException},
+		{"unknown kind", NewHoursException("2026-12-25", "", "vacation", "", ""), ErrInvalidHoursException},
+		{"end before start", NewHoursException("2026-12-25", "2026-12-20", "closed", "", ""), ErrHoursExceptionRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := EncodeHoursExceptions([]HoursException{tt.in})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	encoded, err := EncodeHoursExceptions(nil)
+	require.NoError(t, err)
+	assert.Empty(t, encoded)
+
+	tooMany := make([]HoursException, MaxHoursExceptions+1)
+	_, err = EncodeHoursExceptions(tooMany)
+	assert.ErrorIs(t, err, ErrTooManyHoursException)
+}
+
+func TestNewHoursException_PermanentHasNoEnd(t *testing.T) {
+	t.Parallel()
+	e := NewHoursException("2026-12-24", "2026-12-31", "permanently_closed", "09:00-17:00", "")
+	assert.Empty(t, e.End)
+	assert.Empty(t, e.Hours)
+	assert.Equal(t, "from Dec 24, 2026", e.Period())
+}
+
+func TestListing_HoursNoticeAt(t *testing.T) {
+	t.Parallel()
+	encoded, err := EncodeHoursExceptions([]HoursException{
+		{Start: "2026-12-24", End: "2026-12-26", Kind: HoursExceptionClosed, Note: "Christmas"},
+		{Start: "2026-12-31", Kind: HoursExceptionCustom, Hours: []string{"10:00-14:00"}},
+	})
+	require.NoError(t, err)
+	l := Listing{HoursExceptions: encoded, Timezone: "Africa/Lagos"}
+	lagos, _ := time.LoadLocation("Africa/Lagos")
+
+	notice, ok := l.HoursNoticeAt(time.Date(2026, 12, 25, 12, 0, 0, 0, lagos))
+	require.True(t, ok)
+	assert.True(t, notice.Current)
+	assert.Equal(t, "Dec 24 – Dec 26", notice.Exception.Period())
+
+	notice, ok = l.HoursNoticeAt(time.Date(2026, 12, 20, 12, 0, 0, 0, lagos))
+	require.True(t, ok)
+	assert.False(t, notice.Current)
+	assert.Equal(t, "Christmas", notice.Exception.Note)
+
+	_, ok = l.HoursNoticeAt(time.Date(2026, 11, 1, 12, 0, 0, 0, lagos))
+	assert.False(t, ok, "exceptions beyond the notice window are not announced")
+
+	// 23:30 UTC on Dec 23 is already Dec 24 in Lagos.
+	notice, ok = l.HoursNoticeAt(time.Date(2026, 12, 23, 23, 30, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.True(t, notice.Current)
+}
+
+func TestListing_IsClosedForGood(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, Listing{}.IsClosedForGood(now))
+	assert.True(t, Listing{PermanentlyClosed: true}.IsClosedForGood(now))
+	assert.True(t, Listing{HoursExceptions: `[{"start":"2026-12-01","kind":"permanently_closed"}]`}.IsClosedForGood(now))
+	assert.False(t, Listing{HoursExceptions: `[{"start":"2027-01-01","kind":"permanently_closed"}]`}.IsClosedForGood(now))
+}
+
+func TestListing_ValidateHoursExceptions(t *testing.T) {
+	t.Parallel()
+	l := Listing{Type: Food, Title: "Suya", OwnerOrigin: "Nigeria", City: "Houston", Address: "1 Main St", ContactEmail: "a@b.co"}
+	require.NoError(t, l.Validate())
+
+	l.HoursExceptions = `[{"start":"2026-12-25","kind":"closed"}]`
+	assert.NoError(t, l.Validate())
+
+	l.HoursExceptions = `[{"start":"2026-12-25","kind":"custom"}]`
+	assert.ErrorIs(t, l.Validate(), ErrHoursExceptionHours)
+
+	l.HoursExceptions = `not json`
+	assert.ErrorIs(t, l.Validate(), ErrInvalidHoursException)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidBoundingBox is returned for a bbox that is not four coordinates in range.
+var ErrInvalidBoundingBox = errors.New("bbox must be west,south,east,north in degrees")
+
+// BoundingBox is a rectangle of the map in degrees. West is greater than East when
+// the box crosses the antimeridian.
+type BoundingBox struct {
+	West  float64
+	South float64
+	East  float64
+	North float64
+}
+
+// IsZero reports whether the box is unset.
+func (b BoundingBox) IsZero() bool {
+	return b == BoundingBox{}
+}
+
+// BoundingBoxAround is the smallest box containing every point within miles of
+// lat, lng. Its longitude span widens towards the poles, covers every longitude when
+// the circle reaches a pole, and wraps round the antimeridian when it crosses it.
+func BoundingBoxAround(lat, lng, miles float64) BoundingBox {
+	radius := miles / EarthRadiusMiles // angular radius in radians
+	dLat := radius * 180 / math.Pi
+	b := BoundingBox{South: math.Max(lat-dLat, -90), North: math.Min(lat+dLat, 90), West: -180, East: 180}
+	if lat-dLat <= -90 || lat+dLat >= 90 || radius >= math.Pi/2 {
+		return b
+	}
+	dLng := math.Asin(math.Min(1, math.Sin(radius)/math.Cos(lat*math.Pi/180))) * 180 / math.Pi
+	if dLng >= 180 {
+		return b
+	}
+	b.West, b.East = lng-dLng, lng+dLng
+	if b.West < -180 {
+		b.West += 360
+	}
+	if b.East > 180 {
+		b.East -= 360
+	}
+	return b
+}
+
+// ParseBoundingBox reads "west,south,east,north", the order GeoJSON uses for bbox.
+func ParseBoundingBox(s string) (BoundingBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, ErrInvalidBoundingBox
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return BoundingBox{}, ErrInvalidBoundingBox
+		}
+		v[i] = f
+	}
+	b := BoundingBox{West: v[0], South: v[1], East: v[2], North: v[3]}
+	if b.South > b.North || b.South < -90 || b.North > 90 || b.West < -180 || b.West > 180 || b.East < -180 || b.East > 180 {
+		return BoundingBox{}, ErrInvalidBoundingBox
+	}
+	return b, nil
+}
+
+// ListingCluster is a group of listings close together on the map, shown as one
+// marker at their average position. A cluster of one carries that listing's ID,
+// Title and Type.
+type ListingCluster struct {
+	ID        string
+	Title     string
+	Type      Category
+	Latitude  float64
+	Longitude float64
+	Count     int
+}
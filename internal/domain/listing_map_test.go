@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoundingBox(t *testing.T) {
+	t.Parallel()
+
+	b, err := ParseBoundingBox("-95.5, 29.7,-95.3,29.8")
+	require.NoError(t, err)
+	assert.Equal(t, BoundingBox{West: -95.5, South: 29.7, East: -95.3, North: 29.8}, b)
+	assert.False(t, b.IsZero())
+
+	b, err = ParseBoundingBox("170,-10,-170,10")
+	require.NoError(t, err, "a box may cross the antimeridian")
+	assert.Greater(t, b.West, b.East)
+
+	for _, s := range []string{"", "1,2,3", "a,b,c,d", "0,10,1,5", "0,-91,1,0", "-181,0,0,1"} {
+		_, err = ParseBoundingBox(s)
+		assert.ErrorIs(t, err, ErrInvalidBoundingBox, s)
+	}
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	t.Parallel()
+
+	equator := BoundingBoxAround(0, 10, 69)
+	assert.InDelta(t, 1, equator.North, 0.01)
+	assert.InDelta(t, -1, equator.South, 0.01)
+	assert.InDelta(t, 11, equator.East, 0.01)
+
+	// At 60 degrees north a degree of longitude is half as wide, so the box is twice as wide.
+	oslo := BoundingBoxAround(60, 10, 69)
+	assert.InDelta(t, 1, oslo.North-60, 0.01)
+	assert.InDelta(t, 2, oslo.East-10, 0.02)
+	edge := oslo.East - 0.001
+	assert.Greater(t, DistanceMiles(60, 10, 60, edge), 68.0, "the box reaches the edge of the circle")
+
+	polar := BoundingBoxAround(89.5, 10, 69)
+	assert.Equal(t, 90.0, polar.North)
+	assert.Equal(t, -180.0, polar.West, "a circle over the pole covers every longitude")
+	assert.Equal(t, 180.0, polar.East)
+
+	fiji := BoundingBoxAround(-17, 179.5, 69)
+	assert.Greater(t, fiji.West, fiji.East, "the box wraps round the antimeridian")
+	assert.InDelta(t, -179.45, fiji.East, 0.05)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PendingChangeStatus is the review state of a PendingChange.
+type PendingChangeStatus string
+
+const (
+	PendingChangeStatusPending  PendingChangeStatus = "Pending"
+	PendingChangeStatusApproved PendingChangeStatus = "Approved"
+	PendingChangeStatusRejected PendingChangeStatus = "Rejected"
+	// PendingChangeStatusSuperseded marks a change replaced by a later submission for the same listing.
+	PendingChangeStatusSuperseded PendingChangeStatus = "Superseded"
+)
+
+// PendingChange is a listing submission held for review because its category
+// requires moderation. For a new listing the listing itself is stored with
+// ListingStatusPending; for an edit the live listing is left untouched and the
+// submission is kept here until a moderator applies or discards it.
+type PendingChange struct {
+	CreatedAt  time.Time `json:"created_at"`
+	ReviewedAt time.Time `json:"reviewed_at"`
+	ID         string    `json:"id"`
+	ListingID  string    `json:"listing_id"`
+	// ListingTitle is the public title at submission time, so moderators can
+	// recognise the listing even when the change renames it.
+	ListingTitle string              `json:"listing_title"`
+	ActorID      string              `json:"actor_id"`
+	ActorName    string              `json:"actor_name"`
+	ReviewerID   string              `json:"reviewer_id"`
+	Status       PendingChangeStatus `json:"status"`
+	Proposed     Listing             `json:"proposed"`
+	Changes      []FieldChange       `json:"changes"`
+	IsNew        bool                `json:"is_new"`
+}
+
+// materialListingFields are the fields whose edits need review in a moderated
+// category: the listing's name and every way of reaching or finding it.
+var materialListingFields = map[string]bool{
+	"title":            true,
+	"contact_email":    true,
+	"contact_phone":    true,
+	"contact_whatsapp": true,
+	"website_url":      true,
+	"address":          true,
+}
+
+// moderationListingFields are owned by moderators and never taken from a submission.
+var moderationListingFields = map[string]bool{
+	"id":         true,
+	"owner_id":   true,
+	"status":     true,
+	"is_active":  true,
+	"featured":   true,
+	"created_at": true,
+}
+
+// MaterialChanges returns the subset of changes that touch a material field.
+func MaterialChanges(changes []FieldChange) []FieldChange {
+	var material []FieldChange
+	for _, c := range changes {
+		if materialListingFields[c.Field] {
+			material = append(material, c)
+		}
+	}
+	return material
+}
+
+// ApplyTo returns current with the fields this change touched taken from the
+// proposed listing. Fields edited live since the submission are kept, as are
+// ownership and moderation state.
+func (c PendingChange) ApplyTo(current Listing) Listing {
+	var live, proposed map[string]json.RawMessage
+	raw, _ := json.Marshal(current)
+	_ = json.Unmarshal(raw, &live)
+	raw, _ = json.Marshal(c.Proposed)
+	_ = json.Unmarshal(raw, &proposed)
+
+	for _, ch := range c.Changes {
+		if moderationListingFields[ch.Field] {
+			continue
+		}
+		if v, ok := proposed[ch.Field]; ok {
+			live[ch.Field] = v
+		}
+	}
+
+	var applied Listing
+	raw, _ = json.Marshal(live)
+	_ = json.Unmarshal(raw, &applied)
+	return applied
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaterialChanges(t *testing.T) {
+	t.Parallel()
+	changes := []FieldChange{
+		{Field: "address", After: "12 Broad St"},
+		{Field: "description", After: "Fresh daily"},
+		{Field: "title", After: "Renamed"},
+	}
+	assert.Equal(t, []FieldChange{changes[0], changes[2]}, MaterialChanges(changes))
+	assert.Empty(t, MaterialChanges(changes[1:2]))
+}
+
+func TestPendingChange_ApplyTo(t *testing.T) {
+	t.Parallel()
+	current := Listing{ID: "l1", Title: "Live", Description: "Edited meanwhile", OwnerID: "u2", Status: ListingStatusApproved, Featured: true}
+	pc := PendingChange{
+		Proposed: Listing{ID: "l1", Title: "Renamed", Description: "Stale", OwnerID: "u1", Status: ListingStatusPending},
+		Changes: []FieldChange{
+			{Field: "title", Before: "Live", After: "Renamed"},
+			{Field: "status", Before: "Approved", After: "Pending"},
+		},
+	}
+
+	applied := pc.ApplyTo(current)
+	assert.Equal(t, "Renamed", applied.Title)
+	assert.Equal(t, "Edited meanwhile", applied.Description, "untouched fields keep their live value")
+	assert.Equal(t, ListingStatusApproved, applied.Status, "moderation state is never taken from a submission")
+	assert.Equal(t, "u2", applied.OwnerID)
+	assert.True(t, applied.Featured)
+}
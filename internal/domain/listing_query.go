@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ListingQuery describes a listing search. The zero value matches every active,
+// approved listing in the default feed order. Slice fields match any of their
+// values; zero-valued scalar fields do not filter.
+type ListingQuery struct {
+	// CreatedAfter keeps listings created strictly after this instant.
+	CreatedAfter time.Time
+	// QueryText is matched against the full-text index: every word of it, or a
+	// known spelling of the word, must appear. Punctuation is ignored.
+	QueryText   string
+	OwnerID     string
+	OwnerOrigin string
+	// ViewerID is the signed-in user searching, if any. Listings of shadowed owners
+	// are left out of the default results for everyone but their owner.
+	ViewerID string
+	// Sort is one of title, created_at, status, featured, type, distance or relevance;
+	// Order is asc or desc. Sorting by distance needs Latitude and Longitude and is
+	// nearest first; sorting by relevance needs QueryText and is best match first.
+	Sort  string
+	Order string
+	// Cursor is an opaque token from a previous ListingPage.NextCursor, valid only
+	// with the same Sort and Order. When set it takes precedence over Offset.
+	Cursor   string
+	Types    []Category
+	Cities   []string
+	Statuses []ListingStatus
+	// Latitude, Longitude and RadiusMiles restrict results to a circle. When set
+	// they replace the Cities filter. Latitude and Longitude alone set the point
+	// results report their DistanceMiles from.
+	Latitude    float64
+	Longitude   float64
+	RadiusMiles float64
+	// Bounds restricts results to a map rectangle, alongside any other location filter.
+	Bounds    BoundingBox
+	MinRating float64
+	// MinHeatLevel keeps food listings at least this spicy (1-5).
+	MinHeatLevel int
+	Limit        int
+	Offset       int
+	// IncludeInactive disables the default active-and-approved restriction, and with
+	// it the hiding of shadowed owners' listings.
+	IncludeInactive bool
+	// SkipCount leaves ListingPage.TotalCount at -1 instead of counting every match,
+	// which is the expensive part of fetching a deep page.
+	SkipCount    bool
+	FeaturedOnly bool
+	HasImage     bool
+	HasWebsite   bool
+	// OpenNow keeps listings whose structured hours cover the time of the search on
+	// the listing's own clock. Listings with only free-text hours cannot be evaluated
+	// and are excluded.
+	OpenNow bool
+	// OpenAt keeps listings open at this instant, like OpenNow. It takes precedence
+	// over OpenNow when set.
+	OpenAt time.Time
+	// OpenAtWallClock reads OpenAt's date and time of day on each listing's own clock
+	// rather than as an instant, so Saturday 19:00 means 7pm wherever the listing is.
+	OpenAtWallClock bool
+}
+
+// ListingPage is one page of ListingQuery results.
+type ListingPage struct {
+	// NextCursor fetches the following page; it is empty on the last page.
+	NextCursor string
+	Listings   []Listing
+	// TotalCount is the number of matches across all pages, or -1 if the query set SkipCount.
+	TotalCount int
+}
+
+// ErrInvalidOpenAt is returned for an open_at parameter that is not a date and time.
+var ErrInvalidOpenAt = errors.New("open_at must be a date and time such as 2026-05-02T19:00")
+
+// ParseOpenAt reads an "open_at" request parameter. A time with an offset, such as
+// RFC 3339, is an instant; one without, such as an HTML datetime-local value, is a
+// wall-clock time to be read on each listing's clock.
+func ParseOpenAt(s string) (at time.Time, wallClock bool, err error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02T15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, ErrInvalidOpenAt
+}
+
+// TypeFilter converts a single "type" request parameter into a Types filter.
+// An empty value or "All" means no type restriction.
+func TypeFilter(t string) []Category {
+	if t == "" || t == "All" {
+		return nil
+	}
+	return []Category{Category(t)}
+}
+
+// CityFilter converts a single "city" request parameter into a Cities filter.
+func CityFilter(city string) []string {
+	if city == "" {
+		return nil
+	}
+	return []string{city}
+}
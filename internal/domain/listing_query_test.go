@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpenAt(t *testing.T) {
+	t.Parallel()
+
+	at, wallClock, err := ParseOpenAt("2026-05-02T19:00:00-05:00")
+	require.NoError(t, err)
+	assert.False(t, wallClock)
+	assert.True(t, at.Equal(time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)))
+
+	for _, v := range []string{"2026-05-02T19:00", "2026-05-02T19:00:00", "2026-05-02 19:00"} {
+		at, wallClock, err = ParseOpenAt(v)
+		require.NoError(t, err, v)
+		assert.True(t, wallClock, v)
+		assert.Equal(t, "2026-05-02 19:00", at.Format("2006-01-02 15:04"), v)
+	}
+
+	_, _, err = ParseOpenAt("tonight")
+	assert.ErrorIs(t, err, ErrInvalidOpenAt)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinSearchTermLength is the shortest term the trigram full-text index can match.
+	MinSearchTermLength = 3
+	// MaxSearchTerms caps the terms of one search, so a pasted paragraph cannot
+	// become an expensive full-text query.
+	MaxSearchTerms = 8
+)
+
+// searchSpellings groups the spellings diaspora food goes by, so a search for one
+// finds listings written with another. Entries are lower case; those with a space or
+// hyphen are only reached from a one-word spelling in their group.
+var searchSpellings = [][]string{
+	{"jollof", "jolof", "jolloff", "jellof"},
+	{"egusi", "agushi", "egushi", "egwusi"},
+	{"fufu", "foufou", "foofoo", "fofou"},
+	{"akara", "accara", "kosai", "acaraje"},
+	{"suya", "tsire", "chichinga"},
+	{"garri", "gari"},
+	{"waakye", "waache"},
+	{"shito", "shitor"},
+	{"ogbono", "apon"},
+	{"injera", "enjera", "lahoh"},
+	{"ugali", "sadza", "nshima", "posho"},
+	{"plantain", "dodo", "kelewele", "alloco"},
+	{"moimoi", "moinmoin", "moi moi", "moin moin"},
+	{"chinchin", "chin-chin"},
+	{"pepper soup", "peppersoup"},
+}
+
+// searchVariants maps each spelling to its group.
+var searchVariants = func() map[string][]string {
+	m := make(map[string][]string)
+	for _, group := range searchSpellings {
+		for _, s := range group {
+			m[s] = group
+		}
+	}
+	return m
+}()
+
+// SearchTerms splits free text into the lower-cased words it searches for, dropping
+// punctuation, repeats and words shorter than MinSearchTermLength. At most
+// MaxSearchTerms are returned.
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	for _, w := range words {
+		if len([]rune(w)) < MinSearchTermLength || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// SearchTermVariants returns term followed by its other known spellings.
+func SearchTermVariants(term string) []string {
+	variants := []string{term}
+	for _, v := range searchVariants[term] {
+		if v != term {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"mama", "jollof"}, SearchTerms(`Mama's "JOLLOF" -jollof`))
+	assert.Equal(t, []string{"moi", "ọbẹ"}, SearchTerms("moi-moi ọbẹ a"), "hyphenated and accented words are split on punctuation only")
+	assert.Empty(t, SearchTerms(`- " ( * ^`))
+	assert.Len(t, SearchTerms("one two three four five six seven eight nine ten"), MaxSearchTerms)
+}
+
+func TestSearchTermVariants(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"jolof", "jollof", "jolloff", "jellof"}, SearchTermVariants("jolof"))
+	assert.Equal(t, []string{"agushi", "egusi", "egushi", "egwusi"}, SearchTermVariants("agushi"))
+	assert.Equal(t, []string{"rice"}, SearchTermVariants("rice"))
+}
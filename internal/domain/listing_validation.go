@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -51,6 +52,9 @@ func (l *Listing) Validate() error {
 	if err := l.applyRules(); err != nil {
 		return err
 	}
+	if err := l.validateHoursExceptions(); err != nil {
+		return err
+	}
 	return l.validateTypeSpecific()
 }
 
@@ -87,6 +91,21 @@ func (l *Listing) validateTypeRequirements() error {
 	return nil
 }
 
+func (l *Listing) validateHoursExceptions() error {
+	if l.HoursExceptions == "" {
+		return nil
+	}
+	if !(l.Type == Business || l.Type == Service || l.Type == Food) {
+		return errors.New("special hours not applicable for this listing type")
+	}
+	var exceptions []HoursException
+	if err := json.Unmarshal([]byte(l.HoursExceptions), &exceptions); err != nil {
+		return ErrInvalidHoursException
+	}
+	_, err := EncodeHoursExceptions(exceptions)
+	return err
+}
+
 func (l *Listing) validateContact() error {
 	if l.ContactEmail == "" && l.ContactWhatsApp == "" && l.ContactPhone == "" && l.WebsiteURL == "" {
 		return ErrMissingContact
@@ -116,7 +135,7 @@ func (l *Listing) validateRequest() error {
 		start = time.Now()
 	}
 
-	if l.Deadline.After(start.Add(90 * 24 * time.Hour)) {
+	if l.Deadline.After(start.Add(MaxRequestDeadline)) {
 		return ErrInvalidDeadline
 	}
 	return nil
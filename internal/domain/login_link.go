@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+const (
+	// IdentityProviderEmail is the Provider of identities from emailed sign-in links.
+	// Their Subject is the normalized email address.
+	IdentityProviderEmail = "email"
+	// LoginLinkTTL is how long an emailed sign-in link stays valid.
+	LoginLinkTTL = 15 * time.Minute
+)
+
+// LoginLink is an emailed single-use sign-in link. Only the SHA-256 hash of its
+// token is stored, so the database alone cannot be used to sign in.
+type LoginLink struct {
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	TokenHash string
+	Email     string
+}
+
+// NormalizeEmail checks that s is a bare email address and returns it trimmed and
+// lower-cased, so one inbox always maps to one account.
+func NormalizeEmail(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || addr.Name != "" {
+		return "", ErrInvalidEmail
+	}
+	return s, nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	t.Parallel()
+
+	email, err := NormalizeEmail("  Ada.Obi@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "ada.obi@example.com", email)
+
+	for _, bad := range []string{"", "ada", "ada@", "Ada <ada@example.com>", "a@example.com, b@example.com"} {
+		_, err = NormalizeEmail(bad)
+		assert.ErrorIs(t, err, ErrInvalidEmail, bad)
+	}
+}
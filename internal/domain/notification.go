@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationKind names the event a notification tells its recipient about. Each kind
+// has a matching email template.
+type NotificationKind string
+
+const (
+	NotificationClaimApproved    NotificationKind = "claim_approved"
+	NotificationClaimRejected    NotificationKind = "claim_rejected"
+	NotificationListingRejected  NotificationKind = "listing_rejected"
+	NotificationListingExpired   NotificationKind = "listing_expired"
+	NotificationListingExpiring  NotificationKind = "listing_expiring"
+	NotificationFeedbackReceived NotificationKind = "feedback_received"
+	NotificationLoginLink        NotificationKind = "login_link"
+)
+
+// SecretTTL is how long the secret a kind of notification carries, such as the token
+// in a sign-in link, stays usable. Kinds without a secret return zero. The outbox
+// erases the body of such a message once it is sent or its secret has expired.
+func (k NotificationKind) SecretTTL() time.Duration {
+	if k == NotificationLoginLink {
+		return LoginLinkTTL
+	}
+	return 0
+}
+
+// Email is a rendered message ready to hand to a transport.
+type Email struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Notifier delivers an email through one transport, such as SMTP or a local mail drop.
+type Notifier interface {
+	Send(ctx context.Context, e Email) error
+}
+
+// OutboxStatus is the delivery state of an OutboxMessage.
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "Pending"
+	OutboxStatusSent    OutboxStatus = "Sent"
+	// OutboxStatusFailed marks a message that ran out of delivery attempts.
+	OutboxStatusFailed OutboxStatus = "Failed"
+)
+
+// OutboxMessage is an email queued for delivery. Notifications are written to the
+// outbox in the request that triggers them and sent later, so a mail server outage
+// delays mail instead of failing the request.
+type OutboxMessage struct {
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	SentAt        time.Time
+	ID            string
+	Kind          NotificationKind
+	Status        OutboxStatus
+	LastError     string
+	Email         Email
+	Attempts      int
+}
+
+// NotificationService queues notifications for delivery.
+type NotificationService interface {
+	// Notify queues a notification to an email address.
+	Notify(ctx context.Context, kind NotificationKind, to string, data map[string]interface{}) error
+	// NotifyUser queues a notification to a user's email address.
+	NotifyUser(ctx context.Context, kind NotificationKind, userID string, data map[string]interface{}) error
+	// NotifyAdmins queues a notification to the configured admin address, if any.
+	NotifyAdmins(ctx context.Context, kind NotificationKind, data map[string]interface{}) error
+}
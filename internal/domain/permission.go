@@ -0,0 +1,123 @@
+package domain
+
+import "strings"
+
+// Permission is an admin action a role may be granted.
+type Permission string
+
+const (
+	// PermissionViewDashboard admits a user to /admin at all.
+	PermissionViewDashboard Permission = "dashboard:view"
+	// PermissionViewListings covers the admin listing table and listing history.
+	PermissionViewListings Permission = "listings:view"
+	// PermissionModerateListings covers claims, held changes, bulk status changes and flags.
+	PermissionModerateListings Permission = "listings:moderate"
+	// PermissionManageListings covers CSV upload, deletion and editing other users' listings.
+	PermissionManageListings Permission = "listings:manage"
+	// PermissionManageCategories covers adding categories.
+	PermissionManageCategories Permission = "categories:manage"
+	// PermissionViewAnalytics covers growth charts and the listing export.
+	PermissionViewAnalytics Permission = "analytics:view"
+	// PermissionViewUsers covers the user list.
+	PermissionViewUsers Permission = "users:view"
+	// PermissionAssignRoles covers changing other users' roles.
+	PermissionAssignRoles Permission = "users:assign_roles"
+	// PermissionManageUsers covers signing users out of every device.
+	PermissionManageUsers Permission = "users:manage"
+)
+
+// AllUserRoles lists every role, least privileged first.
+var AllUserRoles = []UserRole{
+	UserRoleUser,
+	UserRoleAnalyst,
+	UserRoleCategoryCurator,
+	UserRoleModerator,
+	UserRoleAdmin,
+	UserRoleSuperAdmin,
+}
+
+var allPermissions = []Permission{
+	PermissionViewDashboard,
+	PermissionViewListings,
+	PermissionModerateListings,
+	PermissionManageListings,
+	PermissionManageCategories,
+	PermissionViewAnalytics,
+	PermissionViewUsers,
+	PermissionAssignRoles,
+	PermissionManageUsers,
+}
+
+// rolePermissions is the permission matrix. Roles not listed, including
+// UserRoleUser, have no admin permissions.
+var rolePermissions = map[UserRole][]Permission{
+	UserRoleAnalyst: {
+		PermissionViewDashboard, PermissionViewListings, PermissionViewAnalytics,
+	},
+	UserRoleCategoryCurator: {
+		PermissionViewDashboard, PermissionManageCategories,
+	},
+	UserRoleModerator: {
+		PermissionViewDashboard, PermissionViewListings, PermissionModerateListings,
+	},
+	UserRoleAdmin:      allPermissions,
+	UserRoleSuperAdmin: allPermissions,
+}
+
+// Can reports whether the role is granted p.
+func (r UserRole) Can(p Permission) bool {
+	for _, have := range rolePermissions[r] {
+		if have == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether the role may use the admin dashboard.
+func (r UserRole) IsStaff() bool {
+	return r.Can(PermissionViewDashboard)
+}
+
+// CanAssign reports whether a user with role r may move another user from role
+// from to role to. Admins manage the staff roles below them; only super admins
+// may grant or take away the admin roles.
+func (r UserRole) CanAssign(from, to UserRole) bool {
+	if !r.Can(PermissionAssignRoles) {
+		return false
+	}
+	if r == UserRoleSuperAdmin {
+		return true
+	}
+	return !from.isAdmin() && !to.isAdmin()
+}
+
+// AssignableRoles lists the roles a user with role r may hand out.
+func (r UserRole) AssignableRoles() []UserRole {
+	var roles []UserRole
+	for _, to := range AllUserRoles {
+		if r.CanAssign(UserRoleUser, to) {
+			roles = append(roles, to)
+		}
+	}
+	return roles
+}
+
+func (r UserRole) isAdmin() bool {
+	return r == UserRoleAdmin || r == UserRoleSuperAdmin
+}
+
+// ParseUserRole matches name against the known roles, ignoring case.
+func ParseUserRole(name string) (UserRole, error) {
+	for _, r := range AllUserRoles {
+		if strings.EqualFold(strings.TrimSpace(name), string(r)) {
+			return r, nil
+		}
+	}
+	return "", ErrInvalidRole
+}
+
+// Can reports whether the user's role is granted p.
+func (u User) Can(p Permission) bool {
+	return u.Role.Can(p)
+}
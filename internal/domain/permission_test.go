@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRole_Can(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range allPermissions {
+		assert.False(t, UserRoleUser.Can(p), "users have no admin permissions: %s", p)
+		assert.False(t, UserRole("").Can(p), "an unset role has no admin permissions: %s", p)
+		assert.True(t, UserRoleAdmin.Can(p), p)
+		assert.True(t, UserRoleSuperAdmin.Can(p), p)
+	}
+
+	assert.True(t, UserRoleModerator.Can(PermissionModerateListings))
+	assert.False(t, UserRoleModerator.Can(PermissionManageListings))
+	assert.True(t, UserRoleCategoryCurator.Can(PermissionManageCategories))
+	assert.False(t, UserRoleCategoryCurator.Can(PermissionViewListings))
+	assert.True(t, UserRoleAnalyst.Can(PermissionViewAnalytics))
+	assert.False(t, UserRoleAnalyst.Can(PermissionModerateListings))
+
+	for _, r := range AllUserRoles {
+		assert.Equal(t, r != UserRoleUser, r.IsStaff(), r)
+	}
+}
+
+func TestUserRole_CanAssign(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, UserRoleAdmin.CanAssign(UserRoleUser, UserRoleModerator))
+	assert.True(t, UserRoleAdmin.CanAssign(UserRoleAnalyst, UserRoleUser))
+	assert.False(t, UserRoleAdmin.CanAssign(UserRoleUser, UserRoleAdmin), "admins cannot make admins")
+	assert.False(t, UserRoleAdmin.CanAssign(UserRoleSuperAdmin, UserRoleUser), "admins cannot demote super admins")
+	assert.True(t, UserRoleSuperAdmin.CanAssign(UserRoleAdmin, UserRoleUser))
+	assert.True(t, UserRoleSuperAdmin.CanAssign(UserRoleUser, UserRoleSuperAdmin))
+	assert.False(t, UserRoleModerator.CanAssign(UserRoleUser, UserRoleAnalyst))
+
+	assert.Equal(t, []UserRole{UserRoleUser, UserRoleAnalyst, UserRoleCategoryCurator, UserRoleModerator}, UserRoleAdmin.AssignableRoles())
+	assert.Equal(t, AllUserRoles, UserRoleSuperAdmin.AssignableRoles())
+	assert.Empty(t, UserRoleAnalyst.AssignableRoles())
+}
+
+func TestParseUserRole(t *testing.T) {
+	t.Parallel()
+
+	role, err := ParseUserRole(" categorycurator ")
+	require.NoError(t, err)
+	assert.Equal(t, UserRoleCategoryCurator, role)
+
+	_, err = ParseUserRole("root")
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
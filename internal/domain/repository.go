@@ -9,7 +9,7 @@ import (
 
 // ListingReader handles read-only queries for listings.
 type ListingReader interface {
-	FindAll(ctx context.Context, filterType string, queryText string, city string, lat float64, lng float64, radius float64, sortField string, sortOrder string, includeInactive bool, limit int, offset int) ([]Listing, int, error)
+	Search(ctx context.Context, q ListingQuery) (ListingPage, error)
 	FindByID(ctx context.Context, id string) (Listing, error)
 	FindByTitle(ctx context.Context, title string) ([]Listing, error)
 	TitleExists(ctx context.Context, title string) (bool, error)
@@ -19,6 +19,9 @@ type ListingReader interface {
 	GetFeaturedListings(ctx context.Context, category string, city string) ([]Listing, error)
 	FindEnrichmentTargets(ctx context.Context, limit int) ([]Listing, error)
 	FindRatingBackfillTargets(ctx context.Context, limit int) ([]Listing, error)
+	// ClusterListings groups the listings matching q that have coordinates into
+	// cells cellDegrees on a side. A cellDegrees of zero returns every listing alone.
+	ClusterListings(ctx context.Context, q ListingQuery, cellDegrees float64) ([]ListingCluster, error)
 }
 
 // ListingWriter handles write operations for listings.
@@ -42,7 +45,23 @@ type ListingSaver interface {
 
 // ListingExpirer handles expiration of stale listings.
 type ListingExpirer interface {
-	ExpireListings(ctx context.Context) (int64, error)
+	// ExpireListings deactivates listings whose deadline, event end or job start has
+	// passed and returns the listings it deactivated.
+	ExpireListings(ctx context.Context) ([]Listing, error)
+	// ListExpiringListings returns owned, active listings that expire at or after now
+	// and before before, skipping those whose owner was already reminded of that expiry.
+	ListExpiringListings(ctx context.Context, now, before time.Time) ([]Listing, error)
+	// MarkExpiryReminderSent records that the owner was reminded of the listing's
+	// current expiry. Extending the listing makes it due for a new reminder.
+	MarkExpiryReminderSent(ctx context.Context, id string, at time.Time) error
+}
+
+// ListingTimezoneStore backfills the time zone of listings saved before it was recorded.
+type ListingTimezoneStore interface {
+	// FindListingsWithoutTimezone returns up to limit listings with no time zone whose
+	// ID sorts after afterID, in ID order.
+	FindListingsWithoutTimezone(ctx context.Context, afterID string, limit int) ([]Listing, error)
+	SetListingTimezone(ctx context.Context, id, tz string) error
 }
 
 // UserStore handles user persistence and lookup.
@@ -50,6 +69,54 @@ type UserStore interface {
 	SaveUser(ctx context.Context, user User) error
 	FindUserByGoogleID(ctx context.Context, googleID string) (User, error)
 	FindUserByID(ctx context.Context, id string) (User, error)
+	// FindUserByIdentity returns the user a provider account is linked to, or
+	// ErrUserNotFound.
+	FindUserByIdentity(ctx context.Context, provider, subject string) (User, error)
+	// FindUserByIdentityEmail returns the user with the oldest identity for email,
+	// ignoring case, or ErrUserNotFound.
+	FindUserByIdentityEmail(ctx context.Context, email string) (User, error)
+	// SaveUserIdentity links a provider account to identity.UserID. It returns
+	// ErrIdentityLinked if the account is already linked to a different user.
+	SaveUserIdentity(ctx context.Context, identity UserIdentity) error
+	// SetUserStatus changes whether a user may sign in. until is only kept for a
+	// suspension. It returns ErrUserNotFound when there is no such user.
+	SetUserStatus(ctx context.Context, id string, status UserStatus, until time.Time, reason string) error
+}
+
+// UserEventStore persists the audit trail of staff actions on users.
+type UserEventStore interface {
+	AppendUserEvent(ctx context.Context, e UserEvent) error
+	// ListUserEvents returns the events recorded for a user, newest first.
+	ListUserEvents(ctx context.Context, userID string) ([]UserEvent, error)
+}
+
+// SessionStore persists browser sessions.
+type SessionStore interface {
+	// SaveSession inserts or replaces a session, keeping its creation time, and drops
+	// sessions that have expired.
+	SaveSession(ctx context.Context, s Session) error
+	// FindSession returns ErrSessionNotFound unless the session exists and expires after now.
+	FindSession(ctx context.Context, id string, now time.Time) (Session, error)
+	// TouchSession records the latest request seen on a session.
+	TouchSession(ctx context.Context, id, ip, userAgent string, at time.Time) error
+	DeleteSession(ctx context.Context, id string) error
+	// ListUserSessions returns a user's unexpired sessions, most recently seen first.
+	ListUserSessions(ctx context.Context, userID string, now time.Time) ([]Session, error)
+	// DeleteUserSession signs one of a user's sessions out. It returns
+	// ErrSessionNotFound when the user has no session with that ID.
+	DeleteUserSession(ctx context.Context, userID, id string) error
+	// DeleteUserSessions signs a user out everywhere and returns how many sessions ended.
+	DeleteUserSessions(ctx context.Context, userID string) (int, error)
+}
+
+// LoginLinkStore persists emailed sign-in links.
+type LoginLinkStore interface {
+	// SaveLoginLink stores a new link and drops links that have expired.
+	SaveLoginLink(ctx context.Context, link LoginLink) error
+	// UseLoginLink marks the link with tokenHash used and returns it. It returns
+	// ErrInvalidLoginLink unless the link exists, is unused and expires after now, so
+	// a link signs in at most once.
+	UseLoginLink(ctx context.Context, tokenHash string, now time.Time) (LoginLink, error)
 }
 
 // FeedbackStore handles feedback persistence.
@@ -57,6 +124,8 @@ type FeedbackStore interface {
 	SaveFeedback(ctx context.Context, feedback Feedback) error
 	GetAllFeedback(ctx context.Context) ([]Feedback, error)
 	GetFeedbackCounts(ctx context.Context) (map[FeedbackType]int, error)
+	// ListFeedbackByUser returns the feedback a user has sent, newest first.
+	ListFeedbackByUser(ctx context.Context, userID string) ([]Feedback, error)
 }
 
 // AdminStore handles admin-specific queries.
@@ -69,8 +138,13 @@ type AdminStore interface {
 type ClaimRequestStore interface {
 	SaveClaimRequest(ctx context.Context, r ClaimRequest) error
 	GetPendingClaimRequests(ctx context.Context) ([]ClaimRequest, error)
-	UpdateClaimRequestStatus(ctx context.Context, id string, status ClaimStatus) error
+	GetClaimRequest(ctx context.Context, id string) (ClaimRequest, error)
+	// ReviewClaimRequest records a decision on a claim. Approval also transfers
+	// ownership of the listing to the claimant.
+	ReviewClaimRequest(ctx context.Context, id string, review ClaimReview) error
 	GetClaimRequestByUserAndListing(ctx context.Context, userID, listingID string) (ClaimRequest, error)
+	// ListClaimRequestsByUser returns a user's claims, newest first.
+	ListClaimRequestsByUser(ctx context.Context, userID string) ([]ClaimRequest, error)
 }
 
 // AnalyticsStore handles growth/analytics queries.
@@ -88,6 +162,69 @@ type CategoryStore interface {
 	SaveCategory(ctx context.Context, c CategoryData) error
 }
 
+// APITokenStore handles personal access token persistence.
+type APITokenStore interface {
+	SaveAPIToken(ctx context.Context, t APIToken) error
+	FindAPITokenByHash(ctx context.Context, hash string) (APIToken, error)
+	ListAPITokens(ctx context.Context, userID string) ([]APIToken, error)
+	RevokeAPIToken(ctx context.Context, userID, id string, at time.Time) error
+	TouchAPIToken(ctx context.Context, id string, at time.Time) error
+}
+
+// ListingEventStore persists the append-only listing audit log.
+type ListingEventStore interface {
+	AppendListingEvent(ctx context.Context, e ListingEvent) error
+	// SaveListingWithHistory saves l together with its history in one transaction:
+	// rev when it is not nil, then e with its Changes diffed between before and l as
+	// stored.
+	SaveListingWithHistory(ctx context.Context, before, l Listing, rev *ListingRevision, e ListingEvent) error
+	// ListListingEvents returns a listing's events, oldest first.
+	ListListingEvents(ctx context.Context, listingID string) ([]ListingEvent, error)
+}
+
+// ListingRevisionStore persists snapshots of listings taken before each save.
+type ListingRevisionStore interface {
+	// SaveListingRevision stores r under the next revision number for its listing and returns that number.
+	SaveListingRevision(ctx context.Context, r ListingRevision) (int, error)
+	// ListListingRevisions returns a listing's revisions, oldest first.
+	ListListingRevisions(ctx context.Context, listingID string) ([]ListingRevision, error)
+	GetListingRevision(ctx context.Context, listingID string, number int) (ListingRevision, error)
+}
+
+// PendingChangeStore persists listing submissions held for moderation.
+type PendingChangeStore interface {
+	// SavePendingChange stores c and supersedes any change still pending for the same listing.
+	SavePendingChange(ctx context.Context, c PendingChange) error
+	GetPendingChange(ctx context.Context, id string) (PendingChange, error)
+	// ListPendingChanges returns the changes awaiting review on listings that still exist, oldest first.
+	ListPendingChanges(ctx context.Context) ([]PendingChange, error)
+	// ResolvePendingChange records a review decision. It returns ErrPendingChangeNotFound
+	// unless the change exists and is still pending, so a change is resolved at most once.
+	ResolvePendingChange(ctx context.Context, id string, status PendingChangeStatus, reviewerID string, at time.Time) error
+}
+
+// OutboxStore persists notification emails awaiting delivery.
+type OutboxStore interface {
+	SaveOutboxMessage(ctx context.Context, m OutboxMessage) error
+	// ListDueOutboxMessages returns up to limit pending messages whose next attempt is
+	// at or before now, oldest first.
+	ListDueOutboxMessages(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error)
+	// UpdateOutboxMessage records the outcome of a delivery attempt, including a
+	// body erased after delivery.
+	UpdateOutboxMessage(ctx context.Context, m OutboxMessage) error
+}
+
+// GeocodeCacheStore persists geocoding results so repeated lookups skip the provider.
+type GeocodeCacheStore interface {
+	// GetGeocodeCacheEntry returns the entry for a normalized address and lookup, or
+	// ErrGeocodeNotCached when there is none that expires after now.
+	GetGeocodeCacheEntry(ctx context.Context, address string, lookup GeocodeLookup, now time.Time) (GeocodeCacheEntry, error)
+	// SaveGeocodeCacheEntry stores e, replacing any entry for the same address and lookup.
+	SaveGeocodeCacheEntry(ctx context.Context, e GeocodeCacheEntry) error
+	// DeleteExpiredGeocodeCacheEntries removes entries that expired at or before now.
+	DeleteExpiredGeocodeCacheEntries(ctx context.Context, now time.Time) (int64, error)
+}
+
 // --- Composed Super-Interface (Backward Compatible) ---
 
 // ListingRepository composes all store interfaces into a single contract.
@@ -95,12 +232,22 @@ type CategoryStore interface {
 type ListingRepository interface {
 	ListingStore
 	ListingExpirer
+	ListingTimezoneStore
 	UserStore
+	UserEventStore
+	SessionStore
+	LoginLinkStore
 	FeedbackStore
 	AdminStore
 	AnalyticsStore
 	CategoryStore
 	ClaimRequestStore
+	APITokenStore
+	ListingEventStore
+	ListingRevisionStore
+	PendingChangeStore
+	OutboxStore
+	GeocodeCacheStore
 }
 
 // DailyMetric represents a daily count of an entity.
@@ -4,7 +4,12 @@ import "context"
 
 // ListingService encapsulates business logic associated with Listings across modules.
 type ListingService interface {
-	ClaimListing(ctx context.Context, user User, listingID string) (ClaimRequest, error)
+	ClaimListing(ctx context.Context, user User, listingID string, evidence ClaimEvidence) (ClaimRequest, error)
+}
+
+// TokenAuthenticator resolves a plaintext bearer token to the token record it belongs to.
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, raw string) (APIToken, error)
 }
 
 // CategorizationService handles category management and caching.
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// SessionMaxAge is how long a browser session lasts after it was last saved.
+const SessionMaxAge = 7 * 24 * time.Hour
+
+// Session is a browser session kept on the server. The cookie carries only a signed
+// random ID, so a session can be listed and revoked before it expires.
+type Session struct {
+	CreatedAt time.Time
+	// LastSeenAt, IP and UserAgent describe the most recent request on the session.
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	// ID is the SHA-256 hash of the ID in the cookie, so a leaked table cannot be
+	// replayed as cookies.
+	ID string
+	// UserID is empty until someone signs in on the session.
+	UserID    string
+	IP        string
+	UserAgent string
+	// Data holds the encoded session values.
+	Data []byte
+}
+
+// Device names the browser and operating system a session was last used from, for
+// the list of signed-in devices.
+func (s Session) Device() string {
+	ua := strings.ToLower(s.UserAgent)
+	browser := firstMatch(ua, "Browser", [][2]string{
+		{"edg/", "Edge"}, {"opr/", "Opera"}, {"firefox/", "Firefox"},
+		{"chrome/", "Chrome"}, {"safari/", "Safari"}, {"curl/", "curl"},
+	})
+	platform := firstMatch(ua, "", [][2]string{
+		{"iphone", "iPhone"}, {"ipad", "iPad"}, {"android", "Android"},
+		{"windows", "Windows"}, {"mac os", "macOS"}, {"linux", "Linux"},
+	})
+	if platform == "" {
+		return browser
+	}
+	return browser + " on " + platform
+}
+
+// firstMatch returns the name paired with the first marker found in ua, or fallback.
+func firstMatch(ua, fallback string, markers [][2]string) string {
+	for _, m := range markers {
+		if strings.Contains(ua, m[0]) {
+			return m[1]
+		}
+	}
+	return fallback
+}
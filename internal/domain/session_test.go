@@ -0,0 +1,22 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_Device(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Mobile/15E148 Safari/604.1": "Safari on iPhone",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"curl/8.7.1": "curl",
+		"":           "Browser",
+	}
+	for ua, want := range tests {
+		assert.Equal(t, want, domain.Session{UserAgent: ua}.Device(), ua)
+	}
+}
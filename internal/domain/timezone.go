@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	// Embed the zone database so listing hours evaluate correctly on hosts without one.
+	_ "time/tzdata"
+)
+
+// usStateZones maps US state codes to the IANA zone covering most of the state.
+var usStateZones = map[string]string{
+	"AL": "America/Chicago", "AK": "America/Anchorage", "AZ": "America/Phoenix", "AR": "America/Chicago",
+	"CA": "America/Los_Angeles", "CO": "America/Denver", "CT": "America/New_York", "DE": "America/New_York",
+	"DC": "America/New_York", "FL": "America/New_York", "GA": "America/New_York", "HI": "Pacific/Honolulu",
+	"ID": "America/Boise", "IL": "America/Chicago", "IN": "America/Indiana/Indianapolis", "IA": "America/Chicago",
+	"KS": "America/Chicago", "KY": "America/New_York", "LA": "America/Chicago", "ME": "America/New_York",
+	"MD": "America/New_York", "MA": "America/New_York", "MI": "America/Detroit", "MN": "America/Chicago",
+	"MS": "America/Chicago", "MO": "America/Chicago", "MT": "America/Denver", "NE": "America/Chicago",
+	"NV": "America/Los_Angeles", "NH": "America/New_York", "NJ": "America/New_York", "NM": "America/Denver",
+	"NY": "America/New_York", "NC": "America/New_York", "ND": "America/Chicago", "OH": "America/New_York",
+	"OK": "America/Chicago", "OR": "America/Los_Angeles", "PA": "America/New_York", "RI": "America/New_York",
+	"SC": "America/New_York", "SD": "America/Chicago", "TN": "America/Chicago", "TX": "America/Chicago",
+	"UT": "America/Denver", "VT": "America/New_York", "VA": "America/New_York", "WA": "America/Los_Angeles",
+	"WV": "America/New_York", "WI": "America/Chicago", "WY": "America/Denver", "PR": "America/Puerto_Rico",
+}
+
+// usStateNames maps full US state names to their codes.
+var usStateNames = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR", "california": "CA", "colorado": "CO",
+	"connecticut": "CT", "delaware": "DE", "district of columbia": "DC", "florida": "FL", "georgia": "GA",
+	"hawaii": "HI", "idaho": "ID", "illinois": "IL", "indiana": "IN", "iowa": "IA", "kansas": "KS",
+	"kentucky": "KY", "louisiana": "LA", "maine": "ME", "maryland": "MD", "massachusetts": "MA",
+	"michigan": "MI", "minnesota": "MN", "mississippi": "MS", "missouri": "MO", "montana": "MT",
+	"nebraska": "NE", "nevada": "NV", "new hampshire": "NH", "new jersey": "NJ", "new mexico": "NM",
+	"new york": "NY", "north carolina": "NC", "north dakota": "ND", "ohio": "OH", "oklahoma": "OK",
+	"oregon": "OR", "pennsylvania": "PA", "rhode island": "RI", "south carolina": "SC", "south dakota": "SD",
+	"tennessee": "TN", "texas": "TX", "utah": "UT", "vermont": "VT", "virginia": "VA", "washington": "WA",
+	"west virginia": "WV", "wisconsin": "WI", "wyoming": "WY", "puerto rico": "PR",
+}
+
+// countryZones maps lower-cased country names and codes to the zone of countries that
+// use a single one.
+var countryZones = map[string]string{
+	"nigeria": "Africa/Lagos", "ng": "Africa/Lagos",
+	"ghana": "Africa/Accra", "gh": "Africa/Accra",
+	"kenya": "Africa/Nairobi", "ke": "Africa/Nairobi",
+	"south africa": "Africa/Johannesburg", "za": "Africa/Johannesburg",
+	"ethiopia": "Africa/Addis_Ababa", "et": "Africa/Addis_Ababa",
+	"egypt": "Africa/Cairo", "eg": "Africa/Cairo",
+	"senegal": "Africa/Dakar", "sn": "Africa/Dakar",
+	"cameroon": "Africa/Douala", "cm": "Africa/Douala",
+	"uganda": "Africa/Kampala", "ug": "Africa/Kampala",
+	"tanzania": "Africa/Dar_es_Salaam", "tz": "Africa/Dar_es_Salaam",
+	"morocco": "Africa/Casablanca", "ma": "Africa/Casablanca",
+	"united kingdom": "Europe/London", "uk": "Europe/London", "gb": "Europe/London",
+	"ireland": "Europe/Dublin", "ie": "Europe/Dublin",
+	"france": "Europe/Paris", "fr": "Europe/Paris",
+	"germany": "Europe/Berlin", "de": "Europe/Berlin",
+}
+
+// ResolveTimezone guesses the IANA zone of a place from its state and country, falling
+// back to its coordinates inside the contiguous US. It returns "" when it cannot tell.
+func ResolveTimezone(lat, lng float64, state, country string) string {
+	state = strings.TrimSpace(state)
+	c := strings.ToLower(strings.TrimSpace(country))
+	isUS := c == "" || c == "usa" || c == "us" || c == "united states" || c == "united states of america"
+
+	if isUS {
+		if code, ok := USStateCode(state); ok {
+			return usStateZones[code]
+		}
+		return usZoneByLongitude(lat, lng)
+	}
+	return countryZones[c]
+}
+
+// USStateCode returns the two-letter code of a US state given its code or full name, in
+// any case.
+func USStateCode(state string) (string, bool) {
+	state = strings.TrimSpace(state)
+	if code, ok := usStateNames[strings.ToLower(state)]; ok {
+		return code, true
+	}
+	code := strings.ToUpper(state)
+	_, ok := usStateZones[code]
+	return code, ok
+}
+
+// usZoneByLongitude approximates the zone of a point in the contiguous US from the
+// meridians its zone boundaries roughly follow.
+func usZoneByLongitude(lat, lng float64) string {
+	if lat < 24 || lat > 50 || lng < -125 || lng > -66 {
+		return ""
+	}
+	switch {
+	case lng >= -85.5:
+		return "America/New_York"
+	case lng >= -101.5:
+		return "America/Chicago"
+	case lng >= -114.5:
+		return "America/Denver"
+	}
+	return "America/Los_Angeles"
+}
+
+// RefreshTimezone sets Timezone from the listing's location when one can be resolved,
+// keeping the current value otherwise.
+func (l *Listing) RefreshTimezone() {
+	if tz := ResolveTimezone(l.Latitude, l.Longitude, l.State, l.Country); tz != "" {
+		l.Timezone = tz
+	}
+}
+
+// TimeLocation returns the zone the listing's hours are written in: its Timezone, or one
+// resolved from its location for listings saved before Timezone existed. ok is false when
+// neither is known.
+func (l Listing) TimeLocation() (loc *time.Location, ok bool) {
+	tz := l.Timezone
+	if tz == "" {
+		tz = ResolveTimezone(l.Latitude, l.Longitude, l.State, l.Country)
+	}
+	if tz == "" {
+		return nil, false
+	}
+	return loadLocation(tz)
+}
+
+// locations caches zones by name; time.LoadLocation parses zone data on every call.
+var locations sync.Map
+
+func loadLocation(name string) (*time.Location, bool) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	locations.Store(name, loc)
+	return loc, true
+}
+
+// LocalTime returns t on the listing's wall clock, or t unchanged when the listing's
+// zone is unknown.
+func (l Listing) LocalTime(t time.Time) time.Time {
+	if loc, ok := l.TimeLocation(); ok {
+		return t.In(loc)
+	}
+	return t
+}
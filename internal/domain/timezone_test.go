@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTimezone(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		state    string
+		country  string
+		expected string
+		lat, lng float64
+	}{
+		{name: "us-state-code", state: "TX", country: "USA", expected: "America/Chicago"},
+		{name: "us-state-name", state: "California", country: "United States", expected: "America/Los_Angeles"},
+		{name: "us-coordinates", country: "USA", lat: 40.71, lng: -74.0, expected: "America/New_York"},
+		{name: "country", country: "Nigeria", expected: "Africa/Lagos"},
+		{name: "unknown-country", country: "Atlantis", expected: ""},
+		{name: "nothing-known", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, domain.ResolveTimezone(tt.lat, tt.lng, tt.state, tt.country))
+		})
+	}
+}
+
+func TestListing_LocalTime(t *testing.T) {
+	t.Parallel()
+	l := domain.Listing{Timezone: "Africa/Lagos"}
+	loc, ok := l.TimeLocation()
+	assert.True(t, ok)
+	assert.Equal(t, "Africa/Lagos", loc.String())
+
+	l = domain.Listing{Timezone: "Not/AZone"}
+	_, ok = l.TimeLocation()
+	assert.False(t, ok)
+}
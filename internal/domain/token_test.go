@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenScopes(t *testing.T) {
+	t.Parallel()
+
+	scopes, err := ParseTokenScopes("listings:read, listings:write listings:read")
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenScope{ScopeListingsRead, ScopeListingsWrite}, scopes)
+
+	_, err = ParseTokenScopes("")
+	assert.ErrorIs(t, err, ErrTokenScopeRequired)
+
+	_, err = ParseTokenScopes("listings:read,root")
+	assert.ErrorIs(t, err, ErrInvalidTokenScope)
+}
+
+func TestAPIToken_HasScope(t *testing.T) {
+	t.Parallel()
+
+	read := APIToken{Scopes: []TokenScope{ScopeListingsRead}}
+	assert.True(t, read.HasScope(ScopeListingsRead))
+	assert.False(t, read.HasScope(ScopeListingsWrite))
+	assert.False(t, read.HasScope(ScopeAdmin))
+
+	admin := APIToken{Scopes: []TokenScope{ScopeAdmin}}
+	assert.True(t, admin.HasScope(ScopeListingsWrite), "admin implies every scope")
+}
@@ -5,12 +5,17 @@ import "time"
 // User represents a registered user (listing owner).
 type User struct {
 	CreatedAt time.Time `json:"created_at"`
-	ID        string    `json:"id"`
-	GoogleID  string    `json:"google_id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	AvatarURL string    `json:"avatar_url"`
-	Role      UserRole  `json:"role"`
+	// SuspendedUntil is when a suspension ends. It is zero unless Status is suspended.
+	SuspendedUntil time.Time  `json:"suspended_until"`
+	ID             string     `json:"id"`
+	GoogleID       string     `json:"google_id"`
+	Email          string     `json:"email"`
+	Name           string     `json:"name"`
+	AvatarURL      string     `json:"avatar_url"`
+	Role           UserRole   `json:"role"`
+	Status         UserStatus `json:"status"`
+	// StatusReason is the staff member's reason for the current suspension or ban.
+	StatusReason string `json:"status_reason"`
 }
 
 type UserRole string
@@ -18,4 +23,27 @@ type UserRole string
 const (
 	UserRoleAdmin UserRole = "Admin"
 	UserRoleUser  UserRole = "User"
+	// UserRoleModerator reviews claims, held listing changes and listing status.
+	UserRoleModerator UserRole = "Moderator"
+	// UserRoleCategoryCurator manages the category list.
+	UserRoleCategoryCurator UserRole = "CategoryCurator"
+	// UserRoleAnalyst reads the dashboard, charts and exports without changing anything.
+	UserRoleAnalyst UserRole = "Analyst"
+	// UserRoleSuperAdmin can do everything an admin can and also grant the admin roles.
+	UserRoleSuperAdmin UserRole = "SuperAdmin"
 )
+
+// IdentityProviderGoogle is the Provider of identities from Google sign-in.
+const IdentityProviderGoogle = "google"
+
+// UserIdentity links a User to an account at a sign-in provider. A user has one for
+// each provider account they sign in with.
+type UserIdentity struct {
+	CreatedAt time.Time
+	// Provider is IdentityProviderGoogle or the ID of an OpenID Connect provider.
+	Provider string
+	// Subject is the provider's stable ID for the account.
+	Subject string
+	UserID  string
+	Email   string
+}
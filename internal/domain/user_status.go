@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserStatus is whether a user may sign in and whether others see what they post. It
+// is set by staff through the admin user page and never by the user.
+type UserStatus string
+
+const (
+	UserStatusActive UserStatus = "active"
+	// UserStatusSuspended blocks a user until User.SuspendedUntil.
+	UserStatusSuspended UserStatus = "suspended"
+	// UserStatusBanned blocks a user until staff reinstate them.
+	UserStatusBanned UserStatus = "banned"
+	// UserStatusShadowed lets a user carry on as normal while their listings are
+	// hidden from everyone but them and staff, so a spammer does not notice and move
+	// to a new account.
+	UserStatusShadowed UserStatus = "shadowed"
+)
+
+// AllUserStatuses lists the statuses in the order the admin user page offers them.
+var AllUserStatuses = []UserStatus{UserStatusActive, UserStatusShadowed, UserStatusSuspended, UserStatusBanned}
+
+// ParseUserStatus returns the status named by s, ignoring case.
+func ParseUserStatus(s string) (UserStatus, error) {
+	for _, status := range AllUserStatuses {
+		if strings.EqualFold(string(status), strings.TrimSpace(s)) {
+			return status, nil
+		}
+	}
+	return "", ErrInvalidUserStatus
+}
+
+// CurrentStatus is the user's status at now. A suspension that has run out counts as
+// active without anyone having to lift it.
+func (u User) CurrentStatus(now time.Time) UserStatus {
+	switch u.Status {
+	case UserStatusBanned, UserStatusShadowed:
+		return u.Status
+	case UserStatusSuspended:
+		if now.Before(u.SuspendedUntil) {
+			return UserStatusSuspended
+		}
+	}
+	return UserStatusActive
+}
+
+// CheckAccess returns ErrUserSuspended or ErrUserBanned, wrapped in a message for the
+// user, when they may not sign in at now.
+func (u User) CheckAccess(now time.Time) error {
+	switch u.CurrentStatus(now) {
+	case UserStatusBanned:
+		return ErrUserBanned
+	case UserStatusSuspended:
+		return fmt.Errorf("%w until %s", ErrUserSuspended, u.SuspendedUntil.Format("Jan 02, 2006"))
+	}
+	return nil
+}
+
+// BlocksAccess reports whether a user with status s may not sign in.
+func (s UserStatus) BlocksAccess() bool {
+	return s == UserStatusSuspended || s == UserStatusBanned
+}
+
+// IsShadowed reports whether others are kept from seeing the user's listings.
+func (u User) IsShadowed() bool {
+	return u.Status == UserStatusShadowed
+}
+
+// IsRestricted reports whether the user is shadowed, suspended or banned right now.
+func (u User) IsRestricted() bool {
+	return u.CurrentStatus(time.Now()) != UserStatusActive
+}
+
+// CanRestrict reports whether a user with role r may suspend, ban or reinstate a user
+// with role target. Only super admins may restrict admins.
+func (r UserRole) CanRestrict(target UserRole) bool {
+	if !r.Can(PermissionManageUsers) {
+		return false
+	}
+	return r == UserRoleSuperAdmin || !target.isAdmin()
+}
+
+// UserAction names a change of status recorded in a UserEvent.
+type UserAction string
+
+const (
+	UserActionSuspend   UserAction = "suspend"
+	UserActionBan       UserAction = "ban"
+	UserActionShadow    UserAction = "shadow"
+	UserActionReinstate UserAction = "reinstate"
+)
+
+// UserActionFor is the action recorded when a user is given status.
+func UserActionFor(status UserStatus) UserAction {
+	switch status {
+	case UserStatusSuspended:
+		return UserActionSuspend
+	case UserStatusBanned:
+		return UserActionBan
+	case UserStatusShadowed:
+		return UserActionShadow
+	}
+	return UserActionReinstate
+}
+
+// UserEvent is one append-only entry in the audit trail of staff actions on a user.
+type UserEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Until is when a suspension ends; it is zero for other actions.
+	Until     time.Time  `json:"until"`
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	ActorID   string     `json:"actor_id"`
+	ActorName string     `json:"actor_name"`
+	Action    UserAction `json:"action"`
+	Reason    string     `json:"reason"`
+	// ListingsDeactivated is how many of the user's listings the action took down.
+	ListingsDeactivated int `json:"listings_deactivated"`
+}
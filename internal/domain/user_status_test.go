@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_CheckAccess(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, User{}.CheckAccess(now), "users saved before statuses existed are active")
+	assert.NoError(t, User{Status: UserStatusActive}.CheckAccess(now))
+	assert.ErrorIs(t, User{Status: UserStatusBanned}.CheckAccess(now), ErrUserBanned)
+
+	suspended := User{Status: UserStatusSuspended, SuspendedUntil: now.Add(24 * time.Hour)}
+	err := suspended.CheckAccess(now)
+	require.ErrorIs(t, err, ErrUserSuspended)
+	assert.Contains(t, err.Error(), "Mar 02, 2026")
+	assert.Equal(t, UserStatusSuspended, suspended.CurrentStatus(now))
+
+	assert.NoError(t, suspended.CheckAccess(now.Add(24*time.Hour)), "a suspension lifts itself when it ends")
+	assert.Equal(t, UserStatusActive, suspended.CurrentStatus(now.Add(24*time.Hour)))
+
+	shadowed := User{Status: UserStatusShadowed}
+	assert.NoError(t, shadowed.CheckAccess(now), "a shadowed user signs in as normal")
+	assert.Equal(t, UserStatusShadowed, shadowed.CurrentStatus(now))
+	assert.True(t, shadowed.IsShadowed())
+	assert.False(t, UserStatusShadowed.BlocksAccess())
+	assert.True(t, UserStatusSuspended.BlocksAccess())
+}
+
+func TestParseUserStatus(t *testing.T) {
+	t.Parallel()
+
+	status, err := ParseUserStatus(" Suspended ")
+	require.NoError(t, err)
+	assert.Equal(t, UserStatusSuspended, status)
+	_, err = ParseUserStatus("deleted")
+	assert.ErrorIs(t, err, ErrInvalidUserStatus)
+
+	assert.Equal(t, UserActionSuspend, UserActionFor(UserStatusSuspended))
+	assert.Equal(t, UserActionBan, UserActionFor(UserStatusBanned))
+	assert.Equal(t, UserActionShadow, UserActionFor(UserStatusShadowed))
+	assert.Equal(t, UserActionReinstate, UserActionFor(UserStatusActive))
+}
+
+func TestUserRole_CanRestrict(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, UserRoleAdmin.CanRestrict(UserRoleUser))
+	assert.True(t, UserRoleAdmin.CanRestrict(UserRoleModerator))
+	assert.False(t, UserRoleAdmin.CanRestrict(UserRoleAdmin), "only super admins restrict admins")
+	assert.True(t, UserRoleSuperAdmin.CanRestrict(UserRoleAdmin))
+	assert.False(t, UserRoleModerator.CanRestrict(UserRoleUser))
+}
@@ -20,6 +20,10 @@ type AppEnv struct {
 	CategorizationSvc domain.CategorizationService
 	MetricsSvc        domain.MetricsService
 	CatCache          *domain.CategoryCache
+	// Notifications is optional; when nil, handlers send no notifications.
+	Notifications domain.NotificationService
+	// SMS is optional; when nil, phone verification of claims is unavailable.
+	SMS domain.SMSSender
 }
 
 // CategoryCache is moved to domain/category.go to avoid circular dependencies
@@ -16,6 +16,7 @@ import (
 	"github.com/jadecobra/agbalumo/internal/infra/metrics"
 	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
 	"github.com/jadecobra/agbalumo/internal/module/admin"
+	"github.com/jadecobra/agbalumo/internal/module/api"
 	"github.com/jadecobra/agbalumo/internal/module/auth"
 	"github.com/jadecobra/agbalumo/internal/module/feedback"
 	"github.com/jadecobra/agbalumo/internal/module/listing"
@@ -60,18 +61,30 @@ func Setup(cfg *config.Config) (*echo.Echo, func(), error) {
 		return nil, nil, err
 	}
 	repo.SetSlowQueryThreshold(time.Duration(cfg.SlowQueryThresholdMs) * time.Millisecond)
+	setupSessions(e, cfg, repo)
 
 	listingSvc := listing.NewListingService(repo, repo, repo)
 	csvSvc := service.NewCSVService()
-	geocodingSvc := service.NewGoogleGeocodingService(cfg.GoogleMapsAPIKey)
+	metricsSvc := metrics.NewService(repo, slog.Default())
+	geocoders, err := service.NewGeocodingService(cfg.GeocodingProviders, cfg.GoogleMapsAPIKey, cfg.GazetteerPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	geocodingSvc := service.NewCachingGeocodingService(geocoders, repo, metricsSvc)
 	csvSvc.Geocoding = geocodingSvc
 	imageSvc := service.NewLocalImageService(cfg.UploadDir)
 	catCache := &domain.CategoryCache{}
 	catSvc := service.NewCategorizationService(repo, catCache)
-	metricsSvc := metrics.NewService(repo, slog.Default())
 
 	app := env.NewAppEnv(repo, cfg, slog.Default(), csvSvc, geocodingSvc, imageSvc, listingSvc, catSvc, metricsSvc)
 
+	notifications, err := newNotificationService(cfg, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	app.Notifications = notifications
+	app.SMS = newSMSSender(cfg)
+
 	renderer, err := ui.NewTemplateRenderer(
 		"ui/templates/*.html",
 		"ui/templates/partials/*.html",
@@ -87,7 +100,7 @@ func Setup(cfg *config.Config) (*echo.Echo, func(), error) {
 	setupRoutes(e, app)
 
 	bgCtx, cancelBg := context.WithCancel(context.Background())
-	setupBackgroundServices(bgCtx, cfg, repo)
+	setupBackgroundServices(bgCtx, cfg, repo, notifications, geocodingSvc)
 
 	cleanup := func() {
 		slog.Info("Executing server cleanup...")
@@ -113,10 +126,10 @@ func setupMiddleware(e *echo.Echo, cfg *config.Config) {
 		e.Use(rateLimiter.Middleware())
 	}
 
-	baseURL := os.Getenv(config.EnvBaseURL)
-	isSecure := cfg.Env == domain.EnvProduction || strings.HasPrefix(baseURL, "https://")
+	isSecure := secureCookies(cfg)
 
 	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{ //nolint:gosec // false positive for CSRF config
+		Skipper:        customMiddleware.HasBearerToken,
 		TokenLookup:    "header:X-CSRF-Token,form:_csrf",
 		CookiePath:     "/",
 		CookieName:     "_csrf",
@@ -124,15 +137,27 @@ func setupMiddleware(e *echo.Echo, cfg *config.Config) {
 		CookieSecure:   isSecure,
 		CookieHTTPOnly: false,
 	}))
+}
 
-	store := sessions.NewCookieStore([]byte(cfg.SessionSecret))
-	store.Options = &sessions.Options{
+// secureCookies reports whether cookies should only be sent over HTTPS.
+func secureCookies(cfg *config.Config) bool {
+	return cfg.Env == domain.EnvProduction || strings.HasPrefix(os.Getenv(config.EnvBaseURL), "https://")
+}
+
+// setupSessions keeps browser sessions in the database. Cookies signed with a
+// previous secret are accepted until its grace period ends.
+func setupSessions(e *echo.Echo, cfg *config.Config, repo domain.SessionStore) {
+	keys := []customMiddleware.SessionKey{{Secret: []byte(cfg.SessionSecret)}}
+	for _, secret := range cfg.SessionPreviousSecrets {
+		keys = append(keys, customMiddleware.SessionKey{Secret: []byte(secret), Until: cfg.SessionPreviousUntil})
+	}
+	store := customMiddleware.NewSessionStore(repo, sessions.Options{
 		Path:     "/",
-		MaxAge:   86400 * 7,
+		MaxAge:   int(domain.SessionMaxAge.Seconds()),
 		HttpOnly: true,
-		Secure:   isSecure,
+		Secure:   secureCookies(cfg),
 		SameSite: http.SameSiteStrictMode,
-	}
+	}, keys...)
 	e.Use(customMiddleware.SessionMiddleware(store))
 }
 
@@ -149,7 +174,9 @@ func setupRoutes(e *echo.Echo, app *env.AppEnv) {
 	}
 
 	authMw := auth.NewAuthMiddleware(domain.UserStore(repo))
+	authMw.Tokens = auth.NewTokenService(repo)
 	fbHandler := feedback.NewFeedbackHandler(app)
+	apiHandler := api.NewAPIHandler(app)
 	pageHandler := common.NewPageHandler(app)
 
 	e.GET("/healthz", func(c echo.Context) error {
@@ -167,6 +194,7 @@ func setupRoutes(e *echo.Echo, app *env.AppEnv) {
 		listingHandler,
 		adminHandler,
 		fbHandler,
+		apiHandler,
 	}
 	for _, module := range modules {
 		module.RegisterRoutes(e, authMw)
@@ -190,7 +218,44 @@ func StaticCacheHeaders() echo.MiddlewareFunc {
 	}
 }
 
-func setupBackgroundServices(ctx context.Context, cfg *config.Config, repo *sqlite.SQLiteRepository) {
+// newNotificationService sends mail over SMTP when a host is configured, and otherwise
+// logs it and writes it to MailDir for local development.
+func newNotificationService(cfg *config.Config, repo *sqlite.SQLiteRepository) (*service.NotificationService, error) {
+	templates, err := service.LoadEmailTemplates(service.DefaultEmailTemplateGlob)
+	if err != nil {
+		return nil, err
+	}
+	var transport domain.Notifier = service.NewFileNotifier(cfg.MailDir, cfg.MailFrom)
+	if cfg.SMTPHost != "" {
+		transport = service.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return service.NewNotificationService(repo, transport, templates, cfg.BaseURL, cfg.NotifyAdminEmail), nil
+}
+
+// newSMSSender sends text messages through Twilio when it is configured. Otherwise
+// codes are only logged in development, and elsewhere phone verification is off: a
+// logged code would let anyone who can read the log verify any phone.
+func newSMSSender(cfg *config.Config) domain.SMSSender {
+	if cfg.TwilioAccountSID != "" && cfg.TwilioAuthToken != "" && cfg.SMSFrom != "" {
+		return service.NewTwilioSMSSender(cfg.TwilioAccountSID, cfg.TwilioAuthToken, cfg.SMSFrom)
+	}
+	if cfg.Env == domain.EnvDevelopment {
+		return service.LogSMSSender{}
+	}
+	slog.Warn("No SMS provider configured; phone verification of claims is disabled")
+	return nil
+}
+
+// newHoursExtractor reads hours with rules, asking Gemini about texts the rules are
+// unsure of when an API key is configured.
+func newHoursExtractor(geminiKey string) domain.HoursExtractor {
+	if geminiKey == "" {
+		return service.NewEscalatingHoursExtractor(nil)
+	}
+	return service.NewEscalatingHoursExtractor(service.NewGeminiHoursExtractor(geminiKey, nil))
+}
+
+func setupBackgroundServices(ctx context.Context, cfg *config.Config, repo *sqlite.SQLiteRepository, notifications *service.NotificationService, geocodeCache *service.CachingGeocodingService) {
 	if err := seeder.EnsureCategoriesSeeded(ctx, repo, "config/categories.json"); err != nil {
 		slog.Error("Failed to seed categories", "error", err)
 	}
@@ -201,9 +266,18 @@ func setupBackgroundServices(ctx context.Context, cfg *config.Config, repo *sqli
 
 	bgService := service.NewBackgroundService(
 		repo,
-		service.NewScraperJob(repo, service.NewWebsiteScraper(), service.NewGeminiHoursExtractor(os.Getenv("GEMINI_API_KEY"), nil)),
+		service.NewScraperJob(repo, service.NewWebsiteScraper(), newHoursExtractor(os.Getenv("GEMINI_API_KEY"))),
 		service.NewRatingEnricherJob(repo, service.NewGooglePlacesClient(cfg.GoogleMapsAPIKey)),
 	)
+	bgService.Notifications = notifications
+	bgService.Timezones = repo
+	bgService.GeocodeCache = geocodeCache
+	bgService.ExpiryReminder = service.NewExpiryReminderJob(
+		repo,
+		notifications,
+		service.NewExtensionLinks(cfg.SessionSecret, cfg.BaseURL),
+		time.Duration(cfg.ExpiryReminderDays)*24*time.Hour,
+	)
 
 	go bgService.StartTicker(ctx)
 }
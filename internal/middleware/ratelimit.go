@@ -10,14 +10,31 @@ import (
 )
 
 type RateLimitConfig struct {
+	// Key names the bucket a request counts against; the client IP when nil.
+	Key   func(c echo.Context) string
 	Rate  rate.Limit
 	Burst int
 }
 
+// idleTTL is how long a bucket is kept after its last request: until it would
+// have refilled, and at least visitorIdleTTL.
+func (c RateLimitConfig) idleTTL() time.Duration {
+	ttl := visitorIdleTTL
+	if c.Rate > 0 && c.Rate != rate.Inf {
+		if refill := time.Duration(float64(c.Burst) / float64(c.Rate) * float64(time.Second)); refill > ttl {
+			ttl = refill
+		}
+	}
+	return ttl
+}
+
+// visitorIdleTTL is the shortest time a bucket is kept after its last request.
+const visitorIdleTTL = 3 * time.Minute
+
 type RateLimiter struct {
 	visitors map[string]*visitor
-	mu       sync.Mutex
 	config   RateLimitConfig
+	mu       sync.Mutex
 }
 
 type visitor struct {
@@ -48,21 +65,23 @@ func (rl *RateLimiter) purge() {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	for ip, v := range rl.visitors {
-		if time.Since(v.lastSeen) > 3*time.Minute {
-			delete(rl.visitors, ip)
+	// A bucket dropped before it refilled would hand out a fresh burst.
+	ttl := rl.config.idleTTL()
+	for key, v := range rl.visitors {
+		if time.Since(v.lastSeen) > ttl {
+			delete(rl.visitors, key)
 		}
 	}
 }
 
-func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
+func (rl *RateLimiter) getVisitor(key string) *rate.Limiter {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	v, exists := rl.visitors[ip]
+	v, exists := rl.visitors[key]
 	if !exists {
 		limiter := rate.NewLimiter(rl.config.Rate, rl.config.Burst)
-		rl.visitors[ip] = &visitor{limiter, time.Now()}
+		rl.visitors[key] = &visitor{limiter, time.Now()}
 		return limiter
 	}
 
@@ -73,8 +92,11 @@ func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
 func (rl *RateLimiter) Middleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ip := c.RealIP()
-			limiter := rl.getVisitor(ip)
+			key := c.RealIP()
+			if rl.config.Key != nil {
+				key = rl.config.Key(c)
+			}
+			limiter := rl.getVisitor(key)
 
 			if !limiter.Allow() {
 				return c.String(http.StatusTooManyRequests, "Too Many Requests")
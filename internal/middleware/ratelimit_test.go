@@ -143,3 +143,32 @@ func TestRateLimiter_Cleanup(t *testing.T) {
 	rl.mu.Unlock()
 	assert.False(t, exists)
 }
+
+func TestRateLimiter_Key(t *testing.T) {
+	t.Parallel()
+	e := echo.New()
+	rl := NewRateLimiter(RateLimitConfig{
+		Key:   func(c echo.Context) string { return c.QueryParam("email") },
+		Rate:  rate.Every(time.Hour),
+		Burst: 1,
+	})
+	h := setupHandler(rl)
+
+	code := func(target, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = ip
+		rec := httptest.NewRecorder()
+		_ = h(e.NewContext(req, rec))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, code("/?email=a", "1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, code("/?email=a", "2.2.2.2"), "the key is shared across IPs")
+	assert.Equal(t, http.StatusOK, code("/?email=b", "1.1.1.1"))
+
+	// A slow bucket is kept until it would have refilled, not just a few minutes.
+	rl.mu.Lock()
+	rl.visitors["a"].lastSeen = time.Now().Add(-30 * time.Minute)
+	rl.mu.Unlock()
+	rl.purge()
+	assert.Equal(t, http.StatusTooManyRequests, code("/?email=a", "1.1.1.1"))
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// sessionTouchInterval is how often a session's last-seen time is written while the
+// client's IP and user agent stay the same.
+const sessionTouchInterval = time.Minute
+
+// SessionKey is a secret that signs session cookies.
+type SessionKey struct {
+	// Until is when cookies signed with a retired secret stop being accepted. It is
+	// zero for the current secret.
+	Until  time.Time
+	Secret []byte
+}
+
+// SessionStore is a sessions.Store that keeps session values on the server. The cookie
+// carries only a random session ID signed with the current key, so sessions can be
+// listed and revoked before they expire.
+type SessionStore struct {
+	Repo    domain.SessionStore
+	Options *sessions.Options
+	Now     func() time.Time
+	// ClientIP reports the address a request came from.
+	ClientIP echo.IPExtractor
+	keys     []sessionCodec
+}
+
+type sessionCodec struct {
+	until time.Time
+	codec *securecookie.SecureCookie
+}
+
+// NewSessionStore returns a store saving sessions to repo. keys[0] signs new cookies;
+// the rest are retired keys still accepted until their Until time.
+func NewSessionStore(repo domain.SessionStore, options sessions.Options, keys ...SessionKey) *SessionStore {
+	s := &SessionStore{
+		Repo:     repo,
+		Options:  &options,
+		Now:      time.Now,
+		ClientIP: echo.ExtractIPFromXFFHeader(),
+	}
+	for _, k := range keys {
+		codec := securecookie.New(k.Secret, nil)
+		codec.MaxAge(options.MaxAge)
+		s.keys = append(s.keys, sessionCodec{until: k.Until, codec: codec})
+	}
+	return s
+}
+
+// Get returns the session for the request, loading it once per request.
+func (s *SessionStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named by the request's cookie. A missing, forged, expired or
+// revoked session yields a new, empty one.
+func (s *SessionStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+	var id string
+	if err = s.decode(name, cookie.Value, &id); err != nil {
+		return session, err
+	}
+
+	ctx := r.Context()
+	now := s.Now()
+	stored, err := s.Repo.FindSession(ctx, HashSessionID(id), now)
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+	if err = (securecookie.GobEncoder{}).Deserialize(stored.Data, &session.Values); err != nil {
+		return session, err
+	}
+	session.ID = id
+	session.IsNew = false
+
+	ip, userAgent := s.ClientIP(r), r.UserAgent()
+	if ip != stored.IP || userAgent != stored.UserAgent || now.Sub(stored.LastSeenAt) >= sessionTouchInterval {
+		// Last-seen details are informational, so failing to record them does not fail the request.
+		_ = s.Repo.TouchSession(ctx, stored.ID, ip, userAgent, now)
+	}
+	return session, nil
+}
+
+// Save stores the session and sets its cookie, or deletes both when MaxAge is negative.
+func (s *SessionStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	ctx := r.Context()
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.Repo.DeleteSession(ctx, HashSessionID(session.ID)); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		session.ID = rand.Text()
+	}
+	data, err := securecookie.GobEncoder{}.Serialize(session.Values)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	maxAge := time.Duration(session.Options.MaxAge) * time.Second
+	if maxAge == 0 {
+		maxAge = domain.SessionMaxAge
+	}
+	userID, _ := session.Values[domain.SessionKeyUserID].(string)
+	err = s.Repo.SaveSession(ctx, domain.Session{
+		ID:         HashSessionID(session.ID),
+		UserID:     userID,
+		Data:       data,
+		IP:         s.ClientIP(r),
+		UserAgent:  r.UserAgent(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(maxAge),
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded, err := s.keys[0].codec.Encode(session.Name(), session.ID)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// decode verifies a cookie with the current key or a retired key still in its grace period.
+func (s *SessionStore) decode(name, value string, id *string) error {
+	now := s.Now()
+	codecs := make([]securecookie.Codec, 0, len(s.keys))
+	for _, k := range s.keys {
+		if k.until.IsZero() || now.Before(k.until) {
+			codecs = append(codecs, k.codec)
+		}
+	}
+	return securecookie.DecodeMulti(name, value, id, codecs...)
+}
+
+// CurrentSessionID is the stored ID of the request's session, or "" before it is saved.
+func CurrentSessionID(c echo.Context) string {
+	sess := GetSession(c)
+	if sess == nil || sess.ID == "" {
+		return ""
+	}
+	return HashSessionID(sess.ID)
+}
+
+// HashSessionID is the form a session's cookie ID is stored and listed in.
+func HashSessionID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
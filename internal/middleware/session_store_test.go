@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/middleware"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionName = "auth_session"
+
+func newSessionStore(repo domain.SessionStore, keys ...middleware.SessionKey) *middleware.SessionStore {
+	return middleware.NewSessionStore(repo, sessions.Options{Path: "/", MaxAge: 3600, HttpOnly: true}, keys...)
+}
+
+// saveSession signs a user in on a new session and returns its cookie.
+func saveSession(t *testing.T, store *middleware.SessionStore, userID string) *http.Cookie {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	sess, err := store.Get(req, sessionName)
+	require.NoError(t, err)
+	sess.Values[domain.SessionKeyUserID] = userID
+	require.NoError(t, sess.Save(req, rec))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0]
+}
+
+func loadSession(store *middleware.SessionStore, cookie *http.Cookie) (*sessions.Session, error) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.6; rv:130.0) Gecko/20100101 Firefox/130.0")
+	req.AddCookie(cookie)
+	return store.Get(req, sessionName)
+}
+
+func TestSessionStore_RoundTripAndRevoke(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	store := newSessionStore(repo, middleware.SessionKey{Secret: []byte("current-secret")})
+
+	cookie := saveSession(t, store, "u1")
+	assert.NotContains(t, cookie.Value, "u1", "only the session ID travels in the cookie")
+
+	sess, err := loadSession(store, cookie)
+	require.NoError(t, err)
+	assert.False(t, sess.IsNew)
+	assert.Equal(t, "u1", sess.Values[domain.SessionKeyUserID])
+
+	list, err := repo.ListUserSessions(context.Background(), "u1", time.Now())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, middleware.HashSessionID(sess.ID), list[0].ID)
+	assert.Equal(t, "Firefox on macOS", list[0].Device(), "loading the session records the device")
+
+	require.NoError(t, repo.DeleteSession(context.Background(), list[0].ID))
+	sess, err = loadSession(store, cookie)
+	require.NoError(t, err)
+	assert.True(t, sess.IsNew, "a revoked session signs nobody in")
+	assert.Empty(t, sess.Values)
+}
+
+func TestSessionStore_RejectsForgedCookie(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	cookie := saveSession(t, newSessionStore(repo, middleware.SessionKey{Secret: []byte("attacker-secret")}), "u1")
+
+	sess, err := loadSession(newSessionStore(repo, middleware.SessionKey{Secret: []byte("current-secret")}), cookie)
+	assert.Error(t, err)
+	assert.True(t, sess.IsNew)
+	assert.Empty(t, sess.Values)
+}
+
+func TestSessionStore_SecretRotation(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	old := []byte("previous-secret")
+	cookie := saveSession(t, newSessionStore(repo, middleware.SessionKey{Secret: old}), "u1")
+
+	now := time.Now()
+	rotated := newSessionStore(repo,
+		middleware.SessionKey{Secret: []byte("current-secret")},
+		middleware.SessionKey{Secret: old, Until: now.Add(time.Hour)},
+	)
+
+	sess, err := loadSession(rotated, cookie)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", sess.Values[domain.SessionKeyUserID], "the previous secret is accepted during the grace period")
+
+	// Saving re-signs the cookie with the current secret.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, rotated.Save(req, rec, sess))
+	current := newSessionStore(repo, middleware.SessionKey{Secret: []byte("current-secret")})
+	resigned, err := loadSession(current, rec.Result().Cookies()[0])
+	require.NoError(t, err)
+	assert.Equal(t, "u1", resigned.Values[domain.SessionKeyUserID])
+
+	rotated.Now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = loadSession(rotated, cookie)
+	assert.Error(t, err, "the previous secret is refused once its grace period ends")
+}
@@ -15,6 +15,8 @@ import (
 	"golang.org/x/time/rate"
 )
 
+const msgAdminCodeRetired = "Admin access is granted by an administrator. Ask one to assign you a role."
+
 type AdminHandler struct {
 	module.BaseHandler
 }
@@ -46,29 +48,47 @@ func (h *AdminHandler) RegisterRoutes(e *echo.Echo, authMw domain.AuthMiddleware
 	adminLoginGroup.Use(adminAuthLimiter.Middleware())
 	adminLoginGroup.POST("", h.HandleLoginAction)
 	adminGroup.Use(h.AdminMiddleware)
-	adminGroup.GET("", h.HandleDashboard)
-	adminGroup.GET("/users", h.HandleUsers)
-	adminGroup.GET(domain.PathListings, h.HandleAllListings)
-	adminGroup.POST("/claims/:id/approve", h.HandleApproveClaim)
-	adminGroup.POST("/claims/:id/reject", h.HandleRejectClaim)
-	adminGroup.POST("/listings/bulk", h.HandleBulkAction)
-	adminGroup.GET("/listings/:id/row", h.HandleListingRow)
-	adminGroup.GET("/listings/delete-confirm", h.HandleAdminDeleteView)
-	adminGroup.POST("/listings/delete", h.HandleAdminDeleteAction)
-	adminGroup.POST("/listings/:id/featured", h.HandleToggleFeatured)
-	adminGroup.POST("/upload", h.HandleBulkUpload)
-	adminGroup.GET("/listings/export", h.HandleExportListings)
-	adminGroup.POST("/categories", h.HandleAddCategory)
+
+	view := RequirePermission(domain.PermissionViewDashboard)
+	viewListings := RequirePermission(domain.PermissionViewListings)
+	moderate := RequirePermission(domain.PermissionModerateListings)
+	manage := RequirePermission(domain.PermissionManageListings)
+	categories := RequirePermission(domain.PermissionManageCategories)
+	analytics := RequirePermission(domain.PermissionViewAnalytics)
+	users := RequirePermission(domain.PermissionViewUsers)
+
+	adminGroup.GET("", h.HandleDashboard, view)
+	adminGroup.GET("/users", h.HandleUsers, users)
+	adminGroup.GET("/users/:id", h.HandleUserDetail, users)
+	adminGroup.POST("/users/:id/role", h.HandleAssignRole, RequirePermission(domain.PermissionAssignRoles))
+	adminGroup.POST("/users/:id/logout", h.HandleForceLogout, RequirePermission(domain.PermissionManageUsers))
+	adminGroup.POST("/users/:id/status", h.HandleSetUserStatus, RequirePermission(domain.PermissionManageUsers))
+	adminGroup.GET(domain.PathListings, h.HandleAllListings, viewListings)
+	adminGroup.POST("/claims/:id/approve", h.HandleApproveClaim, moderate)
+	adminGroup.POST("/claims/:id/reject", h.HandleRejectClaim, moderate)
+	adminGroup.POST("/changes/:id/approve", h.HandleApproveChange, moderate)
+	adminGroup.POST("/changes/:id/reject", h.HandleRejectChange, moderate)
+	adminGroup.POST("/listings/bulk", h.HandleBulkAction, moderate)
+	adminGroup.GET("/listings/:id/row", h.HandleListingRow, viewListings)
+	adminGroup.GET("/listings/:id/history", h.HandleListingHistory, viewListings)
+	adminGroup.GET("/listings/delete-confirm", h.HandleAdminDeleteView, manage)
+	adminGroup.POST("/listings/delete", h.HandleAdminDeleteAction, manage)
+	adminGroup.POST("/listings/:id/featured", h.HandleToggleFeatured, moderate)
+	adminGroup.POST("/listings/:id/closed", h.HandleTogglePermanentlyClosed, moderate)
+	adminGroup.POST("/upload", h.HandleBulkUpload, manage)
+	adminGroup.GET("/listings/export", h.HandleExportListings, analytics)
+	adminGroup.POST("/categories", h.HandleAddCategory, categories)
 
 	// Modal Fragments
-	adminGroup.GET("/modal/charts", h.HandleModalCharts)
-	adminGroup.GET("/modal/users", h.HandleModalUsers)
-	adminGroup.GET("/modal/bulk", h.HandleModalBulk)
-	adminGroup.GET("/modal/category", h.HandleModalCategory)
-	adminGroup.GET("/modal/moderation", h.HandleModalModeration)
+	adminGroup.GET("/modal/charts", h.HandleModalCharts, analytics)
+	adminGroup.GET("/modal/users", h.HandleModalUsers, users)
+	adminGroup.GET("/modal/bulk", h.HandleModalBulk, manage)
+	adminGroup.GET("/modal/category", h.HandleModalCategory, categories)
+	adminGroup.GET("/modal/moderation", h.HandleModalModeration, moderate)
 }
 
-// AdminMiddleware checks if the user is an admin.
+// AdminMiddleware lets through users whose role admits them to the dashboard and
+// sends everyone else to the admin login page.
 func (h *AdminHandler) AdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		u, err := user.RequireUser(c)
@@ -76,7 +96,7 @@ func (h *AdminHandler) AdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return err
 		}
 
-		if u.Role != domain.UserRoleAdmin {
+		if !u.Role.IsStaff() {
 			// Redirect to claim page to enter access code
 			return c.Redirect(http.StatusTemporaryRedirect, "/admin/login")
 		}
@@ -85,11 +105,27 @@ func (h *AdminHandler) AdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// RequirePermission rejects requests from users whose role is not granted p.
+func RequirePermission(p domain.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			u, err := user.RequireUser(c)
+			if err != nil || u == nil {
+				return err
+			}
+			if !u.Can(p) {
+				return ui.RespondErrorMsg(c, http.StatusForbidden, "Your role does not allow this")
+			}
+			return next(c)
+		}
+	}
+}
+
 // HandleLoginView renders the admin access code form.
 func (h *AdminHandler) HandleLoginView(c echo.Context) error {
 	// If already admin, redirect to dashboard
 	if u, ok := user.GetUser(c); ok && u != nil {
-		if u.Role == domain.UserRoleAdmin {
+		if u.Role.IsStaff() {
 			return c.Redirect(http.StatusTemporaryRedirect, domain.PathAdmin)
 		}
 	}
@@ -97,8 +133,12 @@ func (h *AdminHandler) HandleLoginView(c echo.Context) error {
 	return h.renderLoginView(c, "")
 }
 
-// HandleLoginAction processes the access code and promotes the user.
+// HandleLoginAction processes the access code and promotes the user. Outside
+// development the shared code is retired and roles are only granted by an admin.
 func (h *AdminHandler) HandleLoginAction(c echo.Context) error {
+	if h.codePromotionRetired() {
+		return ui.RespondErrorMsg(c, http.StatusForbidden, msgAdminCodeRetired)
+	}
 	code := c.FormValue(domain.FieldCode)
 
 	if code != h.App.Cfg.AdminCode {
@@ -121,13 +161,32 @@ func (h *AdminHandler) HandleLoginAction(c echo.Context) error {
 }
 
 func (h *AdminHandler) renderLoginView(c echo.Context, errMsg string) error {
-	data := map[string]interface{}{}
+	data := map[string]interface{}{"CodeRetired": h.codePromotionRetired()}
 	if errMsg != "" {
 		data["Error"] = errMsg
 	}
 	return c.Render(http.StatusOK, "admin_login.html", data)
 }
 
+// codePromotionRetired reports whether ADMIN_CODE no longer grants the admin role.
+func (h *AdminHandler) codePromotionRetired() bool {
+	return h.App.Cfg.Env == domain.EnvProduction
+}
+
+// takeFlash returns the message left by redirectWithFlash, if any, and clears it.
+func (h *AdminHandler) takeFlash(c echo.Context) interface{} {
+	sess := customMiddleware.GetSession(c)
+	if sess == nil {
+		return nil
+	}
+	flashes := sess.Flashes(domain.FlashMessageKey)
+	if len(flashes) == 0 {
+		return nil
+	}
+	_ = sess.Save(c.Request(), c.Response())
+	return flashes[0]
+}
+
 func (h *AdminHandler) redirectWithFlash(c echo.Context, msg, targetURL string) error {
 	sess := customMiddleware.GetSession(c)
 	if sess != nil {
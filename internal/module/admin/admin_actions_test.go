@@ -101,6 +101,46 @@ func assertFeaturedResponse(t *testing.T, rec *httptest.ResponseRecorder, expect
 	}
 }
 
+func TestAdminHandler_HandleTogglePermanentlyClosed(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+	testutil.SaveTestListing(t, env.App.DB, "123", "Closing Shop")
+
+	toggle := func(closed string) *httptest.ResponseRecorder {
+		formData := url.Values{}
+		formData.Set(domain.FieldClosed, closed)
+		c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodPost, "/admin/listings/123/closed", strings.NewReader(formData.Encode()), "admin_listings.html")
+		c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c.SetParamNames("id")
+		c.SetParamValues("123")
+		_ = h.HandleTogglePermanentlyClosed(c)
+		return rec
+	}
+
+	rec := toggle("true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ag-listing-closed-badge-123")
+	l, _ := env.App.DB.FindByID(context.Background(), "123")
+	assert.True(t, l.PermanentlyClosed)
+
+	page, _ := env.App.DB.Search(context.Background(), domain.ListingQuery{})
+	for _, listed := range page.Listings {
+		assert.NotEqual(t, "123", listed.ID, "a permanently closed listing is hidden")
+	}
+
+	events, _ := env.App.DB.ListListingEvents(context.Background(), "123")
+	if assert.NotEmpty(t, events) {
+		assert.Equal(t, domain.ListingActionCloseForGood, events[len(events)-1].Action)
+	}
+
+	rec = toggle("false")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	l, _ = env.App.DB.FindByID(context.Background(), "123")
+	assert.False(t, l.PermanentlyClosed)
+}
+
 func TestAdminHandler_HandleApproveClaim(t *testing.T) {
 	t.Parallel()
 	env := testutil.SetupTestModuleEnv(t)
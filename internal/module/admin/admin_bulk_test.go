@@ -82,7 +82,8 @@ func TestAdminHandler_HandleBulkUpload_ParseError(t *testing.T) {
 
 	assert.Equal(t, http.StatusFound, rec.Code)
 	// Verify no listing was saved
-	listings, _, _ := env.App.DB.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", true, 10, 0)
+	page, _ := env.App.DB.Search(context.Background(), domain.ListingQuery{IncludeInactive: true, Limit: 10})
+	listings := page.Listings
 	assert.Empty(t, listings)
 }
 
@@ -106,6 +107,7 @@ func TestHandleBulkAction_StatusChanges(t *testing.T) {
 		name         string
 		action       string
 		expectStatus domain.ListingStatus
+		expectNotify bool
 	}{
 		{
 			name:         "Approve",
@@ -116,6 +118,7 @@ func TestHandleBulkAction_StatusChanges(t *testing.T) {
 			name:         "Reject",
 			action:       "reject",
 			expectStatus: domain.ListingStatusRejected,
+			expectNotify: true,
 		},
 	}
 
@@ -124,9 +127,11 @@ func TestHandleBulkAction_StatusChanges(t *testing.T) {
 			t.Parallel()
 			env := testutil.SetupTestModuleEnv(t)
 			defer env.Cleanup()
+			notifications := &testutil.MockNotificationService{}
+			env.App.Notifications = notifications
 			h := admin.NewAdminHandler(env.App)
 
-			_ = env.App.DB.Save(context.Background(), domain.Listing{ID: "l1", Title: "L1", Status: domain.ListingStatusPending})
+			_ = env.App.DB.Save(context.Background(), domain.Listing{ID: "l1", Title: "L1", OwnerID: "owner1", Status: domain.ListingStatusPending})
 
 			form := url.Values{}
 			form.Add("action", tt.action)
@@ -139,6 +144,12 @@ func TestHandleBulkAction_StatusChanges(t *testing.T) {
 				l, _ := env.App.DB.FindByID(context.Background(), "l1")
 				assert.Equal(t, tt.expectStatus, l.Status)
 			}
+			if tt.expectNotify && assert.Len(t, notifications.Sent, 1) {
+				assert.Equal(t, domain.NotificationListingRejected, notifications.Sent[0].Kind)
+				assert.Equal(t, "owner1", notifications.Sent[0].To)
+			} else if !tt.expectNotify {
+				assert.Empty(t, notifications.Sent)
+			}
 		})
 	}
 }
@@ -0,0 +1,69 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/admin"
+	listmod "github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_PendingChanges(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+	ctx := context.Background()
+	repo := env.App.DB
+
+	require.NoError(t, repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Business), Name: "Business", Active: true, RequiresModeration: true}))
+	testutil.SaveTestListing(t, repo, "pc1", "Mama Put")
+	testutil.SaveTestListing(t, repo, "pc2", "Buka Hut")
+
+	owner := &domain.User{ID: "owner-1", Name: "Ada"}
+	submit := func(id, phone string) *domain.PendingChange {
+		l, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		l.ContactPhone = phone
+		held, err := listmod.NewModerationService(repo).Submit(ctx, owner, l)
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		return held
+	}
+	approve, reject := submit("pc1", "555-0100"), submit("pc2", "555-0199")
+
+	c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/modal/moderation", nil, "index.html")
+	require.NoError(t, h.HandleModalModeration(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/admin/changes/"+approve.ID+"/approve")
+	assert.Contains(t, rec.Body.String(), "555-0199")
+
+	c, rec = testutil.SetupAdminContext(http.MethodPost, "/admin/changes/"+approve.ID+"/approve", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(approve.ID)
+	require.NoError(t, h.HandleApproveChange(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, domain.TriggerListingUpdatedPrefix+"pc1", rec.Header().Get(domain.HeaderHXTrigger))
+
+	c, rec = testutil.SetupAdminContext(http.MethodPost, "/admin/changes/"+reject.ID+"/reject", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(reject.ID)
+	require.NoError(t, h.HandleRejectChange(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	approved, _ := repo.FindByID(ctx, "pc1")
+	assert.Equal(t, "555-0100", approved.ContactPhone)
+	rejected, _ := repo.FindByID(ctx, "pc2")
+	assert.Empty(t, rejected.ContactPhone)
+
+	c, rec = testutil.SetupAdminContext(http.MethodPost, "/admin/changes/"+reject.ID+"/approve", nil)
+	c.SetParamNames("id")
+	c.SetParamValues(reject.ID)
+	require.NoError(t, h.HandleApproveChange(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "a resolved change cannot be approved")
+}
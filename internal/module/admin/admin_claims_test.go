@@ -3,13 +3,19 @@ package admin_test
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jadecobra/agbalumo/internal/module/admin"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleApproveClaim(t *testing.T) {
@@ -28,10 +34,63 @@ func TestHandleRejectClaim_Error(t *testing.T) {
 	testClaimAction(t, "reject", "", "", http.StatusNotFound)
 }
 
+func TestHandleRejectClaim_RequiresReason(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+	require.NoError(t, env.App.DB.SaveClaimRequest(context.Background(), domain.ClaimRequest{
+		ID: "claim1", UserID: "u1", ListingID: "l1", Status: domain.ClaimStatusPending,
+	}))
+
+	c, rec := claimFormContext("/admin/claims/claim1/reject", url.Values{"reason": {"  "}})
+	c.SetParamNames("id")
+	c.SetParamValues("claim1")
+	require.NoError(t, h.HandleRejectClaim(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	claim, err := env.App.DB.GetClaimRequest(context.Background(), "claim1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClaimStatusPending, claim.Status)
+}
+
+func TestHandleModalModeration_ScoresClaims(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+	ctx := context.Background()
+
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Mama Put", func(l *domain.Listing) {
+		l.OwnerID = ""
+		l.WebsiteURL = "https://www.mamaput.example"
+	})
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "claim1", UserID: "u1", ListingID: "l1", ListingTitle: "Mama Put", UserEmail: "ada@mamaput.example",
+		DocumentURL: "/static/uploads/claim-doc.webp", Status: domain.ClaimStatusPending, CreatedAt: time.Now(),
+	}))
+
+	c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/modal/moderation", nil, "index.html")
+	require.NoError(t, h.HandleModalModeration(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "score 50")
+	assert.Contains(t, body, "/static/uploads/claim-doc.webp")
+	assert.Contains(t, body, `name="reason"`)
+}
+
+func claimFormContext(path string, form url.Values) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := testutil.SetupAdminContext(http.MethodPost, path, strings.NewReader(form.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	return c, rec
+}
+
 func testClaimAction(t *testing.T, action string, initialStatus, expectedStatus domain.ClaimStatus, expectedCode int) {
 	t.Helper()
 	env := testutil.SetupTestModuleEnv(t)
 	defer env.Cleanup()
+	notifications := &testutil.MockNotificationService{}
+	env.App.Notifications = notifications
 	h := admin.NewAdminHandler(env.App)
 
 	claimID := "claim1"
@@ -43,11 +102,16 @@ func testClaimAction(t *testing.T, action string, initialStatus, expectedStatus
 			ID:        claimID,
 			UserID:    "u1",
 			ListingID: "l1",
+			UserEmail: "ada@example.com",
 			Status:    initialStatus,
 		})
 	}
 
-	c, rec := testutil.SetupAdminContext(http.MethodPost, "/admin/claims/"+claimID+"/"+action, nil)
+	form := url.Values{"notes": {"Called the shop"}}
+	if action == "reject" {
+		form.Set("reason", "The listing's phone number did not confirm the claim")
+	}
+	c, rec := claimFormContext("/admin/claims/"+claimID+"/"+action, form)
 	c.SetParamNames("id")
 	c.SetParamValues(claimID)
 
@@ -66,5 +130,19 @@ func testClaimAction(t *testing.T, action string, initialStatus, expectedStatus
 		claim, err := env.App.DB.GetClaimRequestByUserAndListing(context.Background(), "u1", "l1")
 		assert.NoError(t, err)
 		assert.Equal(t, expectedStatus, claim.Status)
+		assert.Equal(t, "Called the shop", claim.ReviewerNotes)
+		assert.False(t, claim.ReviewedAt.IsZero())
+
+		expectedKind := domain.NotificationClaimRejected
+		if expectedStatus == domain.ClaimStatusApproved {
+			expectedKind = domain.NotificationClaimApproved
+		}
+		if assert.Len(t, notifications.Sent, 1) {
+			assert.Equal(t, expectedKind, notifications.Sent[0].Kind)
+			assert.Equal(t, "ada@example.com", notifications.Sent[0].To)
+			assert.Equal(t, claim.RejectionReason, notifications.Sent[0].Data["RejectionReason"])
+		}
+	} else {
+		assert.Empty(t, notifications.Sent)
 	}
 }
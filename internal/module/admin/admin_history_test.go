@@ -0,0 +1,72 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/admin"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_MutationsAppearInHistory(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+	ctx := context.Background()
+	testutil.SaveTestListing(t, env.App.DB, "h1", "Mama Put", func(l *domain.Listing) {
+		l.Status = domain.ListingStatusPending
+	})
+
+	form := url.Values{}
+	form.Set(domain.FieldAction, "approve")
+	form.Add(domain.ParamListingIDs, "h1")
+	c, _ := testutil.SetupAdminContext(http.MethodPost, "/admin/listings/bulk", strings.NewReader(form.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	require.NoError(t, h.HandleBulkAction(c))
+
+	c, _ = testutil.SetupAdminContext(http.MethodPost, "/admin/listings/h1/featured", strings.NewReader("featured=true"))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.SetParamNames("id")
+	c.SetParamValues("h1")
+	require.NoError(t, h.HandleToggleFeatured(c))
+
+	events, err := env.App.DB.ListListingEvents(ctx, "h1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, domain.ListingActionApprove, events[0].Action)
+	assert.Equal(t, "admin1", events[0].ActorID)
+	assert.Equal(t, domain.ListingActionFeature, events[1].Action)
+
+	c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/listings/h1/history", nil, "admin_listing_history.html")
+	c.SetParamNames("id")
+	c.SetParamValues("h1")
+	require.NoError(t, h.HandleListingHistory(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Mama Put")
+	assert.Less(t, strings.Index(body, events[1].ID), strings.Index(body, events[0].ID), "newest event first")
+	assert.Contains(t, body, "Pending")
+}
+
+func TestAdminHandler_HandleListingHistory_NotFound(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+
+	c, rec := testutil.SetupAdminContext(http.MethodGet, "/admin/listings/nope/history", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("nope")
+	_ = h.HandleListingHistory(c)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -79,7 +79,7 @@ func TestAdminHandler_HandleLoginAction(t *testing.T) {
 			adminCode:  "correct",
 			user:       nil,
 			expectCode: http.StatusTemporaryRedirect,
-			expectLoc:  "/auth/google/login",
+			expectLoc:  "/auth/login",
 		},
 		{
 			name:       "ValidCode_PromotesUser",
@@ -149,3 +149,24 @@ func runSingleAdminLoginActionTest(t *testing.T, tt loginActionTest) {
 		assert.Equal(t, domain.UserRoleAdmin, updatedUser.Role)
 	}
 }
+
+func TestAdminHandler_HandleLoginAction_RetiredInProduction(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	env.App.Cfg.Env = domain.EnvProduction
+	u := domain.User{ID: "u1", Email: "test@example.com", Role: domain.UserRoleUser}
+	assert.NoError(t, env.App.DB.SaveUser(context.Background(), u))
+
+	formData := url.Values{"code": {env.App.Cfg.AdminCode}}
+	c, rec := testutil.SetupModuleContext(http.MethodPost, "/admin/login", strings.NewReader(formData.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.Set(domain.CtxKeyUser, &u)
+
+	_ = admin.NewAdminHandler(env.App).HandleLoginAction(c)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	saved, err := env.App.DB.FindUserByID(context.Background(), u.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.UserRoleUser, saved.Role, "the shared code no longer promotes in production")
+}
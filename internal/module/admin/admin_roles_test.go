@@ -0,0 +1,202 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/admin"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roleAuthMiddleware signs every request in as a user with the given role.
+type roleAuthMiddleware struct {
+	role domain.UserRole
+}
+
+func (m roleAuthMiddleware) OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set(domain.CtxKeyUser, &domain.User{ID: "staff1", Role: m.role})
+		return next(c)
+	}
+}
+
+func (m roleAuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return m.OptionalAuth(next)
+}
+
+func TestAdminHandler_RoutePermissions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		role    domain.UserRole
+		method  string
+		path    string
+		allowed bool
+	}{
+		{domain.UserRoleAnalyst, http.MethodGet, "/admin/listings/export", true},
+		{domain.UserRoleAnalyst, http.MethodPost, "/admin/listings/l1/featured", false},
+		{domain.UserRoleModerator, http.MethodGet, "/admin/listings/export", false},
+		{domain.UserRoleModerator, http.MethodPost, "/admin/changes/none/reject", true},
+		{domain.UserRoleModerator, http.MethodPost, "/admin/listings/delete", false},
+		{domain.UserRoleCategoryCurator, http.MethodPost, "/admin/categories", true},
+		{domain.UserRoleCategoryCurator, http.MethodGet, "/admin/listings", false},
+		{domain.UserRoleModerator, http.MethodPost, "/admin/users/u1/role", false},
+		{domain.UserRoleModerator, http.MethodPost, "/admin/users/u1/logout", false},
+		{domain.UserRoleModerator, http.MethodPost, "/admin/users/u1/status", false},
+		{domain.UserRoleAnalyst, http.MethodGet, "/admin/users/u1", false},
+		{domain.UserRoleAdmin, http.MethodGet, "/admin/listings/export", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+tt.path, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			e := echo.New()
+			e.Renderer = &testutil.TestRenderer{Templates: testutil.NewMainTemplate()}
+			admin.NewAdminHandler(env.App).RegisterRoutes(e, roleAuthMiddleware{role: tt.role})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if tt.allowed {
+				assert.NotEqual(t, http.StatusForbidden, rec.Code)
+			} else {
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAdminMiddleware_NonStaffRedirected(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/admin", nil)
+	c.Set(domain.CtxKeyUser, &domain.User{ID: "u1", Role: domain.UserRoleUser})
+
+	err := admin.NewAdminHandler(env.App).AdminMiddleware(func(c echo.Context) error {
+		t.Fatal("a user without a staff role reached the dashboard")
+		return nil
+	})(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	assert.Equal(t, "/admin/login", rec.Header().Get("Location"))
+}
+
+func assignRole(t *testing.T, h *admin.AdminHandler, actor domain.User, targetID, role string) *httptest.ResponseRecorder {
+	t.Helper()
+	form := url.Values{domain.FieldRole: {role}}
+	c, rec := testutil.SetupTestContextWithSession(http.MethodPost, "/admin/users/"+targetID+"/role", strings.NewReader(form.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.SetParamNames(domain.ParamID)
+	c.SetParamValues(targetID)
+	c.Set(domain.CtxKeyUser, &actor)
+	require.NoError(t, h.HandleAssignRole(c))
+	return rec
+}
+
+func TestAdminHandler_HandleAssignRole(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	h := admin.NewAdminHandler(env.App)
+	adminUser := domain.User{ID: "admin1", GoogleID: "g-admin", Role: domain.UserRoleAdmin}
+	require.NoError(t, env.App.DB.SaveUser(ctx, adminUser))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Ada", Role: domain.UserRoleUser}))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "root", GoogleID: "g-root", Role: domain.UserRoleSuperAdmin}))
+
+	rec := assignRole(t, h, adminUser, "u1", "moderator")
+	assert.Equal(t, http.StatusFound, rec.Code)
+	u, err := env.App.DB.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserRoleModerator, u.Role)
+
+	assert.Equal(t, http.StatusBadRequest, assignRole(t, h, adminUser, "u1", "root").Code)
+	assert.Equal(t, http.StatusNotFound, assignRole(t, h, adminUser, "missing", "Analyst").Code)
+	assert.Equal(t, http.StatusForbidden, assignRole(t, h, adminUser, "u1", "Admin").Code, "only super admins grant admin")
+	assert.Equal(t, http.StatusForbidden, assignRole(t, h, adminUser, "root", "User").Code, "admins cannot demote super admins")
+	assert.Equal(t, http.StatusForbidden, assignRole(t, h, adminUser, "admin1", "User").Code, "nobody changes their own role")
+
+	rec = assignRole(t, h, domain.User{ID: "root", Role: domain.UserRoleSuperAdmin}, "u1", "Admin")
+	assert.Equal(t, http.StatusFound, rec.Code)
+	u, _ = env.App.DB.FindUserByID(ctx, "u1")
+	assert.Equal(t, domain.UserRoleAdmin, u.Role)
+}
+
+func TestAdminHandler_HandleModalUsers_RolePicker(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Ada", Role: domain.UserRoleAnalyst}))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "root", GoogleID: "g-root", Name: "Root", Role: domain.UserRoleSuperAdmin}))
+	h := admin.NewAdminHandler(env.App)
+
+	c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/modal/users", nil, "components/admin_modal_users.html")
+	require.NoError(t, h.HandleModalUsers(c))
+	body := rec.Body.String()
+	assert.Contains(t, body, `data-testid="ag-user-role-u1"`)
+	assert.Contains(t, body, `<option value="Analyst" selected>`)
+	assert.NotContains(t, body, `<option value="Admin"`, "admins cannot grant admin")
+	assert.NotContains(t, body, `data-testid="ag-user-role-root"`, "admins cannot change a super admin")
+	assert.Contains(t, body, `data-testid="ag-user-logout-u1"`)
+	assert.NotContains(t, body, `data-testid="ag-user-logout-root"`, "admins cannot sign a super admin out")
+
+	c, rec = testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/modal/users", nil, "components/admin_modal_users.html")
+	c.Set(domain.CtxKeyUser, &domain.User{ID: "staff1", Role: domain.UserRoleModerator})
+	require.NoError(t, h.HandleModalUsers(c))
+	assert.NotContains(t, rec.Body.String(), `data-testid="ag-user-role-`)
+	assert.NotContains(t, rec.Body.String(), `data-testid="ag-user-logout-`)
+}
+
+func TestAdminHandler_HandleForceLogout(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Ada"}))
+	now := time.Now()
+	for _, id := range []string{"laptop", "phone"} {
+		require.NoError(t, env.App.DB.SaveSession(ctx, domain.Session{ID: id, UserID: "u1", Data: []byte{}, CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}))
+	}
+	h := admin.NewAdminHandler(env.App)
+
+	logout := func(actor domain.User, id string) *httptest.ResponseRecorder {
+		c, rec := testutil.SetupTestContextWithSession(http.MethodPost, "/admin/users/"+id+"/logout", nil)
+		c.Set(domain.CtxKeyUser, actor)
+		c.SetParamNames(domain.ParamID)
+		c.SetParamValues(id)
+		require.NoError(t, h.HandleForceLogout(c))
+		return rec
+	}
+	adminUser := domain.User{ID: "admin1", Role: domain.UserRoleAdmin}
+
+	assert.Equal(t, http.StatusFound, logout(adminUser, "u1").Code)
+	sessions, err := env.App.DB.ListUserSessions(ctx, "u1", now)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.Equal(t, http.StatusNotFound, logout(adminUser, "missing").Code)
+
+	t.Run("Forbidden", func(t *testing.T) {
+		require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "root", GoogleID: "g-root", Name: "Root", Role: domain.UserRoleSuperAdmin}))
+		require.NoError(t, env.App.DB.SaveSession(ctx, domain.Session{ID: "root-laptop", UserID: "root", Data: []byte{}, CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+		assert.Equal(t, http.StatusForbidden, logout(adminUser, "root").Code, "admins cannot sign a super admin out")
+		assert.Equal(t, http.StatusForbidden, logout(domain.User{ID: "mod1", Role: domain.UserRoleModerator}, "root").Code)
+		assert.Equal(t, http.StatusForbidden, logout(domain.User{ID: "root", Role: domain.UserRoleSuperAdmin}, "root").Code, "nobody signs themselves out here")
+		sessions, err = env.App.DB.ListUserSessions(ctx, "root", now)
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+	})
+}
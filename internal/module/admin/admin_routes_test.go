@@ -40,6 +40,10 @@ func TestAdminHandler_RegisterRoutes(t *testing.T) {
 		"/admin/login*":                  http.MethodPost, // Note: the POST route is on the subgroup without trailing slash, Echo might represent it differently or as /admin/login
 		"/admin":                         http.MethodGet,
 		"/admin/users":                   http.MethodGet,
+		"/admin/users/:id":               http.MethodGet,
+		"/admin/users/:id/role":          http.MethodPost,
+		"/admin/users/:id/logout":        http.MethodPost,
+		"/admin/users/:id/status":        http.MethodPost,
 		"/admin/listings":                http.MethodGet,
 		"/admin/claims/:id/approve":      http.MethodPost,
 		"/admin/claims/:id/reject":       http.MethodPost,
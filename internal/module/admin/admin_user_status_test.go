@@ -0,0 +1,203 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/admin"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setUserStatus(t *testing.T, h *admin.AdminHandler, targetID string, form url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+	c, rec := testutil.SetupAdminContext(http.MethodPost, "/admin/users/"+targetID+"/status", strings.NewReader(form.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.SetParamNames(domain.ParamID)
+	c.SetParamValues(targetID)
+	require.NoError(t, h.HandleSetUserStatus(c))
+	return rec
+}
+
+func TestAdminHandler_HandleSetUserStatus_SuspendAndDeactivate(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	h := admin.NewAdminHandler(env.App)
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Spammer"}))
+	owned := func(l *domain.Listing) { l.OwnerID = "u1" }
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Cheap Pills", owned)
+	testutil.SaveTestListing(t, env.App.DB, "l2", "Cheaper Pills", owned)
+	testutil.SaveTestListing(t, env.App.DB, "l3", "Old Pills", owned, func(l *domain.Listing) { l.IsActive = false })
+	testutil.SaveTestListing(t, env.App.DB, "l4", "Mama Put")
+	now := time.Now()
+	require.NoError(t, env.App.DB.SaveSession(ctx, domain.Session{ID: "s1", UserID: "u1", Data: []byte{}, CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	until := now.AddDate(0, 0, 7).Format(time.DateOnly)
+	rec := setUserStatus(t, h, "u1", url.Values{
+		domain.FieldStatus:             {"suspended"},
+		domain.FieldSuspendedUntil:     {until},
+		domain.FieldStatusReason:       {"  posting spam  "},
+		domain.FieldDeactivateListings: {"true"},
+	})
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/admin/users/u1", rec.Header().Get("Location"))
+
+	u, err := env.App.DB.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusSuspended, u.Status)
+	assert.Equal(t, until, u.SuspendedUntil.Format(time.DateOnly))
+	assert.Equal(t, "posting spam", u.StatusReason)
+
+	for id, active := range map[string]bool{"l1": false, "l2": false, "l3": false, "l4": true} {
+		l, findErr := env.App.DB.FindByID(ctx, id)
+		require.NoError(t, findErr)
+		assert.Equal(t, active, l.IsActive, id)
+	}
+	history, err := env.App.DB.ListListingEvents(ctx, "l1")
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	last := history[len(history)-1]
+	assert.Equal(t, domain.ListingActionDeactivate, last.Action)
+	assert.Equal(t, "posting spam", last.Reason)
+	history, _ = env.App.DB.ListListingEvents(ctx, "l3")
+	assert.Empty(t, history, "inactive listings are left alone")
+
+	events, err := env.App.DB.ListUserEvents(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.UserActionSuspend, events[0].Action)
+	assert.Equal(t, "admin1", events[0].ActorID)
+	assert.Equal(t, 2, events[0].ListingsDeactivated)
+
+	sessions, err := env.App.DB.ListUserSessions(ctx, "u1", now)
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "restricting a user signs them out")
+
+	rec = setUserStatus(t, h, "u1", url.Values{domain.FieldStatus: {"active"}})
+	assert.Equal(t, http.StatusFound, rec.Code, "reinstating needs no reason")
+	u, _ = env.App.DB.FindUserByID(ctx, "u1")
+	assert.Equal(t, domain.UserStatusActive, u.Status)
+	events, _ = env.App.DB.ListUserEvents(ctx, "u1")
+	assert.Equal(t, domain.UserActionReinstate, events[0].Action)
+}
+
+func TestAdminHandler_HandleSetUserStatus_Shadow(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	h := admin.NewAdminHandler(env.App)
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Spammer"}))
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Cheap Pills", func(l *domain.Listing) { l.OwnerID = "u1" })
+	now := time.Now()
+	require.NoError(t, env.App.DB.SaveSession(ctx, domain.Session{ID: "s1", UserID: "u1", Data: []byte{}, CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	rec := setUserStatus(t, h, "u1", url.Values{
+		domain.FieldStatus:             {"shadowed"},
+		domain.FieldStatusReason:       {"posting spam"},
+		domain.FieldDeactivateListings: {"true"},
+	})
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	u, err := env.App.DB.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusShadowed, u.Status)
+	assert.NoError(t, u.CheckAccess(now), "a shadowed user can still sign in")
+
+	sessions, err := env.App.DB.ListUserSessions(ctx, "u1", now)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1, "shadowing leaves the user signed in")
+	l, err := env.App.DB.FindByID(ctx, "l1")
+	require.NoError(t, err)
+	assert.True(t, l.IsActive, "shadowing never deactivates listings")
+
+	events, err := env.App.DB.ListUserEvents(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.UserActionShadow, events[0].Action)
+	assert.Zero(t, events[0].ListingsDeactivated)
+}
+
+func TestAdminHandler_HandleSetUserStatus_Rejects(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	h := admin.NewAdminHandler(env.App)
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1"}))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "admin1", GoogleID: "g-admin1", Role: domain.UserRoleAdmin}))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "admin2", GoogleID: "g-admin2", Role: domain.UserRoleAdmin}))
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+
+	tests := []struct {
+		form   url.Values
+		name   string
+		target string
+		code   int
+	}{
+		{url.Values{domain.FieldStatus: {"deleted"}, domain.FieldStatusReason: {"x"}}, "UnknownStatus", "u1", http.StatusBadRequest},
+		{url.Values{domain.FieldStatus: {"banned"}}, "NoReason", "u1", http.StatusBadRequest},
+		{url.Values{domain.FieldStatus: {"suspended"}, domain.FieldStatusReason: {"x"}}, "NoEnd", "u1", http.StatusBadRequest},
+		{url.Values{domain.FieldStatus: {"suspended"}, domain.FieldStatusReason: {"x"}, domain.FieldSuspendedUntil: {"2020-01-01"}}, "EndInPast", "u1", http.StatusBadRequest},
+		{url.Values{domain.FieldStatus: {"banned"}, domain.FieldStatusReason: {"x"}}, "MissingUser", "missing", http.StatusNotFound},
+		{url.Values{domain.FieldStatus: {"banned"}, domain.FieldStatusReason: {"x"}}, "Self", "admin1", http.StatusForbidden},
+		{url.Values{domain.FieldStatus: {"suspended"}, domain.FieldStatusReason: {"x"}, domain.FieldSuspendedUntil: {tomorrow}}, "OtherAdmin", "admin2", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, setUserStatus(t, h, tt.target, tt.form).Code, tt.name)
+	}
+
+	u, err := env.App.DB.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusActive, u.Status)
+	events, err := env.App.DB.ListUserEvents(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestAdminHandler_HandleUserDetail(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	h := admin.NewAdminHandler(env.App)
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g-u1", Name: "Ada"}))
+	require.NoError(t, env.App.DB.SaveUser(ctx, domain.User{ID: "admin1", GoogleID: "g-admin1", Role: domain.UserRoleAdmin}))
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Ada's Kitchen", func(l *domain.Listing) { l.OwnerID = "u1" })
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "cr1", UserID: "u1", ListingID: "l2", ListingTitle: "Mama Put", Status: domain.ClaimStatusPending, CreatedAt: time.Now(),
+	}))
+	require.NoError(t, env.App.DB.SaveFeedback(ctx, domain.Feedback{ID: "f1", UserID: "u1", Type: domain.FeedbackTypeIssue, Content: "Map is slow", CreatedAt: time.Now()}))
+	require.NoError(t, env.App.DB.SetUserStatus(ctx, "u1", domain.UserStatusBanned, time.Time{}, "fake reviews"))
+	require.NoError(t, env.App.DB.AppendUserEvent(ctx, domain.UserEvent{
+		ID: "e1", UserID: "u1", ActorID: "admin1", Action: domain.UserActionBan, Reason: "fake reviews", CreatedAt: time.Now(),
+	}))
+
+	render := func(id string) *httptest.ResponseRecorder {
+		c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/users/"+id, nil, "admin_user_detail.html")
+		c.SetParamNames(domain.ParamID)
+		c.SetParamValues(id)
+		require.NoError(t, h.HandleUserDetail(c))
+		return rec
+	}
+
+	rec := render("u1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	for _, want := range []string{"Ada&#39;s Kitchen", "Mama Put", "Map is slow", "Reason: fake reviews", `data-testid="ag-user-event-e1"`, `data-testid="ag-user-status-form"`} {
+		assert.Contains(t, body, want)
+	}
+
+	assert.NotContains(t, render("admin1").Body.String(), `data-testid="ag-user-status-form"`, "staff cannot restrict themselves")
+	assert.Equal(t, http.StatusNotFound, render("missing").Code)
+}
@@ -2,6 +2,8 @@ package admin_test
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/jadecobra/agbalumo/internal/module/admin"
@@ -29,3 +31,30 @@ func TestAdminHandler_HandleUsers_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestAdminHandler_HandleUsers_RoleBadges(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := admin.NewAdminHandler(env.App)
+
+	c, rec := testutil.SetupAdminIntegrationContext(t, http.MethodGet, "/admin/users", nil, "admin_users.html")
+	for _, u := range []domain.User{
+		{ID: "super", Name: "Super", Email: "super@test.com", Role: domain.UserRoleSuperAdmin},
+		{ID: "mod", Name: "Mod", Email: "mod@test.com", Role: domain.UserRoleModerator},
+		{ID: "plain", Name: "Plain", Email: "plain@test.com", Role: domain.UserRoleUser},
+	} {
+		require.NoError(t, env.App.DB.SaveUser(c.Request().Context(), u))
+	}
+
+	require.NoError(t, h.HandleUsers(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	for id, want := range map[string]string{"super": "SuperAdmin", "mod": "Moderator", "plain": "User"} {
+		badge := regexp.MustCompile(`data-testid="ag-user-role-badge-` + id + `"\s+class="([^"]*)">\s*(\w+)`).FindStringSubmatch(body)
+		require.NotNil(t, badge, id)
+		assert.Equal(t, want, badge[2])
+		assert.Equal(t, id != "plain", strings.Contains(badge[1], "text-purple-400"), "%s is staff", id)
+	}
+}
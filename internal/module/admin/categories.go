@@ -25,14 +25,16 @@ func (h *AdminHandler) HandleAddCategory(c echo.Context) error {
 	}
 
 	claimable := c.FormValue(domain.FieldClaimable) == "true"
+	moderated := c.FormValue(domain.FieldModerated) == "true"
 	now := time.Now()
 	cat := domain.CategoryData{
-		ID:        strings.ToLower(strings.ReplaceAll(name, " ", "-")),
-		Name:      name,
-		Claimable: claimable,
-		Active:    true,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:                 strings.ToLower(strings.ReplaceAll(name, " ", "-")),
+		Name:               name,
+		Claimable:          claimable,
+		RequiresModeration: moderated,
+		Active:             true,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
 	if err := h.App.DB.SaveCategory(ctx, cat); err != nil {
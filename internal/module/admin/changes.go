@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const errPendingChangeNotFound = "Pending change not found"
+
+// HandleApproveChange publishes a listing change held for moderation.
+func (h *AdminHandler) HandleApproveChange(c echo.Context) error {
+	l, err := listing.NewModerationService(h.App.DB).Approve(c.Request().Context(), listing.RequestActor(c), c.Param("id"))
+	if err != nil {
+		return h.respondChangeError(c, err)
+	}
+	c.Response().Header().Add(domain.HeaderHXTrigger, fmt.Sprintf("%s%s", domain.TriggerListingUpdatedPrefix, l.ID))
+	return c.NoContent(http.StatusOK)
+}
+
+// HandleRejectChange discards a listing change held for moderation.
+func (h *AdminHandler) HandleRejectChange(c echo.Context) error {
+	if err := listing.NewModerationService(h.App.DB).Reject(c.Request().Context(), listing.RequestActor(c), c.Param("id")); err != nil {
+		return h.respondChangeError(c, err)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *AdminHandler) respondChangeError(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrPendingChangeNotFound) {
+		return c.String(http.StatusNotFound, errPendingChangeNotFound)
+	}
+	h.LogError(c, "admin: failed to resolve pending change", err)
+	return ui.RespondError(c, err)
+}
@@ -1,9 +1,12 @@
 package admin
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/labstack/echo/v4"
 )
 
@@ -14,7 +17,8 @@ func (h *AdminHandler) HandleApproveClaim(c echo.Context) error {
 	return h.handleClaimAction(c, domain.ClaimStatusApproved)
 }
 
-// HandleRejectClaim rejects a user's claim request.
+// HandleRejectClaim rejects a user's claim request. The form must carry a reason,
+// which is shown to the claimant.
 func (h *AdminHandler) HandleRejectClaim(c echo.Context) error {
 	return h.handleClaimAction(c, domain.ClaimStatusRejected)
 }
@@ -23,9 +27,48 @@ func (h *AdminHandler) handleClaimAction(c echo.Context, status domain.ClaimStat
 	id := c.Param("id")
 	ctx := c.Request().Context()
 
-	if err := h.App.DB.UpdateClaimRequestStatus(ctx, id, status); err != nil {
+	cr, err := listing.NewAuditService(h.App.DB).ResolveClaim(ctx, listing.RequestActor(c), id, domain.ClaimReview{
+		Status:          status,
+		Notes:           c.FormValue(domain.FieldReviewerNotes),
+		RejectionReason: c.FormValue(domain.FieldRejectionReason),
+	})
+	if errors.Is(err, domain.ErrRejectionReasonRequired) {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
 		return c.String(http.StatusNotFound, errClaimRequestNotFound)
 	}
 
+	kind := domain.NotificationClaimRejected
+	if status == domain.ClaimStatusApproved {
+		kind = domain.NotificationClaimApproved
+	}
+	h.Notify(c, func(ctx context.Context, n domain.NotificationService) error {
+		return n.Notify(ctx, kind, cr.UserEmail, map[string]interface{}{
+			"UserName":        cr.UserName,
+			"ListingID":       cr.ListingID,
+			"ListingTitle":    cr.ListingTitle,
+			"RejectionReason": cr.RejectionReason,
+			"ResubmitDate":    cr.ResubmittableAt().Format("January 2, 2006"),
+		})
+	})
+
 	return c.NoContent(http.StatusOK)
 }
+
+// claimReviewItem is a pending claim with the listing it targets and its automatic score.
+type claimReviewItem struct {
+	domain.ClaimRequest
+	Listing domain.Listing
+	Score   domain.ClaimScore
+}
+
+func (h *AdminHandler) claimReviewItems(ctx context.Context, claims []domain.ClaimRequest) []claimReviewItem {
+	items := make([]claimReviewItem, 0, len(claims))
+	for _, cr := range claims {
+		// A deleted listing scores only on the claimant's own evidence.
+		l, _ := h.App.DB.FindByID(ctx, cr.ListingID)
+		items = append(items, claimReviewItem{ClaimRequest: cr, Listing: l, Score: domain.ScoreClaim(cr, l)})
+	}
+	return items
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
-	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
 	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
@@ -32,16 +31,6 @@ func (h *AdminHandler) HandleDashboard(c echo.Context) error {
 		return ui.RespondError(c, err)
 	}
 
-	// Get Flash Messages
-	sess := customMiddleware.GetSession(c)
-	var flashMsg interface{}
-	if sess != nil {
-		if flashes := sess.Flashes(domain.FlashMessageKey); len(flashes) > 0 {
-			flashMsg = flashes[0]
-			_ = sess.Save(c.Request(), c.Response())
-		}
-	}
-
 	return c.Render(http.StatusOK, "admin_dashboard.html", map[string]interface{}{
 		"ClaimRequests":  data.ClaimRequests,
 		"UserCount":      data.UserCount,
@@ -51,7 +40,7 @@ func (h *AdminHandler) HandleDashboard(c echo.Context) error {
 		"Feedbacks":      data.Feedbacks,
 		"User":           c.Get(domain.CtxKeyUser),
 
-		"FlashMessage":    flashMsg,
+		"FlashMessage":    h.takeFlash(c),
 		"ListingCount":    data.ListingCount,
 		"Categories":      data.Categories,
 		"Users":           data.Users,
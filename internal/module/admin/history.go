@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const tmplListingHistory = "admin_listing_history.html"
+
+// HandleListingHistory renders the audit timeline of a listing, newest event first.
+// Deleted listings keep their history, so the page is only a 404 when neither the
+// listing nor any event for it exists.
+func (h *AdminHandler) HandleListingHistory(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	events, err := listing.NewAuditService(h.App.DB).History(ctx, id)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+
+	current, findErr := h.App.DB.FindByID(ctx, id)
+	if findErr != nil && len(events) == 0 {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
+	}
+
+	timeline := make([]domain.ListingEvent, len(events))
+	for i, e := range events {
+		timeline[len(events)-1-i] = e
+	}
+
+	return c.Render(http.StatusOK, tmplListingHistory, map[string]interface{}{
+		"ListingID": id,
+		"Listing":   current,
+		"Deleted":   findErr != nil,
+		"Events":    timeline,
+		"User":      c.Get(domain.CtxKeyUser),
+	})
+}
@@ -26,10 +26,19 @@ func (h *AdminHandler) HandleAllListings(c echo.Context) error {
 	queryText := strings.TrimSpace(c.QueryParam(domain.ParamQuery))
 
 	// Fetch all listings with the given category filter, including inactive ones.
-	listings, totalCountRows, err := h.App.DB.FindAll(ctx, category, queryText, "", 0, 0, 0, sortField, sortOrder, true, pagination.Limit, pagination.Offset)
+	result, err := h.App.DB.Search(ctx, domain.ListingQuery{
+		Types:           domain.TypeFilter(category),
+		QueryText:       queryText,
+		Sort:            sortField,
+		Order:           sortOrder,
+		IncludeInactive: true,
+		Limit:           pagination.Limit,
+		Offset:          pagination.Offset,
+	})
 	if err != nil {
 		return ui.RespondError(c, err)
 	}
+	listings, totalCountRows := result.Listings, result.TotalCount
 
 	hasNextPage := pagination.Offset+len(listings) < totalCountRows
 
@@ -56,7 +65,7 @@ func (h *AdminHandler) HandleAllListings(c echo.Context) error {
 		"QueryText":  queryText,
 		"Counts":     strCounts,
 		"Categories": categories,
-		"TotalCount": totalCountRows, // Use totalCountRows from FindAll for consistent count
+		"TotalCount": totalCountRows, // Use totalCountRows from Search for consistent count
 		"User":       c.Get(domain.CtxKeyUser),
 	})
 }
@@ -83,18 +92,36 @@ func (h *AdminHandler) HandleToggleFeatured(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
-	listing, err := h.App.DB.FindByID(ctx, id)
+	current, err := h.App.DB.FindByID(ctx, id)
 	if err != nil {
 		return ui.RespondError(c, err)
 	}
 
 	if featured {
-		if err := h.validateFeaturedLimit(ctx, listing); err != nil {
+		if err := h.validateFeaturedLimit(ctx, current); err != nil {
 			return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
 		}
 	}
 
-	if err := h.App.DB.SetFeatured(ctx, id, featured); err != nil {
+	if err := listing.NewAuditService(h.App.DB).SetFeatured(ctx, listing.RequestActor(c), id, featured); err != nil {
+		return ui.RespondError(c, err)
+	}
+
+	updatedListing, _ := h.App.DB.FindByID(ctx, id)
+	return h.renderListingRow(c, updatedListing)
+}
+
+// HandleTogglePermanentlyClosed marks a listing as permanently closed, hiding it from
+// the directory, or reopens it.
+func (h *AdminHandler) HandleTogglePermanentlyClosed(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return ui.RespondJSONError(c, http.StatusBadRequest, "Listing ID is required")
+	}
+	closed := c.FormValue(domain.FieldClosed) == "true"
+
+	ctx := c.Request().Context()
+	if err := listing.NewAuditService(h.App.DB).SetPermanentlyClosed(ctx, listing.RequestActor(c), id, closed); err != nil {
 		return ui.RespondError(c, err)
 	}
 
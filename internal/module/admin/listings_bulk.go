@@ -7,6 +7,7 @@ import (
 	"net/url"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/labstack/echo/v4"
 )
 
@@ -14,7 +15,6 @@ import (
 func (h *AdminHandler) HandleBulkAction(c echo.Context) error {
 	action := c.FormValue(domain.FieldAction)
 	selectedIDs := c.Request().PostForm[domain.ParamListingIDs]
-	ctx := c.Request().Context()
 
 	if len(selectedIDs) == 0 {
 		return h.redirectWithFlash(c, "No listings selected", domain.PathAdminListings)
@@ -25,7 +25,7 @@ func (h *AdminHandler) HandleBulkAction(c echo.Context) error {
 	}
 
 	newCategory := c.FormValue(domain.FieldNewCategory)
-	successCount := h.processBulkListings(ctx, selectedIDs, action, newCategory)
+	successCount := h.processBulkListings(c, selectedIDs, action, newCategory)
 
 	return h.redirectWithFlash(c, fmt.Sprintf("Successfully processed %d listings", successCount), domain.PathAdminListings)
 }
@@ -38,42 +38,55 @@ func (h *AdminHandler) redirectToBulkDeleteConfirm(c echo.Context, ids []string)
 	return c.Redirect(http.StatusFound, domain.PathAdminListings+"/delete-confirm?"+query.Encode())
 }
 
-func (h *AdminHandler) processBulkListings(ctx context.Context, ids []string, action, newCategory string) int {
+func (h *AdminHandler) processBulkListings(c echo.Context, ids []string, action, newCategory string) int {
+	ctx := c.Request().Context()
+	actor := listing.RequestActor(c)
+	audit := listing.NewAuditService(h.App.DB)
 	successCount := 0
 	for _, id := range ids {
-		if err := h.applyActionToListing(ctx, id, action, newCategory); err == nil {
-			successCount++
+		l, err := h.applyActionToListing(ctx, audit, actor, id, action, newCategory)
+		if err != nil {
+			continue
+		}
+		successCount++
+		if domain.ListingAction(action) == domain.ListingActionReject {
+			h.Notify(c, func(ctx context.Context, n domain.NotificationService) error {
+				return n.NotifyUser(ctx, domain.NotificationListingRejected, l.OwnerID, map[string]interface{}{
+					"ListingID":    l.ID,
+					"ListingTitle": l.Title,
+				})
+			})
 		}
 	}
 	return successCount
 }
 
-func (h *AdminHandler) applyActionToListing(ctx context.Context, id, action, newCategory string) error {
-	listing, err := h.App.DB.FindByID(ctx, id)
+func (h *AdminHandler) applyActionToListing(ctx context.Context, audit *listing.AuditService, actor domain.Actor, id, action, newCategory string) (domain.Listing, error) {
+	l, err := h.App.DB.FindByID(ctx, id)
 	if err != nil {
-		return err
+		return domain.Listing{}, err
 	}
 
-	switch action {
-	case "approve":
-		listing.Status = domain.ListingStatusApproved
-		listing.IsActive = true
-	case "reject":
-		listing.Status = domain.ListingStatusRejected
-		listing.IsActive = false
+	switch domain.ListingAction(action) {
+	case domain.ListingActionApprove:
+		l.Status = domain.ListingStatusApproved
+		l.IsActive = true
+	case domain.ListingActionReject:
+		l.Status = domain.ListingStatusRejected
+		l.IsActive = false
 		if newCategory != "" {
-			listing.Type = domain.Category(newCategory)
+			l.Type = domain.Category(newCategory)
 		}
-	case "change_category":
+	case domain.ListingActionChangeCategory:
 		if newCategory == "" {
-			return fmt.Errorf("category required")
+			return domain.Listing{}, fmt.Errorf("category required")
 		}
-		listing.Type = domain.Category(newCategory)
+		l.Type = domain.Category(newCategory)
 	default:
-		return fmt.Errorf("unknown action: %s", action)
+		return domain.Listing{}, fmt.Errorf("unknown action: %s", action)
 	}
 
-	return h.App.DB.Save(ctx, listing)
+	return l, audit.Save(ctx, actor, domain.ListingAction(action), l)
 }
 
 // HandleAdminDeleteView renders the double-confirmation page for deleting listings.
@@ -124,9 +137,11 @@ func (h *AdminHandler) HandleAdminDeleteAction(c echo.Context) error {
 	}
 
 	ctx := c.Request().Context()
+	audit := listing.NewAuditService(h.App.DB)
+	actor := listing.RequestActor(c)
 	successCount := 0
 	for _, id := range ids {
-		if err := h.App.DB.Delete(ctx, id); err == nil {
+		if err := audit.Delete(ctx, actor, id); err == nil {
 			successCount++
 		} else {
 			c.Logger().Errorf("Failed to delete listing %s: %v", id, err)
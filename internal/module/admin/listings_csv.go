@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
@@ -25,7 +26,7 @@ func (h *AdminHandler) HandleBulkUpload(c echo.Context) error {
 	defer func() { _ = src.Close() }()
 
 	// 2. Parse and Import
-	result, err := h.App.CSVService.ParseAndImport(c.Request().Context(), src, h.App.DB)
+	result, err := h.App.CSVService.ParseAndImport(c.Request().Context(), src, listing.NewAuditService(h.App.DB).For(listing.RequestActor(c)))
 	if err != nil {
 		return h.redirectWithFlash(c, "Failed to process CSV: "+err.Error(), domain.PathAdmin)
 	}
@@ -48,11 +49,17 @@ func (h *AdminHandler) HandleExportListings(c echo.Context) error {
 
 	// Fetch all listings. Using a large limit for export.
 	// In a very large system, we might want to stream this from the DB directly.
-	listings, _, err := h.App.DB.FindAll(ctx, "", "", "", 0, 0, 0, domain.FieldCreatedAt, "desc", true, 10000, 0)
+	result, err := h.App.DB.Search(ctx, domain.ListingQuery{
+		Sort:            domain.FieldCreatedAt,
+		Order:           "desc",
+		IncludeInactive: true,
+		Limit:           10000,
+	})
 
 	if err != nil {
 		return ui.RespondError(c, err)
 	}
+	listings := result.Listings
 
 	reader, err := h.App.CSVService.GenerateCSV(ctx, listings)
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
@@ -26,8 +28,13 @@ func (h *AdminHandler) HandleModalCharts(c echo.Context) error {
 	})
 }
 
-// HandleModalUsers renders the user management modal fragment.
+// HandleModalUsers renders the user management modal fragment. Users who may assign
+// roles get a role picker on each user they are allowed to change.
 func (h *AdminHandler) HandleModalUsers(c echo.Context) error {
+	actor, err := user.RequireUser(c)
+	if err != nil || actor == nil {
+		return err
+	}
 	ctx := c.Request().Context()
 	users, err := h.App.DB.GetAllUsers(ctx, 10, 0)
 	if err != nil {
@@ -41,6 +48,8 @@ func (h *AdminHandler) HandleModalUsers(c echo.Context) error {
 	return c.Render(http.StatusOK, "admin_modal_users.html", map[string]interface{}{
 		"Users":     users,
 		"UserCount": userCount,
+		"Actor":     actor,
+		"Roles":     actor.Role.AssignableRoles(),
 	})
 }
 
@@ -62,15 +71,21 @@ func (h *AdminHandler) HandleModalCategory(c echo.Context) error {
 	})
 }
 
-// HandleModalModeration renders the claim moderation modal fragment.
+// HandleModalModeration renders the moderation modal fragment: pending claim requests
+// and listing changes held for review.
 func (h *AdminHandler) HandleModalModeration(c echo.Context) error {
 	ctx := c.Request().Context()
 	claimRequests, err := h.App.DB.GetPendingClaimRequests(ctx)
 	if err != nil {
 		return ui.RespondError(c, err)
 	}
+	pendingChanges, err := listing.NewModerationService(h.App.DB).Pending(ctx)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
 
 	return c.Render(http.StatusOK, "admin_modal_moderation.html", map[string]interface{}{
-		"ClaimRequests": claimRequests,
+		"ClaimRequests":  h.claimReviewItems(ctx, claimRequests),
+		"PendingChanges": pendingChanges,
 	})
 }
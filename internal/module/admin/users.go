@@ -1,10 +1,16 @@
 package admin
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
@@ -26,3 +32,203 @@ func (h *AdminHandler) HandleUsers(c echo.Context) error {
 		"Pagination": p,
 	})
 }
+
+const tmplUserDetail = "admin_user_detail.html"
+
+// userListingPageSize is how many of a user's listings are read at a time, both for
+// the user page and when deactivating all of them.
+const userListingPageSize = 100
+
+// HandleUserDetail renders a user's page: their status and its history, listings,
+// claims and feedback.
+func (h *AdminHandler) HandleUserDetail(c echo.Context) error {
+	actor, err := user.RequireUser(c)
+	if err != nil || actor == nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	account, err := h.App.DB.FindUserByID(ctx, c.Param(domain.ParamID))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrUserNotFound.Error())
+	}
+
+	listings, listingCount, err := h.App.DB.FindAllByOwner(ctx, account.ID, userListingPageSize, 0)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	claims, err := h.App.DB.ListClaimRequestsByUser(ctx, account.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	feedback, err := h.App.DB.ListFeedbackByUser(ctx, account.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	events, err := h.App.DB.ListUserEvents(ctx, account.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+
+	return c.Render(http.StatusOK, tmplUserDetail, map[string]interface{}{
+		"Account":       account,
+		"AccountStatus": account.CurrentStatus(time.Now()),
+		"Listings":      listings,
+		"ListingCount":  listingCount,
+		"Claims":        claims,
+		"Feedback":      feedback,
+		"Events":        events,
+		"Statuses":      domain.AllUserStatuses,
+		"CanRestrict":   account.ID != actor.ID && actor.Role.CanRestrict(account.Role),
+		"FlashMessage":  h.takeFlash(c),
+		"User":          actor,
+	})
+}
+
+// HandleSetUserStatus shadows, suspends, bans or reinstates a user. Suspending or
+// banning a user signs them out everywhere and can also deactivate all of their
+// listings; the reason is kept with the user's status, in their history and in each
+// listing's history.
+func (h *AdminHandler) HandleSetUserStatus(c echo.Context) error {
+	actor, err := user.RequireUser(c)
+	if err != nil || actor == nil {
+		return err
+	}
+	status, err := domain.ParseUserStatus(c.FormValue(domain.FieldStatus))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	reason := strings.TrimSpace(c.FormValue(domain.FieldStatusReason))
+	if status != domain.UserStatusActive && reason == "" {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, domain.ErrStatusReasonRequired.Error())
+	}
+	now := time.Now()
+	var until time.Time
+	if status == domain.UserStatusSuspended {
+		until, err = time.Parse(time.DateOnly, c.FormValue(domain.FieldSuspendedUntil))
+		if err != nil || !until.After(now) {
+			return ui.RespondErrorMsg(c, http.StatusBadRequest, domain.ErrInvalidSuspension.Error())
+		}
+	}
+
+	ctx := c.Request().Context()
+	account, err := h.App.DB.FindUserByID(ctx, c.Param(domain.ParamID))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrUserNotFound.Error())
+	}
+	if account.ID == actor.ID || !actor.Role.CanRestrict(account.Role) {
+		return ui.RespondErrorMsg(c, http.StatusForbidden, domain.ErrUserNotRestrictable.Error())
+	}
+
+	if err = h.App.DB.SetUserStatus(ctx, account.ID, status, until, reason); err != nil {
+		return ui.RespondError(c, err)
+	}
+	deactivated := 0
+	// A shadowed user keeps their sessions and listings so they do not notice.
+	if status.BlocksAccess() {
+		if _, err = h.App.DB.DeleteUserSessions(ctx, account.ID); err != nil {
+			return ui.RespondError(c, err)
+		}
+		if c.FormValue(domain.FieldDeactivateListings) != "" {
+			deactivated, err = h.deactivateListings(ctx, domain.ActorFromUser(actor), account.ID, reason)
+			if err != nil {
+				return ui.RespondError(c, err)
+			}
+		}
+	}
+
+	err = h.App.DB.AppendUserEvent(ctx, domain.UserEvent{
+		ID:                  uuid.New().String(),
+		UserID:              account.ID,
+		ActorID:             actor.ID,
+		ActorName:           actor.Name,
+		Action:              domain.UserActionFor(status),
+		Reason:              reason,
+		Until:               until,
+		ListingsDeactivated: deactivated,
+		CreatedAt:           now,
+	})
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+
+	msg := fmt.Sprintf("%s is now %s", account.Name, status)
+	if deactivated > 0 {
+		msg += fmt.Sprintf("; %d listings deactivated", deactivated)
+	}
+	return h.redirectWithFlash(c, msg, domain.PathAdmin+"/users/"+account.ID)
+}
+
+// deactivateListings takes every active listing owned by userID out of the directory
+// and returns how many it deactivated.
+func (h *AdminHandler) deactivateListings(ctx context.Context, actor domain.Actor, userID, reason string) (int, error) {
+	audit := listing.NewAuditService(h.App.DB)
+	deactivated := 0
+	for offset := 0; ; offset += userListingPageSize {
+		listings, _, err := h.App.DB.FindAllByOwner(ctx, userID, userListingPageSize, offset)
+		if err != nil {
+			return deactivated, err
+		}
+		for _, l := range listings {
+			if !l.IsActive {
+				continue
+			}
+			if err = audit.Deactivate(ctx, actor, l.ID, reason); err != nil {
+				return deactivated, err
+			}
+			deactivated++
+		}
+		if len(listings) < userListingPageSize {
+			return deactivated, nil
+		}
+	}
+}
+
+// HandleAssignRole changes another user's role. Nobody may change their own role,
+// and only super admins may grant or remove the admin roles.
+func (h *AdminHandler) HandleAssignRole(c echo.Context) error {
+	actor, err := user.RequireUser(c)
+	if err != nil || actor == nil {
+		return err
+	}
+	role, err := domain.ParseUserRole(c.FormValue(domain.FieldRole))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	target, err := h.App.DB.FindUserByID(ctx, c.Param(domain.ParamID))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrUserNotFound.Error())
+	}
+	if target.ID == actor.ID || !actor.Role.CanAssign(target.Role, role) {
+		return ui.RespondErrorMsg(c, http.StatusForbidden, domain.ErrRoleNotAssignable.Error())
+	}
+
+	target.Role = role
+	if err := h.App.DB.SaveUser(ctx, target); err != nil {
+		return ui.RespondError(c, err)
+	}
+	return h.redirectWithFlash(c, fmt.Sprintf("%s is now %s", target.Name, role), domain.PathAdmin)
+}
+
+// HandleForceLogout signs a user out of every device, for example after their
+// account or a cookie was compromised. Like a status change, staff may only do this
+// to users below them and never to themselves.
+func (h *AdminHandler) HandleForceLogout(c echo.Context) error {
+	actor, err := user.RequireUser(c)
+	if err != nil || actor == nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	target, err := h.App.DB.FindUserByID(ctx, c.Param(domain.ParamID))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrUserNotFound.Error())
+	}
+	if target.ID == actor.ID || !actor.Role.CanRestrict(target.Role) {
+		return ui.RespondErrorMsg(c, http.StatusForbidden, domain.ErrUserNotSignOutable.Error())
+	}
+	n, err := h.App.DB.DeleteUserSessions(ctx, target.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	return h.redirectWithFlash(c, fmt.Sprintf("Signed %s out of %d sessions", target.Name, n), domain.PathAdmin)
+}
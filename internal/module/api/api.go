@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/jadecobra/agbalumo/internal/common"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/infra/env"
+	"github.com/jadecobra/agbalumo/internal/module"
+	"github.com/jadecobra/agbalumo/internal/module/user"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageSize = 30
+	maxPageSize     = 100
+)
+
+// APIHandler serves the versioned JSON API used by the mobile app and partner integrations.
+type APIHandler struct {
+	module.BaseHandler
+}
+
+func NewAPIHandler(app *env.AppEnv) *APIHandler {
+	return &APIHandler{
+		BaseHandler: module.BaseHandler{App: app},
+	}
+}
+
+// RegisterRoutes wires up the /api/v1 endpoints. Authentication is resolved by
+// OptionalAuth; handlers that mutate state answer 401 as JSON rather than redirecting.
+func (h *APIHandler) RegisterRoutes(e *echo.Echo, authMw domain.AuthMiddleware) {
+	v1 := e.Group("/api/v1")
+	v1.GET("/listings", h.HandleListListings)
+	v1.POST("/listings", h.HandleCreateListing)
+	v1.GET("/listings/:id", h.HandleGetListing)
+	v1.PUT("/listings/:id", h.HandleUpdateListing)
+	v1.DELETE("/listings/:id", h.HandleDeleteListing)
+	v1.POST("/listings/:id/claim", h.HandleClaimListing)
+}
+
+// requireUser returns the authenticated user or writes a JSON 401.
+// Callers must return the error immediately; the response is already committed.
+func requireUser(c echo.Context) (*domain.User, error) {
+	u, ok := user.GetUser(c)
+	if !ok || u == nil {
+		_ = ui.RespondJSONError(c, http.StatusUnauthorized, common.ErrMsgLoginRequired)
+		return nil, echo.ErrUnauthorized
+	}
+	return u, nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ListingRequest is the JSON body accepted by the create and update endpoints.
+// Dates use RFC 3339 and are only applied for the listing types that use them.
+type ListingRequest struct {
+	Deadline          *time.Time `json:"deadline"`
+	EventStart        *time.Time `json:"event_start"`
+	EventEnd          *time.Time `json:"event_end"`
+	JobStartDate      *time.Time `json:"job_start_date"`
+	Title             string     `json:"title"`
+	Type              string     `json:"type"`
+	OwnerOrigin       string     `json:"owner_origin"`
+	Description       string     `json:"description"`
+	City              string     `json:"city"`
+	Address           string     `json:"address"`
+	HoursOfOperation  string     `json:"hours_of_operation"`
+	ContactEmail      string     `json:"contact_email"`
+	ContactPhone      string     `json:"contact_phone"`
+	ContactWhatsApp   string     `json:"contact_whatsapp"`
+	WebsiteURL        string     `json:"website_url"`
+	ImageURL          string     `json:"image_url"`
+	Skills            string     `json:"skills"`
+	JobApplyURL       string     `json:"job_apply_url"`
+	Company           string     `json:"company"`
+	PayRange          string     `json:"pay_range"`
+	RegionalSpecialty string     `json:"regional_specialty"`
+	TopDish           string     `json:"top_dish"`
+	HeatLevel         int        `json:"heat_level"`
+}
+
+// ToListing copies the request onto l, leaving server-managed fields untouched.
+func (r *ListingRequest) ToListing(l *domain.Listing) {
+	l.Title = r.Title
+	l.Type = domain.Category(r.Type)
+	l.OwnerOrigin = r.OwnerOrigin
+	l.Description = r.Description
+	l.City = r.City
+	l.Address = r.Address
+	l.HoursOfOperation = r.HoursOfOperation
+	l.ContactEmail = r.ContactEmail
+	l.ContactPhone = r.ContactPhone
+	l.ContactWhatsApp = r.ContactWhatsApp
+	l.WebsiteURL = domain.NormalizeURL(r.WebsiteURL)
+	l.ImageURL = r.ImageURL
+	l.Skills = r.Skills
+	l.JobApplyURL = domain.NormalizeURL(r.JobApplyURL)
+	l.Company = r.Company
+	l.PayRange = r.PayRange
+	l.RegionalSpecialty = r.RegionalSpecialty
+	l.TopDish = r.TopDish
+	l.HeatLevel = r.HeatLevel
+
+	switch l.Type {
+	case domain.Request:
+		assignTime(r.Deadline, &l.Deadline)
+	case domain.Event:
+		assignTime(r.EventStart, &l.EventStart)
+		assignTime(r.EventEnd, &l.EventEnd)
+	case domain.Job:
+		assignTime(r.JobStartDate, &l.JobStartDate)
+	}
+}
+
+func assignTime(src *time.Time, dst *time.Time) {
+	if src != nil && !src.IsZero() {
+		*dst = *src
+	}
+}
+
+// PaginationMeta describes where a page of results sits in the full result set.
+// TotalCount and TotalPages are omitted on cursor pages, which skip counting.
+type PaginationMeta struct {
+	TotalCount  *int   `json:"total_count,omitempty"`
+	TotalPages  *int   `json:"total_pages,omitempty"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	Page        int    `json:"page"`
+	Limit       int    `json:"limit"`
+	HasNextPage bool   `json:"has_next_page"`
+}
+
+// ListingListResponse is the envelope returned by GET /api/v1/listings.
+type ListingListResponse struct {
+	Data       []domain.Listing `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
+}
+
+// ListingResponse is the envelope returned for a single listing. PendingChange is set
+// when a create or update in a moderated category was held for review.
+type ListingResponse struct {
+	PendingChange *domain.PendingChange `json:"pending_change,omitempty"`
+	Data          domain.Listing        `json:"data"`
+}
+
+// ClaimListingRequest is the optional JSON body of a claim. Documents and phone
+// verification are only available through the web form.
+type ClaimListingRequest struct {
+	EvidenceEmail string `json:"evidence_email"`
+}
+
+// ClaimResponse is the envelope returned after a claim request is filed.
+type ClaimResponse struct {
+	Data domain.ClaimRequest `json:"data"`
+}
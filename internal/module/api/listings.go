@@ -0,0 +1,354 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/module/user"
+	"github.com/jadecobra/agbalumo/internal/service"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const errMsgTitleExists = "title already exists"
+
+// HandleListListings returns a page of active listings matching the query filters.
+func (h *APIHandler) HandleListListings(c echo.Context) error {
+	ctx := c.Request().Context()
+	page, limit := parsePage(c)
+	offset := (page - 1) * limit
+
+	q, err := parseListingQuery(c)
+	if err != nil {
+		return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+	}
+	q.Limit, q.Offset = limit, offset
+	q.Cursor = c.QueryParam(domain.ParamCursor)
+	q.SkipCount = q.Cursor != ""
+	q.ViewerID = user.ViewerID(c)
+
+	if len(q.Cities) == 1 && q.RadiusMiles > 0 && q.Latitude == 0 && q.Longitude == 0 {
+		q.Latitude, q.Longitude, _ = h.App.GeocodingSvc.Geocode(ctx, q.Cities[0])
+	}
+
+	result, err := h.App.DB.Search(ctx, q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		h.LogError(c, "api: failed to list listings", err)
+		return ui.RespondJSONError(c, http.StatusInternalServerError, "failed to list listings")
+	}
+	listings := result.Listings
+
+	service.ApplyOpenState(listings, time.Now())
+	if listings == nil {
+		listings = []domain.Listing{}
+	}
+
+	meta := PaginationMeta{
+		Page:        page,
+		Limit:       limit,
+		NextCursor:  result.NextCursor,
+		HasNextPage: result.NextCursor != "",
+	}
+	if result.TotalCount >= 0 {
+		totalPages := (result.TotalCount + limit - 1) / limit
+		meta.TotalCount, meta.TotalPages = &result.TotalCount, &totalPages
+	}
+
+	return c.JSON(http.StatusOK, ListingListResponse{Data: listings, Pagination: meta})
+}
+
+// HandleGetListing returns a single listing by ID.
+func (h *APIHandler) HandleGetListing(c echo.Context) error {
+	l, err := h.findListing(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+	if viewer, _ := user.GetUser(c); listing.HiddenFrom(c.Request().Context(), h.App.DB, l, viewer) {
+		return ui.RespondJSONError(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
+	}
+	return c.JSON(http.StatusOK, ListingResponse{Data: l})
+}
+
+// HandleCreateListing creates a listing owned by the authenticated user.
+func (h *APIHandler) HandleCreateListing(c echo.Context) error {
+	u, err := requireUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req ListingRequest
+	if err := c.Bind(&req); err != nil {
+		return ui.RespondJSONError(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	l := domain.Listing{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+		IsActive:  true,
+		Status:    domain.ListingStatusApproved,
+		OwnerID:   u.ID,
+	}
+	req.ToListing(&l)
+
+	// Default deadline for requests if not provided
+	if l.Type == domain.Request && l.Deadline.IsZero() {
+		l.Deadline = l.CreatedAt.Add(90 * 24 * time.Hour).Add(-time.Minute)
+	}
+
+	held, err := h.save(c, &l)
+	if err != nil {
+		return err
+	}
+	if held != nil {
+		l.Status = domain.ListingStatusPending
+	}
+	return c.JSON(http.StatusCreated, ListingResponse{Data: l, PendingChange: held})
+}
+
+// HandleUpdateListing replaces the editable fields of a listing owned by the caller.
+func (h *APIHandler) HandleUpdateListing(c echo.Context) error {
+	l, err := h.findAndAuthListing(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	var req ListingRequest
+	if err := c.Bind(&req); err != nil {
+		return ui.RespondJSONError(c, http.StatusBadRequest, "invalid request body")
+	}
+	req.ToListing(&l)
+
+	held, err := h.save(c, &l)
+	if err != nil {
+		return err
+	}
+	if held != nil {
+		// The edit awaits review; respond with the version the public still sees.
+		live, err := h.findListing(c, l.ID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusAccepted, ListingResponse{Data: live, PendingChange: held})
+	}
+	return c.JSON(http.StatusOK, ListingResponse{Data: l})
+}
+
+// HandleDeleteListing removes a listing owned by the caller.
+func (h *APIHandler) HandleDeleteListing(c echo.Context) error {
+	l, err := h.findAndAuthListing(c, c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	if err := listing.NewAuditService(h.App.DB).Delete(c.Request().Context(), listing.RequestActor(c), l.ID); err != nil {
+		h.LogError(c, "api: failed to delete listing", err)
+		return ui.RespondJSONError(c, http.StatusInternalServerError, "failed to delete listing")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleClaimListing files a claim request for an unowned listing.
+func (h *APIHandler) HandleClaimListing(c echo.Context) error {
+	u, err := requireUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req ClaimListingRequest
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&req); err != nil {
+			return ui.RespondJSONError(c, http.StatusBadRequest, "invalid request body")
+		}
+	}
+
+	cr, err := h.App.ListingSvc.ClaimListing(c.Request().Context(), *u, c.Param("id"), domain.ClaimEvidence{Email: req.EvidenceEmail})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrListingNotFound):
+			return ui.RespondJSONError(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, domain.ErrListingOwned), errors.Is(err, domain.ErrListingNotClaimable):
+			return ui.RespondJSONError(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, domain.ErrPendingClaimExists), errors.Is(err, domain.ErrClaimCooldown):
+			return ui.RespondJSONError(c, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrInvalidEvidenceEmail):
+			return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+		}
+		h.LogError(c, "api: failed to claim listing", err)
+		return ui.RespondJSONError(c, http.StatusInternalServerError, "failed to submit claim")
+	}
+	return c.JSON(http.StatusCreated, ClaimResponse{Data: cr})
+}
+
+// findListing fetches a listing by ID, writing a JSON 404 when it does not exist.
+// Callers must return the error immediately; the response is already committed.
+func (h *APIHandler) findListing(c echo.Context, id string) (domain.Listing, error) {
+	l, err := h.App.DB.FindByID(c.Request().Context(), id)
+	if err != nil {
+		_ = ui.RespondJSONError(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
+		return domain.Listing{}, echo.ErrNotFound
+	}
+	l.OpenState = service.ComputeOpenState(l, time.Now())
+	l.IsCurrentlyOpen = l.OpenState.IsOpen()
+	return l, nil
+}
+
+// findAndAuthListing resolves the caller and the listing and checks that the caller
+// owns it or is an admin.
+func (h *APIHandler) findAndAuthListing(c echo.Context, id string) (domain.Listing, error) {
+	u, err := requireUser(c)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	l, err := h.findListing(c, id)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	if l.OwnerID != u.ID && !u.Can(domain.PermissionManageListings) {
+		_ = ui.RespondJSONError(c, http.StatusForbidden, "you are not the owner of this listing")
+		return domain.Listing{}, echo.ErrForbidden
+	}
+	return l, nil
+}
+
+// save runs the same checks as the HTML form handlers before submitting l under its
+// category's moderation policy. It returns the held change when l awaits review.
+func (h *APIHandler) save(c echo.Context, l *domain.Listing) (*domain.PendingChange, error) {
+	ctx := c.Request().Context()
+
+	if h.titleTaken(ctx, l.Title, l.ID) {
+		_ = ui.RespondJSONError(c, http.StatusConflict, errMsgTitleExists)
+		return nil, echo.ErrConflict
+	}
+
+	h.populateLocation(ctx, l)
+	l.RefreshTimezone()
+
+	if err := l.Validate(); err != nil {
+		_ = ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+		return nil, echo.ErrBadRequest
+	}
+
+	u, _ := user.GetUser(c)
+	held, err := listing.NewModerationService(h.App.DB).Submit(ctx, u, *l)
+	if err != nil {
+		h.LogError(c, "api: failed to save listing", err)
+		_ = ui.RespondJSONError(c, http.StatusInternalServerError, "failed to save listing")
+		return nil, echo.ErrInternalServerError
+	}
+	return held, nil
+}
+
+func (h *APIHandler) titleTaken(ctx context.Context, title, currentID string) bool {
+	existing, err := h.App.DB.FindByTitle(ctx, title)
+	if err != nil {
+		return false
+	}
+	for _, ext := range existing {
+		if ext.ID != currentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *APIHandler) populateLocation(ctx context.Context, l *domain.Listing) {
+	if l.Address == "" {
+		return
+	}
+	if h.App.GeocodingSvc != nil && l.City == "" {
+		if city, err := h.App.GeocodingSvc.GetCity(ctx, l.Address); err == nil && city != "" {
+			l.City = city
+		}
+	}
+	if l.City == "" {
+		l.City = domain.ExtractCityFromAddress(l.Address)
+	}
+	if l.State == "" {
+		l.State = domain.ExtractStateFromAddress(l.Address)
+	}
+	if l.Country == "" {
+		l.Country = domain.ExtractCountryFromAddress(l.Address)
+	}
+}
+
+// parseListingQuery maps list query parameters onto a ListingQuery. type and city
+// may be repeated to match any of several values.
+func parseListingQuery(c echo.Context) (domain.ListingQuery, error) {
+	params := c.QueryParams()
+	q := domain.ListingQuery{
+		QueryText:    c.QueryParam(domain.ParamQuery),
+		OwnerOrigin:  c.QueryParam(domain.FieldOwnerOrigin),
+		Sort:         c.QueryParam(domain.ParamSort),
+		Order:        c.QueryParam(domain.ParamOrder),
+		FeaturedOnly: c.QueryParam(domain.FieldFeatured) == "true",
+		HasImage:     c.QueryParam("has_image") == "true",
+		OpenNow:      c.QueryParam(domain.ParamOpenNow) == "true",
+	}
+	for _, t := range params[domain.FieldType] {
+		q.Types = append(q.Types, domain.TypeFilter(t)...)
+	}
+	for _, city := range params[domain.FieldCity] {
+		q.Cities = append(q.Cities, domain.CityFilter(city)...)
+	}
+
+	var err error
+	if v := c.QueryParam(domain.ParamRadius); v != "" {
+		if q.RadiusMiles, err = domain.ParseRadius(v); err != nil {
+			return q, err
+		}
+	}
+	var hasOrigin bool
+	if q.Latitude, q.Longitude, hasOrigin, err = domain.ParseCoordinates(c.QueryParam(domain.ParamLat), c.QueryParam(domain.ParamLng)); err != nil {
+		return q, err
+	}
+	if hasOrigin {
+		// A point replaces the city filter, as it does on the home page.
+		q.Cities = nil
+	}
+	if v := c.QueryParam("min_rating"); v != "" {
+		if q.MinRating, err = strconv.ParseFloat(v, 64); err != nil {
+			return q, errors.New("min_rating must be a number")
+		}
+	}
+	if v := c.QueryParam(domain.FieldHeatLevel); v != "" {
+		if q.MinHeatLevel, err = strconv.Atoi(v); err != nil {
+			return q, errors.New("heat_level must be an integer")
+		}
+	}
+	if v := c.QueryParam("created_after"); v != "" {
+		if q.CreatedAfter, err = time.Parse(time.RFC3339, v); err != nil {
+			return q, errors.New("created_after must be an RFC 3339 timestamp")
+		}
+	}
+	if v := c.QueryParam(domain.ParamOpenAt); v != "" {
+		if q.OpenAt, q.OpenAtWallClock, err = domain.ParseOpenAt(v); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
+
+// parsePage reads page and limit, clamping limit to maxPageSize.
+func parsePage(c echo.Context) (page, limit int) {
+	page, _ = strconv.Atoi(c.QueryParam(domain.ParamPage))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page, limit
+}
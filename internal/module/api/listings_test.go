@@ -0,0 +1,385 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/common"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/api"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validBody = `{"title":"Ada Tailoring","type":"Service","owner_origin":"Nigeria","city":"Dallas","contact_email":"ada@example.com","description":"Alterations"}`
+
+func jsonContext(method, target, body string, u *domain.User, id string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := testutil.SetupModuleContext(method, target, strings.NewReader(body))
+	c.Request().Header.Set(common.HeaderContentType, common.MimeJSON)
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	if u != nil {
+		c.Set(domain.CtxKeyUser, u)
+	}
+	return c, rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ui.ErrorResponse {
+	t.Helper()
+	var resp ui.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestHandleListListings_Pagination(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	env.SeedStandardData(t)
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?limit=3&page=1", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.ListingListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Data, 3)
+	require.NotNil(t, resp.Pagination.TotalCount)
+	assert.Equal(t, 4, *resp.Pagination.TotalCount)
+	assert.Equal(t, 2, *resp.Pagination.TotalPages)
+	assert.True(t, resp.Pagination.HasNextPage)
+	assert.NotEmpty(t, resp.Pagination.NextCursor)
+}
+
+func TestHandleListListings_Cursor(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	env.SeedStandardData(t)
+	h := api.NewAPIHandler(env.App)
+
+	seen := map[string]bool{}
+	target := "/api/v1/listings?limit=3"
+	for i := 0; i < 3 && target != ""; i++ {
+		c, rec := jsonContext(http.MethodGet, target, "", nil, "")
+		require.NoError(t, h.HandleListListings(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp api.ListingListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		for _, l := range resp.Data {
+			assert.False(t, seen[l.ID], "listing %s repeated", l.ID)
+			seen[l.ID] = true
+		}
+		if i > 0 {
+			assert.Nil(t, resp.Pagination.TotalCount, "cursor pages skip the count")
+		}
+
+		target = ""
+		if resp.Pagination.NextCursor != "" {
+			target = "/api/v1/listings?limit=3&cursor=" + resp.Pagination.NextCursor
+		}
+	}
+	assert.Len(t, seen, 4)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?cursor=bogus", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleListListings_TypeFilterAndEmpty(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	env.SeedStandardData(t)
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?type=Job", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+
+	var resp api.ListingListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.NotNil(t, resp.Data, "empty result should encode as [] not null")
+	assert.Empty(t, resp.Data)
+	assert.False(t, resp.Pagination.HasNextPage)
+}
+
+func TestHandleListListings_RepeatedFilters(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	env.SeedStandardData(t)
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?type=Food&type=Service&city=Houston&city=Dallas", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+
+	var resp api.ListingListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	ids := []string{}
+	for _, l := range resp.Data {
+		ids = append(ids, l.ID)
+	}
+	assert.ElementsMatch(t, []string{"l2", "l3"}, ids)
+}
+
+func TestHandleListListings_InvalidFilter(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?created_after=yesterday", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, decodeError(t, rec).Error, "created_after")
+
+	c, rec = jsonContext(http.MethodGet, "/api/v1/listings?open_at=tonight", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, decodeError(t, rec).Error, "open_at")
+
+	c, rec = jsonContext(http.MethodGet, "/api/v1/listings?lat=29.79", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, decodeError(t, rec).Error, "lat and lng")
+
+	for _, bad := range []string{"lat=NaN&lng=10", "lat=NaN&lng=10&sort=distance", "lat=29.79&lng=-95.82&radius=NaN"} {
+		c, rec = jsonContext(http.MethodGet, "/api/v1/listings?"+bad, "", nil, "")
+		require.NoError(t, h.HandleListListings(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, bad)
+	}
+}
+
+func TestHandleListListings_NearPoint(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := api.NewAPIHandler(env.App)
+	at := func(lat, lng float64) func(*domain.Listing) {
+		return func(l *domain.Listing) { l.Latitude, l.Longitude = lat, lng }
+	}
+	testutil.SaveTestListing(t, env.App.DB, "houston", "Houston Kitchen", at(29.7604, -95.3698))
+	testutil.SaveTestListing(t, env.App.DB, "katy", "Katy Kitchen", at(29.7858, -95.8245))
+	testutil.SaveTestListing(t, env.App.DB, "dallas", "Dallas Kitchen", at(32.7767, -96.7970))
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings?lat=29.79&lng=-95.82&radius=50&sort=distance", "", nil, "")
+	require.NoError(t, h.HandleListListings(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp api.ListingListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "katy", resp.Data[0].ID)
+	assert.Equal(t, "houston", resp.Data[1].ID)
+	assert.Greater(t, resp.Data[1].DistanceMiles, resp.Data[0].DistanceMiles)
+}
+
+func TestHandleGetListing(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Mama Put")
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodGet, "/api/v1/listings/l1", "", nil, "l1")
+	require.NoError(t, h.HandleGetListing(c))
+	var resp api.ListingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Mama Put", resp.Data.Title)
+
+	c, rec = jsonContext(http.MethodGet, "/api/v1/listings/missing", "", nil, "missing")
+	_ = h.HandleGetListing(c)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, domain.ErrListingNotFound.Error(), decodeError(t, rec).Error)
+}
+
+func TestHandleCreateListing(t *testing.T) {
+	t.Parallel()
+	owner := &domain.User{ID: "owner-1", Role: domain.UserRoleUser}
+
+	tests := []struct {
+		user       *domain.User
+		setup      func(t *testing.T, repo domain.ListingRepository)
+		name       string
+		body       string
+		expectCode int
+	}{
+		{name: "Success", user: owner, body: validBody, expectCode: http.StatusCreated},
+		{name: "Unauthorized", body: validBody, expectCode: http.StatusUnauthorized},
+		{name: "MalformedJSON", user: owner, body: `{"title":`, expectCode: http.StatusBadRequest},
+		{
+			name:       "ValidationError",
+			user:       owner,
+			body:       `{"title":"No Origin","type":"Service","city":"Dallas","contact_email":"a@b.com"}`,
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name: "DuplicateTitle",
+			user: owner,
+			setup: func(t *testing.T, repo domain.ListingRepository) {
+				testutil.SaveTestListing(t, repo, "existing", "Ada Tailoring")
+			},
+			body:       validBody,
+			expectCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			if tt.setup != nil {
+				tt.setup(t, env.App.DB)
+			}
+			h := api.NewAPIHandler(env.App)
+
+			c, rec := jsonContext(http.MethodPost, "/api/v1/listings", tt.body, tt.user, "")
+			_ = h.HandleCreateListing(c)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			if tt.expectCode != http.StatusCreated {
+				assert.Equal(t, tt.expectCode, decodeError(t, rec).Code)
+				return
+			}
+			var resp api.ListingResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, owner.ID, resp.Data.OwnerID)
+			assert.Equal(t, domain.ListingStatusApproved, resp.Data.Status)
+			testutil.AssertListingExists(t, env.App.DB, "Ada Tailoring")
+		})
+	}
+}
+
+func TestHandleUpdateListing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		user       *domain.User
+		name       string
+		expectCode int
+	}{
+		{name: "Owner", user: &domain.User{ID: "owner-1", Role: domain.UserRoleUser}, expectCode: http.StatusOK},
+		{name: "Admin", user: &domain.User{ID: "admin-1", Role: domain.UserRoleAdmin}, expectCode: http.StatusOK},
+		{name: "NotOwner", user: &domain.User{ID: "other", Role: domain.UserRoleUser}, expectCode: http.StatusForbidden},
+		{name: "Anonymous", expectCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			testutil.SaveTestListing(t, env.App.DB, "l1", "Old Title", func(l *domain.Listing) { l.OwnerID = "owner-1" })
+			h := api.NewAPIHandler(env.App)
+
+			c, rec := jsonContext(http.MethodPut, "/api/v1/listings/l1", validBody, tt.user, "l1")
+			_ = h.HandleUpdateListing(c)
+			assert.Equal(t, tt.expectCode, rec.Code)
+
+			saved, err := env.App.DB.FindByID(context.Background(), "l1")
+			require.NoError(t, err)
+			if tt.expectCode == http.StatusOK {
+				assert.Equal(t, "Ada Tailoring", saved.Title)
+				assert.Equal(t, "owner-1", saved.OwnerID, "update must not transfer ownership")
+			} else {
+				assert.Equal(t, "Old Title", saved.Title)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateListing_HeldForModeration(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	require.NoError(t, env.App.DB.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Service), Name: "Service", Active: true, RequiresModeration: true}))
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Old Title", func(l *domain.Listing) {
+		l.OwnerID = "owner-1"
+		l.Type = domain.Service
+	})
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodPut, "/api/v1/listings/l1", validBody, &domain.User{ID: "owner-1", Role: domain.UserRoleUser}, "l1")
+	require.NoError(t, h.HandleUpdateListing(c))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	var resp api.ListingResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "Old Title", resp.Data.Title, "response carries the live version")
+	require.NotNil(t, resp.PendingChange)
+	assert.Equal(t, "Ada Tailoring", resp.PendingChange.Proposed.Title)
+
+	saved, err := env.App.DB.FindByID(ctx, "l1")
+	require.NoError(t, err)
+	assert.Equal(t, "Old Title", saved.Title)
+}
+
+func TestHandleDeleteListing(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Title", func(l *domain.Listing) { l.OwnerID = "owner-1" })
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodDelete, "/api/v1/listings/l1", "", &domain.User{ID: "other"}, "l1")
+	_ = h.HandleDeleteListing(c)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	c, rec = jsonContext(http.MethodDelete, "/api/v1/listings/l1", "", &domain.User{ID: "owner-1"}, "l1")
+	require.NoError(t, h.HandleDeleteListing(c))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	_, err := env.App.DB.FindByID(context.Background(), "l1")
+	assert.Error(t, err)
+}
+
+func TestHandleClaimListing(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := api.NewAPIHandler(env.App)
+
+	c, rec := jsonContext(http.MethodPost, "/api/v1/listings/l1/claim", "", nil, "l1")
+	_ = h.HandleClaimListing(c)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	c, rec = jsonContext(http.MethodPost, "/api/v1/listings/l1/claim", "", &domain.User{ID: "u1"}, "l1")
+	require.NoError(t, h.HandleClaimListing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+
+	e := echo.New()
+	api.NewAPIHandler(env.App).RegisterRoutes(e, nil)
+
+	registered := make(map[string]bool)
+	for _, r := range e.Routes() {
+		registered[r.Method+" "+r.Path] = true
+	}
+	for _, want := range []string{
+		"GET /api/v1/listings",
+		"POST /api/v1/listings",
+		"GET /api/v1/listings/:id",
+		"PUT /api/v1/listings/:id",
+		"DELETE /api/v1/listings/:id",
+		"POST /api/v1/listings/:id/claim",
+	} {
+		assert.True(t, registered[want], "missing route %s", want)
+	}
+}
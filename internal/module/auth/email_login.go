@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// EmailLogin emails a single-use sign-in link to the address in the form. The reply
+// is the same whether or not the address has an account yet.
+func (h *AuthHandler) EmailLogin(c echo.Context) error {
+	// The link is built from the configured base URL only: one built from the request's
+	// Host header could send the token to whoever forged it.
+	if h.App.Notifications == nil || h.App.Cfg.BaseURL == "" {
+		return ui.RespondErrorMsg(c, http.StatusServiceUnavailable, "Email sign-in is not configured")
+	}
+	email, err := domain.NormalizeEmail(c.FormValue(domain.FieldEmail))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	token := rand.Text()
+	now := time.Now()
+	link := domain.LoginLink{
+		TokenHash: hashLoginToken(token),
+		Email:     email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(domain.LoginLinkTTL),
+	}
+	if err = h.App.DB.SaveLoginLink(ctx, link); err != nil {
+		h.LogError(c, "failed to save sign-in link", err)
+		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to send sign-in link")
+	}
+	minutes := int(domain.LoginLinkTTL.Minutes())
+	err = h.App.Notifications.Notify(ctx, domain.NotificationLoginLink, email, map[string]interface{}{
+		"LoginURL":         h.App.Cfg.BaseURL + "/auth/email/verify?" + url.Values{domain.ParamToken: {token}}.Encode(),
+		"ExpiresInMinutes": minutes,
+	})
+	if err != nil {
+		h.LogError(c, "failed to queue sign-in link", err)
+		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to send sign-in link")
+	}
+
+	return h.renderLoginPage(c, map[string]interface{}{"EmailSent": email, "ExpiresInMinutes": minutes})
+}
+
+// EmailLoginConfirm shows the button that completes an emailed sign-in. The link
+// itself signs nobody in, so mail scanners that open links do not use it up.
+func (h *AuthHandler) EmailLoginConfirm(c echo.Context) error {
+	token := c.QueryParam(domain.ParamToken)
+	if token == "" {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, domain.ErrInvalidLoginLink.Error())
+	}
+	return h.renderLoginPage(c, map[string]interface{}{"LoginToken": token})
+}
+
+// EmailLoginVerify uses an emailed sign-in link and starts the same session as the
+// other sign-in methods, creating the user on their first sign-in.
+func (h *AuthHandler) EmailLoginVerify(c echo.Context) error {
+	link, err := h.App.DB.UseLoginLink(c.Request().Context(), hashLoginToken(c.FormValue(domain.ParamToken)), time.Now())
+	if errors.Is(err, domain.ErrInvalidLoginLink) {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		h.LogError(c, "failed to use sign-in link", err)
+		return ui.RespondErrorMsg(c, http.StatusInternalServerError, domain.MsgFailedToLogin)
+	}
+
+	identity := domain.UserIdentity{Provider: domain.IdentityProviderEmail, Subject: link.Email, Email: link.Email}
+	return h.loginWith(c, identity, "", "")
+}
+
+// hashLoginToken is the form a sign-in link's token is stored in.
+func hashLoginToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// emailLinkLimiters limit sign-in link requests per client IP and, so one inbox
+// cannot be flooded from many addresses, per email address.
+func emailLinkLimiters() []echo.MiddlewareFunc {
+	perIP := customMiddleware.NewRateLimiter(customMiddleware.RateLimitConfig{
+		Rate:  rate.Every(time.Minute),
+		Burst: 5,
+	})
+	perEmail := customMiddleware.NewRateLimiter(customMiddleware.RateLimitConfig{
+		Key: func(c echo.Context) string {
+			return "email:" + strings.ToLower(strings.TrimSpace(c.FormValue(domain.FieldEmail)))
+		},
+		Rate:  rate.Every(10 * time.Minute),
+		Burst: 3,
+	})
+	return []echo.MiddlewareFunc{perIP.Middleware(), perEmail.Middleware()}
+}
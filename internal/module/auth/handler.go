@@ -5,13 +5,16 @@ import (
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/infra/env"
+	"github.com/jadecobra/agbalumo/internal/module"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/oauth2"
 )
 
 type AuthHandler struct {
-	App            *env.AppEnv
 	GoogleProvider GoogleProvider
+	// OIDCProviders are the configured OpenID Connect providers by ID.
+	OIDCProviders map[string]*OIDCProvider
+	module.BaseHandler
 }
 
 func NewAuthHandler(app *env.AppEnv) *AuthHandler {
@@ -25,17 +28,36 @@ func NewAuthHandler(app *env.AppEnv) *AuthHandler {
 		googleProvider = NewRealGoogleProvider()
 	}
 
+	oidcProviders := make(map[string]*OIDCProvider, len(app.Cfg.OIDCProviders))
+	for _, p := range app.Cfg.OIDCProviders {
+		oidcProviders[p.ID] = NewOIDCProvider(p)
+	}
+
 	return &AuthHandler{
-		App:            app,
+		BaseHandler:    module.BaseHandler{App: app},
 		GoogleProvider: googleProvider,
+		OIDCProviders:  oidcProviders,
 	}
 }
 
 func (h *AuthHandler) RegisterRoutes(e *echo.Echo, authMw domain.AuthMiddleware) {
+	e.GET("/auth/login", h.LoginPage)
 	e.GET("/auth/dev", h.DevLogin)
 	e.GET("/auth/logout", h.Logout)
 	e.GET("/auth/google/login", h.GoogleLogin)
 	e.GET("/auth/google/callback", h.GoogleCallback)
+	e.GET("/auth/oidc/:provider/login", h.OIDCLogin)
+	e.GET("/auth/oidc/:provider/callback", h.OIDCCallback)
+	e.POST("/auth/email", h.EmailLogin, emailLinkLimiters()...)
+	e.GET("/auth/email/verify", h.EmailLoginConfirm)
+	e.POST("/auth/email/verify", h.EmailLoginVerify)
+
+	tokens := e.Group("/profile/tokens", authMw.RequireAuth)
+	tokens.POST("", h.HandleCreateToken)
+	tokens.POST("/:id/revoke", h.HandleRevokeToken)
+
+	sessions := e.Group("/profile/sessions", authMw.RequireAuth)
+	sessions.POST("/:id/revoke", h.HandleRevokeSession)
 }
 
 type MockGoogleProvider struct {
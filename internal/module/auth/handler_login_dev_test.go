@@ -1,17 +1,21 @@
 package auth_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gorilla/sessions"
 	"github.com/jadecobra/agbalumo/internal/domain"
+	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
 	"github.com/jadecobra/agbalumo/internal/module/auth"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthHandler_DevLogin_Production(t *testing.T) {
@@ -57,3 +61,31 @@ func TestAuthHandler_DevLogin_Success(t *testing.T) {
 	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
 	assert.NotEmpty(t, sess.Values[domain.SessionKeyUserID])
 }
+
+func TestAuthHandler_DevLogin_RotatesSession(t *testing.T) {
+	t.Parallel()
+	app, cleanup := testutil.SetupTestAppEnv(t)
+	defer cleanup()
+	store := customMiddleware.NewSessionStore(app.DB, sessions.Options{Path: "/", MaxAge: 3600},
+		customMiddleware.SessionKey{Secret: []byte("secret")})
+
+	// An anonymous visitor already has a stored session before signing in.
+	req := httptest.NewRequest(http.MethodGet, "/auth/dev?email=test@dev.com", nil)
+	rec := httptest.NewRecorder()
+	sess, err := store.Get(req, domain.SessionName)
+	require.NoError(t, err)
+	require.NoError(t, sess.Save(req, rec))
+	anonymous := customMiddleware.HashSessionID(sess.ID)
+
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("session", sess)
+	require.NoError(t, auth.NewAuthHandler(app).DevLogin(c))
+
+	ctx := context.Background()
+	_, err = app.DB.FindSession(ctx, anonymous, time.Now())
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound, "the pre-sign-in row is deleted")
+	signedIn, err := app.DB.FindSession(ctx, customMiddleware.HashSessionID(sess.ID), time.Now())
+	require.NoError(t, err)
+	assert.NotEqual(t, anonymous, signedIn.ID)
+	assert.NotEmpty(t, signedIn.UserID)
+}
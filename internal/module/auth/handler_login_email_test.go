@@ -0,0 +1,202 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/infra/env"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEmailLogin(t *testing.T) (*env.AppEnv, *auth.AuthHandler, *testutil.MockNotificationService) {
+	t.Helper()
+	app, cleanup := testutil.SetupTestAppEnv(t)
+	t.Cleanup(cleanup)
+	notifications := &testutil.MockNotificationService{}
+	app.Notifications = notifications
+	return app, auth.NewAuthHandler(app), notifications
+}
+
+func emailLoginForm(values url.Values) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := testutil.SetupTestContextWithSession(http.MethodPost, "/auth/email", strings.NewReader(values.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	return c, rec
+}
+
+// requestLoginLink asks for a link to email and returns the token it was sent with.
+func requestLoginLink(t *testing.T, h *auth.AuthHandler, notifications *testutil.MockNotificationService, email string) string {
+	t.Helper()
+	c, rec := emailLoginForm(url.Values{domain.FieldEmail: {email}})
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "login.html")
+	require.NoError(t, h.EmailLogin(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `data-testid="ag-login-email-sent"`)
+
+	require.NotEmpty(t, notifications.Sent)
+	sent := notifications.Sent[len(notifications.Sent)-1]
+	assert.Equal(t, domain.NotificationLoginLink, sent.Kind)
+	link, err := url.Parse(sent.Data["LoginURL"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "/auth/email/verify", link.Path)
+	return link.Query().Get(domain.ParamToken)
+}
+
+func verifyLoginLink(t *testing.T, h *auth.AuthHandler, token string) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	c, rec := emailLoginForm(url.Values{domain.ParamToken: {token}})
+	require.NoError(t, h.EmailLoginVerify(c))
+	return c, rec
+}
+
+func TestAuthHandler_EmailLogin_SignsInOnce(t *testing.T) {
+	t.Parallel()
+	app, h, notifications := setupEmailLogin(t)
+
+	token := requestLoginLink(t, h, notifications, "  Ada@Example.com ")
+	assert.Equal(t, "ada@example.com", notifications.Sent[0].To, "the address is normalized")
+
+	// Opening the link only asks for confirmation.
+	c, rec := testutil.SetupTestContextWithSession(http.MethodGet, "/auth/email/verify?token="+token, nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "login.html")
+	require.NoError(t, h.EmailLoginConfirm(c))
+	assert.Contains(t, rec.Body.String(), `value="`+token+`"`)
+
+	c, rec = verifyLoginLink(t, h, token)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	sess, err := testutil.GetAuthSession(c)
+	require.NoError(t, err)
+	user, err := app.DB.FindUserByIdentity(context.Background(), domain.IdentityProviderEmail, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, sess.Values[domain.SessionKeyUserID])
+	assert.Equal(t, "ada@example.com", user.Email)
+
+	_, rec = verifyLoginLink(t, h, token)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "a link signs in only once")
+
+	// A new link signs in as the same user.
+	c, _ = verifyLoginLink(t, h, requestLoginLink(t, h, notifications, "ada@example.com"))
+	sess, _ = testutil.GetAuthSession(c)
+	assert.Equal(t, user.ID, sess.Values[domain.SessionKeyUserID])
+}
+
+func TestAuthHandler_EmailLogin_JoinsExistingUser(t *testing.T) {
+	t.Parallel()
+	app, h, notifications := setupEmailLogin(t)
+	ctx := context.Background()
+	member := domain.User{ID: "google-member", GoogleID: "g-ada", Email: "Ada@Example.com", Name: "Ada", CreatedAt: time.Now()}
+	require.NoError(t, app.DB.SaveUser(ctx, member))
+	require.NoError(t, app.DB.SaveUserIdentity(ctx, domain.UserIdentity{
+		Provider: domain.IdentityProviderGoogle, Subject: "g-ada", UserID: member.ID, Email: member.Email, CreatedAt: time.Now(),
+	}))
+
+	c, rec := verifyLoginLink(t, h, requestLoginLink(t, h, notifications, "ada@example.com"))
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	sess, err := testutil.GetAuthSession(c)
+	require.NoError(t, err)
+	assert.Equal(t, member.ID, sess.Values[domain.SessionKeyUserID], "no second account is created")
+
+	user, err := app.DB.FindUserByIdentity(ctx, domain.IdentityProviderEmail, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, member.ID, user.ID, "the email identity is linked to the member")
+	count, err := app.DB.GetUserCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestAuthHandler_EmailLogin_Rejects(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InvalidEmail", func(t *testing.T) {
+		t.Parallel()
+		_, h, notifications := setupEmailLogin(t)
+		c, rec := emailLoginForm(url.Values{domain.FieldEmail: {"Ada <ada@example.com>"}})
+		require.NoError(t, h.EmailLogin(c))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, notifications.Sent)
+	})
+
+	t.Run("UnknownToken", func(t *testing.T) {
+		t.Parallel()
+		_, h, _ := setupEmailLogin(t)
+		_, rec := verifyLoginLink(t, h, "forged")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("NoMail", func(t *testing.T) {
+		t.Parallel()
+		app, cleanup := testutil.SetupTestAppEnv(t)
+		defer cleanup()
+		c, rec := emailLoginForm(url.Values{domain.FieldEmail: {"ada@example.com"}})
+		require.NoError(t, auth.NewAuthHandler(app).EmailLogin(c))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("NoBaseURL", func(t *testing.T) {
+		t.Parallel()
+		app, h, notifications := setupEmailLogin(t)
+		app.Cfg.BaseURL = ""
+		c, rec := emailLoginForm(url.Values{domain.FieldEmail: {"ada@example.com"}})
+		require.NoError(t, h.EmailLogin(c))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Empty(t, notifications.Sent)
+	})
+}
+
+func TestAuthHandler_EmailLogin_IgnoresForgedHost(t *testing.T) {
+	t.Parallel()
+	app, h, notifications := setupEmailLogin(t)
+	app.Cfg.BaseURL = "https://agbalumo.example.com"
+
+	c, rec := emailLoginForm(url.Values{domain.FieldEmail: {"ada@example.com"}})
+	c.Request().Host = "evil.example"
+	c.Request().Header.Set("X-Forwarded-Host", "evil.example")
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "login.html")
+	require.NoError(t, h.EmailLogin(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	require.Len(t, notifications.Sent, 1)
+	link, err := url.Parse(notifications.Sent[0].Data["LoginURL"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "agbalumo.example.com", link.Host, "the token is never sent to the host the request named")
+	assert.Equal(t, "https", link.Scheme)
+}
+
+func TestAuthHandler_EmailLogin_RateLimited(t *testing.T) {
+	t.Parallel()
+	app, h, notifications := setupEmailLogin(t)
+	e := echo.New()
+	e.Renderer = testutil.SetupTestRendererForPage(t, "login.html")
+	h.RegisterRoutes(e, auth.NewAuthMiddleware(app.DB))
+
+	post := func(email, ip string) int {
+		req := httptest.NewRequest(http.MethodPost, "/auth/email", strings.NewReader(url.Values{domain.FieldEmail: {email}}.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Each address gets a few links, whichever IP asks for them.
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		assert.Equal(t, http.StatusOK, post("ada@example.com", ip), "request %d", i)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, post("ADA@example.com", "10.0.0.4"))
+
+	// Each IP gets a few requests, whichever addresses it asks for.
+	for i := range 5 {
+		assert.Equal(t, http.StatusOK, post("user"+string(rune('a'+i))+"@example.com", "10.0.0.9"), "request %d", i)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, post("another@example.com", "10.0.0.9"))
+	assert.Len(t, notifications.Sent, 8)
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/module/auth"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthHandler_GoogleCallback_Success(t *testing.T) {
@@ -51,3 +54,25 @@ func TestAuthHandler_GoogleLogin(t *testing.T) {
 	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
 	assert.Equal(t, "http://google.com/auth", rec.Header().Get("Location"))
 }
+
+func TestAuthHandler_GoogleCallback_LinksSignedInUser(t *testing.T) {
+	t.Parallel()
+	app, cleanup := testutil.SetupTestAppEnv(t)
+	defer cleanup()
+	app.Cfg.HasGoogleAuth = true
+	ctx := context.Background()
+
+	signedIn := domain.User{ID: "email-user", Email: "e@example.com", Name: "Email User", CreatedAt: time.Now()}
+	require.NoError(t, app.DB.SaveUser(ctx, signedIn))
+
+	rec := performGoogleCallback(t, app, map[string]string{"id": "google-link", "email": "g@example.com", "name": "G"}, &signedIn)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	linked, err := app.DB.FindUserByIdentity(ctx, domain.IdentityProviderGoogle, "google-link")
+	require.NoError(t, err)
+	assert.Equal(t, signedIn.ID, linked.ID, "the Google account is linked rather than signed in as a new user")
+
+	other := domain.User{ID: "other-user", Email: "o@example.com", Name: "Other", CreatedAt: time.Now()}
+	require.NoError(t, app.DB.SaveUser(ctx, other))
+	rec = performGoogleCallback(t, app, map[string]string{"id": "google-link", "email": "g@example.com", "name": "G"}, &other)
+	assert.Equal(t, http.StatusConflict, rec.Code, "an account linked to someone else is not moved")
+}
@@ -0,0 +1,237 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/infra/env"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOIDC returns an app and handler signing in with a stand-in provider as "acme".
+func setupOIDC(t *testing.T) (*env.AppEnv, *auth.AuthHandler, *testutil.OIDCServer) {
+	t.Helper()
+	srv := testutil.NewOIDCServer(t)
+	app, cleanup := testutil.SetupTestAppEnv(t)
+	t.Cleanup(cleanup)
+	app.Cfg.OIDCProviders = append(app.Cfg.OIDCProviders, srv.Provider("acme"))
+	return app, auth.NewAuthHandler(app), srv
+}
+
+// oidcSignIn starts an OIDC sign-in, lets the stand-in provider approve it and
+// returns the app's response to the callback. signedIn is the user already signed in,
+// if any; edit may change the callback request before it is handled.
+func oidcSignIn(t *testing.T, h *auth.AuthHandler, srv *testutil.OIDCServer, signedIn *domain.User, edit func(r *http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	c, rec := testutil.SetupTestContextWithSession(http.MethodGet, "/auth/oidc/acme/login", nil)
+	c.SetParamNames(domain.ParamProvider)
+	c.SetParamValues("acme")
+	require.NoError(t, h.OIDCLogin(c))
+	require.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+
+	authURL, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	assert.NotEmpty(t, authURL.Query().Get("nonce"))
+	callback := srv.Authorize(t, authURL.String())
+	assert.Equal(t, "/auth/oidc/acme/callback", callback.Path)
+	cookies := rec.Result().Cookies()
+
+	c, rec = testutil.SetupTestContextWithSession(http.MethodGet, callback.RequestURI(), nil)
+	c.SetParamNames(domain.ParamProvider)
+	c.SetParamValues("acme")
+	for _, cookie := range cookies {
+		c.Request().AddCookie(cookie)
+	}
+	if signedIn != nil {
+		c.Set(domain.CtxKeyUser, signedIn)
+	}
+	if edit != nil {
+		edit(c.Request())
+	}
+	require.NoError(t, h.OIDCCallback(c))
+	return rec
+}
+
+func TestAuthHandler_OIDC_SignInCreatesUser(t *testing.T) {
+	t.Parallel()
+	app, h, srv := setupOIDC(t)
+
+	rec := oidcSignIn(t, h, srv, nil, nil)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	assert.Equal(t, "/", rec.Header().Get("Location"))
+
+	user, err := app.DB.FindUserByIdentity(context.Background(), "acme", srv.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, "oidc@example.com", user.Email)
+	assert.Equal(t, "OIDC User", user.Name)
+	assert.Empty(t, user.GoogleID)
+	assert.NotEmpty(t, user.AvatarURL, "an avatar is generated when the provider has no picture")
+
+	// Signing in again finds the same user.
+	oidcSignIn(t, h, srv, nil, nil)
+	again, err := app.DB.FindUserByIdentity(context.Background(), "acme", srv.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, again.ID)
+}
+
+func TestAuthHandler_OIDC_UnverifiedEmailIsNotKept(t *testing.T) {
+	t.Parallel()
+	app, h, srv := setupOIDC(t)
+	srv.Claims = func(claims map[string]any) { claims["email_verified"] = false }
+
+	oidcSignIn(t, h, srv, nil, nil)
+	user, err := app.DB.FindUserByIdentity(context.Background(), "acme", srv.Subject)
+	require.NoError(t, err)
+	assert.Empty(t, user.Email)
+}
+
+func TestAuthHandler_OIDC_LinksSignedInUser(t *testing.T) {
+	t.Parallel()
+	app, h, srv := setupOIDC(t)
+	ctx := context.Background()
+
+	rec := performRegistration(t, app, map[string]string{"id": "google-link", "email": "g@example.com", "name": "Google User"})
+	require.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	googleUser, err := app.DB.FindUserByGoogleID(ctx, "google-link")
+	require.NoError(t, err)
+
+	rec = oidcSignIn(t, h, srv, &googleUser, nil)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+	linked, err := app.DB.FindUserByIdentity(ctx, "acme", srv.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, googleUser.ID, linked.ID, "one user signs in with both providers")
+	byGoogle, err := app.DB.FindUserByIdentity(ctx, domain.IdentityProviderGoogle, "google-link")
+	require.NoError(t, err)
+	assert.Equal(t, googleUser.ID, byGoogle.ID)
+
+	other := domain.User{ID: "other-user", Email: "other@example.com", Name: "Other", CreatedAt: time.Now()}
+	require.NoError(t, app.DB.SaveUser(ctx, other))
+	rec = oidcSignIn(t, h, srv, &other, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code, "an account linked to someone else is not moved")
+}
+
+func TestAuthHandler_OIDC_Rejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		setup    func(srv *testutil.OIDCServer)
+		edit     func(r *http.Request)
+		name     string
+		wantCode int
+	}{
+		{
+			name: "StateMismatch",
+			edit: func(r *http.Request) {
+				q := r.URL.Query()
+				q.Set(domain.ParamState, "forged")
+				r.URL.RawQuery = q.Encode()
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "MissingStateCookie",
+			edit:     func(r *http.Request) { r.Header.Del("Cookie") },
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "ProviderError",
+			edit: func(r *http.Request) {
+				q := r.URL.Query()
+				q.Set(domain.ParamError, "access_denied")
+				r.URL.RawQuery = q.Encode()
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "ForgedSignature",
+			setup:    func(srv *testutil.OIDCServer) { srv.Forge = true },
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "NonceMismatch",
+			setup:    func(srv *testutil.OIDCServer) { srv.Claims = func(c map[string]any) { c["nonce"] = "replayed" } },
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "WrongAudience",
+			setup: func(srv *testutil.OIDCServer) {
+				srv.Claims = func(c map[string]any) { c["aud"] = []string{"another-client"} }
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "WrongIssuer",
+			setup: func(srv *testutil.OIDCServer) {
+				srv.Claims = func(c map[string]any) { c["iss"] = "https://evil.example.com" }
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Expired",
+			setup: func(srv *testutil.OIDCServer) {
+				srv.Claims = func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() }
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "CodeReplayed",
+			edit: func(r *http.Request) {
+				q := r.URL.Query()
+				q.Set(domain.ParamCode, "not-issued")
+				r.URL.RawQuery = q.Encode()
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			app, h, srv := setupOIDC(t)
+			if tt.setup != nil {
+				tt.setup(srv)
+			}
+
+			rec := oidcSignIn(t, h, srv, nil, tt.edit)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			_, err := app.DB.FindUserByIdentity(context.Background(), "acme", srv.Subject)
+			assert.ErrorIs(t, err, domain.ErrUserNotFound, "no user is signed in")
+		})
+	}
+}
+
+func TestAuthHandler_OIDC_UnknownProvider(t *testing.T) {
+	t.Parallel()
+	_, h, _ := setupOIDC(t)
+
+	c, rec := testutil.SetupTestContextWithSession(http.MethodGet, "/auth/oidc/nope/login", nil)
+	c.SetParamNames(domain.ParamProvider)
+	c.SetParamValues("nope")
+	require.NoError(t, h.OIDCLogin(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAuthHandler_LoginPage(t *testing.T) {
+	t.Parallel()
+	app, h, _ := setupOIDC(t)
+	app.Cfg.HasGoogleAuth = true
+
+	c, rec := testutil.SetupTestContextWithSession(http.MethodGet, "/auth/login", nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "login.html")
+	require.NoError(t, h.LoginPage(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `href="/auth/google/login"`)
+	assert.Contains(t, body, `href="/auth/oidc/acme/login"`)
+	assert.Contains(t, body, "Continue with Test acme")
+}
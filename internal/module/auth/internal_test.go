@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/jadecobra/agbalumo/internal/config"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 )
@@ -156,3 +158,13 @@ func TestRealGoogleProvider_GetUserInfo_BadJSON(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, user)
 }
+
+// --- OIDCProvider client Tests ---
+
+func TestOIDCProvider_DefaultClientTimesOut(t *testing.T) {
+	t.Parallel()
+	p := NewOIDCProvider(config.OIDCProvider{ID: "acme"})
+	assert.Equal(t, oidcTimeout, p.client().Timeout)
+	assert.Same(t, p.client(), p.clientContext(context.Background()).Value(oauth2.HTTPClient),
+		"go-oidc and oauth2 use the same client")
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/config"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/oauth2"
+)
+
+// oidcStateMaxAge is how long a user has to finish signing in at the provider.
+const oidcStateMaxAge = 10 * time.Minute
+
+// LoginPage lists the ways to sign in. A signed-in user sees the providers they can
+// link to their account instead.
+func (h *AuthHandler) LoginPage(c echo.Context) error {
+	return h.renderLoginPage(c, map[string]interface{}{})
+}
+
+func (h *AuthHandler) renderLoginPage(c echo.Context, data map[string]interface{}) error {
+	data["OIDCProviders"] = h.App.Cfg.OIDCProviders
+	data["HasEmailLogin"] = h.App.Notifications != nil
+	return h.RenderWithBaseContext(c, "login.html", data)
+}
+
+// OIDCLogin sends the user to an OpenID Connect provider to sign in. The state,
+// nonce and PKCE verifier are kept in a short-lived cookie scoped to the provider's
+// callback.
+func (h *AuthHandler) OIDCLogin(c echo.Context) error {
+	p, ok := h.OIDCProviders[c.Param(domain.ParamProvider)]
+	if !ok {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, "Unknown sign-in provider")
+	}
+	if h.App.Cfg.BaseURL == "" {
+		return ui.RespondErrorMsg(c, http.StatusServiceUnavailable, p.Config.Name+" sign-in is unavailable")
+	}
+
+	state, nonce, verifier := rand.Text(), rand.Text(), oauth2.GenerateVerifier()
+	url, err := p.AuthCodeURL(c.Request().Context(), h.oidcRedirectURL(p.Config.ID), state, nonce, verifier)
+	if err != nil {
+		h.LogError(c, "failed to start OpenID Connect sign-in", err)
+		return ui.RespondErrorMsg(c, http.StatusBadGateway, p.Config.Name+" sign-in is unavailable")
+	}
+
+	c.SetCookie(h.oidcStateCookie(c, p.Config, state+"."+nonce+"."+verifier, int(oidcStateMaxAge.Seconds())))
+	return c.Redirect(http.StatusTemporaryRedirect, url)
+}
+
+// OIDCCallback finishes an OpenID Connect sign-in. A signed-in user has the provider
+// account linked to them; anyone else is signed in as the account's user, which is
+// created on first sign-in.
+func (h *AuthHandler) OIDCCallback(c echo.Context) error {
+	p, ok := h.OIDCProviders[c.Param(domain.ParamProvider)]
+	if !ok {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, "Unknown sign-in provider")
+	}
+
+	cookie, err := c.Cookie(domain.SessionKeyOIDCState)
+	c.SetCookie(h.oidcStateCookie(c, p.Config, "", -1))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, "States don't match or expired")
+	}
+	saved := strings.Split(cookie.Value, ".")
+	if len(saved) != 3 || subtle.ConstantTimeCompare([]byte(saved[0]), []byte(c.QueryParam(domain.ParamState))) != 1 {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, "States don't match or expired")
+	}
+	if c.QueryParam(domain.ParamError) != "" {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, p.Config.Name+" sign-in was cancelled or refused")
+	}
+
+	claims, err := p.Exchange(c.Request().Context(), h.oidcRedirectURL(p.Config.ID), c.QueryParam(domain.ParamCode), saved[2], saved[1])
+	if err != nil {
+		h.LogError(c, "OpenID Connect code exchange failed", err)
+		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Code exchange failed")
+	}
+
+	identity := domain.UserIdentity{Provider: p.Config.ID, Subject: claims.Subject, Email: claims.Email}
+	return h.loginOrLink(c, identity, claims.Name, claims.Picture)
+}
+
+func (h *AuthHandler) oidcStateCookie(c echo.Context, p config.OIDCProvider, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     domain.SessionKeyOIDCState,
+		Value:    value,
+		Path:     "/auth/oidc/" + p.ID + "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   isSecureCookie(c, h.App.Cfg.Env),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// oidcRedirectURL is where a provider sends the user back to. Like every link the
+// app hands out, it is built from the configured base URL, never the request's Host.
+func (h *AuthHandler) oidcRedirectURL(providerID string) string {
+	return h.App.Cfg.BaseURL + "/auth/oidc/" + providerID + "/callback"
+}
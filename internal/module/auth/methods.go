@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -25,22 +28,47 @@ func (h *AuthHandler) DevLogin(c echo.Context) error {
 		return ui.RespondErrorMsg(c, http.StatusForbidden, "Dev login disabled in production")
 	}
 
-	googleID := "dev-" + email
+	identity := domain.UserIdentity{Provider: domain.IdentityProviderGoogle, Subject: "dev-" + email, Email: email}
 	name := "Dev User"
 	avatar := "https://ui-avatars.com/api/?name=Dev+User&background=random"
 
-	return h.loginWith(c, googleID, email, name, avatar)
+	return h.loginWith(c, identity, name, avatar)
 }
 
-// loginWith looks up or creates the user then sets the session and redirects.
-func (h *AuthHandler) loginWith(c echo.Context, googleID, email, name, avatar string) error {
-	u, err := h.findOrCreateUser(c.Request().Context(), googleID, email, name, avatar)
+// loginWith looks up or creates the user signing in with identity then sets the
+// session and redirects.
+func (h *AuthHandler) loginWith(c echo.Context, identity domain.UserIdentity, name, avatar string) error {
+	u, err := h.findOrCreateUser(c.Request().Context(), identity, name, avatar)
 	if err != nil {
+		h.LogError(c, "failed to sign in", err)
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, domain.MsgFailedToLogin)
 	}
+	if err = u.CheckAccess(time.Now()); err != nil {
+		return ui.RespondErrorMsg(c, http.StatusForbidden, err.Error())
+	}
 	return h.setSessionAndRedirect(c, u.ID)
 }
 
+// loginOrLink finishes a provider callback. A signed-in user has the provider account
+// linked to them; anyone else is signed in with loginWith.
+func (h *AuthHandler) loginOrLink(c echo.Context, identity domain.UserIdentity, name, avatar string) error {
+	user, ok := c.Get(domain.CtxKeyUser).(*domain.User)
+	if !ok || user == nil {
+		return h.loginWith(c, identity, name, avatar)
+	}
+
+	identity.UserID = user.ID
+	identity.CreatedAt = time.Now()
+	if err := h.App.DB.SaveUserIdentity(c.Request().Context(), identity); err != nil {
+		if errors.Is(err, domain.ErrIdentityLinked) {
+			return ui.RespondErrorMsg(c, http.StatusConflict, err.Error())
+		}
+		h.LogError(c, "failed to link identity", err)
+		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to link sign-in")
+	}
+	return c.Redirect(http.StatusTemporaryRedirect, "/")
+}
+
 func isSecureCookie(c echo.Context, env string) bool {
 	baseURL := os.Getenv("BASE_URL")
 	return env == domain.EnvProduction || strings.HasPrefix(baseURL, "https://") || c.Scheme() == "https"
@@ -76,31 +104,61 @@ func (h *AuthHandler) Logout(c echo.Context) error {
 	return c.Redirect(http.StatusTemporaryRedirect, "/")
 }
 
-func (h *AuthHandler) findOrCreateUser(ctx context.Context, googleID, email, name, avatar string) (*domain.User, error) {
-	user, err := h.App.DB.FindUserByGoogleID(ctx, googleID)
-	if err != nil {
-		user = domain.User{
-			ID:        uuid.New().String(),
-			GoogleID:  googleID,
-			Email:     email,
-			Name:      name,
-			AvatarURL: avatar,
-			CreatedAt: time.Now(),
-		}
-		if err := h.App.DB.SaveUser(ctx, user); err != nil {
+// findOrCreateUser returns the user identity is linked to, creating one for a new
+// identity. A new email identity joins the user already signing in with that address. The name and avatar are refreshed from the provider when it sends them.
+func (h *AuthHandler) findOrCreateUser(ctx context.Context, identity domain.UserIdentity, name, avatar string) (*domain.User, error) {
+	user, err := h.App.DB.FindUserByIdentity(ctx, identity.Provider, identity.Subject)
+	if errors.Is(err, domain.ErrUserNotFound) && identity.Provider == domain.IdentityProviderGoogle {
+		// Google users are also found by the ID on their user row.
+		user, err = h.App.DB.FindUserByGoogleID(ctx, identity.Subject)
+	}
+	if errors.Is(err, domain.ErrUserNotFound) && identity.Provider == domain.IdentityProviderEmail {
+		// An emailed link proves the address, so it signs in the user who already has
+		// it from another provider instead of creating a second account.
+		user, err = h.App.DB.FindUserByIdentityEmail(ctx, identity.Email)
+	}
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user = newUser(identity, name, avatar)
+		if err = h.App.DB.SaveUser(ctx, user); err != nil {
 			return nil, err
 		}
-		return &user, nil
+	case err != nil:
+		return nil, err
+	case (avatar != "" && user.AvatarURL != avatar) || (name != "" && user.Name != name):
+		user.AvatarURL = cmp.Or(avatar, user.AvatarURL)
+		user.Name = cmp.Or(name, user.Name)
+		_ = h.App.DB.SaveUser(ctx, user)
 	}
 
-	if user.AvatarURL != avatar || user.Name != name {
-		user.AvatarURL = avatar
-		user.Name = name
-		_ = h.App.DB.SaveUser(ctx, user)
+	identity.UserID = user.ID
+	identity.CreatedAt = time.Now()
+	if err = h.App.DB.SaveUserIdentity(ctx, identity); err != nil {
+		return nil, err
 	}
 	return &user, nil
 }
 
+// newUser is a user signing in with identity for the first time. Providers need not
+// send a name or picture, so the email and a generated avatar stand in.
+func newUser(identity domain.UserIdentity, name, avatar string) domain.User {
+	name = cmp.Or(name, identity.Email, "New Member")
+	if avatar == "" {
+		avatar = "https://ui-avatars.com/api/?name=" + url.QueryEscape(name) + "&background=random"
+	}
+	user := domain.User{
+		ID:        uuid.New().String(),
+		Email:     identity.Email,
+		Name:      name,
+		AvatarURL: avatar,
+		CreatedAt: time.Now(),
+	}
+	if identity.Provider == domain.IdentityProviderGoogle {
+		user.GoogleID = identity.Subject
+	}
+	return user
+}
+
 func (h *AuthHandler) setSessionAndRedirect(c echo.Context, userID string) error {
 	sess := customMiddleware.GetSession(c)
 	if sess == nil {
@@ -109,12 +167,21 @@ func (h *AuthHandler) setSessionAndRedirect(c echo.Context, userID string) error
 
 	sess.Options = &sessions.Options{
 		Path:     "/",
-		MaxAge:   86400 * 7,
+		MaxAge:   int(domain.SessionMaxAge.Seconds()),
 		HttpOnly: true,
 		Secure:   isSecureCookie(c, h.App.Cfg.Env),
 		SameSite: http.SameSiteLaxMode,
 	}
 	sess.Values[domain.SessionKeyUserID] = userID
+	// Signing in always issues a new session ID, so an ID fixed by someone else before
+	// sign-in never becomes authenticated. The pre-sign-in row is deleted rather than
+	// left to expire.
+	if old := customMiddleware.CurrentSessionID(c); old != "" {
+		if err := h.App.DB.DeleteSession(c.Request().Context(), old); err != nil {
+			return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to save session")
+		}
+	}
+	sess.ID = ""
 
 	if err := sess.Save(c.Request(), c.Response()); err != nil {
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to save session")
@@ -122,6 +189,9 @@ func (h *AuthHandler) setSessionAndRedirect(c echo.Context, userID string) error
 
 	return c.Redirect(http.StatusTemporaryRedirect, "/")
 }
+
+// GoogleCallback finishes a Google sign-in. Like OIDCCallback, it links the Google
+// account to a signed-in user and otherwise signs in as the account's user.
 func (h *AuthHandler) GoogleCallback(c echo.Context) error {
 	state := c.QueryParam(domain.ParamState)
 
@@ -148,5 +218,6 @@ func (h *AuthHandler) GoogleCallback(c echo.Context) error {
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "User data fetch failed")
 	}
 
-	return h.loginWith(c, gUser.ID, gUser.Email, gUser.Name, gUser.Picture)
+	identity := domain.UserIdentity{Provider: domain.IdentityProviderGoogle, Subject: gUser.ID, Email: gUser.Email}
+	return h.loginOrLink(c, identity, gUser.Name, gUser.Picture)
 }
@@ -2,15 +2,20 @@ package auth
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/middleware"
+	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
 
 // AuthMiddleware provides routing middleware for authentication
 type AuthMiddleware struct {
 	Repo domain.UserStore
+	// Tokens authenticates "Authorization: Bearer" requests. When nil, bearer tokens are rejected.
+	Tokens domain.TokenAuthenticator
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware
@@ -18,17 +23,28 @@ func NewAuthMiddleware(repo domain.UserStore) *AuthMiddleware {
 	return &AuthMiddleware{Repo: repo}
 }
 
-// OptionalAuth injects user into context if session exists
+// OptionalAuth injects user into context if session exists. Suspended and banned
+// users are treated as signed out.
+// Requests carrying a bearer token are authenticated by the token alone; an invalid
+// token or one lacking the scope for the request is rejected outright rather than
+// falling back to the session.
 func (m *AuthMiddleware) OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if raw, ok := middleware.BearerToken(c); ok {
+			return m.authenticateBearer(c, raw, next)
+		}
+
 		sess := middleware.GetSession(c)
 		if sess != nil {
 			if userID, ok := sess.Values[domain.SessionKeyUserID].(string); ok {
 
 				user, err := m.Repo.FindUserByID(c.Request().Context(), userID)
-				if err == nil {
+				if err == nil && user.CheckAccess(time.Now()) != nil {
+					// A suspended or banned user is signed out and carries on anonymously.
+					delete(sess.Values, domain.SessionKeyUserID)
+					_ = sess.Save(c.Request(), c.Response())
+				} else if err == nil {
 					c.Set(domain.CtxKeyUser, &user)
-
 				}
 			}
 		}
@@ -39,6 +55,10 @@ func (m *AuthMiddleware) OptionalAuth(next echo.HandlerFunc) echo.HandlerFunc {
 // RequireAuth redirects to login if no active session
 func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		if c.Get(domain.CtxKeyAPIToken) != nil {
+			return next(c)
+		}
+
 		sess := middleware.GetSession(c)
 		authSuccess := false
 		if sess != nil {
@@ -49,8 +69,50 @@ func (m *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
 
 		if !authSuccess {
 			// Redirect to Google Login
-			return c.Redirect(http.StatusTemporaryRedirect, "/auth/google/login")
+			return c.Redirect(http.StatusTemporaryRedirect, "/auth/login")
 		}
 		return next(c)
 	}
 }
+
+func (m *AuthMiddleware) authenticateBearer(c echo.Context, raw string, next echo.HandlerFunc) error {
+	if m.Tokens == nil {
+		return ui.RespondJSONError(c, http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+	}
+
+	ctx := c.Request().Context()
+	token, err := m.Tokens.Authenticate(ctx, raw)
+	if err != nil {
+		return ui.RespondJSONError(c, http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+	}
+	if !token.HasScope(requiredScope(c)) {
+		return ui.RespondJSONError(c, http.StatusForbidden, domain.ErrInsufficientScope.Error())
+	}
+
+	user, err := m.Repo.FindUserByID(ctx, token.UserID)
+	if err != nil {
+		return ui.RespondJSONError(c, http.StatusUnauthorized, domain.ErrInvalidToken.Error())
+	}
+	if err = user.CheckAccess(time.Now()); err != nil {
+		return ui.RespondJSONError(c, http.StatusForbidden, err.Error())
+	}
+
+	c.Set(domain.CtxKeyUser, &user)
+	c.Set(domain.CtxKeyAPIToken, token)
+	return next(c)
+}
+
+// requiredScope maps a request to the token scope it needs: admin for anything under
+// /admin, listings:read for safe methods and listings:write for everything else.
+func requiredScope(c echo.Context) domain.TokenScope {
+	path := c.Request().URL.Path
+	if path == domain.PathAdmin || strings.HasPrefix(path, domain.PathAdmin+"/") {
+		return domain.ScopeAdmin
+	}
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.ScopeListingsRead
+	default:
+		return domain.ScopeListingsWrite
+	}
+}
@@ -0,0 +1,82 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware_RestrictedUsers(t *testing.T) {
+	t.Parallel()
+	repo := testutil.SetupTestRepository(t)
+	ctx := context.Background()
+	u := testutil.SaveTestUser(t, repo, "restricted-user", "restricted@example.com", domain.UserRoleUser)
+	tokens := auth.NewTokenService(repo)
+	raw, _, err := tokens.Mint(ctx, u, "ci", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+	mw := auth.NewAuthMiddleware(repo)
+	mw.Tokens = tokens
+
+	serve := func(req *http.Request, sess *sessions.Session) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if sess != nil {
+			c.Set("session", sess)
+		}
+		h := mw.OptionalAuth(mw.RequireAuth(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		}))
+		require.NoError(t, h(c))
+		return rec
+	}
+	signedIn := func() *sessions.Session {
+		sess := sessions.NewSession(sessions.NewCookieStore([]byte("secret")), domain.SessionName)
+		sess.Values[domain.SessionKeyUserID] = u.ID
+		return sess
+	}
+	bearer := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/listings", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+raw)
+		return req
+	}
+
+	require.NoError(t, repo.SetUserStatus(ctx, u.ID, domain.UserStatusSuspended, time.Now().Add(time.Hour), "spam"))
+	sess := signedIn()
+	rec := serve(httptest.NewRequest(http.MethodGet, "/profile", nil), sess)
+	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code, "a suspended user is signed out")
+	assert.NotContains(t, sess.Values, domain.SessionKeyUserID)
+
+	rec = serve(bearer(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "suspended")
+
+	require.NoError(t, repo.SetUserStatus(ctx, u.ID, domain.UserStatusSuspended, time.Now().Add(-time.Minute), "spam"))
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodGet, "/profile", nil), signedIn()).Code, "an ended suspension no longer applies")
+	assert.Equal(t, http.StatusOK, serve(bearer(), nil).Code)
+}
+
+func TestAuthHandler_EmailLogin_RefusesBannedUser(t *testing.T) {
+	t.Parallel()
+	app, h, notifications := setupEmailLogin(t)
+
+	verifyLoginLink(t, h, requestLoginLink(t, h, notifications, "ada@example.com"))
+	user, err := app.DB.FindUserByIdentity(context.Background(), domain.IdentityProviderEmail, "ada@example.com")
+	require.NoError(t, err)
+	require.NoError(t, app.DB.SetUserStatus(context.Background(), user.ID, domain.UserStatusBanned, time.Time{}, "fraud"))
+
+	c, rec := verifyLoginLink(t, h, requestLoginLink(t, h, notifications, "ada@example.com"))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	sess, err := testutil.GetAuthSession(c)
+	require.NoError(t, err)
+	assert.NotContains(t, sess.Values, domain.SessionKeyUserID)
+}
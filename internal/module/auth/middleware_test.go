@@ -37,7 +37,7 @@ func TestAuthMiddleware_RequireAuth_Redirect(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
-	assert.Equal(t, "/auth/google/login", rec.Header().Get("Location"))
+	assert.Equal(t, "/auth/login", rec.Header().Get("Location"))
 }
 
 func TestAuthMiddleware_RequireAuth_Success(t *testing.T) {
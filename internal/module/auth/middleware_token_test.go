@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddleware_BearerToken(t *testing.T) {
+	t.Parallel()
+	repo := testutil.SetupTestRepository(t)
+	ctx := context.Background()
+	u := testutil.SaveTestUser(t, repo, "bearer-user", "bearer@example.com", domain.UserRoleUser)
+
+	tokens := auth.NewTokenService(repo)
+	readOnly, _, err := tokens.Mint(ctx, u, "ro", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+	readWrite, _, err := tokens.Mint(ctx, u, "rw", []domain.TokenScope{domain.ScopeListingsRead, domain.ScopeListingsWrite})
+	require.NoError(t, err)
+	revoked, revokedTok, err := tokens.Mint(ctx, u, "gone", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+	require.NoError(t, tokens.Revoke(ctx, u.ID, revokedTok.ID))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     string
+		expectCode int
+	}{
+		{name: "ReadWithReadScope", method: http.MethodGet, path: "/api/v1/listings", header: "Bearer " + readOnly, expectCode: http.StatusOK},
+		{name: "WriteWithReadScope", method: http.MethodPost, path: "/api/v1/listings", header: "Bearer " + readOnly, expectCode: http.StatusForbidden},
+		{name: "WriteWithWriteScope", method: http.MethodPost, path: "/api/v1/listings", header: "bearer " + readWrite, expectCode: http.StatusOK},
+		{name: "AdminPathNeedsAdminScope", method: http.MethodGet, path: "/admin/listings", header: "Bearer " + readWrite, expectCode: http.StatusForbidden},
+		{name: "RevokedToken", method: http.MethodGet, path: "/api/v1/listings", header: "Bearer " + revoked, expectCode: http.StatusUnauthorized},
+		{name: "UnknownToken", method: http.MethodGet, path: "/api/v1/listings", header: "Bearer agb_nope", expectCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(echo.HeaderAuthorization, tt.header)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			mw := auth.NewAuthMiddleware(repo)
+			mw.Tokens = tokens
+
+			var seen *domain.User
+			h := mw.OptionalAuth(mw.RequireAuth(func(c echo.Context) error {
+				seen, _ = c.Get(domain.CtxKeyUser).(*domain.User)
+				return c.NoContent(http.StatusOK)
+			}))
+
+			require.NoError(t, h(c))
+			assert.Equal(t, tt.expectCode, rec.Code)
+			if tt.expectCode == http.StatusOK {
+				require.NotNil(t, seen)
+				assert.Equal(t, u.ID, seen.ID)
+				assert.NotNil(t, c.Get(domain.CtxKeyAPIToken))
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_BearerTokenIgnoresSession(t *testing.T) {
+	t.Parallel()
+	repo := testutil.SetupTestRepository(t)
+	testutil.SaveTestUser(t, repo, "session-user", "session@example.com", domain.UserRoleAdmin)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer agb_invalid")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	store := sessions.NewCookieStore([]byte("secret"))
+	sess, _ := store.Get(req, domain.SessionName)
+	sess.Values[domain.SessionKeyUserID] = "session-user"
+	c.Set("session", sess)
+
+	mw := auth.NewAuthMiddleware(repo)
+	mw.Tokens = auth.NewTokenService(repo)
+	called := false
+	h := mw.OptionalAuth(func(c echo.Context) error {
+		called = true
+		return nil
+	})
+
+	require.NoError(t, h(c))
+	assert.False(t, called, "an invalid bearer token must not fall back to the session")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/jadecobra/agbalumo/internal/config"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// oidcMaxResponseBytes caps the discovery document.
+	oidcMaxResponseBytes = 1 << 20
+	// oidcTimeout bounds each request to a provider, so a slow one cannot hold a
+	// sign-in open.
+	oidcTimeout = 10 * time.Second
+)
+
+// oidcDefaultClient makes provider requests when OIDCProvider.Client is nil.
+var oidcDefaultClient = &http.Client{Timeout: oidcTimeout}
+
+// errInvalidIDToken is wrapped by every ID token verification failure.
+var errInvalidIDToken = errors.New("invalid ID token")
+
+// OIDCClaims are the parts of a verified ID token a sign-in uses. Email is empty
+// unless the provider has verified it.
+type OIDCClaims struct {
+	Subject string
+	Email   string
+	Name    string
+	Picture string
+}
+
+// OIDCProvider signs users in with an OpenID Connect provider using the
+// authorization code flow with PKCE. The provider's endpoints come from its discovery
+// document, and go-oidc checks ID tokens against the keys it publishes.
+type OIDCProvider struct {
+	// Client makes requests to the provider; a client with a timeout when nil.
+	Client *http.Client
+	// Now is the time tokens are checked against; time.Now when nil.
+	Now      func() time.Time
+	provider *oidc.Provider
+	Config   config.OIDCProvider
+	// mu guards provider only; it is never held while talking to the provider, so a
+	// slow provider does not hold up sign-ins that already have what they need.
+	mu sync.Mutex
+}
+
+// NewOIDCProvider returns a provider for cfg. Nothing is fetched until it is used.
+func NewOIDCProvider(cfg config.OIDCProvider) *OIDCProvider {
+	return &OIDCProvider{Config: cfg}
+}
+
+// AuthCodeURL is the provider's sign-in page. verifier is the PKCE code verifier and
+// nonce is echoed back in the ID token.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, redirectURL, state, nonce, verifier string) (string, error) {
+	cfg, _, err := p.oauthConfig(ctx, redirectURL)
+	if err != nil {
+		return "", err
+	}
+	return cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oidc.Nonce(nonce)), nil
+}
+
+// Exchange trades an authorization code for tokens and returns the claims of the
+// verified ID token.
+func (p *OIDCProvider) Exchange(ctx context.Context, redirectURL, code, verifier, nonce string) (*OIDCClaims, error) {
+	cfg, _, err := p.oauthConfig(ctx, redirectURL)
+	if err != nil {
+		return nil, err
+	}
+	token, err := cfg.Exchange(p.clientContext(ctx), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := token.Extra("id_token").(string)
+	if raw == "" {
+		return nil, fmt.Errorf("%w: the token response has no id_token", errInvalidIDToken)
+	}
+	return p.VerifyIDToken(ctx, raw, nonce)
+}
+
+func (p *OIDCProvider) oauthConfig(ctx context.Context, redirectURL string) (*oauth2.Config, *oidc.Provider, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.Config.ClientID,
+		ClientSecret: p.Config.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  redirectURL,
+		Scopes:       append([]string{oidc.ScopeOpenID}, p.Config.Scopes...),
+	}, provider, nil
+}
+
+// discover fetches the discovery document, checking it names our issuer, and keeps
+// the provider it describes. Concurrent first sign-ins may each fetch the document;
+// whichever finishes first is kept.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	provider := p.provider
+	p.mu.Unlock()
+	if provider != nil {
+		return provider, nil
+	}
+
+	var d oidc.ProviderConfig
+	if err := p.getJSON(ctx, p.Config.Issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("discovering %s: %w", p.Config.ID, err)
+	}
+	// The configured issuer has no trailing slash; some providers' issuers do.
+	if strings.TrimSuffix(d.IssuerURL, "/") != p.Config.Issuer {
+		return nil, fmt.Errorf("discovering %s: issuer %q does not match %q", p.Config.ID, d.IssuerURL, p.Config.Issuer)
+	}
+	if d.AuthURL == "" || d.TokenURL == "" || d.JWKSURL == "" {
+		return nil, fmt.Errorf("discovering %s: the discovery document is missing endpoints", p.Config.ID)
+	}
+	// The key set outlives this request, so it only takes the HTTP client from ctx.
+	provider = d.NewProvider(oidc.ClientContext(context.Background(), p.client()))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider == nil {
+		p.provider = provider
+	}
+	return p.provider, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client().Do(req) // #nosec G704 - URL comes from the configured issuer
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseBytes)).Decode(v)
+}
+
+func (p *OIDCProvider) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return oidcDefaultClient
+}
+
+// clientContext carries the provider's client to go-oidc and oauth2, which would
+// otherwise use http.DefaultClient and wait forever.
+func (p *OIDCProvider) clientContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, p.client())
+}
+
+// idTokenClaims are the ID token claims go-oidc leaves to the caller.
+type idTokenClaims struct {
+	AuthorizedFor string          `json:"azp"`
+	Email         string          `json:"email"`
+	Name          string          `json:"name"`
+	Picture       string          `json:"picture"`
+	EmailVerified json.RawMessage `json:"email_verified"`
+}
+
+// VerifyIDToken checks an ID token's signature, issuer, audience, lifetime and nonce
+// and returns its claims.
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, raw, nonce string) (*OIDCClaims, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	verifier := provider.Verifier(&oidc.Config{ClientID: p.Config.ClientID, Now: p.Now})
+	token, err := verifier.Verify(p.clientContext(ctx), raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidIDToken, err)
+	}
+
+	var claims idTokenClaims
+	if err = token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", errInvalidIDToken, err)
+	}
+	switch {
+	case claims.AuthorizedFor != "" && claims.AuthorizedFor != p.Config.ClientID:
+		return nil, fmt.Errorf("%w: authorized for another client", errInvalidIDToken)
+	case nonce == "" || token.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce does not match", errInvalidIDToken)
+	case token.Subject == "":
+		return nil, fmt.Errorf("%w: no subject", errInvalidIDToken)
+	}
+
+	out := &OIDCClaims{Subject: token.Subject, Name: claims.Name, Picture: claims.Picture}
+	// Some providers send email_verified as the string "true".
+	if v := strings.Trim(string(claims.EmailVerified), `"`); v == "true" {
+		out.Email = claims.Email
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const tmplProfileSessions = "profile_sessions"
+
+// HandleRevokeSession signs one of the caller's other devices out and re-renders the
+// device list.
+func (h *AuthHandler) HandleRevokeSession(c echo.Context) error {
+	u, err := h.requireSessionUser(c)
+	if err != nil {
+		return err
+	}
+
+	id := c.Param(domain.ParamID)
+	if id == customMiddleware.CurrentSessionID(c) {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, "Use Logout to sign this device out")
+	}
+	if err = h.App.DB.DeleteUserSession(c.Request().Context(), u.ID, id); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return ui.RespondErrorMsg(c, http.StatusNotFound, err.Error())
+		}
+		return ui.RespondError(c, err)
+	}
+
+	sessions, err := h.App.DB.ListUserSessions(c.Request().Context(), u.ID, time.Now())
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	return c.Render(http.StatusOK, tmplProfileSessions, map[string]interface{}{
+		"User":             u,
+		"Sessions":         sessions,
+		"CurrentSessionID": customMiddleware.CurrentSessionID(c),
+	})
+}
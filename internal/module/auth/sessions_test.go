@@ -0,0 +1,59 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRevokeSession(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := auth.NewAuthHandler(env.App)
+	owner := domain.User{ID: "u1"}
+	ctx := context.Background()
+	now := time.Now()
+
+	current := customMiddleware.HashSessionID("this-device")
+	for _, id := range []string{current, "laptop"} {
+		require.NoError(t, env.App.DB.SaveSession(ctx, domain.Session{
+			ID: id, UserID: owner.ID, Data: []byte{}, UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0",
+			CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour),
+		}))
+	}
+
+	revoke := func(id string, u *domain.User) (int, string) {
+		c, rec := tokenFormContext(t, "/profile/sessions/"+id+"/revoke", url.Values{}, u)
+		sess := sessions.NewSession(customMiddleware.NewTestSessionStore(), domain.SessionName)
+		sess.ID = "this-device"
+		c.Set("session", sess)
+		c.SetParamNames(domain.ParamID)
+		c.SetParamValues(id)
+		_ = h.HandleRevokeSession(c)
+		return rec.Code, rec.Body.String()
+	}
+
+	code, _ := revoke("laptop", &domain.User{ID: "someone-else"})
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = revoke(current, &owner)
+	assert.Equal(t, http.StatusBadRequest, code, "the current device signs out with Logout")
+
+	code, body := revoke("laptop", &owner)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "This device")
+	assert.Contains(t, body, "Firefox on Linux")
+	assert.NotContains(t, body, "/profile/sessions/laptop/revoke")
+	_, err := env.App.DB.FindSession(ctx, "laptop", now)
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+}
@@ -16,9 +16,18 @@ import (
 
 // performRegistration is a helper to simulate a successful Google OAuth callback and registration.
 func performRegistration(t *testing.T, app *env.AppEnv, payload map[string]string) *httptest.ResponseRecorder {
+	return performGoogleCallback(t, app, payload, nil)
+}
+
+// performGoogleCallback completes a Google sign-in with a mocked provider. signedIn is
+// the user already signed in, if any.
+func performGoogleCallback(t *testing.T, app *env.AppEnv, payload map[string]string, signedIn *domain.User) *httptest.ResponseRecorder {
 	c, rec := testutil.SetupTestContextWithSession(http.MethodGet, "/auth/google/callback?state=random-state&code=valid-code", nil)
 	req := c.Request()
 	req.AddCookie(&http.Cookie{Name: domain.SessionKeyOAuthState, Value: "random-state"})
+	if signedIn != nil {
+		c.Set(domain.CtxKeyUser, signedIn)
+	}
 
 	mockProvider := &testutil.MockGoogleProvider{}
 	h := auth.NewAuthHandler(app)
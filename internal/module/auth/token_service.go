@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const (
+	tokenSecretBytes = 32
+	// tokenDisplayLen is how much of the plaintext token is kept as the visible prefix.
+	tokenDisplayLen = len(domain.APITokenPrefix) + 6
+	// tokenTouchInterval throttles last_used_at writes so every API call doesn't hit the write DB.
+	tokenTouchInterval = time.Minute
+)
+
+// TokenService mints, authenticates and revokes personal access tokens.
+type TokenService struct {
+	Store domain.APITokenStore
+	Now   func() time.Time
+}
+
+func NewTokenService(store domain.APITokenStore) *TokenService {
+	return &TokenService{Store: store, Now: time.Now}
+}
+
+// HashAPIToken returns the hex SHA-256 digest stored in place of the plaintext token.
+func HashAPIToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// Mint creates a token for u and returns the plaintext secret. The secret is
+// never persisted, so callers must show it to the user immediately.
+func (s *TokenService) Mint(ctx context.Context, u domain.User, name string, scopes []domain.TokenScope) (string, domain.APIToken, error) {
+	if u.ID == "" {
+		return "", domain.APIToken{}, domain.ErrUserIDRequired
+	}
+	if len(scopes) == 0 {
+		return "", domain.APIToken{}, domain.ErrTokenScopeRequired
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(domain.GrantableScopes(u.Role), scope) {
+			return "", domain.APIToken{}, domain.ErrScopeNotGrantable
+		}
+	}
+
+	secret := make([]byte, tokenSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", domain.APIToken{}, err
+	}
+	raw := domain.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Personal token"
+	}
+
+	t := domain.APIToken{
+		ID:        uuid.New().String(),
+		UserID:    u.ID,
+		Name:      name,
+		Prefix:    raw[:tokenDisplayLen],
+		TokenHash: HashAPIToken(raw),
+		Scopes:    scopes,
+		CreatedAt: s.Now(),
+	}
+	if err := s.Store.SaveAPIToken(ctx, t); err != nil {
+		return "", domain.APIToken{}, err
+	}
+	return raw, t, nil
+}
+
+// Authenticate resolves a plaintext bearer token to its record.
+// Unknown, malformed and revoked tokens all yield domain.ErrInvalidToken.
+func (s *TokenService) Authenticate(ctx context.Context, raw string) (domain.APIToken, error) {
+	if !strings.HasPrefix(raw, domain.APITokenPrefix) {
+		return domain.APIToken{}, domain.ErrInvalidToken
+	}
+	t, err := s.Store.FindAPITokenByHash(ctx, HashAPIToken(raw))
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return domain.APIToken{}, domain.ErrInvalidToken
+		}
+		return domain.APIToken{}, err
+	}
+	if t.IsRevoked() {
+		return domain.APIToken{}, domain.ErrInvalidToken
+	}
+
+	now := s.Now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) > tokenTouchInterval {
+		_ = s.Store.TouchAPIToken(ctx, t.ID, now)
+		t.LastUsedAt = &now
+	}
+	return t, nil
+}
+
+// List returns a user's tokens, newest first.
+func (s *TokenService) List(ctx context.Context, userID string) ([]domain.APIToken, error) {
+	return s.Store.ListAPITokens(ctx, userID)
+}
+
+// Revoke disables one of the user's tokens.
+func (s *TokenService) Revoke(ctx context.Context, userID, id string) error {
+	return s.Store.RevokeAPIToken(ctx, userID, id, s.Now())
+}
@@ -0,0 +1,80 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenService_MintAndAuthenticate(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	svc := auth.NewTokenService(repo)
+	ctx := context.Background()
+	u := domain.User{ID: "u1", Role: domain.UserRoleUser}
+
+	raw, tok, err := svc.Mint(ctx, u, "  ci  ", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(raw, domain.APITokenPrefix))
+	assert.True(t, strings.HasPrefix(raw, tok.Prefix))
+	assert.Equal(t, "ci", tok.Name)
+	assert.NotContains(t, tok.TokenHash, raw, "plaintext must not be stored")
+	assert.Equal(t, auth.HashAPIToken(raw), tok.TokenHash)
+
+	got, err := svc.Authenticate(ctx, raw)
+	require.NoError(t, err)
+	assert.Equal(t, tok.ID, got.ID)
+	require.NotNil(t, got.LastUsedAt)
+
+	for _, bad := range []string{"", "not-a-token", raw + "x", domain.APITokenPrefix + "unknown"} {
+		_, err := svc.Authenticate(ctx, bad)
+		assert.ErrorIs(t, err, domain.ErrInvalidToken, bad)
+	}
+
+	require.NoError(t, svc.Revoke(ctx, u.ID, tok.ID))
+	_, err = svc.Authenticate(ctx, raw)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestTokenService_MintRejectsBadScopes(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	svc := auth.NewTokenService(repo)
+	ctx := context.Background()
+
+	_, _, err := svc.Mint(ctx, domain.User{ID: "u1"}, "x", nil)
+	assert.ErrorIs(t, err, domain.ErrTokenScopeRequired)
+
+	_, _, err = svc.Mint(ctx, domain.User{ID: "u1", Role: domain.UserRoleUser}, "x", []domain.TokenScope{domain.ScopeAdmin})
+	assert.ErrorIs(t, err, domain.ErrScopeNotGrantable)
+
+	_, _, err = svc.Mint(ctx, domain.User{ID: "a1", Role: domain.UserRoleAdmin}, "x", []domain.TokenScope{domain.ScopeAdmin})
+	assert.NoError(t, err)
+}
+
+func TestTokenService_TouchIsThrottled(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	svc := auth.NewTokenService(repo)
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+
+	raw, _, err := svc.Mint(ctx, domain.User{ID: "u1"}, "x", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+
+	first, _ := svc.Authenticate(ctx, raw)
+	now = now.Add(10 * time.Second)
+	_, _ = svc.Authenticate(ctx, raw)
+
+	stored, err := repo.FindAPITokenByHash(ctx, auth.HashAPIToken(raw))
+	require.NoError(t, err)
+	assert.True(t, stored.LastUsedAt.Equal(*first.LastUsedAt), "second use within the interval should not write")
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/user"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const tmplProfileTokens = "profile_tokens"
+
+// HandleCreateToken mints a personal access token and re-renders the token panel
+// with the plaintext secret shown once.
+func (h *AuthHandler) HandleCreateToken(c echo.Context) error {
+	u, err := h.requireSessionUser(c)
+	if err != nil {
+		return err
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, "Invalid Request")
+	}
+	var scopes []domain.TokenScope
+	for _, s := range form[domain.FieldScopes] {
+		scopes = append(scopes, domain.TokenScope(s))
+	}
+
+	svc := NewTokenService(h.App.DB)
+	raw, _, err := svc.Mint(c.Request().Context(), *u, c.FormValue(domain.FieldName), scopes)
+	if err != nil {
+		if errors.Is(err, domain.ErrTokenScopeRequired) || errors.Is(err, domain.ErrScopeNotGrantable) {
+			return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		}
+		return ui.RespondError(c, err)
+	}
+
+	return h.renderTokens(c, u, raw)
+}
+
+// HandleRevokeToken revokes one of the caller's tokens.
+func (h *AuthHandler) HandleRevokeToken(c echo.Context) error {
+	u, err := h.requireSessionUser(c)
+	if err != nil {
+		return err
+	}
+
+	svc := NewTokenService(h.App.DB)
+	if err := svc.Revoke(c.Request().Context(), u.ID, c.Param("id")); err != nil {
+		if errors.Is(err, domain.ErrTokenNotFound) {
+			return ui.RespondErrorMsg(c, http.StatusNotFound, err.Error())
+		}
+		return ui.RespondError(c, err)
+	}
+
+	return h.renderTokens(c, u, "")
+}
+
+// requireSessionUser returns the browser-session user. Token management is not
+// available to token-authenticated requests so a leaked token cannot mint more.
+func (h *AuthHandler) requireSessionUser(c echo.Context) (*domain.User, error) {
+	if c.Get(domain.CtxKeyAPIToken) != nil {
+		_ = ui.RespondJSONError(c, http.StatusForbidden, "token management requires a browser session")
+		return nil, echo.ErrForbidden
+	}
+	return user.RequireUserAPI(c)
+}
+
+func (h *AuthHandler) renderTokens(c echo.Context, u *domain.User, newToken string) error {
+	tokens, err := h.App.DB.ListAPITokens(c.Request().Context(), u.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	return c.Render(http.StatusOK, tmplProfileTokens, map[string]interface{}{
+		"User":        u,
+		"APITokens":   tokens,
+		"TokenScopes": domain.GrantableScopes(u.Role),
+		"NewToken":    newToken,
+	})
+}
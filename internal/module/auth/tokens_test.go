@@ -0,0 +1,107 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/auth"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenFormContext(t *testing.T, path string, form url.Values, u *domain.User) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	c, rec := testutil.SetupModuleContext(http.MethodPost, path, strings.NewReader(form.Encode()))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "profile.html")
+	if u != nil {
+		c.Set(domain.CtxKeyUser, u)
+	}
+	return c, rec
+}
+
+func TestHandleCreateToken(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := auth.NewAuthHandler(env.App)
+	u := &domain.User{ID: "u1", Role: domain.UserRoleUser}
+
+	c, rec := tokenFormContext(t, "/profile/tokens", url.Values{"name": {"sync"}, "scopes": {"listings:read", "listings:write"}}, u)
+	require.NoError(t, h.HandleCreateToken(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), domain.APITokenPrefix)
+	assert.Contains(t, rec.Body.String(), "Copy this token now")
+
+	tokens, err := env.App.DB.ListAPITokens(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "sync", tokens[0].Name)
+	assert.Equal(t, []domain.TokenScope{domain.ScopeListingsRead, domain.ScopeListingsWrite}, tokens[0].Scopes)
+}
+
+func TestHandleCreateToken_Rejections(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		user       *domain.User
+		form       url.Values
+		viaToken   bool
+		name       string
+		expectCode int
+	}{
+		{name: "NoScopes", user: &domain.User{ID: "u1"}, form: url.Values{"name": {"x"}}, expectCode: http.StatusBadRequest},
+		{name: "AdminScopeForUser", user: &domain.User{ID: "u1", Role: domain.UserRoleUser}, form: url.Values{"scopes": {"admin"}}, expectCode: http.StatusBadRequest},
+		{name: "Anonymous", form: url.Values{"scopes": {"listings:read"}}, expectCode: http.StatusUnauthorized},
+		{name: "TokenAuthenticated", user: &domain.User{ID: "u1"}, form: url.Values{"scopes": {"listings:read"}}, viaToken: true, expectCode: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			h := auth.NewAuthHandler(env.App)
+
+			c, rec := tokenFormContext(t, "/profile/tokens", tt.form, tt.user)
+			if tt.viaToken {
+				c.Set(domain.CtxKeyAPIToken, domain.APIToken{ID: "t"})
+			}
+			_ = h.HandleCreateToken(c)
+			assert.Equal(t, tt.expectCode, rec.Code)
+
+			tokens, _ := env.App.DB.ListAPITokens(context.Background(), "u1")
+			assert.Empty(t, tokens)
+		})
+	}
+}
+
+func TestHandleRevokeToken(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := auth.NewAuthHandler(env.App)
+	owner := domain.User{ID: "u1"}
+
+	_, tok, err := auth.NewTokenService(env.App.DB).Mint(context.Background(), owner, "ci", []domain.TokenScope{domain.ScopeListingsRead})
+	require.NoError(t, err)
+
+	c, rec := tokenFormContext(t, "/profile/tokens/"+tok.ID+"/revoke", url.Values{}, &domain.User{ID: "someone-else"})
+	c.SetParamNames("id")
+	c.SetParamValues(tok.ID)
+	_ = h.HandleRevokeToken(c)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	c, rec = tokenFormContext(t, "/profile/tokens/"+tok.ID+"/revoke", url.Values{}, &owner)
+	c.SetParamNames("id")
+	c.SetParamValues(tok.ID)
+	require.NoError(t, h.HandleRevokeToken(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Revoked")
+}
@@ -1,9 +1,12 @@
 package module
 
 import (
+	"context"
+	"net/http"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/infra/env"
 	"github.com/labstack/echo/v4"
-	"net/http"
 )
 
 // BaseHandler provides shared dependencies and utilities for all module handlers.
@@ -18,6 +21,16 @@ func (h *BaseHandler) LogError(c echo.Context, msg string, err error) {
 	}
 }
 
+// Notify queues a notification with send. Notifications are best effort: nothing is
+// sent when the app has no notification service, and a failure is logged rather than
+// failing a request whose change has already been saved.
+func (h *BaseHandler) Notify(c echo.Context, send func(ctx context.Context, n domain.NotificationService) error) {
+	if h.App == nil || h.App.Notifications == nil {
+		return
+	}
+	h.LogError(c, "Failed to queue notification", send(c.Request().Context(), h.App.Notifications))
+}
+
 // RenderWithBaseContext is a shared helper that injects common data (Categories, Env, etc.)
 // into the data map before rendering.
 func (h *BaseHandler) RenderWithBaseContext(c echo.Context, tmpl string, data map[string]interface{}) error {
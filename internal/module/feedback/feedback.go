@@ -1,23 +1,25 @@
 package feedback
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/infra/env"
+	"github.com/jadecobra/agbalumo/internal/module"
 	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 	"github.com/labstack/echo/v4"
 )
 
 type FeedbackHandler struct {
-	App *env.AppEnv
+	module.BaseHandler
 }
 
 func NewFeedbackHandler(app *env.AppEnv) *FeedbackHandler {
-	return &FeedbackHandler{App: app}
+	return &FeedbackHandler{BaseHandler: module.BaseHandler{App: app}}
 }
 
 // RegisterRoutes registers the feedback routes
@@ -66,6 +68,14 @@ func (h *FeedbackHandler) HandleSubmit(c echo.Context) error {
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to save feedback")
 	}
 
+	h.Notify(c, func(ctx context.Context, n domain.NotificationService) error {
+		return n.NotifyAdmins(ctx, domain.NotificationFeedbackReceived, map[string]interface{}{
+			"UserName":     u.Name,
+			"FeedbackType": string(fb.Type),
+			"Content":      fb.Content,
+		})
+	})
+
 	// Return success message or close modal
 	return c.HTML(http.StatusOK, `
 		<div class="flex flex-col items-center justify-center p-8 space-y-4 text-center animate-in fade-in zoom-in-95 duration-300">
@@ -49,12 +49,17 @@ func TestFeedbackHandler_HandleSubmit_Success(t *testing.T) {
 
 	app, cleanup := testutil.SetupTestAppEnv(t)
 	defer cleanup()
+	notifications := &testutil.MockNotificationService{}
+	app.Notifications = notifications
 	h := NewFeedbackHandler(app)
 
 	err := h.HandleSubmit(c)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Contains(t, rec.Body.String(), "check_circle")
+	require.Len(t, notifications.Sent, 1)
+	assert.Equal(t, domain.NotificationFeedbackReceived, notifications.Sent[0].Kind)
+	assert.Equal(t, "This is a bug.", notifications.Sent[0].Data["Content"])
 
 	// Verify feedback in DB
 	feedbacks, err := app.DB.GetAllFeedback(c.Request().Context())
@@ -52,7 +52,8 @@ func TestListingHandler_HandleImageUpload_CacheBusting(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Fetch from DB to check ImageURL
-	all, _, _ := env.App.DB.FindAll(c.Request().Context(), "", "", "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+	page, _ := env.App.DB.Search(c.Request().Context(), domain.ListingQuery{Limit: 10})
+	all := page.Listings
 	assert.Equal(t, 1, len(all))
 	assert.Contains(t, all[0].ImageURL, "/static/uploads/test.webp?t=")
 
@@ -5,6 +5,7 @@ import (
 	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"sync"
@@ -14,7 +15,6 @@ import (
 	"github.com/jadecobra/agbalumo/internal/module"
 	"github.com/jadecobra/agbalumo/internal/service"
 	"github.com/labstack/echo/v4"
-	"strconv"
 )
 
 type ListingHandler struct {
@@ -32,7 +32,10 @@ func (h *ListingHandler) RegisterRoutes(e *echo.Echo, authMw domain.AuthMiddlewa
 	// Public Routes
 	e.GET("/", h.HandleHome)
 	e.GET("/listings/fragment", h.HandleFragment)
+	e.GET("/listings/map", h.HandleMap)
 	e.GET(domain.PathListingID, h.HandleDetail)
+	e.GET(domain.PathListingID+"/extend", h.HandleExtendConfirm)
+	e.POST(domain.PathListingID+"/extend", h.HandleExtend)
 	e.POST("/api/metrics", h.HandleMetricsIngestion)
 
 	// Authenticated Routes
@@ -44,6 +47,10 @@ func (h *ListingHandler) RegisterRoutes(e *echo.Echo, authMw domain.AuthMiddlewa
 	authGroup.DELETE(domain.PathListingID, h.HandleDelete)
 	authGroup.GET(domain.PathProfile, h.HandleProfile)
 	authGroup.POST(domain.PathListingID+"/claim", h.HandleClaim)
+	authGroup.POST(domain.PathListingID+"/claim/phone", h.HandleSendClaimPhoneCode)
+	authGroup.POST(domain.PathListingID+"/claim/phone/verify", h.HandleVerifyClaimPhone)
+	authGroup.GET(domain.PathListingID+"/revisions", h.HandleRevisions)
+	authGroup.POST(domain.PathListingID+"/revisions/:rev/restore", h.HandleRestoreRevision)
 }
 
 // Home Handler
@@ -71,7 +78,7 @@ func (h *ListingHandler) HandleHome(c echo.Context) error {
 		wg sync.WaitGroup
 	)
 
-	var totalCount int
+	var result domain.ListingPage
 	filterType := c.QueryParam(domain.FieldType)
 	if filterType == "All" {
 		filterType = ""
@@ -79,22 +86,27 @@ func (h *ListingHandler) HandleHome(c echo.Context) error {
 		filterType = string(domain.Food)
 	}
 	queryText := c.QueryParam(domain.ParamQuery)
-	city := c.QueryParam(domain.FieldCity)
-	radiusStr := c.QueryParam("radius")
-	var radius float64
-	if radiusStr != "" {
-		radius, _ = strconv.ParseFloat(radiusStr, 64)
-	}
 
-	var lat, lng float64
-	if city != "" && radius > 0 {
-		lat, lng, _ = h.App.GeocodingSvc.Geocode(ctx, city)
+	q := domain.ListingQuery{
+		Types:     domain.TypeFilter(filterType),
+		QueryText: queryText,
+		Sort:      searchSort(queryText),
+		Limit:     limit,
+		Offset:    offset,
+		ViewerID:  user.ViewerID(c),
+	}
+	loc, err := h.parseLocationFilter(c, &q)
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	if err = parseOpenFilter(c, &q); err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
 	}
 
 	wg.Add(4)
 	go func() {
 		defer wg.Done()
-		listings, totalCount, listingsErr = h.App.DB.FindAll(ctx, filterType, queryText, city, lat, lng, radius, "", "", false, limit, offset)
+		result, listingsErr = h.App.DB.Search(ctx, q)
 	}()
 	go func() {
 		defer wg.Done()
@@ -120,7 +132,7 @@ func (h *ListingHandler) HandleHome(c echo.Context) error {
 	if listingsErr != nil {
 		return ui.RespondError(c, listingsErr)
 	}
-	hasNextPage := offset+len(listings) < totalCount
+	listings, totalCount := result.Listings, result.TotalCount
 
 	h.LogError(c, "failed to get listing counts", countsErr)
 	h.LogError(c, "failed to get featured listings", featuredErr)
@@ -133,86 +145,181 @@ func (h *ListingHandler) HandleHome(c echo.Context) error {
 	}
 
 	now := time.Now()
-	for i := range listings {
-		listings[i].IsCurrentlyOpen = service.ComputeIsOpen(listings[i].HoursOfOperation, listings[i].StructuredHours, now)
-	}
-	for i := range featured {
-		featured[i].IsCurrentlyOpen = service.ComputeIsOpen(featured[i].HoursOfOperation, featured[i].StructuredHours, now)
-	}
+	service.ApplyOpenState(listings, now)
+	service.ApplyOpenState(featured, now)
 
 	u := c.Get(domain.CtxKeyUser)
 
 	return h.RenderWithBaseContext(c, domain.TemplateIndex, map[string]interface{}{
 		"Listings":         listings,
-		"Pagination":       Pagination{Page: page, TotalPages: (totalCount + limit - 1) / limit, HasNextPage: hasNextPage, TotalCount: totalCount},
+		"Pagination":       Pagination{Page: page, TotalPages: (totalCount + limit - 1) / limit, HasNextPage: result.NextCursor != "", NextCursor: result.NextCursor, TotalCount: totalCount},
 		"FeaturedListings": featured,
 		"Counts":           strCounts,
 		"Locations":        locations,
 		"TotalCount":       totalCount,
 		"Categories":       categories,
 		"Category":         filterType,
-		"City":             city,
-		"Radius":           radius,
+		"City":             loc.City,
+		"Radius":           q.RadiusMiles,
+		"Lat":              loc.Lat,
+		"Lng":              loc.Lng,
+		"NearLabel":        loc.Near,
 		"QueryText":        queryText,
+		"OpenNow":          q.OpenNow,
+		"OpenAt":           c.QueryParam(domain.ParamOpenAt),
 		"User":             u,
 		"GoogleMapsApiKey": h.App.Cfg.GoogleMapsAPIKey,
 	})
 }
 
+// nearMeRadiusMiles bounds a "near me" search when the visitor has not picked a radius.
+const nearMeRadiusMiles = 25
+
+// locationFilter is the location part of a search as the templates show it.
+type locationFilter struct {
+	// City is the city searched, empty for a "near me" search.
+	City string
+	// Near names the area around Lat and Lng, such as "Katy, TX".
+	Near string
+	// Lat and Lng are kept as sent so pagination links repeat them exactly.
+	Lat string
+	Lng string
+}
+
+// parseLocationFilter reads the city, radius, lat and lng search parameters onto q.
+// Coordinates from the browser's location take precedence over a city: results are
+// limited to the radius around them and sorted nearest first.
+func (h *ListingHandler) parseLocationFilter(c echo.Context, q *domain.ListingQuery) (locationFilter, error) {
+	ctx := c.Request().Context()
+	loc := locationFilter{City: c.QueryParam(domain.FieldCity)}
+	if v := c.QueryParam(domain.ParamRadius); v != "" {
+		radius, err := domain.ParseRadius(v)
+		if err != nil {
+			return loc, err
+		}
+		q.RadiusMiles = radius
+	}
+
+	lat, lng, ok, err := domain.ParseCoordinates(c.QueryParam(domain.ParamLat), c.QueryParam(domain.ParamLng))
+	if err != nil {
+		return loc, err
+	}
+	if ok {
+		loc.City = ""
+		loc.Lat, loc.Lng = c.QueryParam(domain.ParamLat), c.QueryParam(domain.ParamLng)
+		q.Latitude, q.Longitude = lat, lng
+		q.Sort = domain.SortDistance
+		if q.RadiusMiles <= 0 {
+			q.RadiusMiles = nearMeRadiusMiles
+		}
+		var labelErr error
+		loc.Near, labelErr = h.App.GeocodingSvc.ReverseGeocode(ctx, lat, lng)
+		if !errors.Is(labelErr, domain.ErrLocationNotFound) {
+			h.LogError(c, "failed to reverse geocode search location", labelErr)
+		}
+		return loc, nil
+	}
+
+	q.Cities = domain.CityFilter(loc.City)
+	if loc.City != "" && q.RadiusMiles > 0 {
+		var geoErr error
+		q.Latitude, q.Longitude, geoErr = h.App.GeocodingSvc.Geocode(ctx, loc.City)
+		h.LogError(c, "failed to geocode city for radius search", geoErr)
+	}
+	return loc, nil
+}
+
+// searchSort is the order of a home page search: best match first when there is
+// search text. A location search replaces it with nearest first.
+func searchSort(queryText string) string {
+	if queryText == "" {
+		return ""
+	}
+	return domain.SortRelevance
+}
+
+// parseOpenFilter reads the open_now and open_at search parameters onto q.
+func parseOpenFilter(c echo.Context, q *domain.ListingQuery) error {
+	q.OpenNow = c.QueryParam(domain.ParamOpenNow) == "true"
+	v := c.QueryParam(domain.ParamOpenAt)
+	if v == "" {
+		return nil
+	}
+	var err error
+	q.OpenAt, q.OpenAtWallClock, err = domain.ParseOpenAt(v)
+	return err
+}
+
 // Fragment Handler (HTMX)
 func (h *ListingHandler) HandleFragment(c echo.Context) error {
 	filterType := c.QueryParam(domain.FieldType)
 	queryText := c.QueryParam(domain.ParamQuery)
-	city := c.QueryParam(domain.FieldCity)
-	radiusStr := c.QueryParam("radius")
 
 	p := GetPagination(c, 30)
 	page := p.Page
 	limit := p.Limit
 	offset := p.Offset
 
-	var radius float64
-	if radiusStr != "" {
-		radius, _ = strconv.ParseFloat(radiusStr, 64)
-	}
-
 	if filterType == "All" {
 		filterType = ""
 	} else if filterType == "" {
 		filterType = string(domain.Food)
 	}
 
-	// Geocode city if provided for radius search
-	var lat, lng float64
-	if city != "" && radius > 0 {
-		lat, lng, _ = h.App.GeocodingSvc.Geocode(c.Request().Context(), city)
+	// Deep pages arrive with the cursor from the previous page's "next" link and
+	// skip the total count; page numbers are only rendered from the first page.
+	cursor := c.QueryParam(domain.ParamCursor)
+	q := domain.ListingQuery{
+		Types:     domain.TypeFilter(filterType),
+		QueryText: queryText,
+		Sort:      searchSort(queryText),
+		Limit:     limit,
+		Offset:    offset,
+		Cursor:    cursor,
+		SkipCount: cursor != "",
+		ViewerID:  user.ViewerID(c),
+	}
+	loc, err := h.parseLocationFilter(c, &q)
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	if err = parseOpenFilter(c, &q); err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	}
+	result, err := h.App.DB.Search(c.Request().Context(), q)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
 	}
-
-	listings, totalCount, err := h.App.DB.FindAll(c.Request().Context(), filterType, queryText, city, lat, lng, radius, "", "", false, limit, offset)
 	if err != nil {
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, err.Error())
 	}
-	hasNextPage := offset+len(listings) < totalCount
+	listings := result.Listings
+	pagination := Pagination{Page: page, HasNextPage: result.NextCursor != "", NextCursor: result.NextCursor}
+	if result.TotalCount >= 0 {
+		pagination.TotalCount = result.TotalCount
+		pagination.TotalPages = (result.TotalCount + limit - 1) / limit
+	}
 
 	// Fetch featured listings for the selected city and category to support Ada's discovery flow
-	featured, _ := h.App.DB.GetFeaturedListings(c.Request().Context(), filterType, city)
+	featured, _ := h.App.DB.GetFeaturedListings(c.Request().Context(), filterType, loc.City)
 
 	now := time.Now()
-	for i := range listings {
-		listings[i].IsCurrentlyOpen = service.ComputeIsOpen(listings[i].HoursOfOperation, listings[i].StructuredHours, now)
-	}
-	for i := range featured {
-		featured[i].IsCurrentlyOpen = service.ComputeIsOpen(featured[i].HoursOfOperation, featured[i].StructuredHours, now)
-	}
+	service.ApplyOpenState(listings, now)
+	service.ApplyOpenState(featured, now)
 
 	data := map[string]interface{}{
 		"Listings":         listings,
-		"Pagination":       Pagination{Page: page, TotalPages: (totalCount + limit - 1) / limit, HasNextPage: hasNextPage, TotalCount: totalCount},
+		"Pagination":       pagination,
 		"FeaturedListings": featured,
 		"Category":         filterType,
-		"City":             city,
-		"Radius":           radius,
+		"City":             loc.City,
+		"Radius":           q.RadiusMiles,
+		"Lat":              loc.Lat,
+		"Lng":              loc.Lng,
+		"NearLabel":        loc.Near,
 		"QueryText":        queryText,
+		"OpenNow":          q.OpenNow,
+		"OpenAt":           c.QueryParam(domain.ParamOpenAt),
 		"User":             c.Get(domain.CtxKeyUser),
 	}
 
@@ -229,16 +336,40 @@ func (h *ListingHandler) HandleDetail(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	if viewer, _ := user.GetUser(c); HiddenFrom(ctx, h.App.DB, listing, viewer) {
+		return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
+	}
 
 	// Fetch category data to check if claimable
 	category, _ := h.App.DB.GetCategory(ctx, string(listing.Type))
 
-	return c.Render(http.StatusOK, "modal_detail", map[string]interface{}{
+	data := map[string]interface{}{
 		"Listing":          listing,
 		"Category":         category,
 		"User":             c.Get(domain.CtxKeyUser),
 		"GoogleMapsApiKey": h.App.Cfg.GoogleMapsAPIKey,
-	})
+	}
+	if now := time.Now(); listing.IsClosedForGood(now) {
+		data["ClosedForGood"] = true
+	} else if notice, ok := listing.HoursNoticeAt(now); ok {
+		data["HoursNotice"] = notice
+	}
+	if u, ok := user.GetUser(c); ok && listing.OwnerID == "" && category.Claimable {
+		// The latest claim decides whether the user may claim again or sees its status.
+		claim, err := h.App.DB.GetClaimRequestByUserAndListing(ctx, u.ID, listing.ID)
+		switch {
+		case err != nil:
+			data["CanClaim"] = true
+		case claim.Status == domain.ClaimStatusPending:
+			data["ClaimPending"] = true
+		case time.Now().Before(claim.ResubmittableAt()):
+			data["ClaimRetryAt"] = claim.ResubmittableAt()
+		default:
+			data["CanClaim"] = true
+		}
+	}
+
+	return c.Render(http.StatusOK, "modal_detail", data)
 }
 
 // HandleEdit renders the edit modal
@@ -283,7 +414,8 @@ func (h *ListingHandler) findListing(c echo.Context, id string) (domain.Listing,
 		_ = ui.RespondErrorMsg(c, http.StatusNotFound, (domain.ErrListingNotFound).Error())
 		return domain.Listing{}, echo.ErrNotFound
 	}
-	listing.IsCurrentlyOpen = service.ComputeIsOpen(listing.HoursOfOperation, listing.StructuredHours, time.Now())
+	listing.OpenState = service.ComputeOpenState(listing, time.Now())
+	listing.IsCurrentlyOpen = listing.OpenState.IsOpen()
 	return listing, nil
 }
 
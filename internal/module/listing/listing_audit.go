@@ -0,0 +1,264 @@
+package listing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/user"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditStore is the persistence AuditService needs to mutate listings and record their history.
+type AuditStore interface {
+	domain.ListingStore
+	domain.ClaimRequestStore
+	domain.ListingEventStore
+	domain.ListingRevisionStore
+}
+
+// AuditService performs listing mutations and appends a ListingEvent describing each
+// one. Handlers and commands that change a listing should go through it rather than
+// calling the store directly, or the change will be missing from the listing's history.
+type AuditService struct {
+	Store AuditStore
+	Now   func() time.Time
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(store AuditStore) *AuditService {
+	return &AuditService{Store: store, Now: time.Now}
+}
+
+// Save persists l and records the fields that changed. An empty action is recorded as
+// create or update depending on whether the listing already existed. When an existing
+// listing changes, its previous version is kept as a revision first.
+func (s *AuditService) Save(ctx context.Context, actor domain.Actor, action domain.ListingAction, l domain.Listing) error {
+	return s.save(ctx, actor, action, "", l)
+}
+
+func (s *AuditService) save(ctx context.Context, actor domain.Actor, action domain.ListingAction, reason string, l domain.Listing) error {
+	// A failed lookup means the listing is new; any real storage fault surfaces from the write.
+	before, _ := s.Store.FindByID(ctx, l.ID)
+	if action == "" {
+		action = domain.ListingActionUpdate
+		if before.ID == "" {
+			action = domain.ListingActionCreate
+		}
+	}
+
+	var rev *domain.ListingRevision
+	if before.ID != "" && len(domain.DiffListings(before, l)) > 0 {
+		rev = &domain.ListingRevision{
+			ListingID: l.ID,
+			ActorID:   actor.ID,
+			ActorName: actor.Name,
+			Snapshot:  before,
+			CreatedAt: s.Now(),
+		}
+	}
+	// The revision, listing and event are written together, so a failed history write
+	// leaves the listing unchanged.
+	return s.Store.SaveListingWithHistory(ctx, before, l, rev, s.event(actor, action, reason, l.ID))
+}
+
+// Delete removes a listing, recording its final state as the before side of the event.
+func (s *AuditService) Delete(ctx context.Context, actor domain.Actor, id string) error {
+	before, err := s.Store.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.record(ctx, actor, domain.ListingActionDelete, "", id, before, domain.Listing{})
+}
+
+// SetFeatured features or unfeatures a listing.
+func (s *AuditService) SetFeatured(ctx context.Context, actor domain.Actor, id string, featured bool) error {
+	before, err := s.Store.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.Store.SetFeatured(ctx, id, featured); err != nil {
+		return err
+	}
+	action := domain.ListingActionUnfeature
+	if featured {
+		action = domain.ListingActionFeature
+	}
+	return s.recordCurrent(ctx, actor, action, "", before, id)
+}
+
+// SetPermanentlyClosed marks a listing as closed for good, which hides it from the
+// directory without deleting it, or reopens it.
+func (s *AuditService) SetPermanentlyClosed(ctx context.Context, actor domain.Actor, id string, closed bool) error {
+	l, err := s.Store.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	l.PermanentlyClosed = closed
+	action := domain.ListingActionReopen
+	if closed {
+		action = domain.ListingActionCloseForGood
+	}
+	return s.Save(ctx, actor, action, l)
+}
+
+// Deactivate takes a listing out of the directory on behalf of staff, recording their
+// reason in its history. An inactive listing is left alone.
+func (s *AuditService) Deactivate(ctx context.Context, actor domain.Actor, id, reason string) error {
+	l, err := s.Store.FindByID(ctx, id)
+	if err != nil || !l.IsActive {
+		return err
+	}
+	l.IsActive = false
+	return s.save(ctx, actor, domain.ListingActionDeactivate, reason, l)
+}
+
+// ResolveClaim records a moderator's review of a claim request. Approval transfers
+// ownership of the listing, which is recorded against it; rejection leaves the listing
+// untouched and needs a reason to show the claimant.
+func (s *AuditService) ResolveClaim(ctx context.Context, actor domain.Actor, claimID string, review domain.ClaimReview) (domain.ClaimRequest, error) {
+	review.RejectionReason = strings.TrimSpace(review.RejectionReason)
+	if review.Status == domain.ClaimStatusRejected && review.RejectionReason == "" {
+		return domain.ClaimRequest{}, domain.ErrRejectionReasonRequired
+	}
+	cr, err := s.Store.GetClaimRequest(ctx, claimID)
+	if err != nil {
+		return domain.ClaimRequest{}, err
+	}
+	before, _ := s.Store.FindByID(ctx, cr.ListingID)
+
+	review.ReviewerID = actor.ID
+	review.Notes = strings.TrimSpace(review.Notes)
+	if review.ReviewedAt.IsZero() {
+		review.ReviewedAt = s.Now()
+	}
+	if err := s.Store.ReviewClaimRequest(ctx, claimID, review); err != nil {
+		return domain.ClaimRequest{}, err
+	}
+	cr.Status = review.Status
+	cr.ReviewerID = review.ReviewerID
+	cr.ReviewerNotes = review.Notes
+	cr.RejectionReason = review.RejectionReason
+	cr.ReviewedAt = review.ReviewedAt
+
+	if review.Status != domain.ClaimStatusApproved || before.ID == "" {
+		return cr, nil
+	}
+	return cr, s.recordCurrent(ctx, actor, domain.ListingActionClaimApprove, "", before, cr.ListingID)
+}
+
+// Restore replaces a listing's content with that of one of its revisions. The version
+// being replaced becomes a new revision, so a restore can itself be undone.
+func (s *AuditService) Restore(ctx context.Context, actor domain.Actor, listingID string, number int) (domain.Listing, error) {
+	rev, err := s.Store.GetListingRevision(ctx, listingID, number)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	current, err := s.Store.FindByID(ctx, listingID)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+
+	restored := rev.RestoreOnto(current)
+	if err := s.Save(ctx, actor, domain.ListingActionRestore, restored); err != nil {
+		return domain.Listing{}, err
+	}
+	return restored, nil
+}
+
+// RevisionDiff pairs a revision with the changes made by the save that superseded it.
+type RevisionDiff struct {
+	domain.ListingRevision
+	Changes []domain.FieldChange
+}
+
+// Revisions returns a listing's revisions newest first, each diffed against the
+// version that replaced it: the next revision, or the live listing for the latest.
+func (s *AuditService) Revisions(ctx context.Context, listingID string) ([]RevisionDiff, error) {
+	revs, err := s.Store.ListListingRevisions(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	// A deleted listing still has revisions; the latest is then diffed against nothing.
+	next, _ := s.Store.FindByID(ctx, listingID)
+
+	diffs := make([]RevisionDiff, 0, len(revs))
+	for i := len(revs) - 1; i >= 0; i-- {
+		diffs = append(diffs, RevisionDiff{ListingRevision: revs[i], Changes: domain.DiffListings(revs[i].Snapshot, next)})
+		next = revs[i].Snapshot
+	}
+	return diffs, nil
+}
+
+// History returns a listing's events, oldest first.
+func (s *AuditService) History(ctx context.Context, listingID string) ([]domain.ListingEvent, error) {
+	return s.Store.ListListingEvents(ctx, listingID)
+}
+
+// For returns a ListingStore whose writes are recorded as actor, for code such as the
+// CSV importer that accepts a plain store.
+func (s *AuditService) For(actor domain.Actor) domain.ListingStore {
+	return actorStore{ListingStore: s.Store, audit: s, actor: actor}
+}
+
+type actorStore struct {
+	domain.ListingStore
+	audit *AuditService
+	actor domain.Actor
+}
+
+func (a actorStore) Save(ctx context.Context, l domain.Listing) error {
+	return a.audit.Save(ctx, a.actor, "", l)
+}
+
+func (a actorStore) Delete(ctx context.Context, id string) error {
+	return a.audit.Delete(ctx, a.actor, id)
+}
+
+func (a actorStore) SetFeatured(ctx context.Context, id string, featured bool) error {
+	return a.audit.SetFeatured(ctx, a.actor, id, featured)
+}
+
+// recordCurrent re-reads the listing so that before and after are both compared as
+// stored, rather than against an in-memory copy that may differ in time precision.
+func (s *AuditService) recordCurrent(ctx context.Context, actor domain.Actor, action domain.ListingAction, reason string, before domain.Listing, id string) error {
+	after, err := s.Store.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("reload listing %s for history: %w", id, err)
+	}
+	return s.record(ctx, actor, action, reason, id, before, after)
+}
+
+func (s *AuditService) record(ctx context.Context, actor domain.Actor, action domain.ListingAction, reason, id string, before, after domain.Listing) error {
+	e := s.event(actor, action, reason, id)
+	e.Changes = domain.DiffListings(before, after)
+	if err := s.Store.AppendListingEvent(ctx, e); err != nil {
+		return fmt.Errorf("record listing event: %w", err)
+	}
+	return nil
+}
+
+// event is a new history entry for the listing id, without its changes.
+func (s *AuditService) event(actor domain.Actor, action domain.ListingAction, reason, id string) domain.ListingEvent {
+	return domain.ListingEvent{
+		ID:        uuid.New().String(),
+		ListingID: id,
+		ActorID:   actor.ID,
+		ActorName: actor.Name,
+		Action:    action,
+		Reason:    reason,
+		CreatedAt: s.Now(),
+	}
+}
+
+// RequestActor returns the actor for the signed-in user making the request.
+func RequestActor(c echo.Context) domain.Actor {
+	u, _ := user.GetUser(c)
+	return domain.ActorFromUser(u)
+}
@@ -0,0 +1,163 @@
+package listing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	listmod "github.com/jadecobra/agbalumo/internal/module/listing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditActor = domain.Actor{ID: "admin-1", Name: "Ada Admin"}
+
+func changedFields(e domain.ListingEvent) map[string]domain.FieldChange {
+	fields := make(map[string]domain.FieldChange, len(e.Changes))
+	for _, c := range e.Changes {
+		fields[c.Field] = c
+	}
+	return fields
+}
+
+func TestAuditService_RecordsMutations(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	repo := env.App.DB
+	ctx := context.Background()
+	svc := listmod.NewAuditService(repo)
+
+	l := domain.Listing{ID: "a1", Title: "Jollof House", Type: domain.Food, OwnerOrigin: "Ghana", Status: domain.ListingStatusPending, CreatedAt: time.Now()}
+	require.NoError(t, svc.Save(ctx, auditActor, "", l))
+
+	l.Status = domain.ListingStatusApproved
+	require.NoError(t, svc.Save(ctx, auditActor, domain.ListingActionApprove, l))
+	require.NoError(t, svc.SetFeatured(ctx, auditActor, "a1", true))
+	require.NoError(t, svc.Delete(ctx, domain.ActorCLI, "a1"))
+
+	events, err := svc.History(ctx, "a1")
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	assert.Equal(t, domain.ListingActionCreate, events[0].Action)
+	assert.Equal(t, "Jollof House", changedFields(events[0])["title"].After)
+
+	assert.Equal(t, domain.ListingActionApprove, events[1].Action)
+	assert.Equal(t, []domain.FieldChange{{Field: "status", Before: "Pending", After: "Approved"}}, events[1].Changes)
+	assert.Equal(t, auditActor.ID, events[1].ActorID)
+	assert.Equal(t, auditActor.Name, events[1].ActorName)
+
+	assert.Equal(t, domain.ListingActionFeature, events[2].Action)
+	assert.Equal(t, []domain.FieldChange{{Field: "featured", Before: "false", After: "true"}}, events[2].Changes)
+
+	assert.Equal(t, domain.ListingActionDelete, events[3].Action)
+	assert.Equal(t, domain.ActorCLI.ID, events[3].ActorID)
+	assert.Equal(t, domain.FieldChange{Field: "title", Before: "Jollof House"}, changedFields(events[3])["title"])
+}
+
+func TestAuditService_ResolveClaim(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	repo := env.App.DB
+	ctx := context.Background()
+	svc := listmod.NewAuditService(repo)
+
+	testutil.SaveTestListing(t, repo, "c1", "Claimable Shop")
+	for _, cr := range []domain.ClaimRequest{
+		{ID: "claim-ok", ListingID: "c1", UserID: "owner-1", Status: domain.ClaimStatusPending, CreatedAt: time.Now()},
+		{ID: "claim-no", ListingID: "c1", UserID: "owner-2", Status: domain.ClaimStatusPending, CreatedAt: time.Now()},
+	} {
+		require.NoError(t, repo.SaveClaimRequest(ctx, cr))
+	}
+
+	_, err := svc.ResolveClaim(ctx, auditActor, "claim-no", domain.ClaimReview{Status: domain.ClaimStatusRejected})
+	assert.ErrorIs(t, err, domain.ErrRejectionReasonRequired)
+	rejected, err := svc.ResolveClaim(ctx, auditActor, "claim-no", domain.ClaimReview{
+		Status: domain.ClaimStatusRejected, RejectionReason: " Not the owner ", Notes: "Phone rang out",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Not the owner", rejected.RejectionReason)
+	assert.Equal(t, auditActor.ID, rejected.ReviewerID)
+	assert.False(t, rejected.ReviewedAt.IsZero())
+
+	_, err = svc.ResolveClaim(ctx, auditActor, "claim-ok", domain.ClaimReview{Status: domain.ClaimStatusApproved})
+	require.NoError(t, err)
+	_, err = svc.ResolveClaim(ctx, auditActor, "missing", domain.ClaimReview{Status: domain.ClaimStatusApproved})
+	assert.Error(t, err)
+
+	stored, err := repo.GetClaimRequest(ctx, "claim-no")
+	require.NoError(t, err)
+	assert.Equal(t, "Phone rang out", stored.ReviewerNotes)
+
+	events, err := svc.History(ctx, "c1")
+	require.NoError(t, err)
+	require.Len(t, events, 1, "only approval changes the listing")
+	assert.Equal(t, domain.ListingActionClaimApprove, events[0].Action)
+	assert.Equal(t, []domain.FieldChange{{Field: "owner_id", After: "owner-1"}}, events[0].Changes)
+}
+
+func TestAuditService_For(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	svc := listmod.NewAuditService(env.App.DB)
+
+	store := svc.For(auditActor)
+	require.NoError(t, store.Save(ctx, domain.Listing{ID: "f1", Title: "Imported", Type: domain.Business, CreatedAt: time.Now()}))
+
+	events, err := svc.History(ctx, "f1")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, domain.ListingActionCreate, events[0].Action)
+	assert.Equal(t, auditActor.ID, events[0].ActorID)
+}
+
+func TestAuditService_RevisionsAndRestore(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	repo := env.App.DB
+	ctx := context.Background()
+	svc := listmod.NewAuditService(repo)
+
+	l := domain.Listing{ID: "r1", Title: "Original", Type: domain.Food, OwnerOrigin: "Ghana", CreatedAt: time.Now()}
+	require.NoError(t, svc.Save(ctx, auditActor, "", l))
+
+	l.Title = "Vandalized"
+	require.NoError(t, svc.Save(ctx, domain.Actor{ID: "vandal"}, "", l))
+
+	// A save that changes nothing does not add a revision.
+	current, err := repo.FindByID(ctx, "r1")
+	require.NoError(t, err)
+	require.NoError(t, svc.Save(ctx, auditActor, "", current))
+
+	revs, err := svc.Revisions(ctx, "r1")
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	assert.Equal(t, 1, revs[0].Number)
+	assert.Equal(t, "vandal", revs[0].ActorID)
+	assert.Equal(t, []domain.FieldChange{{Field: "title", Before: "Original", After: "Vandalized"}}, revs[0].Changes)
+
+	restored, err := svc.Restore(ctx, auditActor, "r1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Original", restored.Title)
+
+	revs, err = svc.Revisions(ctx, "r1")
+	require.NoError(t, err)
+	require.Len(t, revs, 2, "the restored-over version is kept")
+	assert.Equal(t, "Vandalized", revs[0].Snapshot.Title)
+	assert.Equal(t, []domain.FieldChange{{Field: "title", Before: "Vandalized", After: "Original"}}, revs[0].Changes)
+
+	events, err := svc.History(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ListingActionRestore, events[len(events)-1].Action)
+
+	_, err = svc.Restore(ctx, auditActor, "r1", 99)
+	assert.ErrorIs(t, err, domain.ErrRevisionNotFound)
+}
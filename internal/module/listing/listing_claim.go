@@ -6,22 +6,45 @@ import (
 	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 
+	"context"
 	"errors"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-// HandleClaim processes a request to claim an unowned listing.
+const tmplProfileClaims = "profile_claims"
+
+// HandleClaim processes a request to claim an unowned listing. The claimant may attach
+// a business email and an image of a supporting document.
 func (h *ListingHandler) HandleClaim(c echo.Context) error {
 	u, ok := user.GetUser(c)
 	if !ok {
-		return c.Redirect(http.StatusFound, "/auth/google/login")
+		return c.Redirect(http.StatusFound, "/auth/login")
 	}
 
 	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	var documentURL string
+	if file := h.getFileHeader(c, domain.FieldClaimDocument); file != nil {
+		// Documents get a random name so they cannot be found from the listing ID.
+		url, err := h.App.ImageSvc.UploadImage(ctx, file, "claim-"+uuid.New().String())
+		if err != nil {
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				return ui.RespondError(c, he)
+			}
+			return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to upload document")
+		}
+		documentURL = url
+	}
 
-	_, err := h.App.ListingSvc.ClaimListing(c.Request().Context(), *u, id)
+	_, err := h.App.ListingSvc.ClaimListing(ctx, *u, id, domain.ClaimEvidence{
+		Email:       c.FormValue(domain.FieldEvidenceEmail),
+		DocumentURL: documentURL,
+	})
 	if err != nil {
 		if errors.Is(err, domain.ErrListingNotFound) {
 			return ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
@@ -29,17 +52,108 @@ func (h *ListingHandler) HandleClaim(c echo.Context) error {
 		if errors.Is(err, domain.ErrListingOwned) || errors.Is(err, domain.ErrListingNotClaimable) {
 			return ui.RespondErrorMsg(c, http.StatusForbidden, err.Error())
 		}
-		if errors.Is(err, domain.ErrPendingClaimExists) {
+		if errors.Is(err, domain.ErrPendingClaimExists) || errors.Is(err, domain.ErrClaimCooldown) {
 			return ui.RespondErrorMsg(c, http.StatusConflict, err.Error())
 		}
+		if errors.Is(err, domain.ErrInvalidEvidenceEmail) {
+			return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+		}
 		return ui.RespondErrorMsg(c, http.StatusInternalServerError, "Failed to submit claim: "+err.Error())
 	}
 
-	// HTMX: replace the claim button with a pending-approval notice
+	next := "Follow your claim on your profile"
+	if h.App.SMS != nil {
+		next = "Verify the listing's phone and follow your claim on your profile"
+	}
+
+	// HTMX: replace the claim form with a pending-approval notice
 	c.Response().Header().Set(common.HeaderContentType, common.MimeHTML)
 	return c.HTML(http.StatusOK, `
-		<div class="flex items-center gap-2 bg-earth-accent/10 border border-earth-accent/20 px-3 py-1.5">
-			<span class="material-symbols-outlined text-[14px] text-earth-accent">pending</span>
-			<span class="text-earth-accent text-xs font-bold uppercase tracking-widest">Claim Pending Review</span>
+		<div class="flex flex-col gap-1 bg-earth-accent/10 border border-earth-accent/20 px-3 py-1.5">
+			<span class="flex items-center gap-2">
+				<span class="material-symbols-outlined text-[14px] text-earth-accent">pending</span>
+				<span class="text-earth-accent text-xs font-bold uppercase tracking-widest">Claim Pending Review</span>
+			</span>
+			<a href="/profile#profile-claims" class="text-xs text-earth-accent underline">`+next+`</a>
 		</div>`)
 }
+
+// HandleSendClaimPhoneCode texts a verification code to the phone number of a listing
+// the user has a pending claim on.
+func (h *ListingHandler) HandleSendClaimPhoneCode(c echo.Context) error {
+	return h.handleClaimPhone(c, func(ctx context.Context, v *ClaimPhoneVerifier, u *domain.User) (string, error) {
+		return "We texted a code to the listing's phone number.", v.SendCode(ctx, u.ID, c.Param("id"))
+	})
+}
+
+// HandleVerifyClaimPhone checks the code the user received on the listing's phone.
+func (h *ListingHandler) HandleVerifyClaimPhone(c echo.Context) error {
+	return h.handleClaimPhone(c, func(ctx context.Context, v *ClaimPhoneVerifier, u *domain.User) (string, error) {
+		return "Phone verified.", v.Verify(ctx, u.ID, c.Param("id"), c.FormValue(domain.FieldCode))
+	})
+}
+
+func (h *ListingHandler) handleClaimPhone(c echo.Context, step func(context.Context, *ClaimPhoneVerifier, *domain.User) (string, error)) error {
+	u, err := user.RequireUser(c)
+	if err != nil || u == nil {
+		return err
+	}
+
+	msg, err := step(c.Request().Context(), NewClaimPhoneVerifier(h.App.DB, h.App.SMS), u)
+	switch {
+	case errors.Is(err, domain.ErrClaimNotFound), errors.Is(err, domain.ErrListingNotFound):
+		return ui.RespondErrorMsg(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrNoClaimPhone), errors.Is(err, domain.ErrInvalidPhoneCode):
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrPhoneVerificationUnavailable):
+		return ui.RespondErrorMsg(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrListingOwned):
+		return ui.RespondErrorMsg(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, domain.ErrClaimNotPending), errors.Is(err, domain.ErrPhoneAlreadyVerified):
+		return ui.RespondErrorMsg(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrPhoneCodeRecentlySent):
+		return ui.RespondErrorMsg(c, http.StatusTooManyRequests, err.Error())
+	case err != nil:
+		return ui.RespondError(c, err)
+	}
+	return h.renderClaims(c, u, msg)
+}
+
+// profileClaim is a claim as its claimant sees it on their profile.
+type profileClaim struct {
+	domain.ClaimRequest
+	// CanVerifyPhone is set while the claim is pending, text messages can be sent and
+	// the listing has a phone number that has not been verified yet.
+	CanVerifyPhone bool
+	// CodeSent is set when a code has been sent and not yet used.
+	CodeSent bool
+}
+
+func (h *ListingHandler) profileClaims(ctx context.Context, userID string) ([]profileClaim, error) {
+	claims, err := h.App.DB.ListClaimRequestsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]profileClaim, 0, len(claims))
+	for _, cr := range claims {
+		v := profileClaim{ClaimRequest: cr, CodeSent: cr.PhoneCodeHash != ""}
+		if h.App.SMS != nil && cr.Status == domain.ClaimStatusPending && !cr.PhoneVerified() {
+			l, err := h.App.DB.FindByID(ctx, cr.ListingID)
+			v.CanVerifyPhone = err == nil && l.ContactPhone != ""
+		}
+		views = append(views, v)
+	}
+	return views, nil
+}
+
+func (h *ListingHandler) renderClaims(c echo.Context, u *domain.User, message string) error {
+	claims, err := h.profileClaims(c.Request().Context(), u.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	return c.Render(http.StatusOK, tmplProfileClaims, map[string]interface{}{
+		"User":         u,
+		"Claims":       claims,
+		"ClaimMessage": message,
+	})
+}
@@ -0,0 +1,108 @@
+package listing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSMS struct {
+	to, body string
+}
+
+func (r *recordingSMS) SendSMS(_ context.Context, to, body string) error {
+	r.to, r.body = to, body
+	return nil
+}
+
+func TestClaimPhoneVerifier(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Biz", func(l *domain.Listing) { l.ContactPhone = "+2348000000000" })
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "c1", ListingID: "l1", UserID: "u1", Status: domain.ClaimStatusPending, CreatedAt: time.Now(),
+	}))
+
+	sms := &recordingSMS{}
+	now := time.Now()
+	v := listing.NewClaimPhoneVerifier(env.App.DB, sms)
+	v.Now = func() time.Time { return now }
+	v.NewCode = func() (string, error) { return "123456", nil }
+
+	require.NoError(t, v.SendCode(ctx, "u1", "l1"))
+	assert.Equal(t, "+2348000000000", sms.to)
+	assert.Contains(t, sms.body, "123456")
+	assert.ErrorIs(t, v.SendCode(ctx, "u1", "l1"), domain.ErrPhoneCodeRecentlySent)
+
+	// A wrong guess burns the code.
+	assert.ErrorIs(t, v.Verify(ctx, "u1", "l1", "000000"), domain.ErrInvalidPhoneCode)
+	assert.ErrorIs(t, v.Verify(ctx, "u1", "l1", "123456"), domain.ErrInvalidPhoneCode)
+
+	now = now.Add(domain.ClaimPhoneCodeResendInterval)
+	require.NoError(t, v.SendCode(ctx, "u1", "l1"))
+	require.NoError(t, v.Verify(ctx, "u1", "l1", " 123456 "))
+
+	cr, err := env.App.DB.GetClaimRequest(ctx, "c1")
+	require.NoError(t, err)
+	assert.True(t, cr.PhoneVerified())
+	assert.Empty(t, cr.PhoneCodeHash)
+
+	assert.ErrorIs(t, v.SendCode(ctx, "u1", "l1"), domain.ErrPhoneAlreadyVerified)
+	assert.ErrorIs(t, v.SendCode(ctx, "u2", "l1"), domain.ErrClaimNotFound)
+}
+
+func TestClaimPhoneVerifier_RejectsSettledClaims(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Biz", func(l *domain.Listing) { l.ContactPhone = "+2348000000000" })
+	testutil.SaveTestListing(t, env.App.DB, "l2", "Taken", func(l *domain.Listing) {
+		l.ContactPhone = "+2348000000001"
+		l.OwnerID = "someone-else"
+	})
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "c1", ListingID: "l1", UserID: "u1", Status: domain.ClaimStatusRejected, CreatedAt: time.Now(),
+	}))
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "c2", ListingID: "l2", UserID: "u1", Status: domain.ClaimStatusPending, CreatedAt: time.Now(),
+	}))
+
+	sms := &recordingSMS{}
+	v := listing.NewClaimPhoneVerifier(env.App.DB, sms)
+	assert.ErrorIs(t, v.SendCode(ctx, "u1", "l1"), domain.ErrClaimNotPending)
+	assert.ErrorIs(t, v.Verify(ctx, "u1", "l1", "123456"), domain.ErrClaimNotPending)
+	assert.ErrorIs(t, v.SendCode(ctx, "u1", "l2"), domain.ErrListingOwned)
+	assert.Empty(t, sms.to, "no code is sent")
+}
+
+func TestClaimPhoneVerifier_NoSender(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+
+	testutil.SaveTestListing(t, env.App.DB, "l1", "Biz", func(l *domain.Listing) { l.ContactPhone = "+2348000000000" })
+	require.NoError(t, env.App.DB.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "c1", ListingID: "l1", UserID: "u1", Status: domain.ClaimStatusPending, CreatedAt: time.Now(),
+	}))
+
+	v := listing.NewClaimPhoneVerifier(env.App.DB, nil)
+	assert.ErrorIs(t, v.SendCode(ctx, "u1", "l1"), domain.ErrPhoneVerificationUnavailable)
+	assert.ErrorIs(t, v.Verify(ctx, "u1", "l1", "123456"), domain.ErrPhoneVerificationUnavailable)
+
+	cr, err := env.App.DB.GetClaimRequest(ctx, "c1")
+	require.NoError(t, err)
+	assert.Empty(t, cr.PhoneCodeHash)
+	assert.False(t, cr.PhoneVerified())
+}
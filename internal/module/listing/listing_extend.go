@@ -0,0 +1,109 @@
+package listing
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/service"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const tmplListingExtend = "listing_extend.html"
+
+// HandleExtendConfirm shows what following an expiry reminder's extension link will do.
+// The link carries its own authorization, so no session is needed, and the extension
+// itself is a POST so that mail scanners opening the link cannot trigger it.
+func (h *ListingHandler) HandleExtendConfirm(c echo.Context) error {
+	token := c.QueryParam(domain.ParamToken)
+	l, err := h.findExtendableListing(c, token)
+	if err != nil {
+		return err
+	}
+	next, _, err := h.extension(c, l)
+	if err != nil {
+		return err
+	}
+	return h.RenderWithBaseContext(c, tmplListingExtend, map[string]interface{}{
+		"Listing":     l,
+		"NewDeadline": next.Deadline,
+		"Token":       token,
+	})
+}
+
+// HandleExtend extends a request's deadline from a signed extension link. The change
+// is recorded against the listing's owner, who the link was sent to.
+func (h *ListingHandler) HandleExtend(c echo.Context) error {
+	l, err := h.findExtendableListing(c, c.FormValue(domain.ParamToken))
+	if err != nil {
+		return err
+	}
+	next, actor, err := h.extension(c, l)
+	if err != nil {
+		return err
+	}
+	if err := NewAuditService(h.App.DB).Save(c.Request().Context(), actor, domain.ListingActionExtend, next); err != nil {
+		return ui.RespondError(c, err)
+	}
+
+	return h.RenderWithBaseContext(c, tmplListingExtend, map[string]interface{}{
+		"Listing":  next,
+		"Extended": true,
+	})
+}
+
+// findExtendableListing fetches the listing a signed extension link is for, writing a
+// 404 or 403 when there is none or the token does not verify. Callers must return the
+// error immediately; the response is already committed.
+func (h *ListingHandler) findExtendableListing(c echo.Context, token string) (domain.Listing, error) {
+	l, err := h.App.DB.FindByID(c.Request().Context(), c.Param(domain.ParamID))
+	if err != nil {
+		_ = ui.RespondErrorMsg(c, http.StatusNotFound, domain.ErrListingNotFound.Error())
+		return domain.Listing{}, echo.ErrNotFound
+	}
+	links := service.NewExtensionLinks(h.App.Cfg.SessionSecret, h.App.Cfg.BaseURL)
+	if err := links.Verify(l, token); err != nil {
+		_ = ui.RespondErrorMsg(c, http.StatusForbidden, err.Error())
+		return domain.Listing{}, echo.ErrForbidden
+	}
+	return l, nil
+}
+
+// extension returns l as extending it would leave it, with the owner the change is
+// recorded against. The link outlives the state it was sent for, so a listing taken
+// offline by staff, or one whose owner is suspended or banned, is not extended. Like
+// findExtendableListing, it writes the response for any error it returns.
+func (h *ListingHandler) extension(c echo.Context, l domain.Listing) (domain.Listing, domain.Actor, error) {
+	ctx := c.Request().Context()
+	now := time.Now()
+	actor := domain.Actor{ID: l.OwnerID}
+	if owner, err := h.App.DB.FindUserByID(ctx, l.OwnerID); err == nil {
+		if accessErr := owner.CheckAccess(now); accessErr != nil {
+			_ = ui.RespondErrorMsg(c, http.StatusForbidden, accessErr.Error())
+			return l, actor, echo.ErrForbidden
+		}
+		actor = domain.ActorFromUser(&owner)
+	}
+
+	history, err := h.App.DB.ListListingEvents(ctx, l.ID)
+	if err != nil {
+		_ = ui.RespondError(c, err)
+		return l, actor, err
+	}
+	next, err := l.ExtendDeadline(now, history)
+	if err != nil {
+		return l, actor, respondExtendError(c, err)
+	}
+	return next, actor, nil
+}
+
+func respondExtendError(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrInvalidDeadline) || errors.Is(err, domain.ErrListingNotExtendable) {
+		_ = ui.RespondErrorMsg(c, http.StatusConflict, err.Error())
+		return echo.ErrConflict
+	}
+	_ = ui.RespondError(c, err)
+	return err
+}
@@ -0,0 +1,207 @@
+package listing_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/service"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedExpiringRequest(t *testing.T, env testutil.ModuleTestEnv, id string) (domain.Listing, string) {
+	t.Helper()
+	testutil.SaveTestListing(t, env.App.DB, id, "Need a tailor", func(l *domain.Listing) {
+		l.Type = domain.Request
+		l.OwnerID = "owner-1"
+		l.Deadline = time.Now().Add(48 * time.Hour)
+	})
+	require.NoError(t, env.App.DB.SaveUser(context.Background(), domain.User{ID: "owner-1", Name: "Ada"}))
+	l, err := env.App.DB.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	return l, service.NewExtensionLinks(env.App.Cfg.SessionSecret, env.App.Cfg.BaseURL).Token(l)
+}
+
+func TestHandleExtendConfirm(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	l, token := seedExpiringRequest(t, env, "ext-view")
+	h := listing.NewListingHandler(env.App)
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/ext-view/extend?token="+url.QueryEscape(token), nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "listing_extend.html")
+	c.SetParamNames("id")
+	c.SetParamValues("ext-view")
+
+	require.NoError(t, h.HandleExtendConfirm(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `action="/listings/ext-view/extend"`)
+	assert.Contains(t, body, l.Deadline.Add(domain.ListingExtensionPeriod).Format("Monday, 2 January 2006"))
+
+	// Viewing the page changes nothing.
+	after, _ := env.App.DB.FindByID(context.Background(), "ext-view")
+	assert.True(t, after.Deadline.Equal(l.Deadline))
+}
+
+func TestHandleExtend(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	l, token := seedExpiringRequest(t, env, "ext-do")
+	h := listing.NewListingHandler(env.App)
+
+	extend := func(token string) *http.Response {
+		form := url.Values{domain.ParamToken: {token}}
+		c, rec := testutil.SetupModuleContext(http.MethodPost, "/listings/ext-do/extend", strings.NewReader(form.Encode()))
+		c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "listing_extend.html")
+		c.SetParamNames("id")
+		c.SetParamValues("ext-do")
+		_ = h.HandleExtend(c)
+		return rec.Result()
+	}
+
+	assert.Equal(t, http.StatusOK, extend(token).StatusCode)
+	after, err := env.App.DB.FindByID(context.Background(), "ext-do")
+	require.NoError(t, err)
+	assert.WithinDuration(t, l.Deadline.Add(domain.ListingExtensionPeriod), after.Deadline, time.Second)
+
+	events, err := listing.NewAuditService(env.App.DB).History(context.Background(), "ext-do")
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, domain.ListingActionExtend, last.Action)
+	assert.Equal(t, "owner-1", last.ActorID)
+
+	// The link is bound to the old deadline, so it cannot be replayed.
+	assert.Equal(t, http.StatusForbidden, extend(token).StatusCode)
+	replayed, err := listing.NewAuditService(env.App.DB).History(context.Background(), "ext-do")
+	require.NoError(t, err)
+	assert.Len(t, replayed, len(events), "a rejected replay records nothing")
+}
+
+// assertNothingWritten checks a rejected extension left listing id as it was and
+// wrote no listing or history of its own.
+func assertNothingWritten(t *testing.T, env testutil.ModuleTestEnv, id string, before domain.Listing, eventsBefore int) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := env.App.DB.FindByID(ctx, "")
+	assert.Error(t, err, "no empty listing is saved")
+	blank, err := env.App.DB.ListListingEvents(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, blank)
+
+	after, err := env.App.DB.FindByID(ctx, id)
+	if before.ID == "" {
+		assert.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	assert.Equal(t, before.IsActive, after.IsActive)
+	assert.True(t, after.Deadline.Equal(before.Deadline))
+	events, err := env.App.DB.ListListingEvents(ctx, id)
+	require.NoError(t, err)
+	assert.Len(t, events, eventsBefore)
+}
+
+func TestHandleExtend_Rejected(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	_, token := seedExpiringRequest(t, env, "ext-bad")
+	testutil.SaveTestListing(t, env.App.DB, "ext-biz", "Shop")
+	biz, _ := env.App.DB.FindByID(context.Background(), "ext-biz")
+	bizToken := service.NewExtensionLinks(env.App.Cfg.SessionSecret, env.App.Cfg.BaseURL).Token(biz)
+	h := listing.NewListingHandler(env.App)
+
+	tests := []struct {
+		name       string
+		id         string
+		token      string
+		expectCode int
+	}{
+		{name: "ForgedToken", id: "ext-bad", token: token + "x", expectCode: http.StatusForbidden},
+		{name: "MissingToken", id: "ext-bad", token: "", expectCode: http.StatusForbidden},
+		{name: "UnknownListing", id: "missing", token: token, expectCode: http.StatusNotFound},
+		{name: "NotARequest", id: "ext-biz", token: bizToken, expectCode: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/"+tt.id+"/extend?token="+url.QueryEscape(tt.token), nil)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			assert.Error(t, h.HandleExtendConfirm(c))
+			assert.Equal(t, tt.expectCode, rec.Code)
+
+			before, _ := env.App.DB.FindByID(context.Background(), tt.id)
+			eventsBefore, err := env.App.DB.ListListingEvents(context.Background(), tt.id)
+			require.NoError(t, err)
+			form := url.Values{domain.ParamToken: {tt.token}}
+			c, rec = testutil.SetupModuleContext(http.MethodPost, "/listings/"+tt.id+"/extend", strings.NewReader(form.Encode()))
+			c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			assert.Error(t, h.HandleExtend(c))
+			assert.Equal(t, tt.expectCode, rec.Code)
+			assertNothingWritten(t, env, tt.id, before, len(eventsBefore))
+		})
+	}
+}
+
+func TestHandleExtend_StaleLinkCannotReactivate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		takeDown   func(t *testing.T, env testutil.ModuleTestEnv, id string)
+		name       string
+		expectCode int
+	}{
+		{
+			name: "DeactivatedByModerator",
+			takeDown: func(t *testing.T, env testutil.ModuleTestEnv, id string) {
+				require.NoError(t, listing.NewAuditService(env.App.DB).Deactivate(context.Background(), domain.Actor{ID: "admin-1"}, id, "Spam"))
+			},
+			expectCode: http.StatusConflict,
+		},
+		{
+			name: "OwnerBanned",
+			takeDown: func(t *testing.T, env testutil.ModuleTestEnv, _ string) {
+				require.NoError(t, env.App.DB.SetUserStatus(context.Background(), "owner-1", domain.UserStatusBanned, time.Time{}, "Fraud"))
+			},
+			expectCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			id := "ext-" + tt.name
+			_, token := seedExpiringRequest(t, env, id)
+			tt.takeDown(t, env, id)
+			before, err := env.App.DB.FindByID(context.Background(), id)
+			require.NoError(t, err)
+			eventsBefore, err := env.App.DB.ListListingEvents(context.Background(), id)
+			require.NoError(t, err)
+			h := listing.NewListingHandler(env.App)
+
+			form := url.Values{domain.ParamToken: {token}}
+			c, rec := testutil.SetupModuleContext(http.MethodPost, "/listings/"+id+"/extend", strings.NewReader(form.Encode()))
+			c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+			assert.Error(t, h.HandleExtend(c))
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			assertNothingWritten(t, env, id, before, len(eventsBefore))
+		})
+	}
+}
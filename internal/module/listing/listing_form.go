@@ -34,6 +34,12 @@ type ListingFormRequest struct {
 	RegionalSpecialty string `form:"regional_specialty"`
 	HeatLevel         int    `form:"heat_level"`
 	RemoveImage       bool   `form:"remove_image"`
+	// Special hours arrive as parallel lists, one entry per row of the form.
+	ExceptionStart []string `form:"exception_start"`
+	ExceptionEnd   []string `form:"exception_end"`
+	ExceptionKind  []string `form:"exception_kind"`
+	ExceptionHours []string `form:"exception_hours"`
+	ExceptionNote  []string `form:"exception_note"`
 }
 
 // ToListing maps the DTO fields directly to the domain Listing and parses dates.
@@ -66,8 +72,7 @@ func (req *ListingFormRequest) ToListing(l *domain.Listing) error {
 	if err := parseJobStartDate(req, l); err != nil {
 		return err
 	}
-
-	return nil
+	return parseHoursExceptions(req, l)
 }
 
 func (h *ListingHandler) bindAndMapListing(c echo.Context, l *domain.Listing) error {
@@ -112,6 +117,36 @@ func parseJobStartDate(req *ListingFormRequest, l *domain.Listing) error {
 	return assignFormDate(req.JobStartDate, datetimeLocalFormat, "Invalid Job Start Date Format", &l.JobStartDate)
 }
 
+// parseHoursExceptions collects the special hours rows that have a start date. Listing
+// types without hours keep none.
+func parseHoursExceptions(req *ListingFormRequest, l *domain.Listing) error {
+	l.HoursExceptions = ""
+	if !(l.Type == domain.Business || l.Type == domain.Service || l.Type == domain.Food) {
+		return nil
+	}
+	var exceptions []domain.HoursException
+	for i, start := range req.ExceptionStart {
+		if start == "" {
+			continue
+		}
+		exceptions = append(exceptions, domain.NewHoursException(start, formValueAt(req.ExceptionEnd, i),
+			formValueAt(req.ExceptionKind, i), formValueAt(req.ExceptionHours, i), formValueAt(req.ExceptionNote, i)))
+	}
+	encoded, err := domain.EncodeHoursExceptions(exceptions)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	l.HoursExceptions = encoded
+	return nil
+}
+
+func formValueAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
 func assignFormDate(val, format, errMsg string, target *time.Time) error {
 	parsed, err := parseFormDate(val, format, errMsg)
 	if err == nil && !parsed.IsZero() {
@@ -29,7 +29,8 @@ func TestListingHandler_FormParsing(t *testing.T) {
 			setup:          func(t *testing.T, repo domain.ListingRepository) {},
 			expectedStatus: http.StatusOK,
 			verify: func(t *testing.T, repo domain.ListingRepository) {
-				listings, _, err := repo.FindAll(context.Background(), "", "Event Test", "", 0.0, 0.0, 0.0, "", "", false, 1, 0)
+				page, err := repo.Search(context.Background(), domain.ListingQuery{QueryText: "Event Test", Limit: 1})
+				listings := page.Listings
 				assert.NoError(t, err)
 				if assert.Len(t, listings, 1) {
 					assert.Equal(t, 2027, listings[0].EventStart.Year())
@@ -44,7 +45,8 @@ func TestListingHandler_FormParsing(t *testing.T) {
 			setup:          func(t *testing.T, repo domain.ListingRepository) {},
 			expectedStatus: http.StatusOK,
 			verify: func(t *testing.T, repo domain.ListingRepository) {
-				listings, _, err := repo.FindAll(context.Background(), "", "Job Test", "", 0.0, 0.0, 0.0, "", "", false, 1, 0)
+				page, err := repo.Search(context.Background(), domain.ListingQuery{QueryText: "Job Test", Limit: 1})
+				listings := page.Listings
 				assert.NoError(t, err)
 				if assert.Len(t, listings, 1) {
 					assert.Equal(t, 2027, listings[0].JobStartDate.Year())
@@ -58,7 +60,8 @@ func TestListingHandler_FormParsing(t *testing.T) {
 			setup:          func(t *testing.T, repo domain.ListingRepository) {},
 			expectedStatus: http.StatusOK,
 			verify: func(t *testing.T, repo domain.ListingRepository) {
-				listings, _, err := repo.FindAll(context.Background(), "", "Request Test", "", 0.0, 0.0, 0.0, "", "", false, 1, 0)
+				page, err := repo.Search(context.Background(), domain.ListingQuery{QueryText: "Request Test", Limit: 1})
+				listings := page.Listings
 				assert.NoError(t, err)
 				if assert.Len(t, listings, 1) {
 					assert.Equal(t, 2026, listings[0].Deadline.Year())
@@ -123,7 +126,8 @@ func TestListingHandler_URLNormalization(t *testing.T) {
 			_ = h.HandleCreate(c)
 
 			assert.Equal(t, http.StatusOK, rec.Code)
-			listings, _, err := env.App.DB.FindAll(context.Background(), "", "URL Test "+tt.name, "", 0.0, 0.0, 0.0, "", "", false, 1, 0)
+			page, err := env.App.DB.Search(context.Background(), domain.ListingQuery{QueryText: "URL Test " + tt.name, Limit: 1})
+			listings := page.Listings
 			assert.NoError(t, err)
 			if assert.Len(t, listings, 1) {
 				assert.Equal(t, tt.expected, listings[0].WebsiteURL)
@@ -58,3 +58,28 @@ func TestParseJobStartDate(t *testing.T) {
 	err = parseJobStartDate(req, l)
 	assert.Error(t, err)
 }
+
+func TestParseHoursExceptions(t *testing.T) {
+	t.Parallel()
+	req := &ListingFormRequest{
+		ExceptionStart: []string{"2026-12-24", "", "2026-03-01"},
+		ExceptionEnd:   []string{"2026-12-26", "", "2026-03-30"},
+		ExceptionKind:  []string{"closed", "closed", "custom"},
+		ExceptionHours: []string{"", "", "18:00-23:00, 00:00 - 02:00"},
+		ExceptionNote:  []string{"Christmas", "", "Ramadan"},
+	}
+	l := &domain.Listing{Type: domain.Food}
+
+	assert.NoError(t, parseHoursExceptions(req, l))
+	assert.Equal(t, []domain.HoursException{
+		{Start: "2026-03-01", End: "2026-03-30", Kind: domain.HoursExceptionCustom, Hours: []string{"18:00-23:00", "00:00-02:00"}, Note: "Ramadan"},
+		{Start: "2026-12-24", End: "2026-12-26", Kind: domain.HoursExceptionClosed, Note: "Christmas"},
+	}, l.HoursExceptionList())
+
+	req.ExceptionHours[2] = "after sunset"
+	assert.Error(t, parseHoursExceptions(req, l))
+
+	l = &domain.Listing{Type: domain.Event, HoursExceptions: `[{"start":"2026-12-24","kind":"closed"}]`}
+	assert.NoError(t, parseHoursExceptions(&ListingFormRequest{}, l))
+	assert.Empty(t, l.HoursExceptions)
+}
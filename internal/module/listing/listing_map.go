@@ -0,0 +1,120 @@
+package listing
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/user"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// mimeGeoJSON is the media type of a GeoJSON document (RFC 7946).
+	mimeGeoJSON = "application/geo+json"
+	// maxMapFeatures caps the markers one map request returns.
+	maxMapFeatures = 1000
+	// clusterMaxZoom is the zoom level from which listings are drawn one marker each.
+	clusterMaxZoom = 15
+	// clusterCellPixels is the width on screen of the grid cells listings are
+	// clustered into, about the size of a cluster marker.
+	clusterCellPixels = 64
+)
+
+// mapFeatureCollection is a GeoJSON FeatureCollection of listing markers.
+type mapFeatureCollection struct {
+	Type     string       `json:"type"`
+	Features []mapFeature `json:"features"`
+}
+
+type mapFeature struct {
+	Type       string        `json:"type"`
+	Geometry   mapPoint      `json:"geometry"`
+	Properties mapProperties `json:"properties"`
+}
+
+type mapPoint struct {
+	Type string `json:"type"`
+	// Coordinates are longitude then latitude, as GeoJSON orders them.
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// mapProperties describe a marker: either a cluster of PointCount listings or one
+// listing with its ID, Title and Type.
+type mapProperties struct {
+	ID         string `json:"id,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Type       string `json:"type,omitempty"`
+	PointCount int    `json:"point_count"`
+	Cluster    bool   `json:"cluster"`
+}
+
+// HandleMap returns the listings inside the bbox query parameter as GeoJSON for the
+// home page map. Below clusterMaxZoom nearby listings are grouped on a grid so a
+// zoomed-out map stays readable; the type, q, open_now and open_at filters apply as
+// they do to the list.
+func (h *ListingHandler) HandleMap(c echo.Context) error {
+	bounds, err := domain.ParseBoundingBox(c.QueryParam("bbox"))
+	if err != nil {
+		return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+	}
+	zoom, err := strconv.Atoi(c.QueryParam("zoom"))
+	if err != nil || zoom < 0 {
+		return ui.RespondJSONError(c, http.StatusBadRequest, "zoom must be a map zoom level")
+	}
+
+	filterType := c.QueryParam(domain.FieldType)
+	if filterType == "All" {
+		filterType = ""
+	} else if filterType == "" {
+		filterType = string(domain.Food)
+	}
+	q := domain.ListingQuery{
+		Types:     domain.TypeFilter(filterType),
+		QueryText: c.QueryParam(domain.ParamQuery),
+		Bounds:    bounds,
+		Limit:     maxMapFeatures,
+		ViewerID:  user.ViewerID(c),
+	}
+	if err = parseOpenFilter(c, &q); err != nil {
+		return ui.RespondJSONError(c, http.StatusBadRequest, err.Error())
+	}
+
+	clusters, err := h.App.DB.ClusterListings(c.Request().Context(), q, clusterCellDegrees(zoom))
+	if err != nil {
+		h.LogError(c, "failed to cluster listings for map", err)
+		return ui.RespondJSONError(c, http.StatusInternalServerError, "failed to load map")
+	}
+
+	fc := mapFeatureCollection{Type: "FeatureCollection", Features: make([]mapFeature, 0, len(clusters))}
+	for _, cl := range clusters {
+		fc.Features = append(fc.Features, mapFeature{
+			Type:     "Feature",
+			Geometry: mapPoint{Type: "Point", Coordinates: [2]float64{cl.Longitude, cl.Latitude}},
+			Properties: mapProperties{
+				ID:         cl.ID,
+				Title:      cl.Title,
+				Type:       string(cl.Type),
+				PointCount: cl.Count,
+				Cluster:    cl.Count > 1,
+			},
+		})
+	}
+	body, err := json.Marshal(fc)
+	if err != nil {
+		return ui.RespondJSONError(c, http.StatusInternalServerError, "failed to load map")
+	}
+	return c.Blob(http.StatusOK, mimeGeoJSON, body)
+}
+
+// clusterCellDegrees is the side of the clustering grid at a zoom level, or zero when
+// listings are not clustered. A 256 pixel map tile spans 360/2^zoom degrees.
+func clusterCellDegrees(zoom int) float64 {
+	if zoom >= clusterMaxZoom {
+		return 0
+	}
+	return 360 / math.Exp2(float64(zoom)) * clusterCellPixels / 256
+}
@@ -0,0 +1,97 @@
+package listing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type geoJSON struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Geometry struct {
+			Type        string     `json:"type"`
+			Coordinates [2]float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			ID         string `json:"id"`
+			Title      string `json:"title"`
+			PointCount int    `json:"point_count"`
+			Cluster    bool   `json:"cluster"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+func TestHandleMap(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+	at := func(typ domain.Category, lat, lng float64) func(*domain.Listing) {
+		return func(l *domain.Listing) { l.Type, l.Latitude, l.Longitude = typ, lat, lng }
+	}
+	testutil.SaveTestListing(t, env.App.DB, "downtown", "Downtown Suya", at(domain.Food, 29.7604, -95.3698))
+	testutil.SaveTestListing(t, env.App.DB, "midtown", "Midtown Jollof", at(domain.Food, 29.7440, -95.3802))
+	testutil.SaveTestListing(t, env.App.DB, "tailor", "Heights Tailor", at(domain.Service, 29.7988, -95.3983))
+	testutil.SaveTestListing(t, env.App.DB, "dallas", "Dallas Suya", at(domain.Food, 32.7767, -96.7970))
+
+	get := func(target string) (*http.Response, geoJSON) {
+		t.Helper()
+		c, rec := testutil.SetupModuleContext(http.MethodGet, target, nil)
+		require.NoError(t, h.HandleMap(c))
+		var fc geoJSON
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+		}
+		return rec.Result(), fc
+	}
+
+	res, fc := get("/listings/map?bbox=-100,28,-94,34&zoom=5")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/geo+json", res.Header.Get("Content-Type"))
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	require.Len(t, fc.Features, 2, "food in Houston clusters at a state-wide zoom")
+	assert.True(t, fc.Features[0].Properties.Cluster)
+	assert.Equal(t, 2, fc.Features[0].Properties.PointCount)
+	assert.Equal(t, "dallas", fc.Features[1].Properties.ID)
+	assert.Equal(t, [2]float64{-96.7970, 32.7767}, fc.Features[1].Geometry.Coordinates, "GeoJSON is longitude first")
+
+	_, fc = get("/listings/map?bbox=-95.5,29.7,-95.3,29.8&zoom=16&type=All&q=Suya")
+	require.Len(t, fc.Features, 1, "street level shows single listings matching the query")
+	assert.Equal(t, "Downtown Suya", fc.Features[0].Properties.Title)
+	assert.False(t, fc.Features[0].Properties.Cluster)
+
+	_, fc = get("/listings/map?bbox=-95.5,29.7,-95.3,29.8&zoom=16&type=Service")
+	require.Len(t, fc.Features, 1)
+	assert.Equal(t, "tailor", fc.Features[0].Properties.ID)
+
+	res, _ = get("/listings/map?bbox=1,2,3&zoom=5")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	res, _ = get("/listings/map?bbox=-100,28,-94,34")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode, "zoom is required")
+}
+
+func TestHandleHome_MapToggle(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/", nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+	require.NoError(t, h.HandleHome(c))
+	assert.NotContains(t, rec.Body.String(), `id="listings-map"`, "no map without a Maps API key")
+
+	env.App.Cfg.GoogleMapsAPIKey = "maps-key"
+	c, rec = testutil.SetupModuleContext(http.MethodGet, "/", nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+	require.NoError(t, h.HandleHome(c))
+	assert.Contains(t, rec.Body.String(), `id="listings-map"`)
+	assert.Contains(t, rec.Body.String(), `data-listings-view="map"`)
+}
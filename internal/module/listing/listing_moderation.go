@@ -0,0 +1,175 @@
+package listing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ModerationStore is the persistence ModerationService needs.
+type ModerationStore interface {
+	AuditStore
+	domain.CategoryStore
+	domain.PendingChangeStore
+}
+
+// ModerationService applies each category's moderation policy to listings submitted
+// by users. In a category that requires moderation, new listings are stored as pending
+// and material edits are held for review while the live listing stays unchanged.
+type ModerationService struct {
+	Store ModerationStore
+	Audit *AuditService
+	Now   func() time.Time
+}
+
+// NewModerationService creates a new ModerationService.
+func NewModerationService(store ModerationStore) *ModerationService {
+	return &ModerationService{Store: store, Audit: NewAuditService(store), Now: time.Now}
+}
+
+// Submit saves a listing created or edited by u. It returns the held change when the
+// submission needs review, or nil when it went live. Users who can moderate are never held.
+func (s *ModerationService) Submit(ctx context.Context, u *domain.User, l domain.Listing) (*domain.PendingChange, error) {
+	return s.submit(ctx, u, "", l)
+}
+
+// Restore brings back a listing's content from one of its revisions on behalf of u.
+// A restore is an edit like any other, so material changes in a moderated category are
+// held for review; it returns the live listing and the held change, if any.
+func (s *ModerationService) Restore(ctx context.Context, u *domain.User, listingID string, number int) (domain.Listing, *domain.PendingChange, error) {
+	rev, err := s.Store.GetListingRevision(ctx, listingID, number)
+	if err != nil {
+		return domain.Listing{}, nil, err
+	}
+	current, err := s.Store.FindByID(ctx, listingID)
+	if err != nil {
+		return domain.Listing{}, nil, err
+	}
+
+	restored := rev.RestoreOnto(current)
+	held, err := s.submit(ctx, u, domain.ListingActionRestore, restored)
+	if err != nil {
+		return domain.Listing{}, nil, err
+	}
+	if held != nil {
+		return current, held, nil
+	}
+	return restored, nil, nil
+}
+
+func (s *ModerationService) submit(ctx context.Context, u *domain.User, action domain.ListingAction, l domain.Listing) (*domain.PendingChange, error) {
+	actor := domain.ActorFromUser(u)
+	// A failed lookup means the listing is new; any real storage fault surfaces from Save.
+	before, _ := s.Store.FindByID(ctx, l.ID)
+
+	if (u != nil && u.Can(domain.PermissionModerateListings)) || !s.moderated(ctx, before.Type, l.Type) {
+		return nil, s.Audit.Save(ctx, actor, action, l)
+	}
+
+	if before.ID == "" {
+		l.Status = domain.ListingStatusPending
+		if err := s.Audit.Save(ctx, actor, action, l); err != nil {
+			return nil, err
+		}
+		return s.hold(ctx, actor, l.Title, l, domain.DiffListings(domain.Listing{}, l), true)
+	}
+
+	// Listings that are not public yet have nothing to protect, and cosmetic edits go live.
+	changes := domain.DiffListings(before, l)
+	if before.Status != domain.ListingStatusApproved || len(domain.MaterialChanges(changes)) == 0 {
+		return nil, s.Audit.Save(ctx, actor, action, l)
+	}
+	return s.hold(ctx, actor, before.Title, l, changes, false)
+}
+
+// moderated reports whether either category requires moderation, so that moving a
+// listing out of a moderated category cannot be used to skip review.
+func (s *ModerationService) moderated(ctx context.Context, types ...domain.Category) bool {
+	for _, t := range types {
+		if t == "" {
+			continue
+		}
+		if cat, err := s.Store.GetCategory(ctx, string(t)); err == nil && cat.RequiresModeration {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ModerationService) hold(ctx context.Context, actor domain.Actor, title string, l domain.Listing, changes []domain.FieldChange, isNew bool) (*domain.PendingChange, error) {
+	pc := domain.PendingChange{
+		ID:           uuid.New().String(),
+		ListingID:    l.ID,
+		ListingTitle: title,
+		ActorID:      actor.ID,
+		ActorName:    actor.Name,
+		Status:       domain.PendingChangeStatusPending,
+		Proposed:     l,
+		Changes:      changes,
+		IsNew:        isNew,
+		CreatedAt:    s.Now(),
+	}
+	if err := s.Store.SavePendingChange(ctx, pc); err != nil {
+		return nil, fmt.Errorf("hold listing change for review: %w", err)
+	}
+	return &pc, nil
+}
+
+// Pending returns the changes awaiting review, oldest first.
+func (s *ModerationService) Pending(ctx context.Context) ([]domain.PendingChange, error) {
+	return s.Store.ListPendingChanges(ctx)
+}
+
+// Approve publishes a held change: a new listing is approved, and an edit is applied
+// onto the live listing. The listing's history records the moderator as the actor.
+func (s *ModerationService) Approve(ctx context.Context, actor domain.Actor, id string) (domain.Listing, error) {
+	pc, current, err := s.resolve(ctx, actor, id, domain.PendingChangeStatusApproved)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+
+	next := current
+	if pc.IsNew {
+		next.Status = domain.ListingStatusApproved
+	} else {
+		next = pc.ApplyTo(current)
+	}
+	if err := s.Audit.Save(ctx, actor, domain.ListingActionApprove, next); err != nil {
+		return domain.Listing{}, err
+	}
+	return next, nil
+}
+
+// Reject discards a held change. A rejected edit leaves the live listing as it was;
+// a rejected new listing is marked rejected so it never becomes public.
+func (s *ModerationService) Reject(ctx context.Context, actor domain.Actor, id string) error {
+	pc, current, err := s.resolve(ctx, actor, id, domain.PendingChangeStatusRejected)
+	if err != nil || !pc.IsNew {
+		return err
+	}
+	current.Status = domain.ListingStatusRejected
+	return s.Audit.Save(ctx, actor, domain.ListingActionReject, current)
+}
+
+// resolve marks a pending change reviewed and returns it with its live listing.
+func (s *ModerationService) resolve(ctx context.Context, actor domain.Actor, id string, status domain.PendingChangeStatus) (domain.PendingChange, domain.Listing, error) {
+	pc, err := s.Store.GetPendingChange(ctx, id)
+	if err != nil {
+		return domain.PendingChange{}, domain.Listing{}, err
+	}
+	if pc.Status != domain.PendingChangeStatusPending {
+		return domain.PendingChange{}, domain.Listing{}, domain.ErrPendingChangeNotFound
+	}
+	current, err := s.Store.FindByID(ctx, pc.ListingID)
+	if err != nil {
+		// The queue hides changes to deleted listings, so they are gone here too.
+		return domain.PendingChange{}, domain.Listing{}, domain.ErrPendingChangeNotFound
+	}
+	if err := s.Store.ResolvePendingChange(ctx, id, status, actor.ID, s.Now()); err != nil {
+		return domain.PendingChange{}, domain.Listing{}, err
+	}
+	return pc, current, nil
+}
@@ -0,0 +1,157 @@
+package listing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	listmod "github.com/jadecobra/agbalumo/internal/module/listing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func moderateCategory(t *testing.T, repo domain.ListingRepository, c domain.Category) {
+	t.Helper()
+	require.NoError(t, repo.SaveCategory(context.Background(), domain.CategoryData{
+		ID: string(c), Name: string(c), Active: true, RequiresModeration: true,
+	}))
+}
+
+func TestModerationService_Submit(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	repo := env.App.DB
+	ctx := context.Background()
+	svc := listmod.NewModerationService(repo)
+	moderateCategory(t, repo, domain.Food)
+
+	owner := &domain.User{ID: "owner-1", Name: "Ada", Role: domain.UserRoleUser}
+	admin := &domain.User{ID: "admin-1", Name: "Bola", Role: domain.UserRoleAdmin}
+	newListing := func(id string, c domain.Category) domain.Listing {
+		return domain.Listing{ID: id, Title: "Title " + id, Type: c, OwnerID: owner.ID, OwnerOrigin: "Nigeria",
+			ContactEmail: "a@example.com", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now()}
+	}
+
+	t.Run("new listing in moderated category is pending", func(t *testing.T) {
+		held, err := svc.Submit(ctx, owner, newListing("m-new", domain.Food))
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		assert.True(t, held.IsNew)
+
+		stored, err := repo.FindByID(ctx, "m-new")
+		require.NoError(t, err)
+		assert.Equal(t, domain.ListingStatusPending, stored.Status)
+	})
+
+	t.Run("unmoderated category and admins go live", func(t *testing.T) {
+		held, err := svc.Submit(ctx, owner, newListing("m-free", domain.Service))
+		require.NoError(t, err)
+		assert.Nil(t, held)
+
+		held, err = svc.Submit(ctx, admin, newListing("m-admin", domain.Food))
+		require.NoError(t, err)
+		assert.Nil(t, held)
+		stored, _ := repo.FindByID(ctx, "m-admin")
+		assert.Equal(t, domain.ListingStatusApproved, stored.Status)
+	})
+
+	t.Run("material edit is held and live listing unchanged", func(t *testing.T) {
+		testutil.SaveTestListing(t, repo, "m-edit", "Live Title", func(l *domain.Listing) { l.Type = domain.Food })
+		edit, _ := repo.FindByID(ctx, "m-edit")
+		edit.Title = "Renamed"
+		edit.Description = "Now with suya"
+
+		held, err := svc.Submit(ctx, owner, edit)
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		assert.False(t, held.IsNew)
+		assert.Equal(t, "Live Title", held.ListingTitle)
+
+		live, _ := repo.FindByID(ctx, "m-edit")
+		assert.Equal(t, "Live Title", live.Title)
+		assert.Empty(t, live.Description)
+	})
+
+	t.Run("cosmetic edit goes live", func(t *testing.T) {
+		testutil.SaveTestListing(t, repo, "m-cosmetic", "Cosmetic", func(l *domain.Listing) { l.Type = domain.Food })
+		edit, _ := repo.FindByID(ctx, "m-cosmetic")
+		edit.Description = "Fresh daily"
+
+		held, err := svc.Submit(ctx, owner, edit)
+		require.NoError(t, err)
+		assert.Nil(t, held)
+		live, _ := repo.FindByID(ctx, "m-cosmetic")
+		assert.Equal(t, "Fresh daily", live.Description)
+	})
+
+	t.Run("moving out of a moderated category is still reviewed", func(t *testing.T) {
+		testutil.SaveTestListing(t, repo, "m-move", "Mover", func(l *domain.Listing) { l.Type = domain.Food })
+		edit, _ := repo.FindByID(ctx, "m-move")
+		edit.Type = domain.Service
+		edit.ContactPhone = "555-0100"
+
+		held, err := svc.Submit(ctx, owner, edit)
+		require.NoError(t, err)
+		assert.NotNil(t, held)
+	})
+}
+
+func TestModerationService_ApproveAndReject(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	repo := env.App.DB
+	ctx := context.Background()
+	svc := listmod.NewModerationService(repo)
+	moderateCategory(t, repo, domain.Food)
+	owner := &domain.User{ID: "owner-1", Name: "Ada"}
+
+	testutil.SaveTestListing(t, repo, "r1", "Live Title", func(l *domain.Listing) { l.Type = domain.Food })
+	edit, _ := repo.FindByID(ctx, "r1")
+	edit.Title = "Renamed"
+	held, err := svc.Submit(ctx, owner, edit)
+	require.NoError(t, err)
+
+	// A live edit made while the change waits must survive its approval.
+	live, _ := repo.FindByID(ctx, "r1")
+	live.Description = "Edited meanwhile"
+	require.NoError(t, repo.Save(ctx, live))
+
+	approved, err := svc.Approve(ctx, auditActor, held.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", approved.Title)
+	stored, _ := repo.FindByID(ctx, "r1")
+	assert.Equal(t, "Renamed", stored.Title)
+	assert.Equal(t, "Edited meanwhile", stored.Description)
+
+	_, err = svc.Approve(ctx, auditActor, held.ID)
+	assert.ErrorIs(t, err, domain.ErrPendingChangeNotFound, "a change is applied once")
+
+	events, err := repo.ListListingEvents(ctx, "r1")
+	require.NoError(t, err)
+	last := events[len(events)-1]
+	assert.Equal(t, domain.ListingActionApprove, last.Action)
+	assert.Equal(t, auditActor.ID, last.ActorID)
+
+	newListing := domain.Listing{ID: "r2", Title: "Brand New", Type: domain.Food, OwnerOrigin: "Ghana",
+		ContactEmail: "b@example.com", IsActive: true, CreatedAt: time.Now()}
+	held, err = svc.Submit(ctx, owner, newListing)
+	require.NoError(t, err)
+
+	pending, err := svc.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "r2", pending[0].ListingID)
+
+	require.NoError(t, svc.Reject(ctx, auditActor, held.ID))
+	stored, _ = repo.FindByID(ctx, "r2")
+	assert.Equal(t, domain.ListingStatusRejected, stored.Status)
+
+	pending, err = svc.Pending(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
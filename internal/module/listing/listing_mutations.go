@@ -74,9 +74,12 @@ func (h *ListingHandler) HandleUpdate(c echo.Context) error {
 	return h.processAndSave(c, &listing)
 }
 
+// checkListingAuth writes a 403 response and returns echo.ErrForbidden unless uRaw owns
+// the listing or may manage all listings. Callers must return the sentinel immediately.
 func (h *ListingHandler) checkListingAuth(c echo.Context, listing domain.Listing, uRaw *domain.User) error {
-	if listing.OwnerID != uRaw.ID && uRaw.Role != domain.UserRoleAdmin {
-		return ui.RespondErrorMsg(c, http.StatusForbidden, "You are not the owner of this listing")
+	if listing.OwnerID != uRaw.ID && !uRaw.Can(domain.PermissionManageListings) {
+		_ = ui.RespondErrorMsg(c, http.StatusForbidden, "You are not the owner of this listing")
+		return echo.ErrForbidden
 	}
 	return nil
 }
@@ -114,7 +117,7 @@ func (h *ListingHandler) HandleDelete(c echo.Context) error {
 		return err
 	}
 
-	if err := h.App.DB.Delete(c.Request().Context(), id); err != nil {
+	if err := NewAuditService(h.App.DB).Delete(c.Request().Context(), RequestActor(c), id); err != nil {
 		return ui.RespondError(c, err)
 	}
 
@@ -133,14 +136,26 @@ func (h *ListingHandler) bindAndMapWithImageCheck(c echo.Context, l *domain.List
 
 func (h *ListingHandler) processAndSave(c echo.Context, l *domain.Listing) error {
 	h.autoPopulateLocation(c.Request().Context(), l)
+	l.RefreshTimezone()
 
 	if err := l.Validate(); err != nil {
 		return ui.RespondErrorMsg(c, http.StatusBadRequest, "Validation Error: "+err.Error())
 	}
 
-	if err := h.App.DB.Save(c.Request().Context(), *l); err != nil {
+	ctx := c.Request().Context()
+	u, _ := user.GetUser(c)
+	held, err := NewModerationService(h.App.DB).Submit(ctx, u, *l)
+	if err != nil {
 		return ui.RespondError(c, err)
 	}
+	if held != nil && !held.IsNew {
+		// The edit awaits review, so the card keeps showing the public version.
+		live, err := h.App.DB.FindByID(ctx, l.ID)
+		if err != nil {
+			return ui.RespondError(c, err)
+		}
+		*l = live
+	}
 
 	// Trigger an HTMX event so other components (like admin table rows) can update themselves
 	c.Response().Header().Add(domain.HeaderHXTrigger, fmt.Sprintf("%s%s", domain.TriggerListingUpdatedPrefix, l.ID))
@@ -156,8 +171,9 @@ func (h *ListingHandler) processAndSave(c echo.Context, l *domain.Listing) error
 	}
 
 	return h.RenderWithBaseContext(c, tmplListingCard, map[string]interface{}{
-		"Listing": l,
-		"User":    usr,
+		"Listing":  l,
+		"User":     usr,
+		"InReview": held != nil,
 	})
 }
 
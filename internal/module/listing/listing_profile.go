@@ -1,6 +1,10 @@
 package listing
 
 import (
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	customMiddleware "github.com/jadecobra/agbalumo/internal/middleware"
 	"github.com/jadecobra/agbalumo/internal/module/user"
 	"github.com/jadecobra/agbalumo/internal/ui"
 
@@ -19,9 +23,23 @@ func (h *ListingHandler) HandleProfile(c echo.Context) error {
 		return ui.RespondError(c, err)
 	}
 
+	tokens, err := h.App.DB.ListAPITokens(c.Request().Context(), u.ID)
+	h.LogError(c, "failed to list API tokens", err)
+
+	claims, err := h.profileClaims(c.Request().Context(), u.ID)
+	h.LogError(c, "failed to list claim requests", err)
+
+	sessions, err := h.App.DB.ListUserSessions(c.Request().Context(), u.ID, time.Now())
+	h.LogError(c, "failed to list sessions", err)
+
 	data := map[string]interface{}{
 		"User":             u,
 		"Listings":         listings,
+		"APITokens":        tokens,
+		"Claims":           claims,
+		"Sessions":         sessions,
+		"CurrentSessionID": customMiddleware.CurrentSessionID(c),
+		"TokenScopes":      domain.GrantableScopes(u.Role),
 		"GoogleMapsApiKey": h.App.Cfg.GoogleMapsAPIKey,
 	}
 
@@ -3,13 +3,17 @@ package listing_test
 import (
 	"context"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/module/listing"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleHome(t *testing.T) {
@@ -47,6 +51,40 @@ func TestHandleDetail(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "Detail View")
 }
 
+func TestHandleDetail_HoursBanner(t *testing.T) {
+	t.Parallel()
+	today := time.Now().UTC().Format(time.DateOnly)
+	tests := []struct {
+		name   string
+		mutate func(l *domain.Listing)
+		want   string
+	}{
+		{"closure today", func(l *domain.Listing) {
+			l.HoursExceptions = `[{"start":"` + today + `","kind":"closed","note":"Staff holiday"}]`
+		}, "Staff holiday"},
+		{"closed for good", func(l *domain.Listing) { l.PermanentlyClosed = true }, "closed permanently"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/1", nil)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			testutil.SaveTestListing(t, env.App.DB, "1", "Banner View", func(l *domain.Listing) {
+				l.Timezone = "UTC"
+				tt.mutate(l)
+			})
+
+			assert.NoError(t, listing.NewListingHandler(env.App).HandleDetail(c))
+			assert.Contains(t, rec.Body.String(), "ag-detail-hours-banner")
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+}
+
 func TestHandleProfile(t *testing.T) {
 	t.Parallel()
 	c, rec := testutil.SetupModuleContext(http.MethodGet, "/profile", nil)
@@ -88,6 +126,154 @@ func TestHandleFragment(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), `id="featured-section" hx-swap-oob="true"`)
 }
 
+func TestHandleFragment_Cursor(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+	for i := 1; i <= 31; i++ {
+		testutil.SaveTestListing(t, env.App.DB, strconv.Itoa(i), "Cursor Result "+strconv.Itoa(i))
+	}
+
+	renderer := testutil.SetupTestRendererForPage(t, "index.html")
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All", nil)
+	c.Echo().Renderer = renderer
+	if err := h.HandleFragment(c); err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`cursor=([A-Za-z0-9_-]+)`).FindStringSubmatch(rec.Body.String())
+	if !assert.Len(t, m, 2, "next link should carry a cursor") {
+		return
+	}
+
+	c2, rec2 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&page=2&cursor="+m[1], nil)
+	c2.Echo().Renderer = renderer
+	if err := h.HandleFragment(c2); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, http.StatusOK, rec2.Code)
+	first, second := resultTitles(rec.Body.String()), resultTitles(rec2.Body.String())
+	assert.Len(t, first, 30)
+	assert.Len(t, second, 1)
+	for title := range second {
+		assert.False(t, first[title], "%s repeated on the cursor page", title)
+	}
+	assert.NotContains(t, rec2.Body.String(), "cursor=", "last page has no next link")
+
+	c3, rec3 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?cursor=bogus", nil)
+	_ = h.HandleFragment(c3)
+	assert.Equal(t, http.StatusBadRequest, rec3.Code)
+}
+
+func TestHandleFragment_OpenFilter(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+	hours := func(structured string) func(*domain.Listing) {
+		return func(l *domain.Listing) { l.StructuredHours = structured }
+	}
+	testutil.SaveTestListing(t, env.App.DB, "lunch", "Lunch Spot", hours(`{"fri":["11:00-14:00"]}`))
+	testutil.SaveTestListing(t, env.App.DB, "dinner", "Dinner Spot", hours(`{"fri":["18:00-22:00"]}`))
+
+	// 2026-05-01 is a Friday; without an offset the time is read on each listing's clock.
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&open_at=2026-05-01T12:00", nil)
+	if err := h.HandleFragment(c); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, rec.Body.String(), "Lunch Spot")
+	assert.NotContains(t, rec.Body.String(), "Dinner Spot")
+
+	c2, rec2 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&open_at=tonight", nil)
+	_ = h.HandleFragment(c2)
+	assert.Equal(t, http.StatusBadRequest, rec2.Code)
+}
+
+func TestHandleFragment_NearMe(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	geocoder := &testutil.MockGeocodingService{}
+	geocoder.On("ReverseGeocode", mock.Anything, 29.79, -95.82).Return("Katy, TX", nil)
+	geocoder.On("ReverseGeocode", mock.Anything, mock.Anything, mock.Anything).Return("", domain.ErrLocationNotFound)
+	env.App.GeocodingSvc = geocoder
+	h := listing.NewListingHandler(env.App)
+	at := func(lat, lng float64) func(*domain.Listing) {
+		return func(l *domain.Listing) { l.Latitude, l.Longitude = lat, lng }
+	}
+	testutil.SaveTestListing(t, env.App.DB, "houston", "Houston Kitchen", at(29.7604, -95.3698))
+	testutil.SaveTestListing(t, env.App.DB, "katy", "Katy Kitchen", at(29.7858, -95.8245))
+	testutil.SaveTestListing(t, env.App.DB, "dallas", "Dallas Kitchen", at(32.7767, -96.7970))
+	renderer := testutil.SetupTestRendererForPage(t, "index.html")
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&city=Dallas&lat=29.79&lng=-95.82", nil)
+	c.Echo().Renderer = renderer
+	if err := h.HandleFragment(c); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	assert.Contains(t, body, "Near Katy, TX")
+	assert.NotContains(t, body, "Houston Kitchen", "the default radius is 25 miles")
+	assert.NotContains(t, body, "Dallas Kitchen", "coordinates replace the city")
+	assert.Contains(t, body, "Katy Kitchen")
+	assert.Contains(t, body, "· 0.4 mi")
+	geocoder.AssertExpectations(t)
+
+	c2, rec2 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&radius=50&lat=29.79&lng=-95.82", nil)
+	c2.Echo().Renderer = renderer
+	if err := h.HandleFragment(c2); err != nil {
+		t.Fatal(err)
+	}
+	body = rec2.Body.String()
+	assert.Less(t, strings.Index(body, "Katy Kitchen"), strings.Index(body, "Houston Kitchen"), "nearest first")
+
+	c3, rec3 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&radius=10&lat=30.5&lng=-94", nil)
+	c3.Echo().Renderer = renderer
+	if err := h.HandleFragment(c3); err != nil {
+		t.Fatal(err)
+	}
+	body = rec3.Body.String()
+	assert.Contains(t, body, "Near your location", "the area could not be named")
+	assert.Contains(t, body, "radius=25&lat=30.5&lng=-94", "expanding the radius keeps the location")
+
+	for _, bad := range []string{"lat=95&lng=0", "lat=NaN&lng=10", "lat=10&lng=Inf", "radius=NaN&lat=29.79&lng=-95.82"} {
+		c4, rec4 := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?"+bad, nil)
+		_ = h.HandleFragment(c4)
+		assert.Equal(t, http.StatusBadRequest, rec4.Code, bad)
+	}
+}
+
+func TestHandleFragment_SearchRelevance(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+	testutil.SaveTestListing(t, env.App.DB, "palace", "Suya Palace", func(l *domain.Listing) {
+		l.CreatedAt = time.Now().Add(-time.Hour)
+	})
+	testutil.SaveTestListing(t, env.App.DB, "grill", "Corner Grill", func(l *domain.Listing) {
+		l.Description = "Suya on Fridays"
+	})
+	renderer := testutil.SetupTestRendererForPage(t, "index.html")
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/fragment?type=All&q=suya", nil)
+	c.Echo().Renderer = renderer
+	if err := h.HandleFragment(c); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	assert.Less(t, strings.Index(body, "Suya Palace"), strings.Index(body, "Corner Grill"), "a title match ranks above a newer description match")
+}
+
+func resultTitles(body string) map[string]bool {
+	titles := map[string]bool{}
+	for _, m := range regexp.MustCompile(`Cursor Result \d+`).FindAllString(body, -1) {
+		titles[m] = true
+	}
+	return titles
+}
+
 func TestHandleDetail_NotFound(t *testing.T) {
 	t.Parallel()
 	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/nonexistent", nil)
@@ -102,6 +288,37 @@ func TestHandleDetail_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
 
+func TestHandleDetail_ShadowedOwner(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	h := listing.NewListingHandler(env.App)
+	ctx := context.Background()
+	if err := env.App.DB.SaveUser(ctx, domain.User{ID: "spammer", GoogleID: "g-spammer"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.App.DB.SetUserStatus(ctx, "spammer", domain.UserStatusShadowed, time.Time{}, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	testutil.SaveTestListing(t, env.App.DB, "1", "Cheap Pills", func(l *domain.Listing) { l.OwnerID = "spammer" })
+
+	detail := func(viewer *domain.User) int {
+		c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/1", nil)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		if viewer != nil {
+			c.Set(domain.CtxKeyUser, viewer)
+		}
+		_ = h.HandleDetail(c)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, detail(nil))
+	assert.Equal(t, http.StatusNotFound, detail(&domain.User{ID: "someone-else", Role: domain.UserRoleUser}))
+	assert.Equal(t, http.StatusOK, detail(&domain.User{ID: "spammer"}), "the owner sees nothing amiss")
+	assert.Equal(t, http.StatusOK, detail(&domain.User{ID: "mod1", Role: domain.UserRoleModerator}))
+}
+
 func TestHandleFragment_AdaDefaulting(t *testing.T) {
 	t.Parallel()
 	env := testutil.SetupTestModuleEnv(t)
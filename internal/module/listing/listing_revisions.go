@@ -0,0 +1,61 @@
+package listing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/ui"
+	"github.com/labstack/echo/v4"
+)
+
+const tmplListingRevisions = "modal_listing_revisions"
+
+// HandleRevisions renders the revision history of a listing, with the changes each
+// later save made, for its owner or an admin.
+func (h *ListingHandler) HandleRevisions(c echo.Context) error {
+	l, _, err := h.findAndAuthListing(c, c.Param(domain.ParamID))
+	if err != nil {
+		return err
+	}
+	return h.renderRevisions(c, l, false)
+}
+
+// HandleRestoreRevision restores a listing's content from one of its revisions. Owners
+// go through moderation, so a restore cannot publish unreviewed material changes.
+func (h *ListingHandler) HandleRestoreRevision(c echo.Context) error {
+	l, u, err := h.findAndAuthListing(c, c.Param(domain.ParamID))
+	if err != nil {
+		return err
+	}
+
+	number, err := strconv.Atoi(c.Param(domain.ParamRevision))
+	if err != nil {
+		return ui.RespondErrorMsg(c, http.StatusBadRequest, "Invalid revision number")
+	}
+
+	live, held, err := NewModerationService(h.App.DB).Restore(c.Request().Context(), u, l.ID, number)
+	if err != nil {
+		if errors.Is(err, domain.ErrRevisionNotFound) {
+			return ui.RespondErrorMsg(c, http.StatusNotFound, err.Error())
+		}
+		return ui.RespondError(c, err)
+	}
+
+	c.Response().Header().Add(domain.HeaderHXTrigger, fmt.Sprintf("%s%s", domain.TriggerListingUpdatedPrefix, l.ID))
+	return h.renderRevisions(c, live, held != nil)
+}
+
+func (h *ListingHandler) renderRevisions(c echo.Context, l domain.Listing, inReview bool) error {
+	revisions, err := NewAuditService(h.App.DB).Revisions(c.Request().Context(), l.ID)
+	if err != nil {
+		return ui.RespondError(c, err)
+	}
+	return h.RenderWithBaseContext(c, tmplListingRevisions, map[string]interface{}{
+		"Listing":   l,
+		"Revisions": revisions,
+		"InReview":  inReview,
+	})
+}
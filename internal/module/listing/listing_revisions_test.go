@@ -0,0 +1,123 @@
+package listing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/module/listing"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedRevisedListing(t *testing.T, repo domain.ListingRepository, id string) {
+	t.Helper()
+	testutil.SaveTestListing(t, repo, id, "Original Title", func(l *domain.Listing) { l.OwnerID = "owner-1" })
+	l, err := repo.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	l.Title = "Vandalized Title"
+	require.NoError(t, listing.NewAuditService(repo).Save(context.Background(), domain.Actor{ID: "owner-1"}, "", l))
+}
+
+func TestHandleRevisions(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	seedRevisedListing(t, env.App.DB, "rev-view")
+	h := listing.NewListingHandler(env.App)
+
+	c, rec := testutil.SetupModuleContext(http.MethodGet, "/listings/rev-view/revisions", nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+	c.SetParamNames("id")
+	c.SetParamValues("rev-view")
+	c.Set("User", domain.User{ID: "owner-1", Role: domain.UserRoleUser})
+
+	require.NoError(t, h.HandleRevisions(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Revision 1")
+	assert.Contains(t, body, "Original Title")
+	assert.Contains(t, body, "/listings/rev-view/revisions/1/restore")
+}
+
+func TestHandleRestoreRevision(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		user       domain.User
+		name       string
+		rev        string
+		wantTitle  string
+		expectCode int
+	}{
+		{name: "Owner", user: domain.User{ID: "owner-1", Role: domain.UserRoleUser}, rev: "1", expectCode: http.StatusOK, wantTitle: "Original Title"},
+		{name: "Admin", user: domain.User{ID: "admin-1", Role: domain.UserRoleAdmin}, rev: "1", expectCode: http.StatusOK, wantTitle: "Original Title"},
+		{name: "Forbidden_NotOwner", user: domain.User{ID: "other", Role: domain.UserRoleUser}, rev: "1", expectCode: http.StatusForbidden, wantTitle: "Vandalized Title"},
+		{name: "UnknownRevision", user: domain.User{ID: "owner-1", Role: domain.UserRoleUser}, rev: "7", expectCode: http.StatusNotFound, wantTitle: "Vandalized Title"},
+		{name: "InvalidRevision", user: domain.User{ID: "owner-1", Role: domain.UserRoleUser}, rev: "latest", expectCode: http.StatusBadRequest, wantTitle: "Vandalized Title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			env := testutil.SetupTestModuleEnv(t)
+			defer env.Cleanup()
+			id := "rev-" + tt.name
+			seedRevisedListing(t, env.App.DB, id)
+			h := listing.NewListingHandler(env.App)
+
+			c, rec := testutil.SetupModuleContext(http.MethodPost, "/listings/"+id+"/revisions/"+tt.rev+"/restore", nil)
+			c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+			c.SetParamNames("id", "rev")
+			c.SetParamValues(id, tt.rev)
+			c.Set("User", tt.user)
+
+			_ = h.HandleRestoreRevision(c)
+
+			assert.Equal(t, tt.expectCode, rec.Code)
+			l, err := env.App.DB.FindByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, l.Title)
+			if tt.expectCode == http.StatusOK {
+				assert.Equal(t, domain.TriggerListingUpdatedPrefix+id, rec.Header().Get(domain.HeaderHXTrigger))
+			}
+		})
+	}
+}
+
+func TestHandleRestoreRevision_ModeratedCategoryHoldsOwnerRestore(t *testing.T) {
+	t.Parallel()
+	env := testutil.SetupTestModuleEnv(t)
+	defer env.Cleanup()
+	ctx := context.Background()
+	moderateCategory(t, env.App.DB, domain.Food)
+	testutil.SaveTestListing(t, env.App.DB, "rev-held", "Original Title", func(l *domain.Listing) {
+		l.OwnerID = "owner-1"
+		l.Type = domain.Food
+	})
+	l, err := env.App.DB.FindByID(ctx, "rev-held")
+	require.NoError(t, err)
+	l.Title = "Reviewed Title"
+	require.NoError(t, listing.NewAuditService(env.App.DB).Save(ctx, domain.Actor{ID: "admin-1"}, "", l))
+	h := listing.NewListingHandler(env.App)
+
+	c, rec := testutil.SetupModuleContext(http.MethodPost, "/listings/rev-held/revisions/1/restore", nil)
+	c.Echo().Renderer = testutil.SetupTestRendererForPage(t, "index.html")
+	c.SetParamNames("id", "rev")
+	c.SetParamValues("rev-held", "1")
+	c.Set("User", domain.User{ID: "owner-1", Role: domain.UserRoleUser})
+
+	require.NoError(t, h.HandleRestoreRevision(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ag-listing-restore-in-review")
+
+	live, err := env.App.DB.FindByID(ctx, "rev-held")
+	require.NoError(t, err)
+	assert.Equal(t, "Reviewed Title", live.Title)
+
+	pending, err := listing.NewModerationService(env.App.DB).Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "Original Title", pending[0].Proposed.Title)
+}
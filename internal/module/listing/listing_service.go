@@ -2,6 +2,9 @@ package listing
 
 import (
 	"context"
+	"fmt"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,12 +33,20 @@ func NewListingService(
 
 // ClaimListing creates a pending claim request for an unclaimed, claimable listing.
 // It validates that the listing exists, is unclaimed, is a claimable type, and that
-// the user does not already have a pending claim for this listing.
-func (s *ListingService) ClaimListing(ctx context.Context, user domain.User, listingID string) (domain.ClaimRequest, error) {
+// the user has neither a pending claim for this listing nor one rejected within
+// domain.ClaimCooldown.
+func (s *ListingService) ClaimListing(ctx context.Context, user domain.User, listingID string, evidence domain.ClaimEvidence) (domain.ClaimRequest, error) {
 	if user.ID == "" {
 		return domain.ClaimRequest{}, domain.ErrUserIDRequired
 	}
 
+	evidence.Email = strings.TrimSpace(evidence.Email)
+	if evidence.Email != "" {
+		if _, err := mail.ParseAddress(evidence.Email); err != nil {
+			return domain.ClaimRequest{}, domain.ErrInvalidEvidenceEmail
+		}
+	}
+
 	listing, err := s.ListingStore.FindByID(ctx, listingID)
 	if err != nil {
 		return domain.ClaimRequest{}, domain.ErrListingNotFound
@@ -54,21 +65,29 @@ func (s *ListingService) ClaimListing(ctx context.Context, user domain.User, lis
 		return domain.ClaimRequest{}, domain.ErrListingNotClaimable
 	}
 
-	// Check for an existing pending claim from this user
+	// Check for an existing pending or recently rejected claim from this user
+	now := time.Now()
 	existing, err := s.ClaimRequestStore.GetClaimRequestByUserAndListing(ctx, user.ID, listingID)
-	if err == nil && existing.Status == domain.ClaimStatusPending {
-		return domain.ClaimRequest{}, domain.ErrPendingClaimExists
+	if err == nil {
+		if existing.Status == domain.ClaimStatusPending {
+			return domain.ClaimRequest{}, domain.ErrPendingClaimExists
+		}
+		if retry := existing.ResubmittableAt(); now.Before(retry) {
+			return domain.ClaimRequest{}, fmt.Errorf("%w; you can claim it again on %s", domain.ErrClaimCooldown, retry.Format("Jan 2, 2006"))
+		}
 	}
 
 	cr := domain.ClaimRequest{
-		ID:           uuid.New().String(),
-		ListingID:    listingID,
-		ListingTitle: listing.Title,
-		UserID:       user.ID,
-		UserName:     user.Name,
-		UserEmail:    user.Email,
-		Status:       domain.ClaimStatusPending,
-		CreatedAt:    time.Now(),
+		ID:            uuid.New().String(),
+		ListingID:     listingID,
+		ListingTitle:  listing.Title,
+		UserID:        user.ID,
+		UserName:      user.Name,
+		UserEmail:     user.Email,
+		EvidenceEmail: evidence.Email,
+		DocumentURL:   evidence.DocumentURL,
+		Status:        domain.ClaimStatusPending,
+		CreatedAt:     now,
 	}
 
 	if err := s.ClaimRequestStore.SaveClaimRequest(ctx, cr); err != nil {
@@ -3,6 +3,7 @@ package listing_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	listmod "github.com/jadecobra/agbalumo/internal/module/listing"
 
@@ -28,7 +29,7 @@ func TestListingService_ClaimListing(t *testing.T) {
 		testutil.SaveTestListing(t, repo, "loc-123", "Test Listing")
 		_ = repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Business), Name: "Business", Claimable: true})
 
-		cr, err := svc.ClaimListing(ctx, testSvcUser, "loc-123")
+		cr, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
 		require.NoError(t, err)
 		require.Equal(t, domain.ClaimStatusPending, cr.Status)
 		require.Equal(t, testSvcUser.ID, cr.UserID)
@@ -47,7 +48,7 @@ func TestListingService_ClaimListing(t *testing.T) {
 		repo := env.App.DB
 		svc := listmod.NewListingService(repo, repo, repo)
 
-		_, err := svc.ClaimListing(ctx, domain.User{}, "loc-123")
+		_, err := svc.ClaimListing(ctx, domain.User{}, "loc-123", domain.ClaimEvidence{})
 		require.Error(t, err)
 		require.ErrorIs(t, err, domain.ErrUserIDRequired)
 	})
@@ -59,7 +60,7 @@ func TestListingService_ClaimListing(t *testing.T) {
 		repo := env.App.DB
 		svc := listmod.NewListingService(repo, repo, repo)
 
-		_, err := svc.ClaimListing(ctx, testSvcUser, "bad-id")
+		_, err := svc.ClaimListing(ctx, testSvcUser, "bad-id", domain.ClaimEvidence{})
 		require.Error(t, err)
 		require.ErrorIs(t, err, domain.ErrListingNotFound)
 	})
@@ -73,7 +74,7 @@ func TestListingService_ClaimListing(t *testing.T) {
 
 		testutil.SaveTestListing(t, repo, "loc-123", "Test Listing", func(l *domain.Listing) { l.OwnerID = "someone-else" })
 
-		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123")
+		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
 		require.Error(t, err)
 		require.ErrorIs(t, err, domain.ErrListingOwned)
 	})
@@ -88,7 +89,7 @@ func TestListingService_ClaimListing(t *testing.T) {
 		testutil.SaveTestListing(t, repo, "loc-123", "Test Job", func(l *domain.Listing) { l.Type = domain.Job })
 		_ = repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Job), Name: "Job", Claimable: false})
 
-		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123")
+		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
 		require.Error(t, err)
 		require.ErrorIs(t, err, domain.ErrListingNotClaimable)
 	})
@@ -104,8 +105,56 @@ func TestListingService_ClaimListing(t *testing.T) {
 		_ = repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Business), Name: "Business", Claimable: true})
 		_ = repo.SaveClaimRequest(ctx, domain.ClaimRequest{ID: "existing", UserID: testSvcUser.ID, ListingID: "loc-123", Status: domain.ClaimStatusPending})
 
-		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123")
+		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
 		require.Error(t, err)
 		require.ErrorIs(t, err, domain.ErrPendingClaimExists)
 	})
+
+	t.Run("evidence is stored with the claim", func(t *testing.T) {
+		t.Parallel()
+		env := testutil.SetupTestModuleEnv(t)
+		defer env.Cleanup()
+		repo := env.App.DB
+		svc := listmod.NewListingService(repo, repo, repo)
+
+		testutil.SaveTestListing(t, repo, "loc-123", "Test Listing")
+		_ = repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Business), Name: "Business", Claimable: true})
+
+		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{Email: "not-an-email"})
+		require.ErrorIs(t, err, domain.ErrInvalidEvidenceEmail)
+
+		cr, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{Email: " owner@shop.example ", DocumentURL: "/static/uploads/doc.webp"})
+		require.NoError(t, err)
+		saved, err := repo.GetClaimRequest(ctx, cr.ID)
+		require.NoError(t, err)
+		require.Equal(t, "owner@shop.example", saved.EvidenceEmail)
+		require.Equal(t, "/static/uploads/doc.webp", saved.DocumentURL)
+	})
+
+	t.Run("rejected claim can be resubmitted after cooldown", func(t *testing.T) {
+		t.Parallel()
+		env := testutil.SetupTestModuleEnv(t)
+		defer env.Cleanup()
+		repo := env.App.DB
+		svc := listmod.NewListingService(repo, repo, repo)
+
+		testutil.SaveTestListing(t, repo, "loc-123", "Test Listing")
+		_ = repo.SaveCategory(ctx, domain.CategoryData{ID: string(domain.Business), Name: "Business", Claimable: true})
+		rejected := domain.ClaimRequest{
+			ID: "rejected", UserID: testSvcUser.ID, ListingID: "loc-123", Status: domain.ClaimStatusRejected,
+			CreatedAt: time.Now().Add(-48 * time.Hour), ReviewedAt: time.Now().Add(-24 * time.Hour),
+		}
+		require.NoError(t, repo.SaveClaimRequest(ctx, rejected))
+
+		_, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
+		require.ErrorIs(t, err, domain.ErrClaimCooldown)
+
+		rejected.CreatedAt = time.Now().Add(-domain.ClaimCooldown - 48*time.Hour)
+		rejected.ReviewedAt = time.Now().Add(-domain.ClaimCooldown - time.Hour)
+		require.NoError(t, repo.SaveClaimRequest(ctx, rejected))
+
+		cr, err := svc.ClaimListing(ctx, testSvcUser, "loc-123", domain.ClaimEvidence{})
+		require.NoError(t, err)
+		require.Equal(t, domain.ClaimStatusPending, cr.Status)
+	})
 }
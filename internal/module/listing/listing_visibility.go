@@ -0,0 +1,18 @@
+package listing
+
+import (
+	"context"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// HiddenFrom reports whether l must look missing to viewer, who is nil when nobody is
+// signed in. The listings of a shadowed owner are only shown to that owner and to
+// staff; search leaves them out the same way through ListingQuery.ViewerID.
+func HiddenFrom(ctx context.Context, users domain.UserStore, l domain.Listing, viewer *domain.User) bool {
+	if l.OwnerID == "" || (viewer != nil && (viewer.ID == l.OwnerID || viewer.Role.IsStaff())) {
+		return false
+	}
+	owner, err := users.FindUserByID(ctx, l.OwnerID)
+	return err == nil && owner.IsShadowed()
+}
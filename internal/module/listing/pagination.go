@@ -15,6 +15,9 @@ type Pagination struct {
 	TotalCount  int
 	TotalPages  int
 	HasNextPage bool
+	// NextCursor continues the feed by keyset; TotalPages is 0 on cursor pages,
+	// which skip the total count.
+	NextCursor string
 }
 
 // GetPageRange returns a slice of page numbers to display in the UI.
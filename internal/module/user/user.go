@@ -28,6 +28,14 @@ func GetUser(c echo.Context) (*domain.User, bool) {
 	}
 }
 
+// ViewerID returns the signed-in user's ID, or "" when nobody is signed in.
+func ViewerID(c echo.Context) string {
+	if u, ok := GetUser(c); ok && u != nil {
+		return u.ID
+	}
+	return ""
+}
+
 // MustUser retrieves the authenticated user from the context.
 // Panics if user is not present - use only when auth is guaranteed by middleware.
 func MustUser(c echo.Context) *domain.User {
@@ -43,7 +51,7 @@ func MustUser(c echo.Context) *domain.User {
 func RequireUser(c echo.Context) (*domain.User, error) {
 	u, ok := GetUser(c)
 	if !ok || u == nil {
-		return nil, c.Redirect(http.StatusTemporaryRedirect, "/auth/google/login")
+		return nil, c.Redirect(http.StatusTemporaryRedirect, "/auth/login")
 	}
 	return u, nil
 }
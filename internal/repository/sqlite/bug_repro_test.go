@@ -9,7 +9,7 @@ import (
 	"github.com/jadecobra/agbalumo/internal/testutil"
 )
 
-func TestFindAll_CityFilter_Regression(t *testing.T) {
+func TestSearch_CityFilter_Regression(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -65,9 +65,10 @@ func TestFindAll_CityFilter_Regression(t *testing.T) {
 	}
 
 	// EXECUTION: Filter by Dallas
-	res, _, err := repo.FindAll(ctx, string(domain.Food), "", "Dallas", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Food}, Cities: []string{"Dallas"}, Limit: 20})
+	res := page.Listings
 	if err != nil {
-		t.Fatalf("FindAll failed: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
 
 	// VERIFICATION: We expect 3 results if the fix for missing city data is implemented.
@@ -82,7 +83,7 @@ func TestFindAll_CityFilter_Regression(t *testing.T) {
 	}
 }
 
-func TestFindAll_SearchSuya_Regression(t *testing.T) {
+func TestSearch_SearchSuya_Regression(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -116,9 +117,10 @@ func TestFindAll_SearchSuya_Regression(t *testing.T) {
 	}
 
 	// EXECUTION: Search for "Suya"
-	res, _, err := repo.FindAll(ctx, "", "Suya", "", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{QueryText: "Suya", Limit: 20})
+	res := page.Listings
 	if err != nil {
-		t.Fatalf("FindAll failed: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
 
 	// VERIFICATION: Expected 2 results.
@@ -37,3 +37,17 @@ func (r *SQLiteRepository) GetAllFeedback(ctx context.Context) ([]domain.Feedbac
 	}
 	return feedbacks, rows.Err()
 }
+
+// ListFeedbackByUser retrieves the feedback a user has sent, newest first.
+func (r *SQLiteRepository) ListFeedbackByUser(ctx context.Context, userID string) ([]domain.Feedback, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT id, user_id, type, content, created_at FROM feedback WHERE user_id = ? ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, func(s Scanner) (domain.Feedback, error) {
+		var f domain.Feedback
+		err := s.Scan(&f.ID, &f.UserID, &f.Type, &f.Content, &f.CreatedAt)
+		return f, err
+	})
+}
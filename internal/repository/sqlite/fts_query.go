@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ftsMatchExpr turns free text into an FTS5 query that cannot be a syntax error:
+// each search term, or any of its spellings, must match as a quoted phrase. It is
+// empty when the text has no term long enough to search for.
+func ftsMatchExpr(text string) string {
+	terms := domain.SearchTerms(text)
+	clauses := make([]string, 0, len(terms))
+	for _, term := range terms {
+		variants := domain.SearchTermVariants(term)
+		phrases := make([]string, len(variants))
+		for i, v := range variants {
+			phrases[i] = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+		}
+		if len(phrases) == 1 {
+			clauses = append(clauses, phrases[0])
+			continue
+		}
+		clauses = append(clauses, "("+strings.Join(phrases, " OR ")+")")
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// ftsRankFrom joins listings to their bm25 relevance to an FTS5 query as fts_rank,
+// lower being better, with the column weights listings_fts is configured with. The
+// CROSS JOIN reads the matches first, so only matching listings are looked up. The
+// query is bound to the placeholder; listingOrder.signature ties cursors to it.
+const ftsRankFrom = `(SELECT rowid AS fts_rowid, rank AS fts_rank FROM listings_fts WHERE listings_fts MATCH ?) ` +
+	`CROSS JOIN listings ON listings.rowid = fts_rowid`
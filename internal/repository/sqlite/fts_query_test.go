@@ -0,0 +1,16 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFTSMatchExpr(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `"mama" AND ("jollof" OR "jolof" OR "jolloff" OR "jellof")`, ftsMatchExpr(`mama's jollof`))
+	assert.Equal(t, `("peppersoup" OR "pepper soup")`, ftsMatchExpr("PepperSoup"))
+	assert.Equal(t, `"near" AND "rice"`, ftsMatchExpr(`NEAR("rice"`))
+	assert.Empty(t, ftsMatchExpr(`-" a`))
+}
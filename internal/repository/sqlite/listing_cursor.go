@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// orderTerm is one ORDER BY key. Terms are bare columns so keyset predicates can seek
+// an index; Save always writes them, so they are never NULL. The exceptions are the
+// distance from a search's centre, which is computed from the never-NULL coordinates,
+// and relevance, which is joined to each matching listing.
+type orderTerm struct {
+	expr string
+	// key selects the value stored in cursors when it differs from expr.
+	key string
+	// from is the table expression expr reads from when it is not a listings column.
+	// It must leave listings' own column names unambiguous.
+	from string
+	// fromArgs are bound to the placeholders in from.
+	fromArgs []interface{}
+	desc     bool
+}
+
+// listingOrder is a total order over listings. The final term is always rowid so
+// that every row has a unique position and keyset pages never overlap.
+type listingOrder []orderTerm
+
+func (o listingOrder) sql() string {
+	parts := make([]string, len(o))
+	for i, t := range o {
+		dir := " ASC"
+		if t.desc {
+			dir = " DESC"
+		}
+		parts[i] = t.expr + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// from is the table expression listings are read from to sort them by o.
+func (o listingOrder) from() string {
+	for _, t := range o {
+		if t.from != "" {
+			return t.from
+		}
+	}
+	return "listings"
+}
+
+// fromArgs are the arguments bound to the placeholders in from, placed before any
+// others each time from appears in a query.
+func (o listingOrder) fromArgs() []interface{} {
+	for _, t := range o {
+		if t.from != "" {
+			return t.fromArgs
+		}
+	}
+	return nil
+}
+
+// signature identifies the order a cursor was minted for, so a cursor cannot be
+// replayed against a different sort or, for relevance, a different search.
+func (o listingOrder) signature() string {
+	src := o.sql()
+	if from := o.from(); from != "listings" {
+		src = from + " ORDER BY " + src
+	}
+	for _, arg := range o.fromArgs() {
+		src += "\x00" + fmt.Sprint(arg)
+	}
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(src)))
+}
+
+// after returns the keyset predicate selecting rows that sort strictly after keys.
+// When every term runs the same way this is a row-value comparison, which SQLite can
+// answer with an index seek; mixed directions expand to
+// (k0 > v0) OR (k0 = v0 AND k1 > v1) OR ..., with > flipped to < for DESC terms.
+func (o listingOrder) after(keys []interface{}) (string, []interface{}) {
+	if o.uniform() {
+		exprs := make([]string, len(o))
+		for i, t := range o {
+			exprs[i] = t.expr
+		}
+		op := " > "
+		if o[0].desc {
+			op = " < "
+		}
+		return "(" + strings.Join(exprs, ", ") + ")" + op + "(" + placeholders(len(o)) + ")", keys
+	}
+
+	clauses := make([]string, len(o))
+	var args []interface{}
+	for i, t := range o {
+		var conds []string
+		for j := 0; j < i; j++ {
+			conds = append(conds, o[j].expr+" = ?")
+			args = append(args, keys[j])
+		}
+		op := " > ?"
+		if t.desc {
+			op = " < ?"
+		}
+		conds = append(conds, t.expr+op)
+		args = append(args, keys[i])
+		clauses[i] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+func (o listingOrder) uniform() bool {
+	for _, t := range o[1:] {
+		if t.desc != o[0].desc {
+			return false
+		}
+	}
+	return true
+}
+
+type listingCursor struct {
+	Sig  string        `json:"s"`
+	Keys []interface{} `json:"k"`
+}
+
+func (o listingOrder) encodeCursor(keys []interface{}) string {
+	raw, _ := json.Marshal(listingCursor{Sig: o.signature(), Keys: keys})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (o listingOrder) decodeCursor(cursor string) ([]interface{}, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var c listingCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	if c.Sig != o.signature() || len(c.Keys) != len(o) {
+		return nil, domain.ErrInvalidCursor
+	}
+	return c.Keys, nil
+}
+
+// cursorAfter reads the sort keys of the listing with the given ID and encodes them
+// as the cursor for the page that follows it.
+func (r *SQLiteRepository) cursorAfter(ctx context.Context, o listingOrder, id string) (string, error) {
+	exprs := make([]string, len(o))
+	for i, t := range o {
+		exprs[i] = t.expr
+		if t.key != "" {
+			exprs[i] = t.key
+		}
+	}
+	keys := make([]interface{}, len(o))
+	dest := make([]interface{}, len(o))
+	for i := range keys {
+		dest[i] = &keys[i]
+	}
+
+	// #nosec G202 - Dynamic query construction with trusted internal fragments
+	query := `SELECT ` + strings.Join(exprs, ", ") + ` FROM ` + o.from() + ` WHERE id = ?`
+	args := append(append([]interface{}{}, o.fromArgs()...), id)
+	if err := r.readDB.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
+		return "", err
+	}
+	for i, k := range keys {
+		if b, ok := k.([]byte); ok {
+			keys[i] = string(b)
+		}
+	}
+	return o.encodeCursor(keys), nil
+}
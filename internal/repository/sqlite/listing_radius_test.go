@@ -10,7 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFindAll_RadiusSearch(t *testing.T) {
+func TestSearch_RadiusSearch(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -61,14 +61,16 @@ func TestFindAll_RadiusSearch(t *testing.T) {
 
 	// Test 1: Search within 5 miles of Dallas
 	// Should only find l1
-	res, _, err := repo.FindAll(ctx, "", "", "", dallasLat, dallasLng, 5, "", "", false, 10, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Latitude: dallasLat, Longitude: dallasLng, RadiusMiles: 5, Limit: 10})
+	res := page.Listings
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(res))
 	assert.Equal(t, "dallas-1", res[0].ID)
 
 	// Test 2: Search within 50 miles of Dallas
 	// Should find l1 and l2
-	res, _, err = repo.FindAll(ctx, "", "", "", dallasLat, dallasLng, 50, "", "", false, 10, 0)
+	page, err = repo.Search(ctx, domain.ListingQuery{Latitude: dallasLat, Longitude: dallasLng, RadiusMiles: 50, Limit: 10})
+	res = page.Listings
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(res))
 
@@ -82,7 +84,8 @@ func TestFindAll_RadiusSearch(t *testing.T) {
 
 	// Test 3: Search within 250 miles of Dallas
 	// Should find all three
-	res, _, err = repo.FindAll(ctx, "", "", "", dallasLat, dallasLng, 250, "", "", false, 10, 0)
+	page, err = repo.Search(ctx, domain.ListingQuery{Latitude: dallasLat, Longitude: dallasLng, RadiusMiles: 250, Limit: 10})
+	res = page.Listings
 	assert.NoError(t, err)
 	assert.Equal(t, 3, len(res))
 }
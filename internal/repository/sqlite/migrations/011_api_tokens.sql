@@ -0,0 +1,14 @@
+-- Personal access tokens for non-browser clients. Only the SHA-256 hash of the secret is stored.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    last_used_at DATETIME,
+    revoked_at DATETIME
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id);
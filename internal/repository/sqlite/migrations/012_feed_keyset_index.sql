@@ -0,0 +1,3 @@
+-- Index the default feed order so keyset cursors can seek instead of scanning.
+-- Columns are ascending so a backwards scan also yields the rowid tie-break DESC.
+CREATE INDEX IF NOT EXISTS idx_listings_feed_order ON listings(is_active, status, featured, heat_level, rating, created_at);
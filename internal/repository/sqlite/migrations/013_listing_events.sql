@@ -0,0 +1,23 @@
+-- Append-only audit log of listing mutations. Rows outlive the listing they describe,
+-- so there is deliberately no foreign key to listings.
+CREATE TABLE IF NOT EXISTS listing_events (
+    id TEXT PRIMARY KEY,
+    listing_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    actor_name TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    changes TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_listing_events_listing ON listing_events(listing_id, created_at);
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS listing_events_no_update BEFORE UPDATE ON listing_events
+BEGIN
+    SELECT RAISE(ABORT, 'listing_events is append-only');
+END;
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS listing_events_no_delete BEFORE DELETE ON listing_events
+BEGIN
+    SELECT RAISE(ABORT, 'listing_events is append-only');
+END;
@@ -0,0 +1,10 @@
+-- Snapshots of listings taken before each save, numbered per listing.
+CREATE TABLE IF NOT EXISTS listing_revisions (
+    listing_id TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    actor_id TEXT NOT NULL,
+    actor_name TEXT NOT NULL DEFAULT '',
+    snapshot TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (listing_id, number)
+);
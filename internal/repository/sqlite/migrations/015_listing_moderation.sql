@@ -0,0 +1,23 @@
+-- Categories can require review of new listings and material edits.
+ALTER TABLE categories ADD COLUMN requires_moderation BOOLEAN NOT NULL DEFAULT 0;
+-- STATEMENT
+-- Listing submissions held for moderation. proposed is the submitted listing as JSON
+-- and changes its diff against the live version.
+CREATE TABLE IF NOT EXISTS listing_pending_changes (
+    id TEXT PRIMARY KEY,
+    listing_id TEXT NOT NULL,
+    listing_title TEXT NOT NULL DEFAULT '',
+    actor_id TEXT NOT NULL,
+    actor_name TEXT NOT NULL DEFAULT '',
+    is_new BOOLEAN NOT NULL DEFAULT 0,
+    proposed TEXT NOT NULL,
+    changes TEXT NOT NULL DEFAULT '[]',
+    status TEXT NOT NULL DEFAULT 'Pending',
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    reviewed_at DATETIME
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_listing_pending_changes_status ON listing_pending_changes(status, created_at);
+-- STATEMENT
+CREATE UNIQUE INDEX IF NOT EXISTS idx_listing_pending_changes_open ON listing_pending_changes(listing_id) WHERE status = 'Pending';
@@ -0,0 +1,8 @@
+-- Expiry reminders sent to listing owners, one per listing per expiry date, so that
+-- extending a listing makes it due for a fresh reminder.
+CREATE TABLE IF NOT EXISTS listing_expiry_reminders (
+    listing_id TEXT NOT NULL,
+    expires_on DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL,
+    PRIMARY KEY (listing_id, expires_on)
+);
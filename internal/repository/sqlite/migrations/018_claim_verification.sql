@@ -0,0 +1,18 @@
+-- Evidence a claimant attaches to a claim and the moderator's review of it.
+ALTER TABLE claim_requests ADD COLUMN evidence_email TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN document_url TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN phone_code_hash TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN phone_code_sent_at DATETIME;
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN phone_verified_at DATETIME;
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN reviewer_id TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN reviewer_notes TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN rejection_reason TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE claim_requests ADD COLUMN reviewed_at DATETIME;
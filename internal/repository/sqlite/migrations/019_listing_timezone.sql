@@ -0,0 +1,3 @@
+-- IANA time zone each listing's hours are written in. Existing rows are backfilled
+-- at startup from their state, country and coordinates.
+ALTER TABLE listings ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Date-ranged special hours (holiday closures, custom hours, permanent closure) as a
+-- JSON array, and an admin flag that hides a permanently closed listing without
+-- deleting it.
+ALTER TABLE listings ADD COLUMN hours_exceptions TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE listings ADD COLUMN permanently_closed INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Open-now searches convert the search time into each zone listings are in, which
+-- they look up with SELECT DISTINCT timezone.
+CREATE INDEX IF NOT EXISTS idx_listings_timezone ON listings(timezone);
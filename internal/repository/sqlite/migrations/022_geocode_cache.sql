@@ -0,0 +1,15 @@
+-- Geocoding results keyed by normalized address. Rows with found = 0 record failed
+-- lookups and expire sooner than successful ones.
+CREATE TABLE IF NOT EXISTS geocode_cache (
+    address TEXT NOT NULL,
+    lookup TEXT NOT NULL,
+    city TEXT NOT NULL DEFAULT '',
+    latitude REAL NOT NULL DEFAULT 0,
+    longitude REAL NOT NULL DEFAULT 0,
+    found INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (address, lookup)
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_geocode_cache_expires_at ON geocode_cache(expires_at);
@@ -0,0 +1,28 @@
+-- R*Tree spatial index over listing coordinates (023). Each listing with a location is
+-- one point box keyed by its rowid; listings at 0,0 have no location and are left out.
+CREATE VIRTUAL TABLE IF NOT EXISTS listings_rtree USING rtree(
+    id,
+    min_lat, max_lat,
+    min_lng, max_lng
+);
+-- STATEMENT
+INSERT INTO listings_rtree (id, min_lat, max_lat, min_lng, max_lng)
+SELECT rowid, latitude, latitude, longitude, longitude FROM listings
+WHERE latitude != 0 OR longitude != 0;
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS listings_rtree_ai AFTER INSERT ON listings
+WHEN new.latitude != 0 OR new.longitude != 0 BEGIN
+    INSERT INTO listings_rtree (id, min_lat, max_lat, min_lng, max_lng)
+    VALUES (new.rowid, new.latitude, new.latitude, new.longitude, new.longitude);
+END;
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS listings_rtree_ad AFTER DELETE ON listings BEGIN
+    DELETE FROM listings_rtree WHERE id = old.rowid;
+END;
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS listings_rtree_au AFTER UPDATE OF latitude, longitude ON listings BEGIN
+    DELETE FROM listings_rtree WHERE id = old.rowid;
+    INSERT INTO listings_rtree (id, min_lat, max_lat, min_lng, max_lng)
+    SELECT new.rowid, new.latitude, new.latitude, new.longitude, new.longitude
+    WHERE new.latitude != 0 OR new.longitude != 0;
+END;
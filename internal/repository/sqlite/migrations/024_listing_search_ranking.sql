@@ -0,0 +1,38 @@
+-- Rebuild FTS5 for relevance ranking (024): index top_dish and regional_specialty, put
+-- the columns in weight order and set bm25 column weights as the default rank, so
+-- title matches outrank dish and specialty matches, then description, then address.
+DROP TABLE IF EXISTS listings_fts;
+-- STATEMENT
+CREATE VIRTUAL TABLE IF NOT EXISTS listings_fts USING fts5(
+    title, top_dish, regional_specialty, description, address, city, state, country,
+    content=listings,
+    content_rowid=rowid,
+    tokenize='trigram'
+);
+-- STATEMENT
+INSERT INTO listings_fts(listings_fts, rank) VALUES('rank', 'bm25(10.0, 5.0, 5.0, 2.0, 1.0, 1.0, 1.0, 1.0)');
+-- STATEMENT
+DROP TRIGGER IF EXISTS listings_ai;
+-- STATEMENT
+CREATE TRIGGER listings_ai AFTER INSERT ON listings BEGIN
+    INSERT INTO listings_fts(rowid, title, top_dish, regional_specialty, description, address, city, state, country)
+    VALUES (new.rowid, new.title, new.top_dish, new.regional_specialty, new.description, new.address, new.city, new.state, new.country);
+END;
+-- STATEMENT
+DROP TRIGGER IF EXISTS listings_ad;
+-- STATEMENT
+CREATE TRIGGER listings_ad AFTER DELETE ON listings BEGIN
+    INSERT INTO listings_fts(listings_fts, rowid, title, top_dish, regional_specialty, description, address, city, state, country)
+    VALUES ('delete', old.rowid, old.title, old.top_dish, old.regional_specialty, old.description, old.address, old.city, old.state, old.country);
+END;
+-- STATEMENT
+DROP TRIGGER IF EXISTS listings_au;
+-- STATEMENT
+CREATE TRIGGER listings_au AFTER UPDATE ON listings BEGIN
+    INSERT INTO listings_fts(listings_fts, rowid, title, top_dish, regional_specialty, description, address, city, state, country)
+    VALUES ('delete', old.rowid, old.title, old.top_dish, old.regional_specialty, old.description, old.address, old.city, old.state, old.country);
+    INSERT INTO listings_fts(rowid, title, top_dish, regional_specialty, description, address, city, state, country)
+    VALUES (new.rowid, new.title, new.top_dish, new.regional_specialty, new.description, new.address, new.city, new.state, new.country);
+END;
+-- STATEMENT
+INSERT INTO listings_fts(listings_fts) VALUES('rebuild');
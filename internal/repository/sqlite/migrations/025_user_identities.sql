@@ -0,0 +1,19 @@
+-- Sign-in identities (025): each row links a provider account to a user, so a user
+-- can sign in with Google and any configured OpenID Connect provider. Existing Google
+-- IDs become identities, and google_id is NULL rather than empty for users without one.
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+-- STATEMENT
+UPDATE users SET google_id = NULL WHERE google_id = '';
+-- STATEMENT
+INSERT OR IGNORE INTO user_identities (provider, subject, user_id, email, created_at)
+SELECT 'google', google_id, id, COALESCE(email, ''), COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM users WHERE google_id IS NOT NULL;
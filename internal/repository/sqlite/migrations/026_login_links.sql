@@ -0,0 +1,11 @@
+-- Emailed sign-in links (026). Only the SHA-256 hash of each token is stored; a link
+-- is single use, so used_at is set when it signs someone in.
+CREATE TABLE IF NOT EXISTS login_links (
+    token_hash TEXT PRIMARY KEY,
+    email TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_login_links_expires ON login_links(expires_at);
@@ -0,0 +1,16 @@
+-- Server-side browser sessions (027). The cookie holds a signed random ID and only
+-- its SHA-256 hash is stored here, so sessions can be listed and revoked.
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT,
+    data BLOB NOT NULL,
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_sessions_expires ON sessions(expires_at);
@@ -0,0 +1,33 @@
+-- Whether a user may sign in, and the audit trail of staff suspending, banning and
+-- reinstating users.
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+-- STATEMENT
+ALTER TABLE users ADD COLUMN suspended_until DATETIME;
+-- STATEMENT
+ALTER TABLE users ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+ALTER TABLE listing_events ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+-- STATEMENT
+CREATE TABLE IF NOT EXISTS user_events (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    actor_name TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    until DATETIME,
+    listings_deactivated INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL
+);
+-- STATEMENT
+CREATE INDEX IF NOT EXISTS idx_user_events_user ON user_events(user_id, created_at);
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS user_events_no_update BEFORE UPDATE ON user_events
+BEGIN
+    SELECT RAISE(ABORT, 'user_events is append-only');
+END;
+-- STATEMENT
+CREATE TRIGGER IF NOT EXISTS user_events_no_delete BEFORE DELETE ON user_events
+BEGIN
+    SELECT RAISE(ABORT, 'user_events is append-only');
+END;
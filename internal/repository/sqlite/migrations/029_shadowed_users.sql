@@ -0,0 +1,3 @@
+-- Public listing queries skip listings whose owner is shadow-moderated; the partial
+-- index keeps that lookup to the few users it applies to.
+CREATE INDEX IF NOT EXISTS idx_users_shadowed ON users(id) WHERE status = 'shadowed';
@@ -0,0 +1,2 @@
+-- Emailed sign-in links find the user who already signed in with the same address.
+CREATE INDEX IF NOT EXISTS idx_user_identities_email ON user_identities(email COLLATE NOCASE);
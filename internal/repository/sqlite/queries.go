@@ -16,30 +16,58 @@ const ListingSelectionsSQL = `
 	enrichment_attempted_at,
 	COALESCE(rating, 0.0), COALESCE(review_count, 0),
 	rating_updated_at,
-	COALESCE(structured_hours, '')
+	COALESCE(structured_hours, ''),
+	COALESCE(timezone, ''),
+	COALESCE(hours_exceptions, ''), COALESCE(permanently_closed, 0)
 `
 
 // UserSelectionsSQL is the shared column selection for reading users.
-const UserSelectionsSQL = `id, google_id, email, name, avatar_url, COALESCE(role, 'User'), created_at`
+const UserSelectionsSQL = `id, COALESCE(google_id, ''), email, name, avatar_url, COALESCE(role, 'User'), created_at,
+	status, suspended_until, status_reason`
 
 // CategorySelectionsSQL is the shared column selection for reading categories.
-const CategorySelectionsSQL = `id, name, claimable, is_system, active, requires_special_validation, created_at, updated_at`
+const CategorySelectionsSQL = `id, name, claimable, is_system, active, requires_special_validation, requires_moderation, created_at, updated_at`
 
 // Shared SQL fragments
 const (
-	ListingActiveApprovedSQL = `is_active = 1 AND status = 'Approved'`
-	ListingFilterTypeSQL     = ` AND type = ?`
+	ListingActiveApprovedSQL = `is_active = 1 AND status = 'Approved' AND permanently_closed = 0`
+	// ListingOwnerNotShadowedSQL drops listings whose owner is shadow-moderated.
+	ListingOwnerNotShadowedSQL = `owner_id NOT IN (SELECT id FROM users WHERE status = 'shadowed')`
+	// ListingOpenAtSQL matches listings open at a local date and time: by a range of
+	// that day still running, or by an overnight range of the day before that runs past
+	// midnight. Ranges compare as "HH:MM" strings; a close before the open wraps midnight.
+	// Args: listingDayHoursSQL's for the day, the time twice, listingDayHoursSQL's for
+	// the day before and the time once more.
+	ListingOpenAtSQL = `(EXISTS (SELECT 1 FROM json_each(` + listingDayHoursSQL + `) AS h
+			WHERE ? >= substr(h.value, 1, 5) AND (? < substr(h.value, 7, 5) OR substr(h.value, 1, 5) > substr(h.value, 7, 5)))
+		OR EXISTS (SELECT 1 FROM json_each(` + listingDayHoursSQL + `) AS h
+			WHERE substr(h.value, 1, 5) > substr(h.value, 7, 5) AND ? < substr(h.value, 7, 5)))`
+
+	// listingDayHoursSQL is the JSON array of "HH:MM-HH:MM" ranges a listing keeps on a
+	// date: those of the special hours entry covering it, the latest starting one winning
+	// as in domain.HoursExceptionCovering, otherwise the structured_hours date key, then
+	// its weekday key. Args: the "YYYY-MM-DD" date twice, then the JSON paths of the date
+	// and weekday keys.
+	listingDayHoursSQL = `COALESCE(
+		(SELECT COALESCE(CASE x.value ->> '$.kind' WHEN 'custom' THEN x.value -> '$.hours' END, '[]')
+			FROM json_each(CASE WHEN json_valid(hours_exceptions) THEN hours_exceptions END) AS x
+			WHERE x.value ->> '$.start' <= ?
+			  AND (x.value ->> '$.kind' = 'permanently_closed' OR COALESCE(x.value ->> '$.end', x.value ->> '$.start') >= ?)
+			ORDER BY x.value ->> '$.start' DESC LIMIT 1),
+		CASE WHEN json_valid(structured_hours) THEN structured_hours END -> ?,
+		CASE WHEN json_valid(structured_hours) THEN structured_hours END -> ?)`
 )
 
 // Shared Read Queries
 const (
-	ListingGetCountsSQL    = `SELECT type, COUNT(*) FROM listings WHERE ` + ListingActiveApprovedSQL + ` GROUP BY type`
-	ListingGetLocationsSQL = `SELECT DISTINCT city, state, country FROM listings WHERE ` + ListingActiveApprovedSQL + ` AND city != '' ORDER BY country ASC, state ASC, city ASC`
+	ListingGetCountsSQL    = `SELECT type, COUNT(*) FROM listings WHERE ` + ListingActiveApprovedSQL + ` AND ` + ListingOwnerNotShadowedSQL + ` GROUP BY type`
+	ListingGetLocationsSQL = `SELECT DISTINCT city, state, country FROM listings WHERE ` + ListingActiveApprovedSQL + ` AND ` + ListingOwnerNotShadowedSQL + ` AND city != '' ORDER BY country ASC, state ASC, city ASC`
 	ListingTitleExistsSQL  = `SELECT EXISTS(SELECT 1 FROM listings WHERE title = ?)`
+	ListingTimezonesSQL    = `SELECT DISTINCT timezone FROM listings WHERE timezone != ''`
 	UserGetCountSQL        = `SELECT COUNT(*) FROM users`
 )
 
-const listingColumns = `(id, owner_id, title, description, type, owner_origin, city, state, country, address, hours_of_operation, is_active, created_at, image_url, contact_email, contact_phone, contact_whatsapp, website_url, deadline, event_start, event_end, skills, job_start_date, job_apply_url, company, pay_range, status, featured, heat_level, regional_specialty, top_dish, payment_methods, menu_url, latitude, longitude, enrichment_attempted_at, delivery_platforms, rating, review_count, rating_updated_at, structured_hours)`
+const listingColumns = `(id, owner_id, title, description, type, owner_origin, city, state, country, address, hours_of_operation, is_active, created_at, image_url, contact_email, contact_phone, contact_whatsapp, website_url, deadline, event_start, event_end, skills, job_start_date, job_apply_url, company, pay_range, status, featured, heat_level, regional_specialty, top_dish, payment_methods, menu_url, latitude, longitude, enrichment_attempted_at, delivery_platforms, rating, review_count, rating_updated_at, structured_hours, timezone, hours_exceptions, permanently_closed)`
 
 const listingUpsertUpdate = `ON CONFLICT(id) DO UPDATE SET
 		owner_id = excluded.owner_id,
@@ -80,30 +108,46 @@ const listingUpsertUpdate = `ON CONFLICT(id) DO UPDATE SET
 		rating = excluded.rating,
 		review_count = excluded.review_count,
 		rating_updated_at = excluded.rating_updated_at,
-		structured_hours = excluded.structured_hours;`
+		structured_hours = excluded.structured_hours,
+		timezone = excluded.timezone,
+		hours_exceptions = excluded.hours_exceptions,
+		permanently_closed = excluded.permanently_closed;`
 
 // ListingUpsertSQL is the shared UPSERT query for both single and batch saves.
 const ListingUpsertSQL = `INSERT INTO listings ` + listingColumns + `
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	` + listingUpsertUpdate
 
 // CategoryUpsertSQL is the shared UPSERT query for category saving.
 const CategoryUpsertSQL = `
-	INSERT INTO categories (id, name, claimable, is_system, active, requires_special_validation, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO categories (id, name, claimable, is_system, active, requires_special_validation, requires_moderation, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		name = excluded.name,
 		claimable = excluded.claimable,
 		is_system = excluded.is_system,
 		active = excluded.active,
 		requires_special_validation = excluded.requires_special_validation,
+		requires_moderation = excluded.requires_moderation,
 		updated_at = excluded.updated_at;
 	`
 
-// ClaimUpsertSQL is the shared UPSERT query for claim saves.
+// ClaimUpsertSQL is the shared UPSERT query for claim saves. The claimant and
+// listing are fixed once a claim exists; evidence and review state may change.
 const ClaimUpsertSQL = `
-	INSERT INTO claim_requests (id, listing_id, listing_title, user_id, user_name, user_email, status, created_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO claim_requests (id, listing_id, listing_title, user_id, user_name, user_email, status, created_at,
+		evidence_email, document_url, phone_code_hash, phone_code_sent_at, phone_verified_at,
+		reviewer_id, reviewer_notes, rejection_reason, reviewed_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
-		status = excluded.status;
+		status = excluded.status,
+		evidence_email = excluded.evidence_email,
+		document_url = excluded.document_url,
+		phone_code_hash = excluded.phone_code_hash,
+		phone_code_sent_at = excluded.phone_code_sent_at,
+		phone_verified_at = excluded.phone_verified_at,
+		reviewer_id = excluded.reviewer_id,
+		reviewer_notes = excluded.reviewer_notes,
+		rejection_reason = excluded.rejection_reason,
+		reviewed_at = excluded.reviewed_at;
 	`
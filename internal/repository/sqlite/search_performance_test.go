@@ -33,36 +33,47 @@ func BenchmarkSearchPerformance(b *testing.B) {
 	}
 	b.Log("Seeding complete.")
 
-	b.Run("FindAll_Default_Page1", func(b *testing.B) {
+	b.Run("Search_Default_Page1", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", false, 30, 0)
+			_, _ = repo.Search(ctx, domain.ListingQuery{Limit: 30})
 		}
 	})
 	runRemainingSearchBenchmarks(b, repo, ctx)
 }
 
 func runRemainingSearchBenchmarks(b *testing.B, repo *sqlite.SQLiteRepository, ctx context.Context) {
-	b.Run("FindAll_Search_Page1", func(b *testing.B) {
+	b.Run("Search_Search_Page1", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = repo.FindAll(ctx, "", "ghana", "", 0.0, 0.0, 0.0, "", "", false, 30, 0)
+			_, _ = repo.Search(ctx, domain.ListingQuery{QueryText: "ghana", Limit: 30})
 		}
 	})
 
-	b.Run("FindAll_Filter_Page1", func(b *testing.B) {
+	b.Run("Search_Filter_Page1", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = repo.FindAll(ctx, string(domain.Business), "", "", 0.0, 0.0, 0.0, "", "", false, 30, 0)
+			_, _ = repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Business}, Limit: 30})
 		}
 	})
 
-	b.Run("FindAll_Search_Filter_Page1", func(b *testing.B) {
+	b.Run("Search_Search_Filter_Page1", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = repo.FindAll(ctx, string(domain.Business), "ghana", "", 0.0, 0.0, 0.0, "", "", false, 30, 0)
+			_, _ = repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Business}, QueryText: "ghana", Limit: 30})
 		}
 	})
 
-	b.Run("FindAll_Deep_Pagination", func(b *testing.B) {
+	b.Run("Search_Deep_Pagination", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", false, 30, 5000)
+			_, _ = repo.Search(ctx, domain.ListingQuery{Limit: 30, Offset: 5000})
+		}
+	})
+
+	b.Run("Search_Deep_Cursor", func(b *testing.B) {
+		page, err := repo.Search(ctx, domain.ListingQuery{Limit: 1, Offset: 4999, SkipCount: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = repo.Search(ctx, domain.ListingQuery{Limit: 30, Cursor: page.NextCursor, SkipCount: true})
 		}
 	})
 }
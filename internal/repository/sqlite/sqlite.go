@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"os"
@@ -22,10 +23,20 @@ type Scanner interface {
 	Scan(dest ...interface{}) error
 }
 
+// execer is what *sql.DB and *sql.Tx share, so a write can run alone or inside a
+// larger transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type SQLiteRepository struct {
 	writeDB            *sql.DB
 	readDB             *sql.DB
 	slowQueryThreshold time.Duration
+	// noSpatialIndex makes location filters scan the latitude/longitude index
+	// instead of the listings_rtree R*Tree, for benchmarking one against the other.
+	noSpatialIndex bool
 }
 
 // NewSQLiteRepositoryFromDB creates a new repository using an existing DB connection for both pools.
@@ -88,6 +99,12 @@ func (r *SQLiteRepository) SetSlowQueryThreshold(d time.Duration) {
 	r.slowQueryThreshold = d
 }
 
+// SetSpatialIndex chooses whether radius and map searches use the R*Tree spatial
+// index. It is on by default; the benchmark command turns it off for comparison.
+func (r *SQLiteRepository) SetSpatialIndex(enabled bool) {
+	r.noSpatialIndex = !enabled
+}
+
 func applyPragmas(db *sql.DB) error {
 	pragmas := []string{
 		"PRAGMA journal_mode=WAL;",
@@ -10,7 +10,7 @@ import (
 // SaveCategory inserts or updates a category.
 func (r *SQLiteRepository) SaveCategory(ctx context.Context, c domain.CategoryData) error {
 	_, err := r.writeDB.ExecContext(ctx, CategoryUpsertSQL,
-		c.ID, c.Name, c.Claimable, c.IsSystem, c.Active, c.RequiresSpecialValidation, c.CreatedAt, c.UpdatedAt,
+		c.ID, c.Name, c.Claimable, c.IsSystem, c.Active, c.RequiresSpecialValidation, c.RequiresModeration, c.CreatedAt, c.UpdatedAt,
 	)
 	return err
 }
@@ -40,7 +40,7 @@ func (r *SQLiteRepository) GetCategories(ctx context.Context, filter domain.Cate
 	for rows.Next() {
 		var c domain.CategoryData
 		var created, updated sql.NullTime
-		err := rows.Scan(&c.ID, &c.Name, &c.Claimable, &c.IsSystem, &c.Active, &c.RequiresSpecialValidation, &created, &updated)
+		err := rows.Scan(&c.ID, &c.Name, &c.Claimable, &c.IsSystem, &c.Active, &c.RequiresSpecialValidation, &c.RequiresModeration, &created, &updated)
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +58,7 @@ func (r *SQLiteRepository) GetCategories(ctx context.Context, filter domain.Cate
 // EnsureCoreCategories seeds the categories table with core types that must exist.
 func (r *SQLiteRepository) UpsertCoreCategory(ctx context.Context, c domain.CategoryData) error {
 	_, err := r.writeDB.ExecContext(ctx, CategoryUpsertSQL,
-		c.ID, c.Name, c.Claimable, c.IsSystem, c.Active, c.RequiresSpecialValidation, c.CreatedAt, c.UpdatedAt,
+		c.ID, c.Name, c.Claimable, c.IsSystem, c.Active, c.RequiresSpecialValidation, c.RequiresModeration, c.CreatedAt, c.UpdatedAt,
 	)
 	return err
 }
@@ -74,7 +74,7 @@ func (r *SQLiteRepository) GetCategory(ctx context.Context, name string) (domain
 
 	var c domain.CategoryData
 	var created, updated sql.NullTime
-	err := row.Scan(&c.ID, &c.Name, &c.Claimable, &c.IsSystem, &c.Active, &c.RequiresSpecialValidation, &created, &updated)
+	err := row.Scan(&c.ID, &c.Name, &c.Claimable, &c.IsSystem, &c.Active, &c.RequiresSpecialValidation, &c.RequiresModeration, &created, &updated)
 	if err == sql.ErrNoRows {
 		return domain.CategoryData{}, domain.ErrCategoryNotFound
 	}
@@ -7,48 +7,70 @@ import (
 	"github.com/jadecobra/agbalumo/internal/domain"
 )
 
+const claimColumns = `id, listing_id, COALESCE(listing_title,''), user_id, COALESCE(user_name,''), COALESCE(user_email,''), status, created_at,
+	evidence_email, document_url, phone_code_hash, phone_code_sent_at, phone_verified_at,
+	reviewer_id, reviewer_notes, rejection_reason, reviewed_at`
+
+func scanClaimRequest(s Scanner) (domain.ClaimRequest, error) {
+	var cr domain.ClaimRequest
+	var codeSent, phoneVerified, reviewed sql.NullTime
+	err := s.Scan(&cr.ID, &cr.ListingID, &cr.ListingTitle, &cr.UserID, &cr.UserName, &cr.UserEmail, &cr.Status, &cr.CreatedAt,
+		&cr.EvidenceEmail, &cr.DocumentURL, &cr.PhoneCodeHash, &codeSent, &phoneVerified,
+		&cr.ReviewerID, &cr.ReviewerNotes, &cr.RejectionReason, &reviewed)
+	if err != nil {
+		return domain.ClaimRequest{}, err
+	}
+	cr.PhoneCodeSentAt = codeSent.Time
+	cr.PhoneVerifiedAt = phoneVerified.Time
+	cr.ReviewedAt = reviewed.Time
+	return cr, nil
+}
+
 // SaveClaimRequest inserts or updates a claim request.
 func (r *SQLiteRepository) SaveClaimRequest(ctx context.Context, req domain.ClaimRequest) error {
 	_, err := r.writeDB.ExecContext(ctx, ClaimUpsertSQL,
 		req.ID, req.ListingID, req.ListingTitle, req.UserID, req.UserName, req.UserEmail, req.Status, req.CreatedAt,
+		req.EvidenceEmail, req.DocumentURL, req.PhoneCodeHash, nullTime(req.PhoneCodeSentAt), nullTime(req.PhoneVerifiedAt),
+		req.ReviewerID, req.ReviewerNotes, req.RejectionReason, nullTime(req.ReviewedAt),
 	)
 	return err
 }
 
 // GetPendingClaimRequests returns all claim requests with status=Pending.
 func (r *SQLiteRepository) GetPendingClaimRequests(ctx context.Context) ([]domain.ClaimRequest, error) {
-	query := `
-		SELECT id, listing_id, COALESCE(listing_title,''), user_id, COALESCE(user_name,''), COALESCE(user_email,''), status, created_at
-		FROM claim_requests
-		WHERE status = 'Pending'
-		ORDER BY created_at ASC
-	`
-	rows, err := r.readDB.QueryContext(ctx, query)
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+claimColumns+` FROM claim_requests WHERE status = ? ORDER BY created_at ASC`,
+		domain.ClaimStatusPending)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = rows.Close() }()
+	return scanAll(rows, scanClaimRequest)
+}
 
-	var results []domain.ClaimRequest
-	for rows.Next() {
-		var cr domain.ClaimRequest
-		if err := rows.Scan(&cr.ID, &cr.ListingID, &cr.ListingTitle, &cr.UserID, &cr.UserName, &cr.UserEmail, &cr.Status, &cr.CreatedAt); err != nil {
-			return nil, err
-		}
-		results = append(results, cr)
+// GetClaimRequest retrieves a claim request by ID.
+func (r *SQLiteRepository) GetClaimRequest(ctx context.Context, id string) (domain.ClaimRequest, error) {
+	row := r.readDB.QueryRowContext(ctx, `SELECT `+claimColumns+` FROM claim_requests WHERE id = ?`, id)
+	cr, err := scanClaimRequest(row)
+	if err == sql.ErrNoRows {
+		return domain.ClaimRequest{}, ErrClaimRequestNotFound
 	}
-	return results, rows.Err()
+	return cr, err
 }
 
-// UpdateClaimRequestStatus updates a claim request's status.
-func (r *SQLiteRepository) UpdateClaimRequestStatus(ctx context.Context, id string, status domain.ClaimStatus) error {
+// ReviewClaimRequest records a moderator's decision on a claim request. Approving a
+// claim transfers ownership of the listing to the claimant in the same transaction.
+func (r *SQLiteRepository) ReviewClaimRequest(ctx context.Context, id string, review domain.ClaimReview) error {
 	tx, err := r.writeDB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	res, err := tx.ExecContext(ctx, `UPDATE claim_requests SET status = ? WHERE id = ?`, status, id)
+	res, err := tx.ExecContext(ctx, `
+		UPDATE claim_requests
+		SET status = ?, reviewer_id = ?, reviewer_notes = ?, rejection_reason = ?, reviewed_at = ?
+		WHERE id = ?`,
+		review.Status, review.ReviewerID, review.Notes, review.RejectionReason, nullTime(review.ReviewedAt), id)
 	if err != nil {
 		return err
 	}
@@ -61,7 +83,7 @@ func (r *SQLiteRepository) UpdateClaimRequestStatus(ctx context.Context, id stri
 		return ErrClaimRequestNotFound
 	}
 
-	if status == domain.ClaimStatusApproved {
+	if review.Status == domain.ClaimStatusApproved {
 		_, err = tx.ExecContext(ctx, `
 			UPDATE listings SET owner_id = (
 				SELECT user_id FROM claim_requests WHERE id = ?
@@ -77,20 +99,26 @@ func (r *SQLiteRepository) UpdateClaimRequestStatus(ctx context.Context, id stri
 	return tx.Commit()
 }
 
-// GetClaimRequestByUserAndListing retrieves any existing claim request for a user/listing pair.
+// GetClaimRequestByUserAndListing retrieves the latest claim request for a user/listing pair.
 func (r *SQLiteRepository) GetClaimRequestByUserAndListing(ctx context.Context, userID, listingID string) (domain.ClaimRequest, error) {
-	query := `
-		SELECT id, listing_id, COALESCE(listing_title,''), user_id, COALESCE(user_name,''), COALESCE(user_email,''), status, created_at
-		FROM claim_requests
+	row := r.readDB.QueryRowContext(ctx, `
+		SELECT `+claimColumns+` FROM claim_requests
 		WHERE user_id = ? AND listing_id = ?
 		ORDER BY created_at DESC
-		LIMIT 1
-	`
-	row := r.readDB.QueryRowContext(ctx, query, userID, listingID)
-	var cr domain.ClaimRequest
-	err := row.Scan(&cr.ID, &cr.ListingID, &cr.ListingTitle, &cr.UserID, &cr.UserName, &cr.UserEmail, &cr.Status, &cr.CreatedAt)
+		LIMIT 1`, userID, listingID)
+	cr, err := scanClaimRequest(row)
 	if err == sql.ErrNoRows {
 		return domain.ClaimRequest{}, ErrClaimRequestNotFound
 	}
 	return cr, err
 }
+
+// ListClaimRequestsByUser returns a user's claim requests, newest first.
+func (r *SQLiteRepository) ListClaimRequestsByUser(ctx context.Context, userID string) ([]domain.ClaimRequest, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+claimColumns+` FROM claim_requests WHERE user_id = ? ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanClaimRequest)
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// GetGeocodeCacheEntry returns the unexpired cached result for address and lookup.
+func (r *SQLiteRepository) GetGeocodeCacheEntry(ctx context.Context, address string, lookup domain.GeocodeLookup, now time.Time) (domain.GeocodeCacheEntry, error) {
+	e := domain.GeocodeCacheEntry{Address: address, Lookup: lookup}
+	err := r.readDB.QueryRowContext(ctx,
+		`SELECT city, latitude, longitude, found, created_at, expires_at FROM geocode_cache
+		WHERE address = ? AND lookup = ? AND expires_at > ?`,
+		address, lookup, now.UTC(),
+	).Scan(&e.City, &e.Latitude, &e.Longitude, &e.Found, &e.CreatedAt, &e.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.GeocodeCacheEntry{}, domain.ErrGeocodeNotCached
+	}
+	if err != nil {
+		return domain.GeocodeCacheEntry{}, err
+	}
+	return e, nil
+}
+
+// SaveGeocodeCacheEntry stores e, replacing the previous result for its address and lookup.
+func (r *SQLiteRepository) SaveGeocodeCacheEntry(ctx context.Context, e domain.GeocodeCacheEntry) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`INSERT INTO geocode_cache (address, lookup, city, latitude, longitude, found, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(address, lookup) DO UPDATE SET
+			city = excluded.city, latitude = excluded.latitude, longitude = excluded.longitude,
+			found = excluded.found, created_at = excluded.created_at, expires_at = excluded.expires_at`,
+		e.Address, e.Lookup, e.City, e.Latitude, e.Longitude, e.Found, e.CreatedAt.UTC(), e.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+// DeleteExpiredGeocodeCacheEntries prunes entries that expired at or before now.
+func (r *SQLiteRepository) DeleteExpiredGeocodeCacheEntries(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.writeDB.ExecContext(ctx, `DELETE FROM geocode_cache WHERE expires_at <= ?`, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,58 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeocodeCache_SaveGetAndPrune(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	coords := domain.GeocodeCacheEntry{
+		Address: "houston, tx", Lookup: domain.GeocodeLookupCoords,
+		Latitude: 29.76, Longitude: -95.37, Found: true,
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, repo.SaveGeocodeCacheEntry(ctx, coords))
+
+	got, err := repo.GetGeocodeCacheEntry(ctx, "houston, tx", domain.GeocodeLookupCoords, now)
+	require.NoError(t, err)
+	assert.Equal(t, coords.Latitude, got.Latitude)
+	assert.Equal(t, coords.Longitude, got.Longitude)
+	assert.True(t, got.Found)
+	assert.True(t, coords.ExpiresAt.Equal(got.ExpiresAt))
+
+	_, err = repo.GetGeocodeCacheEntry(ctx, "houston, tx", domain.GeocodeLookupCity, now)
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotCached, "lookups are cached separately")
+	_, err = repo.GetGeocodeCacheEntry(ctx, "houston, tx", domain.GeocodeLookupCoords, now.Add(time.Hour))
+	assert.ErrorIs(t, err, domain.ErrGeocodeNotCached, "expired entries are not returned")
+
+	miss := domain.GeocodeCacheEntry{
+		Address: "houston, tx", Lookup: domain.GeocodeLookupCoords,
+		CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	}
+	require.NoError(t, repo.SaveGeocodeCacheEntry(ctx, miss))
+	got, err = repo.GetGeocodeCacheEntry(ctx, "houston, tx", domain.GeocodeLookupCoords, now)
+	require.NoError(t, err)
+	assert.False(t, got.Found, "saving replaces the previous entry")
+
+	require.NoError(t, repo.SaveGeocodeCacheEntry(ctx, domain.GeocodeCacheEntry{
+		Address: "dallas", Lookup: domain.GeocodeLookupCity, City: "Dallas", Found: true,
+		CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour),
+	}))
+	pruned, err := repo.DeleteExpiredGeocodeCacheEntries(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned)
+	got, err = repo.GetGeocodeCacheEntry(ctx, "dallas", domain.GeocodeLookupCity, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Dallas", got.City)
+}
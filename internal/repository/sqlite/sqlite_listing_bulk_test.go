@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 
 	"github.com/jadecobra/agbalumo/internal/seeder"
@@ -29,7 +30,8 @@ func TestSQLiteRepository_BulkInsertListings(t *testing.T) {
 	t.Logf("Bulk inserted %d listings in %v", count, duration)
 
 	// 3. Verify total count increased appropriately
-	_, totalCount, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", true, 1, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{IncludeInactive: true, Limit: 1})
+	totalCount := page.TotalCount
 	if err != nil {
 		t.Fatalf("Failed to count listings: %v", err)
 	}
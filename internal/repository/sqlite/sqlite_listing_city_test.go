@@ -9,7 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFindAll_CityFiltering(t *testing.T) {
+func TestSearch_CityFiltering(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -20,19 +20,22 @@ func TestFindAll_CityFiltering(t *testing.T) {
 	saveTestListing(t, ctx, repo, domain.Listing{ID: "3", Title: "Food Dallas", Type: domain.Food, City: "Dallas", IsActive: true, Status: domain.ListingStatusApproved})
 
 	// 1. Filter by City only
-	res, _, err := repo.FindAll(ctx, "", "", "Houston", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Cities: []string{"Houston"}, Limit: 10})
+	res := page.Listings
 	assert.NoError(t, err)
 	assert.Len(t, res, 2)
 
 	// 2. Filter by City + Category
-	res, _, err = repo.FindAll(ctx, string(domain.Food), "", "Houston", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+	page, err = repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Food}, Cities: []string{"Houston"}, Limit: 10})
+	res = page.Listings
 	assert.NoError(t, err)
 	if assert.Len(t, res, 1) {
 		assert.Equal(t, "1", res[0].ID)
 	}
 
 	// 3. Filter by City + Search
-	res, _, err = repo.FindAll(ctx, "", "Service", "Houston", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+	page, err = repo.Search(ctx, domain.ListingQuery{QueryText: "Service", Cities: []string{"Houston"}, Limit: 10})
+	res = page.Listings
 	assert.NoError(t, err)
 	if assert.Len(t, res, 1) {
 		assert.Equal(t, "2", res[0].ID)
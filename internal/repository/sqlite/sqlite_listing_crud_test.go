@@ -274,10 +274,11 @@ func TestDefaultSortingWithRating(t *testing.T) {
 	_ = repo.Save(ctx, l2)
 	_ = repo.Save(ctx, l3)
 
-	// Note: FindAll defaults to Category Food if empty, so we set Type to Food above.
-	listings, _, err := repo.FindAll(ctx, string(domain.Food), "", "", 0, 0, 0, "", "", false, 10, 0)
+	// Note: the home feed defaults to Category Food if empty, so we set Type to Food above.
+	page, err := repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Food}, Limit: 10})
+	listings := page.Listings
 	if err != nil {
-		t.Fatalf("FindAll failed: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
 
 	if len(listings) < 3 {
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const listingEventColumns = `id, listing_id, actor_id, actor_name, action, reason, changes, created_at`
+
+func scanListingEvent(s Scanner) (domain.ListingEvent, error) {
+	var e domain.ListingEvent
+	var changes string
+	if err := s.Scan(&e.ID, &e.ListingID, &e.ActorID, &e.ActorName, &e.Action, &e.Reason, &changes, &e.CreatedAt); err != nil {
+		return domain.ListingEvent{}, err
+	}
+	if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+		return domain.ListingEvent{}, err
+	}
+	return e, nil
+}
+
+// AppendListingEvent inserts an audit event. Events are never updated or deleted.
+func (r *SQLiteRepository) AppendListingEvent(ctx context.Context, e domain.ListingEvent) error {
+	return appendListingEvent(ctx, r.writeDB, e)
+}
+
+// SaveListingWithHistory saves l, the revision rev when it is not nil, and e in one
+// transaction, so a listing never changes without its history. e.Changes is diffed
+// between before and l as stored.
+func (r *SQLiteRepository) SaveListingWithHistory(ctx context.Context, before, l domain.Listing, rev *domain.ListingRevision, e domain.ListingEvent) error {
+	tx, err := r.writeDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if rev != nil {
+		if _, err = saveListingRevision(ctx, tx, *rev); err != nil {
+			return fmt.Errorf("snapshot listing revision: %w", err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, ListingUpsertSQL, r.listingArgs(l)...); err != nil {
+		return err
+	}
+	after, err := scanListing(tx.QueryRowContext(ctx, `SELECT `+r.buildListingColumns()+` FROM listings WHERE id = ?`, l.ID))
+	if err != nil {
+		return fmt.Errorf("reload listing %s for history: %w", l.ID, err)
+	}
+	e.Changes = domain.DiffListings(before, after)
+	if err = appendListingEvent(ctx, tx, e); err != nil {
+		return fmt.Errorf("record listing event: %w", err)
+	}
+	return tx.Commit()
+}
+
+func appendListingEvent(ctx context.Context, db execer, e domain.ListingEvent) error {
+	changes := e.Changes
+	if changes == nil {
+		changes = []domain.FieldChange{}
+	}
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO listing_events (`+listingEventColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.ListingID, e.ActorID, e.ActorName, e.Action, e.Reason, string(raw), e.CreatedAt,
+	)
+	return err
+}
+
+// ListListingEvents returns every event recorded for a listing, oldest first.
+func (r *SQLiteRepository) ListListingEvents(ctx context.Context, listingID string) ([]domain.ListingEvent, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+listingEventColumns+` FROM listing_events WHERE listing_id = ? ORDER BY created_at ASC, rowid ASC`, listingID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanListingEvent)
+}
@@ -0,0 +1,97 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingEvents_AppendAndList(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	second := domain.ListingEvent{ID: "e2", ListingID: "l1", ActorID: "u1", Action: domain.ListingActionFeature, CreatedAt: now,
+		Changes: []domain.FieldChange{{Field: "featured", Before: "false", After: "true"}}}
+	first := domain.ListingEvent{ID: "e1", ListingID: "l1", ActorID: "u1", ActorName: "Ada", Action: domain.ListingActionCreate, CreatedAt: now.Add(-time.Hour)}
+	other := domain.ListingEvent{ID: "e3", ListingID: "l2", ActorID: "cli", Action: domain.ListingActionDelete, CreatedAt: now}
+	for _, e := range []domain.ListingEvent{second, first, other} {
+		require.NoError(t, repo.AppendListingEvent(ctx, e))
+	}
+
+	events, err := repo.ListListingEvents(ctx, "l1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "e1", events[0].ID)
+	assert.Equal(t, "Ada", events[0].ActorName)
+	assert.Empty(t, events[0].Changes)
+	assert.Equal(t, second.Changes, events[1].Changes)
+	assert.True(t, events[1].CreatedAt.Equal(now))
+
+	none, err := repo.ListListingEvents(ctx, "missing")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestListingEvents_AppendOnly(t *testing.T) {
+	t.Parallel()
+	repo, dsn := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	require.NoError(t, repo.AppendListingEvent(ctx, domain.ListingEvent{ID: "e1", ListingID: "l1", Action: domain.ListingActionCreate, CreatedAt: time.Now()}))
+
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	_, err = db.ExecContext(ctx, `UPDATE listing_events SET action = 'delete' WHERE id = 'e1'`)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = db.ExecContext(ctx, `DELETE FROM listing_events WHERE id = 'e1'`)
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestSaveListingWithHistory(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	testutil.SaveTestListing(t, repo, "l1", "Suya Spot")
+	before, err := repo.FindByID(ctx, "l1")
+	require.NoError(t, err)
+	require.NoError(t, repo.AppendListingEvent(ctx, domain.ListingEvent{ID: "taken", ListingID: "l1", Action: domain.ListingActionCreate, CreatedAt: time.Now()}))
+
+	save := func(title, eventID string) error {
+		l := before
+		l.Title = title
+		rev := &domain.ListingRevision{ListingID: "l1", ActorID: "u1", Snapshot: before, CreatedAt: time.Now()}
+		return repo.SaveListingWithHistory(ctx, before, l, rev,
+			domain.ListingEvent{ID: eventID, ListingID: "l1", ActorID: "u1", Action: domain.ListingActionUpdate, CreatedAt: time.Now()})
+	}
+
+	// A failed event write rolls back the revision and the listing.
+	require.Error(t, save("Lost Title", "taken"))
+	stored, err := repo.FindByID(ctx, "l1")
+	require.NoError(t, err)
+	assert.Equal(t, "Suya Spot", stored.Title)
+	revisions, err := repo.ListListingRevisions(ctx, "l1")
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+
+	require.NoError(t, save("Suya Palace", "e1"))
+	stored, err = repo.FindByID(ctx, "l1")
+	require.NoError(t, err)
+	assert.Equal(t, "Suya Palace", stored.Title)
+	revisions, err = repo.ListListingRevisions(ctx, "l1")
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "Suya Spot", revisions[0].Snapshot.Title)
+	events, err := repo.ListListingEvents(ctx, "l1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, []domain.FieldChange{{Field: "title", Before: "Suya Spot", After: "Suya Palace"}}, events[1].Changes)
+}
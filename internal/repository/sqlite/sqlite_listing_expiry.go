@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// listingExpirySQL is the stored column ExpireListings compares for each listing type.
+// A job's expiry is derived from its start date, so reminders are keyed on that.
+const listingExpirySQL = `CASE listings.type WHEN 'Request' THEN listings.deadline WHEN 'Event' THEN listings.event_end ELSE listings.job_start_date END`
+
+// ListExpiringListings returns owned, active listings that expire in [now, before)
+// and have no reminder recorded for their current expiry.
+func (r *SQLiteRepository) ListExpiringListings(ctx context.Context, now, before time.Time) ([]domain.Listing, error) {
+	now, before = now.UTC(), before.UTC()
+	jobNow, jobBefore := now.Add(-domain.JobListingLifetime), before.Add(-domain.JobListingLifetime)
+
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT `+ListingSelectionsSQL+` FROM listings
+		WHERE is_active = true AND owner_id != ''
+		AND (
+			(type = 'Request' AND deadline >= ? AND deadline < ?)
+			OR (type = 'Event' AND event_end >= ? AND event_end < ?)
+			OR (type = 'Job' AND job_start_date >= ? AND job_start_date < ?)
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM listing_expiry_reminders r
+			WHERE r.listing_id = listings.id AND r.expires_on = `+listingExpirySQL+`
+		)
+		ORDER BY created_at ASC`,
+		now, before, now, before, jobNow, jobBefore)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanListing)
+}
+
+// MarkExpiryReminderSent records a reminder against the listing's current expiry.
+func (r *SQLiteRepository) MarkExpiryReminderSent(ctx context.Context, id string, at time.Time) error {
+	_, err := r.writeDB.ExecContext(ctx, `
+		INSERT OR IGNORE INTO listing_expiry_reminders (listing_id, expires_on, sent_at)
+		SELECT id, `+listingExpirySQL+`, ? FROM listings WHERE id = ?`,
+		at.UTC(), id)
+	return err
+}
@@ -1,24 +1,29 @@
 package sqlite
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildListingWhere_LocationFallback(t *testing.T) {
 	tests := []struct {
 		name          string
 		expectedWhere string
-		filters       ListingFilters
+		query         domain.ListingQuery
 	}{
 		{
 			name: "fallback to city string match when radius search has zero coordinates",
-			filters: ListingFilters{
-				City:        "Dallas",
-				Radius:      10,
-				IncludedLat: 0,
-				IncludedLng: 0,
+			query: domain.ListingQuery{
+				Cities:      []string{"Dallas"},
+				RadiusMiles: 10,
+				Latitude:    0,
+				Longitude:   0,
 			},
 			expectedWhere: "(city = ? OR address LIKE ?)",
 		},
@@ -28,8 +33,86 @@ func TestBuildListingWhere_LocationFallback(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			where, _ := repo.buildListingWhere(tt.filters)
+			where, _ := repo.buildListingWhere(tt.query, time.Now(), nil)
 			assert.Contains(t, where, tt.expectedWhere)
 		})
 	}
 }
+
+func TestListingsRTree_FollowsListings(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLiteRepository(":memory:")
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+	ctx := context.Background()
+
+	rtreeRows := func() []float64 {
+		t.Helper()
+		rows, qErr := repo.writeDB.QueryContext(ctx, `SELECT min_lat, min_lng FROM listings_rtree`)
+		require.NoError(t, qErr)
+		defer func() { _ = rows.Close() }()
+		var got []float64
+		for rows.Next() {
+			var lat, lng float64
+			require.NoError(t, rows.Scan(&lat, &lng))
+			got = append(got, lat, lng)
+		}
+		return got
+	}
+
+	l := domain.Listing{
+		ID: "moves", Title: "Moves", Type: domain.Food, OwnerOrigin: "Nigeria", City: "Houston",
+		Latitude: 29.76, Longitude: -95.37, IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Save(ctx, l))
+	got := rtreeRows()
+	require.Len(t, got, 2)
+	assert.InDelta(t, 29.76, got[0], 1e-4)
+
+	l.Latitude, l.Longitude = 32.78, -96.80
+	require.NoError(t, repo.Save(ctx, l))
+	got = rtreeRows()
+	require.Len(t, got, 2, "an update replaces the entry")
+	assert.InDelta(t, -96.80, got[1], 1e-4)
+
+	l.Latitude, l.Longitude = 0, 0
+	require.NoError(t, repo.Save(ctx, l))
+	assert.Empty(t, rtreeRows(), "listings without a location are not indexed")
+
+	l.Latitude, l.Longitude = 29.76, -95.37
+	require.NoError(t, repo.Save(ctx, l))
+	require.NoError(t, repo.Delete(ctx, l.ID))
+	assert.Empty(t, rtreeRows())
+}
+
+func TestBuildListingWhere_SpatialIndex(t *testing.T) {
+	t.Parallel()
+	repo, err := NewSQLiteRepository(":memory:")
+	require.NoError(t, err)
+	defer func() { _ = repo.Close() }()
+
+	plan := func(q domain.ListingQuery) string {
+		t.Helper()
+		where, args := repo.buildListingWhere(q, time.Now(), nil)
+		rows, qErr := repo.writeDB.Query(`EXPLAIN QUERY PLAN SELECT id FROM listings`+where, args...)
+		require.NoError(t, qErr)
+		defer func() { _ = rows.Close() }()
+		var details []string
+		for rows.Next() {
+			var id, parent, notUsed int
+			var detail string
+			require.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+			details = append(details, detail)
+		}
+		return strings.Join(details, "\n")
+	}
+
+	radius := domain.ListingQuery{Latitude: 6.52, Longitude: 3.38, RadiusMiles: 10}
+	assert.Contains(t, plan(radius), "listings_rtree VIRTUAL TABLE INDEX")
+
+	pacific := domain.ListingQuery{Bounds: domain.BoundingBox{West: 170, South: -20, East: -170, North: 20}}
+	assert.Contains(t, plan(pacific), "listings_rtree VIRTUAL TABLE INDEX", "both halves of a wrapped box use the index")
+
+	repo.SetSpatialIndex(false)
+	assert.NotContains(t, plan(radius), "listings_rtree")
+}
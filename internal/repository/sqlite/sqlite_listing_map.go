@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ClusterListings groups the listings matching q into a grid of cells cellDegrees on
+// a side, so a zoomed-out map draws one marker per cell instead of every listing.
+// Listings without coordinates are left out. Larger clusters come first and q.Limit,
+// when set, caps the number returned.
+func (r *SQLiteRepository) ClusterListings(ctx context.Context, q domain.ListingQuery, cellDegrees float64) ([]domain.ListingCluster, error) {
+	start := time.Now()
+	defer r.logSlowQuery("ClusterListings", start)
+
+	var zones []string
+	if q.OpenNow || !q.OpenAt.IsZero() {
+		var err error
+		if zones, err = r.listingTimezones(ctx); err != nil {
+			return nil, err
+		}
+	}
+	where, args := r.buildListingWhere(q, start, zones)
+	where += ` AND (latitude != 0 OR longitude != 0)`
+
+	// The cell size is written into the expression so the GROUP BY terms are constant
+	// expressions of each row. It is derived from the zoom level, never read from the
+	// request as is.
+	groupBy := `rowid`
+	if cellDegrees > 0 {
+		cell := strconv.FormatFloat(cellDegrees, 'g', -1, 64)
+		groupBy = `CAST(floor(latitude / ` + cell + `) AS INTEGER), CAST(floor(longitude / ` + cell + `) AS INTEGER)`
+	}
+	limit := -1
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	args = append(args, limit)
+
+	// #nosec G202 - Dynamic query construction with trusted internal fragments
+	query := `SELECT COUNT(*), AVG(latitude), AVG(longitude), MIN(id), MIN(title), MIN(type) FROM listings` +
+		where + ` GROUP BY ` + groupBy + ` ORDER BY COUNT(*) DESC, MIN(id) LIMIT ?`
+	rows, err := r.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var clusters []domain.ListingCluster
+	for rows.Next() {
+		var c domain.ListingCluster
+		if err := rows.Scan(&c.Count, &c.Latitude, &c.Longitude, &c.ID, &c.Title, &c.Type); err != nil {
+			return nil, err
+		}
+		if c.Count > 1 {
+			c.ID, c.Title, c.Type = "", "", ""
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters, rows.Err()
+}
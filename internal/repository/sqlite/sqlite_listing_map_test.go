@@ -0,0 +1,97 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedMapListings(t *testing.T) domain.ListingRepository {
+	t.Helper()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	place := func(id string, typ domain.Category, lat, lng float64) {
+		saveTestListing(t, ctx, repo, domain.Listing{
+			ID: id, Title: id, Type: typ, OwnerOrigin: "Nigeria", City: "Houston",
+			Latitude: lat, Longitude: lng, IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now(),
+		})
+	}
+	place("downtown", domain.Food, 29.7604, -95.3698)
+	place("midtown", domain.Food, 29.7440, -95.3802)
+	place("heights", domain.Service, 29.7988, -95.3983)
+	place("katy", domain.Food, 29.7858, -95.8245)
+	place("dallas", domain.Food, 32.7767, -96.7970)
+	place("nowhere", domain.Food, 0, 0)
+	return repo
+}
+
+func TestClusterListings(t *testing.T) {
+	t.Parallel()
+	repo := seedMapListings(t)
+	ctx := context.Background()
+	texas := domain.BoundingBox{West: -100, South: 28, East: -94, North: 34}
+
+	clusters, err := repo.ClusterListings(ctx, domain.ListingQuery{Bounds: texas}, 0.5)
+	require.NoError(t, err)
+	require.Len(t, clusters, 3, "downtown, midtown and the heights share a cell")
+	assert.Equal(t, 3, clusters[0].Count)
+	assert.Empty(t, clusters[0].ID, "clusters do not name a listing")
+	assert.InDelta(t, (29.7604+29.7440+29.7988)/3, clusters[0].Latitude, 1e-9)
+	assert.Equal(t, 1, clusters[1].Count)
+	assert.Equal(t, "dallas", clusters[1].ID)
+	assert.Equal(t, "dallas", clusters[1].Title)
+	assert.Equal(t, domain.Food, clusters[1].Type)
+
+	clusters, err = repo.ClusterListings(ctx, domain.ListingQuery{Bounds: texas, Types: []domain.Category{domain.Food}}, 0.5)
+	require.NoError(t, err)
+	assert.Equal(t, 2, clusters[0].Count, "filters apply before clustering")
+
+	clusters, err = repo.ClusterListings(ctx, domain.ListingQuery{Bounds: texas}, 0)
+	require.NoError(t, err)
+	assert.Len(t, clusters, 5, "no clustering at zero cell size; listings without coordinates are left out")
+
+	clusters, err = repo.ClusterListings(ctx, domain.ListingQuery{Bounds: texas, Limit: 2}, 0)
+	require.NoError(t, err)
+	assert.Len(t, clusters, 2)
+}
+
+func TestSearch_Bounds(t *testing.T) {
+	t.Parallel()
+	repo := seedMapListings(t)
+
+	houston := domain.BoundingBox{West: -95.5, South: 29.7, East: -95.3, North: 29.8}
+	assert.ElementsMatch(t, []string{"downtown", "midtown", "heights"}, searchIDs(t, repo, domain.ListingQuery{Bounds: houston}))
+
+	// A box crossing the antimeridian keeps both of its edges.
+	pacific := domain.BoundingBox{West: 170, South: -90, East: -95.5, North: 90}
+	assert.ElementsMatch(t, []string{"dallas", "katy"}, searchIDs(t, repo, domain.ListingQuery{Bounds: pacific}))
+}
+
+func TestSearch_RadiusSpatialIndex(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	place := func(id string, lat, lng float64) {
+		saveTestListing(t, ctx, repo, domain.Listing{
+			ID: id, Title: id, Type: domain.Food, OwnerOrigin: "Nigeria", City: "Oslo",
+			Latitude: lat, Longitude: lng, IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now(),
+		})
+	}
+	// At 60 degrees north 20 miles east is about 0.58 degrees of longitude, outside
+	// a box that assumes a degree is 69 * 0.707 miles wide.
+	place("oslo", 59.9139, 10.7522)
+	place("east", 59.9139, 11.33)
+	place("stockholm", 59.3293, 18.0686)
+	place("nowhere", 0, 0)
+
+	q := domain.ListingQuery{Latitude: 59.9139, Longitude: 10.7522, RadiusMiles: 25, Sort: domain.SortDistance}
+	assert.Equal(t, []string{"oslo", "east"}, searchIDs(t, repo, q))
+
+	repo.SetSpatialIndex(false)
+	assert.Equal(t, []string{"oslo", "east"}, searchIDs(t, repo, q), "the B-tree path finds the same listings")
+}
@@ -54,8 +54,8 @@ func TestExpireListings(t *testing.T) {
 		t.Fatalf("ExpireListings failed: %v", err)
 	}
 
-	if expired != 2 {
-		t.Errorf("Expected 2 expired listings, got %d", expired)
+	if len(expired) != 2 {
+		t.Errorf("Expected 2 expired listings, got %d", len(expired))
 	}
 
 	l, _ := repo.FindByID(ctx, "act-1")
@@ -64,6 +64,57 @@ func TestExpireListings(t *testing.T) {
 	}
 }
 
+func TestListExpiringListings(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	window := now.Add(72 * time.Hour)
+
+	_ = repo.Save(ctx, domain.Listing{ID: "req-soon", OwnerID: "u1", Type: domain.Request, Deadline: now.Add(24 * time.Hour), IsActive: true})
+	_ = repo.Save(ctx, domain.Listing{ID: "evt-soon", OwnerID: "u1", Type: domain.Event, EventEnd: now.Add(48 * time.Hour), IsActive: true})
+	_ = repo.Save(ctx, domain.Listing{ID: "job-soon", OwnerID: "u1", Type: domain.Job, JobStartDate: now.Add(-domain.JobListingLifetime + time.Hour), IsActive: true})
+	// Not due: later than the window, already expired, unowned, inactive, or never expiring.
+	_ = repo.Save(ctx, domain.Listing{ID: "req-later", OwnerID: "u1", Type: domain.Request, Deadline: now.Add(30 * 24 * time.Hour), IsActive: true})
+	_ = repo.Save(ctx, domain.Listing{ID: "req-past", OwnerID: "u1", Type: domain.Request, Deadline: now.Add(-time.Hour), IsActive: true})
+	_ = repo.Save(ctx, domain.Listing{ID: "req-unowned", Type: domain.Request, Deadline: now.Add(24 * time.Hour), IsActive: true})
+	_ = repo.Save(ctx, domain.Listing{ID: "req-inactive", OwnerID: "u1", Type: domain.Request, Deadline: now.Add(24 * time.Hour), IsActive: false})
+	_ = repo.Save(ctx, domain.Listing{ID: "biz", OwnerID: "u1", Type: domain.Business, IsActive: true})
+
+	due, err := repo.ListExpiringListings(ctx, now, window)
+	if err != nil {
+		t.Fatalf("ListExpiringListings failed: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, l := range due {
+		ids[l.ID] = true
+	}
+	if len(due) != 3 || !ids["req-soon"] || !ids["evt-soon"] || !ids["job-soon"] {
+		t.Fatalf("expected req-soon, evt-soon and job-soon, got %v", ids)
+	}
+
+	if err := repo.MarkExpiryReminderSent(ctx, "req-soon", now); err != nil {
+		t.Fatalf("MarkExpiryReminderSent failed: %v", err)
+	}
+	// Marking twice for the same expiry is harmless.
+	if err := repo.MarkExpiryReminderSent(ctx, "req-soon", now); err != nil {
+		t.Fatalf("MarkExpiryReminderSent failed: %v", err)
+	}
+	due, _ = repo.ListExpiringListings(ctx, now, window)
+	if len(due) != 2 {
+		t.Fatalf("expected reminded listing to be skipped, got %d listings", len(due))
+	}
+
+	// A new deadline needs a new reminder.
+	l, _ := repo.FindByID(ctx, "req-soon")
+	l.Deadline = now.Add(60 * time.Hour)
+	_ = repo.Save(ctx, l)
+	due, _ = repo.ListExpiringListings(ctx, now, window)
+	if len(due) != 3 {
+		t.Fatalf("expected extended listing to be due again, got %d listings", len(due))
+	}
+}
+
 func TestGetPendingClaimRequests(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
@@ -97,7 +148,7 @@ func TestGetPendingClaimRequests(t *testing.T) {
 	}
 }
 
-func TestUpdateClaimRequestStatus_Approve(t *testing.T) {
+func TestReviewClaimRequest_Approve(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -113,9 +164,9 @@ func TestUpdateClaimRequestStatus_Approve(t *testing.T) {
 	})
 
 	// Approve it
-	err := repo.UpdateClaimRequestStatus(ctx, "cr1", domain.ClaimStatusApproved)
+	err := repo.ReviewClaimRequest(ctx, "cr1", domain.ClaimReview{Status: domain.ClaimStatusApproved, ReviewerID: "admin", ReviewedAt: time.Now()})
 	if err != nil {
-		t.Fatalf("UpdateClaimRequestStatus failed: %v", err)
+		t.Fatalf("ReviewClaimRequest failed: %v", err)
 	}
 
 	// Verify status updated
@@ -131,6 +182,54 @@ func TestUpdateClaimRequestStatus_Approve(t *testing.T) {
 	}
 }
 
+func TestReviewClaimRequest_Reject(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+
+	_ = repo.Save(ctx, domain.Listing{ID: "l1", Title: "Biz", Type: domain.Business, OwnerID: "admin", IsActive: true, CreatedAt: time.Now()})
+	verified := time.Now().Add(-time.Hour)
+	_ = repo.SaveClaimRequest(ctx, domain.ClaimRequest{
+		ID: "cr1", ListingID: "l1", UserID: "u1", EvidenceEmail: "owner@biz.example",
+		DocumentURL: "/static/uploads/doc.webp", PhoneVerifiedAt: verified,
+		Status: domain.ClaimStatusPending, CreatedAt: time.Now().Add(-2 * time.Hour),
+	})
+
+	if err := repo.ReviewClaimRequest(ctx, "missing", domain.ClaimReview{Status: domain.ClaimStatusRejected}); err != sqlite.ErrClaimRequestNotFound {
+		t.Errorf("Expected ErrClaimRequestNotFound, got %v", err)
+	}
+
+	reviewed := time.Now()
+	err := repo.ReviewClaimRequest(ctx, "cr1", domain.ClaimReview{
+		Status: domain.ClaimStatusRejected, ReviewerID: "admin", Notes: "No reply on the phone",
+		RejectionReason: "We could not confirm you run this business", ReviewedAt: reviewed,
+	})
+	if err != nil {
+		t.Fatalf("ReviewClaimRequest failed: %v", err)
+	}
+
+	claims, err := repo.ListClaimRequestsByUser(ctx, "u1")
+	if err != nil || len(claims) != 1 {
+		t.Fatalf("Expected 1 claim for u1, got %d (%v)", len(claims), err)
+	}
+	cr := claims[0]
+	if cr.Status != domain.ClaimStatusRejected || cr.ReviewerID != "admin" || cr.ReviewerNotes != "No reply on the phone" ||
+		cr.RejectionReason != "We could not confirm you run this business" {
+		t.Errorf("Review not stored: %+v", cr)
+	}
+	if !cr.ReviewedAt.Equal(reviewed) || !cr.PhoneVerifiedAt.Equal(verified) {
+		t.Errorf("Expected timestamps to round-trip, got reviewed %v verified %v", cr.ReviewedAt, cr.PhoneVerifiedAt)
+	}
+	if cr.EvidenceEmail != "owner@biz.example" || cr.DocumentURL != "/static/uploads/doc.webp" {
+		t.Errorf("Evidence not stored: %+v", cr)
+	}
+
+	l, _ := repo.FindByID(ctx, "l1")
+	if l.OwnerID != "admin" {
+		t.Errorf("Expected rejection to keep owner admin, got %s", l.OwnerID)
+	}
+}
+
 func TestGetClaimRequestByUserAndListing(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
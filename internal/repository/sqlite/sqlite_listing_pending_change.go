@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const pendingChangeColumns = `id, listing_id, listing_title, actor_id, actor_name, is_new, proposed, changes, status, reviewer_id, created_at, reviewed_at`
+
+func scanPendingChange(s Scanner) (domain.PendingChange, error) {
+	var c domain.PendingChange
+	var proposed, changes string
+	var reviewed sql.NullTime
+	err := s.Scan(&c.ID, &c.ListingID, &c.ListingTitle, &c.ActorID, &c.ActorName, &c.IsNew,
+		&proposed, &changes, &c.Status, &c.ReviewerID, &c.CreatedAt, &reviewed)
+	if err != nil {
+		return domain.PendingChange{}, err
+	}
+	if err := json.Unmarshal([]byte(proposed), &c.Proposed); err != nil {
+		return domain.PendingChange{}, err
+	}
+	if err := json.Unmarshal([]byte(changes), &c.Changes); err != nil {
+		return domain.PendingChange{}, err
+	}
+	if reviewed.Valid {
+		c.ReviewedAt = reviewed.Time
+	}
+	return c, nil
+}
+
+// SavePendingChange stores a change, superseding any change still pending for the same listing.
+func (r *SQLiteRepository) SavePendingChange(ctx context.Context, c domain.PendingChange) error {
+	proposed, err := json.Marshal(c.Proposed)
+	if err != nil {
+		return err
+	}
+	changes := c.Changes
+	if changes == nil {
+		changes = []domain.FieldChange{}
+	}
+	rawChanges, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.writeDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE listing_pending_changes SET status = ?, reviewed_at = ? WHERE listing_id = ? AND status = ?`,
+		domain.PendingChangeStatusSuperseded, c.CreatedAt, c.ListingID, domain.PendingChangeStatusPending)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO listing_pending_changes (`+pendingChangeColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL)`,
+		c.ID, c.ListingID, c.ListingTitle, c.ActorID, c.ActorName, c.IsNew,
+		string(proposed), string(rawChanges), domain.PendingChangeStatusPending, "", c.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPendingChange returns a change in any status.
+func (r *SQLiteRepository) GetPendingChange(ctx context.Context, id string) (domain.PendingChange, error) {
+	row := r.readDB.QueryRowContext(ctx,
+		`SELECT `+pendingChangeColumns+` FROM listing_pending_changes WHERE id = ?`, id)
+	c, err := scanPendingChange(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PendingChange{}, domain.ErrPendingChangeNotFound
+	}
+	return c, err
+}
+
+// ListPendingChanges returns the changes awaiting review, oldest first. Changes to
+// listings deleted since their submission have nothing left to review and are skipped.
+func (r *SQLiteRepository) ListPendingChanges(ctx context.Context) ([]domain.PendingChange, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+pendingChangeColumns+` FROM listing_pending_changes
+		WHERE status = ? AND listing_id IN (SELECT id FROM listings)
+		ORDER BY created_at ASC, rowid ASC`,
+		domain.PendingChangeStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanPendingChange)
+}
+
+// ResolvePendingChange records a review decision on a change that is still pending.
+func (r *SQLiteRepository) ResolvePendingChange(ctx context.Context, id string, status domain.PendingChangeStatus, reviewerID string, at time.Time) error {
+	res, err := r.writeDB.ExecContext(ctx,
+		`UPDATE listing_pending_changes SET status = ?, reviewer_id = ?, reviewed_at = ? WHERE id = ? AND status = ?`,
+		status, reviewerID, at, id, domain.PendingChangeStatusPending)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrPendingChangeNotFound
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingChanges_SaveSupersedesAndResolve(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, id := range []string{"l1", "l2"} {
+		saveTestListing(t, ctx, repo, domain.Listing{ID: id, Title: "Live " + id, Type: domain.Service, OwnerOrigin: "Nigeria", CreatedAt: now})
+	}
+
+	change := func(id, listingID, title string, at time.Time) domain.PendingChange {
+		return domain.PendingChange{
+			ID: id, ListingID: listingID, ListingTitle: "Live", ActorID: "u1", ActorName: "Ada", CreatedAt: at,
+			Proposed: domain.Listing{ID: listingID, Title: title},
+			Changes:  []domain.FieldChange{{Field: "title", Before: "Live", After: title}},
+		}
+	}
+	require.NoError(t, repo.SavePendingChange(ctx, change("c1", "l1", "First", now)))
+	require.NoError(t, repo.SavePendingChange(ctx, change("c2", "l2", "Other", now.Add(time.Second))))
+	require.NoError(t, repo.SavePendingChange(ctx, change("c3", "l1", "Second", now.Add(2*time.Second))))
+
+	pending, err := repo.ListPendingChanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "c2", pending[0].ID)
+	assert.Equal(t, "c3", pending[1].ID)
+	assert.Equal(t, "Second", pending[1].Proposed.Title)
+	assert.Equal(t, "title", pending[1].Changes[0].Field)
+
+	first, err := repo.GetPendingChange(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PendingChangeStatusSuperseded, first.Status)
+
+	require.NoError(t, repo.ResolvePendingChange(ctx, "c3", domain.PendingChangeStatusApproved, "admin1", now))
+	resolved, err := repo.GetPendingChange(ctx, "c3")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PendingChangeStatusApproved, resolved.Status)
+	assert.Equal(t, "admin1", resolved.ReviewerID)
+	assert.True(t, resolved.ReviewedAt.Equal(now))
+
+	err = repo.ResolvePendingChange(ctx, "c3", domain.PendingChangeStatusRejected, "admin1", now)
+	assert.ErrorIs(t, err, domain.ErrPendingChangeNotFound, "a change is resolved at most once")
+
+	require.NoError(t, repo.Delete(ctx, "l2"))
+	pending, err = repo.ListPendingChanges(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending, "changes to deleted listings leave the queue")
+
+	_, err = repo.GetPendingChange(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrPendingChangeNotFound)
+}
+
+func TestCategory_RequiresModerationRoundTrip(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveCategory(ctx, domain.CategoryData{ID: "crafts", Name: "Crafts", Active: true, RequiresModeration: true}))
+	cat, err := repo.GetCategory(ctx, "crafts")
+	require.NoError(t, err)
+	assert.True(t, cat.RequiresModeration)
+}
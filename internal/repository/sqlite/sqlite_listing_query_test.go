@@ -0,0 +1,334 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedQueryListings(t *testing.T) domain.ListingRepository {
+	t.Helper()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	base := func(id, title string) domain.Listing {
+		return domain.Listing{ID: id, Title: title, Type: domain.Service, OwnerOrigin: "Nigeria", City: "Houston", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: now.Add(-48 * time.Hour)}
+	}
+
+	food := base("food", "Suya Spot")
+	food.Type, food.City, food.OwnerOrigin = domain.Food, "Dallas", "Ghana"
+	food.Rating, food.HeatLevel, food.ImageURL = 4.6, 4, "/static/uploads/suya.webp"
+	food.StructuredHours = `{"sun":["00:00-24:00"],"mon":["00:00-24:00"],"tue":["00:00-24:00"],"wed":["00:00-24:00"],"thu":["00:00-24:00"],"fri":["00:00-24:00"],"sat":["00:00-24:00"]}`
+	saveTestListing(t, ctx, repo, food)
+
+	svc := base("svc", "Ada Tailoring")
+	svc.Featured, svc.Rating = true, 3.9
+	svc.StructuredHours = `{"sun":[],"mon":[],"tue":[],"wed":[],"thu":[],"fri":[],"sat":[]}`
+	saveTestListing(t, ctx, repo, svc)
+
+	fresh := base("fresh", "New Braids")
+	fresh.City, fresh.CreatedAt = "Atlanta", now
+	saveTestListing(t, ctx, repo, fresh)
+
+	pending := base("pending", "Pending Shop")
+	pending.Status = domain.ListingStatusPending
+	saveTestListing(t, ctx, repo, pending)
+
+	return repo
+}
+
+func searchIDs(t *testing.T, repo domain.ListingRepository, q domain.ListingQuery) []string {
+	t.Helper()
+	page, err := repo.Search(context.Background(), q)
+	require.NoError(t, err)
+	return idsOf(page.Listings)
+}
+
+func TestSearch_QueryFilters(t *testing.T) {
+	t.Parallel()
+	repo := seedQueryListings(t)
+
+	tests := []struct {
+		name  string
+		query domain.ListingQuery
+		want  []string
+	}{
+		{"default hides unapproved", domain.ListingQuery{}, []string{"food", "svc", "fresh"}},
+		{"type set", domain.ListingQuery{Types: []domain.Category{domain.Food, domain.Job}}, []string{"food"}},
+		{"multiple cities", domain.ListingQuery{Cities: []string{"Dallas", "Atlanta"}}, []string{"food", "fresh"}},
+		{"owner origin", domain.ListingQuery{OwnerOrigin: "Ghana"}, []string{"food"}},
+		{"status with inactive", domain.ListingQuery{IncludeInactive: true, Statuses: []domain.ListingStatus{domain.ListingStatusPending}}, []string{"pending"}},
+		{"featured", domain.ListingQuery{FeaturedOnly: true}, []string{"svc"}},
+		{"min rating", domain.ListingQuery{MinRating: 4}, []string{"food"}},
+		{"heat level", domain.ListingQuery{MinHeatLevel: 3}, []string{"food"}},
+		{"created after", domain.ListingQuery{CreatedAfter: time.Now().Add(-time.Hour)}, []string{"fresh"}},
+		{"has image", domain.ListingQuery{HasImage: true}, []string{"food"}},
+		{"open now", domain.ListingQuery{OpenNow: true}, []string{"food"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, searchIDs(t, repo, tt.query))
+		})
+	}
+}
+
+func TestSearch_OpenNowHonorsHoursExceptions(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	today := time.Now().Format(time.DateOnly)
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	allDay := func(id, exceptions string) domain.Listing {
+		l := domain.Listing{ID: id, Title: id, Type: domain.Food, OwnerOrigin: "Nigeria", City: "Houston", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now()}
+		l.StructuredHours = `{"sun":["00:00-24:00"],"mon":["00:00-24:00"],"tue":["00:00-24:00"],"wed":["00:00-24:00"],"thu":["00:00-24:00"],"fri":["00:00-24:00"],"sat":["00:00-24:00"]}`
+		l.HoursExceptions = exceptions
+		return l
+	}
+	saveTestListing(t, ctx, repo, allDay("regular", ""))
+	saveTestListing(t, ctx, repo, allDay("past-holiday", `[{"start":"`+yesterday+`","kind":"closed"}]`))
+	saveTestListing(t, ctx, repo, allDay("holiday", `[{"start":"`+yesterday+`","end":"`+today+`","kind":"closed"}]`))
+	saveTestListing(t, ctx, repo, allDay("gone", `[{"start":"`+yesterday+`","kind":"permanently_closed"}]`))
+	saveTestListing(t, ctx, repo, allDay("reopened-today", `[{"start":"`+yesterday+`","end":"`+today+`","kind":"closed"},{"start":"`+today+`","kind":"custom","hours":["00:00-24:00"]}]`))
+	flagged := allDay("flagged", "")
+	flagged.PermanentlyClosed = true
+	saveTestListing(t, ctx, repo, flagged)
+
+	assert.ElementsMatch(t, []string{"regular", "past-holiday", "reopened-today"}, searchIDs(t, repo, domain.ListingQuery{OpenNow: true}))
+	assert.NotContains(t, searchIDs(t, repo, domain.ListingQuery{}), "flagged")
+	assert.Contains(t, searchIDs(t, repo, domain.ListingQuery{IncludeInactive: true}), "flagged")
+}
+
+func TestSearch_OpenAtListingTimezones(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+
+	listing := func(id, tz, hours string) domain.Listing {
+		return domain.Listing{ID: id, Title: id, Type: domain.Food, OwnerOrigin: "Nigeria", City: "Houston", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now(), Timezone: tz, StructuredHours: hours}
+	}
+	saveTestListing(t, ctx, repo, listing("chicago", "America/Chicago", `{"fri":["09:00-18:00"]}`))
+	saveTestListing(t, ctx, repo, listing("newyork", "America/New_York", `{"fri":["17:00-20:00"]}`))
+	saveTestListing(t, ctx, repo, listing("la", "America/Los_Angeles", `{"fri":["09:00-17:00"]}`))
+	saveTestListing(t, ctx, repo, listing("late", "America/Chicago", `{"thu":["18:00-03:00"],"fri":[]}`))
+	saveTestListing(t, ctx, repo, listing("dated", "America/Chicago", `{"fri":["09:00-18:00"],"2026-05-01":[]}`))
+
+	tests := []struct {
+		name  string
+		query domain.ListingQuery
+		want  []string
+	}{
+		// 23:30 UTC on Friday 2026-05-01 is 18:30 in Chicago, 19:30 in New York and 16:30 in Los Angeles.
+		{"instant on each listing's clock", domain.ListingQuery{OpenAt: time.Date(2026, 5, 1, 23, 30, 0, 0, time.UTC)}, []string{"newyork", "la"}},
+		// 07:00 UTC is 02:00 in Chicago, still inside Thursday's overnight range.
+		{"overnight range from the day before", domain.ListingQuery{OpenAt: time.Date(2026, 5, 1, 7, 0, 0, 0, time.UTC)}, []string{"late"}},
+		{"wall clock", domain.ListingQuery{OpenAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), OpenAtWallClock: true}, []string{"chicago", "la"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.want, searchIDs(t, repo, tt.query))
+		})
+	}
+}
+
+func TestSearch_Cursor(t *testing.T) {
+	t.Parallel()
+	repo := seedQueryListings(t)
+	ctx := context.Background()
+
+	var seen []string
+	q := domain.ListingQuery{Limit: 2}
+	for i := 0; i < 3; i++ {
+		page, err := repo.Search(ctx, q)
+		require.NoError(t, err)
+		for _, l := range page.Listings {
+			seen = append(seen, l.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor, q.SkipCount = page.NextCursor, true
+	}
+	assert.Equal(t, []string{"svc", "food", "fresh"}, seen)
+}
+
+func TestSearch_CursorIsStableAcrossInserts(t *testing.T) {
+	t.Parallel()
+	repo := seedQueryListings(t)
+	ctx := context.Background()
+	q := domain.ListingQuery{Sort: "title", Order: "asc", Limit: 2}
+
+	first, err := repo.Search(ctx, q)
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, []string{"svc", "fresh"}, idsOf(first.Listings))
+
+	// A listing sorting before the cursor must not shift the next page.
+	early := domain.Listing{ID: "early", Title: "Aaa Early", Type: domain.Service, OwnerOrigin: "Nigeria", City: "Houston", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now()}
+	saveTestListing(t, ctx, repo, early)
+
+	q.Cursor, q.SkipCount = first.NextCursor, true
+	second, err := repo.Search(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"food"}, idsOf(second.Listings))
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, -1, second.TotalCount)
+}
+
+func TestSearch_InvalidCursor(t *testing.T) {
+	t.Parallel()
+	repo := seedQueryListings(t)
+	ctx := context.Background()
+
+	page, err := repo.Search(ctx, domain.ListingQuery{Limit: 1})
+	require.NoError(t, err)
+
+	_, err = repo.Search(ctx, domain.ListingQuery{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	_, err = repo.Search(ctx, domain.ListingQuery{Sort: "title", Cursor: page.NextCursor})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor, "cursor minted for a different sort")
+}
+
+func idsOf(listings []domain.Listing) []string {
+	ids := make([]string, 0, len(listings))
+	for _, l := range listings {
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
+
+func TestSearch_SortByDistance(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	place := func(id string, lat, lng float64) {
+		saveTestListing(t, ctx, repo, domain.Listing{
+			ID: id, Title: id, Type: domain.Food, OwnerOrigin: "Nigeria", City: "Houston",
+			Latitude: lat, Longitude: lng, IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now(),
+		})
+	}
+	place("houston", 29.7604, -95.3698)
+	place("katy", 29.7858, -95.8245)
+	place("sugarland", 29.6197, -95.6349)
+	place("dallas", 32.7767, -96.7970)
+
+	katy := domain.ListingQuery{Latitude: 29.79, Longitude: -95.82, RadiusMiles: 50, Sort: domain.SortDistance, Limit: 2}
+	first, err := repo.Search(ctx, katy)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"katy", "sugarland"}, idsOf(first.Listings))
+	assert.Less(t, first.Listings[0].DistanceMiles, 1.0)
+	assert.InDelta(t, domain.DistanceMiles(29.79, -95.82, 29.6197, -95.6349), first.Listings[1].DistanceMiles, 0.01)
+
+	next := katy
+	next.Cursor, next.SkipCount = first.NextCursor, true
+	assert.Equal(t, []string{"houston"}, searchIDs(t, repo, next), "dallas is outside the radius")
+
+	moved := next
+	moved.Latitude = 29.76
+	_, err = repo.Search(ctx, moved)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor, "cursor minted for a different point")
+
+	q := domain.ListingQuery{Sort: domain.SortDistance}
+	assert.Len(t, searchIDs(t, repo, q), 4, "without a point distance falls back to the default order")
+
+	texted := katy
+	texted.Limit, texted.QueryText = 0, "katy"
+	assert.Equal(t, []string{"katy"}, searchIDs(t, repo, texted), "search text still filters a distance sort")
+}
+
+func TestSearch_QueryTextSanitized(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	saveTestListing(t, ctx, repo, domain.Listing{ID: "mama", Title: "Mama's Jollof Kitchen", City: "Houston", IsActive: true})
+	saveTestListing(t, ctx, repo, domain.Listing{ID: "soup", Title: "Soup Spot", TopDish: "Agushi soup", City: "Houston", IsActive: true})
+
+	for query, want := range map[string][]string{
+		`mama's "jollof`:  {"mama"},
+		`jollof -kitchen`: {"mama"},
+		`jollof OR NEAR(`: nil,
+		`jolof`:           {"mama"},
+		`egusi`:           {"soup"},
+		`-`:               nil,
+		`a b`:             nil,
+		`kitchen* ^mama:`: {"mama"},
+	} {
+		page, err := repo.Search(ctx, domain.ListingQuery{QueryText: query})
+		require.NoError(t, err, query)
+		assert.ElementsMatch(t, want, idsOf(page.Listings), query)
+
+		// Relevance binds the same query into the full-text join.
+		page, err = repo.Search(ctx, domain.ListingQuery{QueryText: query, Sort: domain.SortRelevance})
+		require.NoError(t, err, query)
+		assert.ElementsMatch(t, want, idsOf(page.Listings), query)
+	}
+}
+
+func TestSearch_SortByRelevance(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	place := func(id string, l domain.Listing) {
+		l.ID, l.Type, l.OwnerOrigin, l.IsActive, l.Status, l.CreatedAt = id, domain.Food, "Nigeria", true, domain.ListingStatusApproved, time.Now()
+		if l.Title == "" {
+			l.Title = "Kitchen " + id
+		}
+		saveTestListing(t, ctx, repo, l)
+	}
+	place("address", domain.Listing{Address: "1 Suya Lane, Houston"})
+	place("title", domain.Listing{Title: "Suya Palace"})
+	place("description", domain.Listing{Description: "We grill suya every evening."})
+	place("dish", domain.Listing{TopDish: "Beef suya"})
+	place("other", domain.Listing{Description: "Jollof and plantain."})
+
+	q := domain.ListingQuery{QueryText: "suya", Sort: domain.SortRelevance, Limit: 2}
+	first, err := repo.Search(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"title", "dish"}, idsOf(first.Listings))
+	require.NotEmpty(t, first.NextCursor)
+
+	next := q
+	next.Cursor, next.SkipCount = first.NextCursor, true
+	assert.Equal(t, []string{"description", "address"}, searchIDs(t, repo, next))
+
+	other := next
+	other.QueryText = "grill"
+	_, err = repo.Search(ctx, other)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor, "cursor minted for a different search")
+
+	assert.Len(t, searchIDs(t, repo, domain.ListingQuery{Sort: domain.SortRelevance}), 5, "without a search relevance falls back to the default order")
+}
+
+func TestSearch_HidesShadowedOwners(t *testing.T) {
+	t.Parallel()
+	repo := seedQueryListings(t)
+	ctx := context.Background()
+	require.NoError(t, repo.SaveUser(ctx, domain.User{ID: "spammer", GoogleID: "g-spammer"}))
+	spam := domain.Listing{ID: "spam", Title: "Cheap Pills", Type: domain.Service, OwnerID: "spammer", OwnerOrigin: "Nigeria", City: "Houston", IsActive: true, Status: domain.ListingStatusApproved, CreatedAt: time.Now()}
+	saveTestListing(t, ctx, repo, spam)
+
+	services := domain.ListingQuery{Types: []domain.Category{domain.Service}}
+	assert.Contains(t, searchIDs(t, repo, services), "spam")
+	require.NoError(t, repo.SetUserStatus(ctx, "spammer", domain.UserStatusShadowed, time.Time{}, "spam"))
+
+	assert.NotContains(t, searchIDs(t, repo, services), "spam")
+	owner := services
+	owner.ViewerID = "spammer"
+	assert.Contains(t, searchIDs(t, repo, owner), "spam", "the owner still finds their own listing")
+	staff := services
+	staff.IncludeInactive = true
+	assert.Contains(t, searchIDs(t, repo, staff), "spam")
+
+	counts, err := repo.GetCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, counts[domain.Service], "svc and fresh only")
+}
@@ -4,26 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 )
 
-type ListingFilters struct {
-	Type            string
-	QueryText       string
-	OwnerID         string
-	City            string
-	WebsiteURL      string
-	ListingStatus   domain.ListingStatus
-	IncludedLat     float64
-	IncludedLng     float64
-	Radius          float64
-	IncludeInactive bool
-	FeaturedOnly    bool
-}
-
 func scanListing(s Scanner) (domain.Listing, error) {
 	var l domain.Listing
 	var deadline, eventStart, eventEnd, jobStart sql.NullTime
@@ -44,6 +31,8 @@ func scanListing(s Scanner) (domain.Listing, error) {
 		&l.Rating, &l.ReviewCount,
 		&ratingUpdatedAtStr,
 		&l.StructuredHours,
+		&l.Timezone,
+		&l.HoursExceptions, &l.PermanentlyClosed,
 	)
 
 	if err != nil {
@@ -94,43 +83,91 @@ func parseNullableTime(s string) *time.Time {
 	return nil
 }
 
-func (r *SQLiteRepository) FindAll(ctx context.Context, filterType string, queryText string, city string, lat float64, lng float64, radius float64, sortField string, sortOrder string, includeInactive bool, limit int, offset int) ([]domain.Listing, int, error) {
+// Search returns the page of listings matching q. Pages are fetched by keyset when
+// q.Cursor is set, so deep pages cost the same as the first and rows inserted while
+// a client pages through results are neither repeated nor skipped.
+func (r *SQLiteRepository) Search(ctx context.Context, q domain.ListingQuery) (domain.ListingPage, error) {
 	start := time.Now()
-	defer r.logSlowQuery("FindAll", start)
+	defer r.logSlowQuery("Search", start)
+
+	order := r.buildOrderClause(q)
+	var zones []string
+	if q.OpenNow || !q.OpenAt.IsZero() {
+		var err error
+		if zones, err = r.listingTimezones(ctx); err != nil {
+			return domain.ListingPage{}, err
+		}
+	}
+	filter := q
+	if order.from() == ftsRankFrom {
+		// Sorting by relevance joins the full-text matches, which filters by QueryText.
+		filter.QueryText = ""
+	}
+	where, args := r.buildListingWhere(filter, start, zones)
 
-	filters := ListingFilters{
-		Type:            filterType,
-		QueryText:       queryText,
-		City:            city,
-		IncludedLat:     lat,
-		IncludedLng:     lng,
-		Radius:          radius,
-		IncludeInactive: includeInactive,
+	page := domain.ListingPage{TotalCount: -1}
+	if !q.SkipCount {
+		countArgs := append(append([]interface{}{}, order.fromArgs()...), args...)
+		totalCount, err := r.getCount(ctx, order.from(), where, countArgs)
+		if err != nil {
+			return domain.ListingPage{}, err
+		}
+		page.TotalCount = totalCount
 	}
-	where, args := r.buildListingWhere(filters)
 
-	totalCount, err := r.getCount(ctx, "listings", where, args)
-	if err != nil {
-		return nil, 0, err
+	offset := q.Offset
+	if q.Cursor != "" {
+		keys, err := order.decodeCursor(q.Cursor)
+		if err != nil {
+			return domain.ListingPage{}, err
+		}
+		keyset, keysetArgs := order.after(keys)
+		where += ` AND ` + keyset
+		args = append(append([]interface{}{}, args...), keysetArgs...)
+		offset = 0
 	}
 
-	order := r.buildOrderClause(sortField, sortOrder)
+	// Fetch one extra row to learn whether another page exists without counting.
+	limit := -1 // SQLite: no limit
+	if q.Limit > 0 {
+		limit = q.Limit + 1
+	}
 
 	listings, err := r.queryListingsPaginated(ctx, where, order, args, limit, offset)
 	if err != nil {
-		return nil, 0, err
+		return domain.ListingPage{}, err
 	}
-	return listings, totalCount, nil
+
+	if q.Limit > 0 && len(listings) > q.Limit {
+		listings = listings[:q.Limit]
+		if page.NextCursor, err = r.cursorAfter(ctx, order, listings[q.Limit-1].ID); err != nil {
+			return domain.ListingPage{}, err
+		}
+	}
+	if q.Latitude != 0 || q.Longitude != 0 {
+		for i, l := range listings {
+			if l.Latitude != 0 || l.Longitude != 0 {
+				listings[i].DistanceMiles = domain.DistanceMiles(q.Latitude, q.Longitude, l.Latitude, l.Longitude)
+			}
+		}
+	}
+	page.Listings = listings
+	return page, nil
 }
 
-func (r *SQLiteRepository) queryListingsPaginated(ctx context.Context, where, order string, baseArgs []interface{}, limit, offset int) ([]domain.Listing, error) {
-	args := make([]interface{}, 0, len(baseArgs)+2)
+func (r *SQLiteRepository) queryListingsPaginated(ctx context.Context, where string, o listingOrder, baseArgs []interface{}, limit, offset int) ([]domain.Listing, error) {
+	// from appears twice, so its arguments are bound for both.
+	fromArgs := o.fromArgs()
+	args := make([]interface{}, 0, 2*len(fromArgs)+len(baseArgs)+2)
+	args = append(args, fromArgs...)
+	args = append(args, fromArgs...)
 	args = append(args, baseArgs...)
 	args = append(args, limit, offset)
 
+	order, from := o.sql(), o.from()
 	// #nosec G202 - Dynamic query construction with trusted internal fragments
-	query := `SELECT ` + r.buildListingColumns() + ` FROM listings 
-	          WHERE rowid IN (SELECT rowid FROM listings ` + where + ` ORDER BY ` + order + ` LIMIT ? OFFSET ?)
+	query := `SELECT ` + r.buildListingColumns() + ` FROM ` + from + ` 
+	          WHERE rowid IN (SELECT rowid FROM ` + from + where + ` ORDER BY ` + order + ` LIMIT ? OFFSET ?)
 	          ORDER BY ` + order
 
 	rows, err := r.readDB.QueryContext(ctx, query, args...)
@@ -146,83 +183,288 @@ func (r *SQLiteRepository) buildListingColumns() string {
 	return ListingSelectionsSQL
 }
 
-func (r *SQLiteRepository) buildListingWhere(filters ListingFilters) (string, []interface{}) {
+// buildListingWhere translates q into a WHERE clause. now is the instant OpenNow is
+// evaluated at and zones are the time zones listings are in, for OpenNow and OpenAt.
+func (r *SQLiteRepository) buildListingWhere(q domain.ListingQuery, now time.Time, zones []string) (string, []interface{}) {
 	where := " WHERE 1=1"
 	var args []interface{}
 
-	if !filters.IncludeInactive {
+	if !q.IncludeInactive {
 		where += ` AND ` + ListingActiveApprovedSQL
+		// A shadowed owner still finds their own listings, so nothing looks amiss to them.
+		where += ` AND (owner_id = ? OR ` + ListingOwnerNotShadowedSQL + `)`
+		args = append(args, q.ViewerID)
 	}
 
-	if filters.Type != "" {
-		where += ListingFilterTypeSQL
-		args = append(args, filters.Type)
+	if len(q.Types) > 0 {
+		where += ` AND type IN (` + placeholders(len(q.Types)) + `)`
+		for _, t := range q.Types {
+			args = append(args, string(t))
+		}
 	}
 
-	if filters.OwnerID != "" {
+	if len(q.Statuses) > 0 {
+		where += ` AND status IN (` + placeholders(len(q.Statuses)) + `)`
+		for _, s := range q.Statuses {
+			args = append(args, string(s))
+		}
+	}
+
+	if q.OwnerID != "" {
 		where += ` AND owner_id = ?`
-		args = append(args, filters.OwnerID)
+		args = append(args, q.OwnerID)
 	}
 
-	if filters.Radius > 0 && filters.IncludedLat != 0 && filters.IncludedLng != 0 {
-		// Bounding Box Optimization (Roughly 1 degree = 69 miles)
-		latDelta := filters.Radius / 69.0
-		lngDelta := filters.Radius / (69.0 * 0.707) // Approximation for mid-latitudes
+	if q.OwnerOrigin != "" {
+		where += ` AND owner_origin = ?`
+		args = append(args, q.OwnerOrigin)
+	}
 
-		where += ` AND latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?`
-		args = append(args, filters.IncludedLat-latDelta, filters.IncludedLat+latDelta, filters.IncludedLng-lngDelta, filters.IncludedLng+lngDelta)
+	if q.RadiusMiles > 0 && q.Latitude != 0 && q.Longitude != 0 {
+		// The bounding box narrows the search through the spatial index before the
+		// exact distance is computed for what is left.
+		box, boxArgs := r.boundsSQL(domain.BoundingBoxAround(q.Latitude, q.Longitude, q.RadiusMiles))
+		where += ` AND ` + box
+		args = append(args, boxArgs...)
 
 		// Haversine formula for exact radius filtering
-		// 3959 is the Earth's radius in miles
-		where += ` AND (3959 * acos(cos(radians(?)) * cos(radians(latitude)) * cos(radians(longitude) - radians(?)) + sin(radians(?)) * sin(radians(latitude)))) <= ?`
-		args = append(args, filters.IncludedLat, filters.IncludedLng, filters.IncludedLat, filters.Radius)
-	} else if filters.City != "" {
-		where += ` AND (city = ? OR address LIKE ?)`
-		args = append(args, filters.City, "%"+filters.City+"%")
+		where += ` AND ` + distanceSQL("?", "?") + ` <= ?`
+		args = append(args, distanceArgs(q.Latitude, q.Longitude)...)
+		args = append(args, q.RadiusMiles)
+	} else if len(q.Cities) > 0 {
+		clauses := make([]string, 0, len(q.Cities))
+		for _, city := range q.Cities {
+			clauses = append(clauses, `city = ? OR address LIKE ?`)
+			args = append(args, city, "%"+city+"%")
+		}
+		where += ` AND (` + strings.Join(clauses, " OR ") + `)`
 	}
 
-	if filters.FeaturedOnly {
+	if !q.Bounds.IsZero() {
+		box, boxArgs := r.boundsSQL(q.Bounds)
+		where += ` AND ` + box
+		args = append(args, boxArgs...)
+	}
+
+	if q.FeaturedOnly {
 		where += ` AND featured = 1`
 	}
 
-	if filters.WebsiteURL != "" {
+	if q.HasWebsite {
 		where += ` AND website_url != ''`
 	}
 
-	if filters.QueryText != "" {
-		where += ` AND rowid IN (SELECT rowid FROM listings_fts WHERE listings_fts MATCH ?)`
-		args = append(args, filters.QueryText)
+	if q.HasImage {
+		where += ` AND image_url != ''`
+	}
+
+	if q.MinRating > 0 {
+		where += ` AND rating >= ?`
+		args = append(args, q.MinRating)
+	}
+
+	if q.MinHeatLevel > 0 {
+		where += ` AND heat_level >= ?`
+		args = append(args, q.MinHeatLevel)
+	}
+
+	if !q.CreatedAfter.IsZero() {
+		where += ` AND created_at > ?`
+		args = append(args, q.CreatedAfter.UTC())
+	}
+
+	if q.OpenNow || !q.OpenAt.IsZero() {
+		at := q.OpenAt
+		if at.IsZero() {
+			at = now
+		}
+		clause, openArgs := openAtClause(at, q.OpenAtWallClock && !q.OpenAt.IsZero(), zones)
+		where += ` AND permanently_closed = 0 AND ` + clause
+		args = append(args, openArgs...)
+	}
+
+	if q.QueryText != "" {
+		if match := ftsMatchExpr(q.QueryText); match != "" {
+			where += ` AND rowid IN (SELECT rowid FROM listings_fts WHERE listings_fts MATCH ?)`
+			args = append(args, match)
+		} else {
+			// Nothing searchable, such as only punctuation or one- and two-letter words.
+			where += ` AND 0`
+		}
 	}
 
 	return where, args
 }
 
-func (r *SQLiteRepository) buildOrderClause(sortField, sortOrder string) string {
-	if sortField == "" {
-		return "featured DESC, heat_level DESC, rating DESC, created_at DESC"
+// weekdayKeys are the day keys used in structured_hours JSON, indexed by time.Weekday.
+var weekdayKeys = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// openAtClause matches listings open at at on their own clocks. SQLite has no zone
+// data, so at is converted in Go for each of zones and listings whose zones agree on
+// the local time share a branch; listings in no known zone read at as given. A
+// wallClock time is read unchanged on every listing's clock.
+func openAtClause(at time.Time, wallClock bool, zones []string) (string, []interface{}) {
+	if wallClock {
+		zones = nil
+	}
+	type clock struct {
+		local time.Time
+		zones []interface{}
+	}
+	var clocks []*clock
+	byWallTime := map[string]*clock{}
+	for _, tz := range zones {
+		loc, ok := domain.Listing{Timezone: tz}.TimeLocation()
+		if !ok {
+			continue
+		}
+		local := at.In(loc)
+		key := local.Format("2006-01-02 15:04")
+		c := byWallTime[key]
+		if c == nil {
+			c = &clock{local: local}
+			byWallTime[key] = c
+			clocks = append(clocks, c)
+		}
+		c.zones = append(c.zones, tz)
+	}
+
+	if len(clocks) == 0 {
+		return ListingOpenAtSQL, openAtArgs(at)
+	}
+
+	var sb strings.Builder
+	var args []interface{}
+	sb.WriteString(`(CASE`)
+	for _, c := range clocks {
+		sb.WriteString(` WHEN timezone IN (` + placeholders(len(c.zones)) + `) THEN ` + ListingOpenAtSQL)
+		args = append(append(args, c.zones...), openAtArgs(c.local)...)
+	}
+	sb.WriteString(` ELSE ` + ListingOpenAtSQL + ` END)`)
+	return sb.String(), append(args, openAtArgs(at)...)
+}
+
+// openAtArgs are ListingOpenAtSQL's arguments for the local date and time of t.
+func openAtArgs(t time.Time) []interface{} {
+	hhmm := t.Format("15:04")
+	return append(append(dayHoursArgs(t), hhmm, hhmm), append(dayHoursArgs(t.AddDate(0, 0, -1)), hhmm)...)
+}
+
+// dayHoursArgs are listingDayHoursSQL's arguments for the local date of t.
+func dayHoursArgs(t time.Time) []interface{} {
+	date := t.Format(time.DateOnly)
+	return []interface{}{date, date, `$."` + date + `"`, "$." + weekdayKeys[t.Weekday()]}
+}
+
+// listingTimezones returns the distinct time zones listings are in.
+func (r *SQLiteRepository) listingTimezones(ctx context.Context) ([]string, error) {
+	rows, err := r.readDB.QueryContext(ctx, ListingTimezonesSQL)
+	if err != nil {
+		return nil, err
 	}
+	defer func() { _ = rows.Close() }()
+	var zones []string
+	for rows.Next() {
+		var tz string
+		if err := rows.Scan(&tz); err != nil {
+			return nil, err
+		}
+		zones = append(zones, tz)
+	}
+	return zones, rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// boundsSQL restricts listings to a map rectangle, wrapping round the antimeridian
+// when b.West is east of b.East. Candidates are the listings_rtree entries overlapping
+// the box; the R*Tree stores 32-bit floats rounded outwards, so the exact comparison
+// on the listing's own coordinates decides.
+func (r *SQLiteRepository) boundsSQL(b domain.BoundingBox) (string, []interface{}) {
+	exact := `latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?`
+	ranges := [][2]float64{{b.West, b.East}}
+	if b.West > b.East {
+		exact = `latitude BETWEEN ? AND ? AND (longitude >= ? OR longitude <= ?)`
+		ranges = [][2]float64{{b.West, 180}, {-180, b.East}}
+	}
+	args := []interface{}{b.South, b.North, b.West, b.East}
+	if r.noSpatialIndex {
+		return exact, args
+	}
+
+	// One R*Tree query per longitude range: the R*Tree only uses ANDed constraints.
+	subqueries := make([]string, 0, len(ranges))
+	for _, lr := range ranges {
+		subqueries = append(subqueries, `SELECT id FROM listings_rtree WHERE max_lat >= ? AND min_lat <= ? AND max_lng >= ? AND min_lng <= ?`)
+		args = append(args, b.South, b.North, lr[0], lr[1])
+	}
+	return exact + ` AND rowid IN (` + strings.Join(subqueries, ` UNION ALL `) + `)`, args
+}
+
+// distanceSQL is the great-circle distance in miles to a listing from the point whose
+// latitude and longitude are the SQL expressions lat and lng. Bind a point to
+// distanceSQL("?", "?") with distanceArgs.
+func distanceSQL(lat, lng string) string {
+	return `(` + strconv.FormatFloat(domain.EarthRadiusMiles, 'f', -1, 64) + ` * acos(min(1.0, cos(radians(` + lat +
+		`)) * cos(radians(latitude)) * cos(radians(longitude) - radians(` + lng +
+		`)) + sin(radians(` + lat + `)) * sin(radians(latitude)))))`
+}
+
+// distanceArgs are the arguments of distanceSQL("?", "?") for a point.
+func distanceArgs(lat, lng float64) []interface{} {
+	return []interface{}{lat, lng, lat}
+}
 
-	field := "created_at"
+// distanceFrom joins listings to the search's centre as the columns search_lat and
+// search_lng, so that sorting by distance binds the point rather than writing it into
+// the query. Being in from, the point is also part of listingOrder.signature, so a
+// cursor minted for one point is refused for another.
+const distanceFrom = `(SELECT ? AS search_lat, ? AS search_lng) CROSS JOIN listings`
+
+func (r *SQLiteRepository) buildOrderClause(q domain.ListingQuery) listingOrder {
+	sortField, sortOrder := q.Sort, q.Order
+	rowID := orderTerm{expr: "rowid", desc: true}
+	featured := orderTerm{expr: domain.FieldFeatured, desc: true}
+	// created_at is stored as text; read it back as text so cursors compare byte-for-byte.
+	createdAt := orderTerm{expr: domain.FieldCreatedAt, key: "CAST(created_at AS TEXT)", desc: true}
+
+	if sortField == domain.SortDistance && (q.Latitude != 0 || q.Longitude != 0) {
+		distance := orderTerm{
+			expr:     distanceSQL("search_lat", "search_lng"),
+			from:     distanceFrom,
+			fromArgs: []interface{}{q.Latitude, q.Longitude},
+			desc:     strings.ToLower(sortOrder) == "desc",
+		}
+		return listingOrder{distance, rowID}
+	}
+	if sortField == domain.SortRelevance {
+		if match := ftsMatchExpr(q.QueryText); match != "" {
+			return listingOrder{{expr: "fts_rank", from: ftsRankFrom, fromArgs: []interface{}{match}}, rowID}
+		}
+	}
+	if sortField == "" || sortField == domain.SortDistance || sortField == domain.SortRelevance {
+		return listingOrder{featured, {expr: "heat_level", desc: true}, {expr: "rating", desc: true}, createdAt, rowID}
+	}
+
+	term := createdAt
 	switch sortField {
 	case "title":
-		field = "title"
+		term = orderTerm{expr: "title"}
 	case domain.FieldStatus:
-		field = domain.FieldStatus
+		term = orderTerm{expr: domain.FieldStatus}
 	case domain.FieldFeatured:
-		field = domain.FieldFeatured
+		term = featured
 	case domain.FieldType:
-		field = domain.FieldType
-	}
-
-	order := "DESC"
-	if strings.ToLower(sortOrder) == "asc" {
-		order = "ASC"
+		term = orderTerm{expr: domain.FieldType}
 	}
+	term.desc = strings.ToLower(sortOrder) != "asc"
 
-	if field == domain.FieldFeatured {
-		return domain.FieldFeatured + " " + order + ", created_at DESC"
+	if term.expr == domain.FieldFeatured {
+		return listingOrder{term, createdAt, rowID}
 	}
-	return domain.FieldFeatured + " DESC, " + field + " " + order
+	return listingOrder{featured, term, rowID}
 }
 
 func (r *SQLiteRepository) getCount(ctx context.Context, table, where string, args []interface{}) (int, error) {
@@ -263,8 +505,7 @@ func (r *SQLiteRepository) FindByTitle(ctx context.Context, title string) ([]dom
 }
 
 func (r *SQLiteRepository) FindAllByOwner(ctx context.Context, ownerID string, limit int, offset int) ([]domain.Listing, int, error) {
-	filters := ListingFilters{OwnerID: ownerID, IncludeInactive: true}
-	where, args := r.buildListingWhere(filters)
+	where, args := r.buildListingWhere(domain.ListingQuery{OwnerID: ownerID, IncludeInactive: true}, time.Now(), nil)
 
 	totalCount, err := r.getCount(ctx, "listings", where, args)
 	if err != nil {
@@ -327,12 +568,12 @@ func (r *SQLiteRepository) GetLocations(ctx context.Context) ([]domain.Location,
 
 // GetFeaturedListings returns featured listings set by admin, optionally filtered by category and city.
 func (r *SQLiteRepository) GetFeaturedListings(ctx context.Context, category string, city string) ([]domain.Listing, error) {
-	filters := ListingFilters{
-		Type:         category,
-		City:         city,
+	q := domain.ListingQuery{
+		Types:        domain.TypeFilter(category),
+		Cities:       domain.CityFilter(city),
 		FeaturedOnly: true,
 	}
-	where, args := r.buildListingWhere(filters)
+	where, args := r.buildListingWhere(q, time.Now(), nil)
 	return r.queryListingsSimple(ctx, where+" ORDER BY created_at DESC LIMIT 3", args...)
 }
 
@@ -343,6 +584,6 @@ func (r *SQLiteRepository) FindEnrichmentTargets(ctx context.Context, limit int)
 }
 
 func (r *SQLiteRepository) FindRatingBackfillTargets(ctx context.Context, limit int) ([]domain.Listing, error) {
-	where := "WHERE is_active = 1 AND status = 'Approved' AND type = 'Food' AND (rating_updated_at IS NULL OR rating_updated_at < datetime('now', '-30 days')) LIMIT ?"
+	where := "WHERE " + ListingActiveApprovedSQL + " AND type = 'Food' AND (rating_updated_at IS NULL OR rating_updated_at < datetime('now', '-30 days')) LIMIT ?"
 	return r.queryListingsSimple(ctx, where, limit)
 }
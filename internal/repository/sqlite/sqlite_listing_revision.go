@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const listingRevisionColumns = `listing_id, number, actor_id, actor_name, snapshot, created_at`
+
+func scanListingRevision(s Scanner) (domain.ListingRevision, error) {
+	var r domain.ListingRevision
+	var snapshot string
+	if err := s.Scan(&r.ListingID, &r.Number, &r.ActorID, &r.ActorName, &snapshot, &r.CreatedAt); err != nil {
+		return domain.ListingRevision{}, err
+	}
+	if err := json.Unmarshal([]byte(snapshot), &r.Snapshot); err != nil {
+		return domain.ListingRevision{}, err
+	}
+	return r, nil
+}
+
+// SaveListingRevision stores a snapshot under the next free revision number for its listing.
+func (r *SQLiteRepository) SaveListingRevision(ctx context.Context, rev domain.ListingRevision) (int, error) {
+	return saveListingRevision(ctx, r.writeDB, rev)
+}
+
+func saveListingRevision(ctx context.Context, db execer, rev domain.ListingRevision) (int, error) {
+	snapshot, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return 0, err
+	}
+	var number int
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO listing_revisions (`+listingRevisionColumns+`)
+		SELECT ?, COALESCE(MAX(number), 0) + 1, ?, ?, ?, ? FROM listing_revisions WHERE listing_id = ?
+		RETURNING number`,
+		rev.ListingID, rev.ActorID, rev.ActorName, string(snapshot), rev.CreatedAt, rev.ListingID,
+	).Scan(&number)
+	return number, err
+}
+
+// ListListingRevisions returns every revision of a listing, oldest first.
+func (r *SQLiteRepository) ListListingRevisions(ctx context.Context, listingID string) ([]domain.ListingRevision, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+listingRevisionColumns+` FROM listing_revisions WHERE listing_id = ? ORDER BY number ASC`, listingID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanListingRevision)
+}
+
+// GetListingRevision returns one revision of a listing.
+func (r *SQLiteRepository) GetListingRevision(ctx context.Context, listingID string, number int) (domain.ListingRevision, error) {
+	row := r.readDB.QueryRowContext(ctx,
+		`SELECT `+listingRevisionColumns+` FROM listing_revisions WHERE listing_id = ? AND number = ?`, listingID, number)
+	rev, err := scanListingRevision(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ListingRevision{}, domain.ErrRevisionNotFound
+	}
+	return rev, err
+}
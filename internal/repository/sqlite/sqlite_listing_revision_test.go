@@ -0,0 +1,46 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingRevisions_NumberedPerListing(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	save := func(listingID, title string) int {
+		n, err := repo.SaveListingRevision(ctx, domain.ListingRevision{
+			ListingID: listingID, ActorID: "u1", ActorName: "Ada", CreatedAt: now,
+			Snapshot: domain.Listing{ID: listingID, Title: title, Type: domain.Food, CreatedAt: now},
+		})
+		require.NoError(t, err)
+		return n
+	}
+	assert.Equal(t, 1, save("l1", "First"))
+	assert.Equal(t, 2, save("l1", "Second"))
+	assert.Equal(t, 1, save("l2", "Other"))
+
+	revs, err := repo.ListListingRevisions(ctx, "l1")
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	assert.Equal(t, "First", revs[0].Snapshot.Title)
+	assert.Equal(t, 2, revs[1].Number)
+	assert.Equal(t, "Ada", revs[1].ActorName)
+	assert.True(t, revs[0].Snapshot.CreatedAt.Equal(now))
+
+	rev, err := repo.GetListingRevision(ctx, "l1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", rev.Snapshot.Title)
+
+	_, err = repo.GetListingRevision(ctx, "l1", 3)
+	assert.ErrorIs(t, err, domain.ErrRevisionNotFound)
+}
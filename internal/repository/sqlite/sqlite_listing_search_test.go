@@ -10,7 +10,7 @@ import (
 	"github.com/jadecobra/agbalumo/internal/domain"
 )
 
-func TestFindAll_Filtering(t *testing.T) {
+func TestSearch_Filtering(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -21,40 +21,45 @@ func TestFindAll_Filtering(t *testing.T) {
 	saveTestListing(t, ctx, repo, domain.Listing{ID: "3", Title: "Deleted Item", Type: "Product", Status: domain.ListingStatusRejected, IsActive: false, CreatedAt: time.Now()})
 
 	// 1. Find All Active (Default for Public) - should only return Approved
-	res, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Limit: 20})
+	res := page.Listings
 	if err != nil {
-		t.Fatalf("FindAll failed: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
 	if len(res) != 1 {
 		t.Errorf("Expected 1 approved listing, got %d", len(res))
 	}
 
 	// 2. Filter by Type
-	res, _, _ = repo.FindAll(ctx, "Business", "", "", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	page, _ = repo.Search(ctx, domain.ListingQuery{Types: []domain.Category{domain.Business}, Limit: 20})
+	res = page.Listings
 	if len(res) != 1 || res[0].Title != "Jollof Rice" {
 		t.Errorf("Type filtering failed")
 	}
 
 	// 3. Search text (public) - should NOT find Pending "Braiding"
-	res, _, _ = repo.FindAll(ctx, "", "Braiding", "", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	page, _ = repo.Search(ctx, domain.ListingQuery{QueryText: "Braiding", Limit: 20})
+	res = page.Listings
 	if len(res) != 0 {
 		t.Errorf("Expected 0 results for public search of pending listing, got %d", len(res))
 	}
 
 	// 3b. Search text (admin) - should find Pending "Braiding"
-	res, _, _ = repo.FindAll(ctx, "", "Braiding", "", 0.0, 0.0, 0.0, "", "", true, 20, 0)
+	page, _ = repo.Search(ctx, domain.ListingQuery{QueryText: "Braiding", IncludeInactive: true, Limit: 20})
+	res = page.Listings
 	if len(res) != 1 {
 		t.Errorf("Expected 1 result for admin search of pending listing, got %d", len(res))
 	}
 
 	// 4. Include Inactive (Admin view)
-	res, _, _ = repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", true, 20, 0)
+	page, _ = repo.Search(ctx, domain.ListingQuery{IncludeInactive: true, Limit: 20})
+	res = page.Listings
 	if len(res) != 3 {
 		t.Errorf("Expected 3 listings including inactive, got %d", len(res))
 	}
 }
 
-func TestFindAll_Sorting_Featured(t *testing.T) {
+func TestSearch_Sorting_Featured(t *testing.T) {
 	t.Parallel()
 	repo, _ := testutil.SetupTestRepositoryUnique(t)
 	ctx := context.Background()
@@ -65,9 +70,10 @@ func TestFindAll_Sorting_Featured(t *testing.T) {
 	saveTestListing(t, ctx, repo, domain.Listing{ID: "3", Title: "C-Normal", Status: domain.ListingStatusApproved, IsActive: true, Featured: false, CreatedAt: time.Now().Add(2 * time.Second)})
 
 	// Test sort by featured DESC (Featured should be first)
-	res, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "featured", "DESC", true, 10, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{Sort: "featured", Order: "DESC", IncludeInactive: true, Limit: 10})
+	res := page.Listings
 	if err != nil {
-		t.Fatalf("FindAll failed: %v", err)
+		t.Fatalf("Search failed: %v", err)
 	}
 
 	if len(res) != 3 {
@@ -79,9 +85,10 @@ func TestFindAll_Sorting_Featured(t *testing.T) {
 	}
 
 	// Test sort by featured ASC (Featured should be last)
-	resAsc, _, errAsc := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "featured", "ASC", true, 10, 0)
+	page, errAsc := repo.Search(ctx, domain.ListingQuery{Sort: "featured", Order: "ASC", IncludeInactive: true, Limit: 10})
+	resAsc := page.Listings
 	if errAsc != nil {
-		t.Fatalf("FindAll failed: %v", errAsc)
+		t.Fatalf("Search failed: %v", errAsc)
 	}
 
 	if len(resAsc) != 3 {
@@ -113,7 +120,8 @@ func TestListingRepository_FTS(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		res, _, _ := repo.FindAll(ctx, "", tt.query, "", 0.0, 0.0, 0.0, "", "", false, 10, 0)
+		page, _ := repo.Search(ctx, domain.ListingQuery{QueryText: tt.query, Limit: 10})
+		res := page.Listings
 		if len(res) != 1 || res[0].ID != tt.want {
 			t.Errorf("FTS query %q failed: got %d results, want ID %s", tt.query, len(res), tt.want)
 		}
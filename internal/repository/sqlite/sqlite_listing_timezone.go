@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// FindListingsWithoutTimezone returns up to limit listings with no time zone whose ID
+// sorts after afterID, in ID order.
+func (r *SQLiteRepository) FindListingsWithoutTimezone(ctx context.Context, afterID string, limit int) ([]domain.Listing, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT `+ListingSelectionsSQL+` FROM listings
+		WHERE timezone = '' AND id > ?
+		ORDER BY id ASC
+		LIMIT ?`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanListing)
+}
+
+// SetListingTimezone stores a listing's time zone without touching its other columns.
+func (r *SQLiteRepository) SetListingTimezone(ctx context.Context, id, tz string) error {
+	_, err := r.writeDB.ExecContext(ctx, `UPDATE listings SET timezone = ? WHERE id = ?`, tz, id)
+	return err
+}
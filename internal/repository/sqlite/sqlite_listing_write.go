@@ -76,8 +76,8 @@ func (r *SQLiteRepository) insertBatch(ctx context.Context, tx *sql.Tx, batch []
 }
 
 func (r *SQLiteRepository) buildBulkInsertSQL(batch []domain.Listing) (string, []interface{}) {
-	const numFields = 41
-	const placeholders = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	const numFields = 44
+	const placeholders = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
 	var sb strings.Builder
 	// Pre-allocate approximate size: len(batch) * len(placeholders) + SQL header/footer
@@ -101,7 +101,7 @@ func (r *SQLiteRepository) buildBulkInsertSQL(batch []domain.Listing) (string, [
 }
 
 func (r *SQLiteRepository) listingArgs(l domain.Listing) []interface{} {
-	args := make([]interface{}, 41)
+	args := make([]interface{}, 44)
 	r.fillListingArgs(args, 0, l)
 	return args
 }
@@ -148,6 +148,9 @@ func (r *SQLiteRepository) fillListingArgs(args []interface{}, offset int, l dom
 	args[offset+38] = l.ReviewCount
 	args[offset+39] = l.RatingUpdatedAt
 	args[offset+40] = l.StructuredHours
+	args[offset+41] = l.Timezone
+	args[offset+42] = l.HoursExceptions
+	args[offset+43] = l.PermanentlyClosed
 }
 
 func (r *SQLiteRepository) ensureStatus(s domain.ListingStatus) string {
@@ -184,9 +187,11 @@ func (r *SQLiteRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *SQLiteRepository) ExpireListings(ctx context.Context) (int64, error) {
+// ExpireListings deactivates listings past their deadline, event end or job start,
+// in batches, and returns the listings it deactivated.
+func (r *SQLiteRepository) ExpireListings(ctx context.Context) ([]domain.Listing, error) {
 	now := time.Now().UTC()
-	var totalAffected int64
+	var expired []domain.Listing
 	batchSize := 100
 
 	query := `
@@ -204,28 +209,27 @@ func (r *SQLiteRepository) ExpireListings(ctx context.Context) (int64, error) {
 			)
 			LIMIT ?
 		)
-	`
+		RETURNING ` + ListingSelectionsSQL
 
 	for {
-		result, err := r.writeDB.ExecContext(ctx, query, now, now, now.AddDate(0, 0, -90), batchSize)
+		rows, err := r.writeDB.QueryContext(ctx, query, now, now, now.Add(-domain.JobListingLifetime), batchSize)
 		if err != nil {
-			return totalAffected, err
+			return expired, err
 		}
-
-		affected, err := result.RowsAffected()
+		batch, err := scanAll(rows, scanListing)
 		if err != nil {
-			return totalAffected, err
+			return expired, err
 		}
 
-		totalAffected += affected
-		if affected < int64(batchSize) {
+		expired = append(expired, batch...)
+		if len(batch) < batchSize {
 			break
 		}
 
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	return totalAffected, nil
+	return expired, nil
 }
 
 // SetFeatured toggles the featured status of a listing.
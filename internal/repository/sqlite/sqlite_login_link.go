@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// SaveLoginLink stores a new sign-in link, first dropping links that have expired.
+func (r *SQLiteRepository) SaveLoginLink(ctx context.Context, link domain.LoginLink) error {
+	if _, err := r.writeDB.ExecContext(ctx, `DELETE FROM login_links WHERE expires_at <= ?`, link.CreatedAt.UTC()); err != nil {
+		return err
+	}
+	_, err := r.writeDB.ExecContext(ctx,
+		`INSERT INTO login_links (token_hash, email, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		link.TokenHash, link.Email, link.CreatedAt.UTC(), link.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+// UseLoginLink marks an unused, unexpired link used in the same statement that finds
+// it, so two requests with one link cannot both sign in.
+func (r *SQLiteRepository) UseLoginLink(ctx context.Context, tokenHash string, now time.Time) (domain.LoginLink, error) {
+	var link domain.LoginLink
+	err := r.writeDB.QueryRowContext(ctx, `
+	UPDATE login_links SET used_at = ?
+	WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?
+	RETURNING token_hash, email, created_at, expires_at`,
+		now.UTC(), tokenHash, now.UTC(),
+	).Scan(&link.TokenHash, &link.Email, &link.CreatedAt, &link.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LoginLink{}, domain.ErrInvalidLoginLink
+	}
+	return link, err
+}
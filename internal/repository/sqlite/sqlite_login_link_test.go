@@ -0,0 +1,65 @@
+package sqlite_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLinks_SingleUse(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.SaveLoginLink(ctx, domain.LoginLink{
+		TokenHash: "h1", Email: "ada@example.com", CreatedAt: now, ExpiresAt: now.Add(domain.LoginLinkTTL),
+	}))
+
+	_, err := repo.UseLoginLink(ctx, "unknown", now)
+	assert.ErrorIs(t, err, domain.ErrInvalidLoginLink)
+	_, err = repo.UseLoginLink(ctx, "h1", now.Add(domain.LoginLinkTTL))
+	assert.ErrorIs(t, err, domain.ErrInvalidLoginLink, "expired links do not sign in")
+
+	// Of several simultaneous uses, exactly one succeeds.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var used []domain.LoginLink
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if link, useErr := repo.UseLoginLink(ctx, "h1", now.Add(time.Minute)); useErr == nil {
+				mu.Lock()
+				used = append(used, link)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, used, 1)
+	assert.Equal(t, "ada@example.com", used[0].Email)
+	assert.True(t, used[0].ExpiresAt.Equal(now.Add(domain.LoginLinkTTL)))
+}
+
+func TestLoginLinks_SaveDropsExpired(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	require.NoError(t, repo.SaveLoginLink(ctx, domain.LoginLink{TokenHash: "old", Email: "a@example.com", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}))
+	require.NoError(t, repo.SaveLoginLink(ctx, domain.LoginLink{TokenHash: "new", Email: "a@example.com", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}))
+
+	// Used at a time it was still valid, the old link would work had it been kept.
+	_, err := repo.UseLoginLink(ctx, "old", now.Add(-30*time.Minute))
+	assert.ErrorIs(t, err, domain.ErrInvalidLoginLink)
+	_, err = repo.UseLoginLink(ctx, "new", now)
+	assert.NoError(t, err)
+}
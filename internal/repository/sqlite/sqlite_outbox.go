@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const outboxColumns = `id, kind, recipient, subject, html_body, text_body, status, attempts, last_error, created_at, next_attempt_at, sent_at`
+
+func scanOutboxMessage(s Scanner) (domain.OutboxMessage, error) {
+	var m domain.OutboxMessage
+	var sent sql.NullTime
+	err := s.Scan(&m.ID, &m.Kind, &m.Email.To, &m.Email.Subject, &m.Email.HTML, &m.Email.Text,
+		&m.Status, &m.Attempts, &m.LastError, &m.CreatedAt, &m.NextAttemptAt, &sent)
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	if sent.Valid {
+		m.SentAt = sent.Time
+	}
+	return m, nil
+}
+
+// SaveOutboxMessage queues a message for delivery.
+func (r *SQLiteRepository) SaveOutboxMessage(ctx context.Context, m domain.OutboxMessage) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`INSERT INTO notification_outbox (`+outboxColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ID, m.Kind, m.Email.To, m.Email.Subject, m.Email.HTML, m.Email.Text,
+		m.Status, m.Attempts, m.LastError, m.CreatedAt.UTC(), m.NextAttemptAt.UTC(), nullTime(m.SentAt),
+	)
+	return err
+}
+
+// ListDueOutboxMessages returns pending messages ready for another attempt, oldest first.
+func (r *SQLiteRepository) ListDueOutboxMessages(ctx context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+outboxColumns+` FROM notification_outbox
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY created_at ASC, rowid ASC LIMIT ?`,
+		domain.OutboxStatusPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanOutboxMessage)
+}
+
+// UpdateOutboxMessage records the outcome of a delivery attempt. The bodies are
+// written too, so a message carrying a secret can be erased once delivered.
+func (r *SQLiteRepository) UpdateOutboxMessage(ctx context.Context, m domain.OutboxMessage) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`UPDATE notification_outbox SET html_body = ?, text_body = ?, status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, sent_at = ? WHERE id = ?`,
+		m.Email.HTML, m.Email.Text, m.Status, m.Attempts, m.LastError, m.NextAttemptAt.UTC(), nullTime(m.SentAt), m.ID,
+	)
+	return err
+}
+
+// nullTime stores the zero time as NULL. Due times are compared as stored text, so
+// every time written here is normalised to UTC.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
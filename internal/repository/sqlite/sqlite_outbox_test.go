@@ -0,0 +1,59 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_DueMessagesAndUpdate(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queue := func(id string, created, next time.Time) {
+		require.NoError(t, repo.SaveOutboxMessage(ctx, domain.OutboxMessage{
+			ID: id, Kind: domain.NotificationClaimApproved, Status: domain.OutboxStatusPending,
+			Email:     domain.Email{To: "ada@example.com", Subject: "Subject " + id, HTML: "<p>hi</p>", Text: "hi"},
+			CreatedAt: created, NextAttemptAt: next,
+		}))
+	}
+	queue("later", now.Add(-time.Minute), now.Add(time.Hour))
+	queue("second", now.Add(-time.Minute), now)
+	queue("first", now.Add(-2*time.Minute), now.Add(-time.Minute))
+
+	due, err := repo.ListDueOutboxMessages(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "first", due[0].ID)
+	assert.Equal(t, "second", due[1].ID)
+	assert.Equal(t, domain.Email{To: "ada@example.com", Subject: "Subject first", HTML: "<p>hi</p>", Text: "hi"}, due[0].Email)
+	assert.True(t, due[0].SentAt.IsZero())
+
+	sent := due[0]
+	sent.Status, sent.Attempts, sent.SentAt = domain.OutboxStatusSent, 1, now
+	require.NoError(t, repo.UpdateOutboxMessage(ctx, sent))
+
+	due, err = repo.ListDueOutboxMessages(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "second", due[0].ID)
+
+	retry := due[0]
+	retry.Attempts, retry.NextAttemptAt, retry.Email.HTML, retry.Email.Text = 1, now, "", ""
+	require.NoError(t, repo.UpdateOutboxMessage(ctx, retry))
+	due, err = repo.ListDueOutboxMessages(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, domain.Email{To: "ada@example.com", Subject: "Subject second"}, due[0].Email, "bodies are updated")
+
+	due, err = repo.ListDueOutboxMessages(ctx, now.Add(2*time.Hour), 1)
+	require.NoError(t, err)
+	assert.Len(t, due, 1, "limit applies")
+}
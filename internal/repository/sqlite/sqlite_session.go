@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const sessionColumns = `id, COALESCE(user_id, ''), data, ip, user_agent, created_at, last_seen_at, expires_at`
+
+func scanSession(s Scanner) (domain.Session, error) {
+	var sess domain.Session
+	err := s.Scan(&sess.ID, &sess.UserID, &sess.Data, &sess.IP, &sess.UserAgent, &sess.CreatedAt, &sess.LastSeenAt, &sess.ExpiresAt)
+	return sess, err
+}
+
+// SaveSession upserts a session, keeping the creation time of one being re-saved, and
+// first drops sessions that have expired.
+func (r *SQLiteRepository) SaveSession(ctx context.Context, s domain.Session) error {
+	if _, err := r.writeDB.ExecContext(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, s.LastSeenAt.UTC()); err != nil {
+		return err
+	}
+	_, err := r.writeDB.ExecContext(ctx, `
+	INSERT INTO sessions (id, user_id, data, ip, user_agent, created_at, last_seen_at, expires_at)
+	VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		user_id = excluded.user_id,
+		data = excluded.data,
+		ip = excluded.ip,
+		user_agent = excluded.user_agent,
+		last_seen_at = excluded.last_seen_at,
+		expires_at = excluded.expires_at`,
+		s.ID, s.UserID, s.Data, s.IP, s.UserAgent, s.CreatedAt.UTC(), s.LastSeenAt.UTC(), s.ExpiresAt.UTC(),
+	)
+	return err
+}
+
+// FindSession returns an unexpired session by its hashed ID.
+func (r *SQLiteRepository) FindSession(ctx context.Context, id string, now time.Time) (domain.Session, error) {
+	row := r.readDB.QueryRowContext(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE id = ? AND expires_at > ?`, id, now.UTC())
+	s, err := scanSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	return s, err
+}
+
+// TouchSession records the latest request seen on a session.
+func (r *SQLiteRepository) TouchSession(ctx context.Context, id, ip, userAgent string, at time.Time) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`UPDATE sessions SET ip = ?, user_agent = ?, last_seen_at = ? WHERE id = ?`,
+		ip, userAgent, at.UTC(), id)
+	return err
+}
+
+// DeleteSession ends a session.
+func (r *SQLiteRepository) DeleteSession(ctx context.Context, id string) error {
+	_, err := r.writeDB.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	return err
+}
+
+// ListUserSessions returns a user's unexpired sessions, most recently seen first.
+func (r *SQLiteRepository) ListUserSessions(ctx context.Context, userID string, now time.Time) ([]domain.Session, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+sessionColumns+` FROM sessions WHERE user_id = ? AND expires_at > ? ORDER BY last_seen_at DESC`,
+		userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanSession)
+}
+
+// DeleteUserSession ends one of a user's sessions.
+func (r *SQLiteRepository) DeleteUserSession(ctx context.Context, userID, id string) error {
+	res, err := r.writeDB.ExecContext(ctx, `DELETE FROM sessions WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteUserSessions ends every session of a user.
+func (r *SQLiteRepository) DeleteUserSessions(ctx context.Context, userID string) (int, error) {
+	res, err := r.writeDB.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = ?`, userID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,80 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions_SaveFindAndTouch(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s := domain.Session{
+		ID: "s1", Data: []byte("values"), IP: "10.0.0.1", UserAgent: "curl/8",
+		CreatedAt: now, LastSeenAt: now, ExpiresAt: now.Add(time.Hour),
+	}
+	require.NoError(t, repo.SaveSession(ctx, s))
+
+	// Signing in re-saves the session with a user, keeping when it began.
+	s.UserID, s.LastSeenAt = "u1", now.Add(time.Minute)
+	s.CreatedAt = s.LastSeenAt
+	require.NoError(t, repo.SaveSession(ctx, s))
+	got, err := repo.FindSession(ctx, "s1", now)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", got.UserID)
+	assert.Equal(t, []byte("values"), got.Data)
+	assert.True(t, got.CreatedAt.Equal(now))
+
+	require.NoError(t, repo.TouchSession(ctx, "s1", "10.0.0.2", "Firefox", now.Add(2*time.Minute)))
+	got, _ = repo.FindSession(ctx, "s1", now)
+	assert.Equal(t, "10.0.0.2", got.IP)
+	assert.True(t, got.LastSeenAt.Equal(now.Add(2*time.Minute)))
+
+	_, err = repo.FindSession(ctx, "s1", now.Add(time.Hour))
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound, "expired sessions are not found")
+
+	require.NoError(t, repo.DeleteSession(ctx, "s1"))
+	_, err = repo.FindSession(ctx, "s1", now)
+	assert.ErrorIs(t, err, domain.ErrSessionNotFound)
+}
+
+func TestSessions_PerUser(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	save := func(id, userID string, lastSeen time.Time) {
+		require.NoError(t, repo.SaveSession(ctx, domain.Session{
+			ID: id, UserID: userID, Data: []byte{}, CreatedAt: lastSeen, LastSeenAt: lastSeen, ExpiresAt: now.Add(time.Hour),
+		}))
+	}
+	save("laptop", "u1", now.Add(-time.Hour))
+	save("phone", "u1", now)
+	save("other", "u2", now)
+	save("anonymous", "", now)
+
+	list, err := repo.ListUserSessions(ctx, "u1", now)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "phone", list[0].ID, "most recently seen first")
+
+	assert.ErrorIs(t, repo.DeleteUserSession(ctx, "u1", "other"), domain.ErrSessionNotFound, "users cannot end another user's session")
+	require.NoError(t, repo.DeleteUserSession(ctx, "u1", "laptop"))
+
+	n, err := repo.DeleteUserSessions(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = repo.FindSession(ctx, "other", now)
+	assert.NoError(t, err)
+	_, err = repo.FindSession(ctx, "anonymous", now)
+	assert.NoError(t, err)
+}
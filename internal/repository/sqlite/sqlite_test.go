@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/jadecobra/agbalumo/internal/repository/sqlite"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 	_ "modernc.org/sqlite"
@@ -25,7 +26,7 @@ func TestNewSQLiteRepositoryFromDB(t *testing.T) {
 
 	// Verify we can use it
 	ctx := context.Background()
-	_, _, err := repo.FindAll(ctx, "All", "", "", 0.0, 0.0, 0.0, "", "", false, 20, 0)
+	_, err := repo.Search(ctx, domain.ListingQuery{Limit: 20})
 	if err == nil {
 		t.Error("Expected error due to missing tables, got nil")
 	}
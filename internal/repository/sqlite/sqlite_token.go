@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+const apiTokenColumns = `id, user_id, name, prefix, token_hash, scopes, created_at, last_used_at, revoked_at`
+
+func scanAPIToken(s Scanner) (domain.APIToken, error) {
+	var t domain.APIToken
+	var scopes string
+	var lastUsed, revoked sql.NullTime
+	if err := s.Scan(&t.ID, &t.UserID, &t.Name, &t.Prefix, &t.TokenHash, &scopes, &t.CreatedAt, &lastUsed, &revoked); err != nil {
+		return domain.APIToken{}, err
+	}
+	// Scopes were validated when the token was minted; unknown values are dropped rather than failing the read.
+	t.Scopes, _ = domain.ParseTokenScopes(scopes)
+	if lastUsed.Valid {
+		t.LastUsedAt = &lastUsed.Time
+	}
+	if revoked.Valid {
+		t.RevokedAt = &revoked.Time
+	}
+	return t, nil
+}
+
+// SaveAPIToken inserts a new personal access token.
+func (r *SQLiteRepository) SaveAPIToken(ctx context.Context, t domain.APIToken) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`INSERT INTO api_tokens (`+apiTokenColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, t.UserID, t.Name, t.Prefix, t.TokenHash, domain.JoinTokenScopes(t.Scopes), t.CreatedAt, t.LastUsedAt, t.RevokedAt,
+	)
+	return err
+}
+
+// FindAPITokenByHash looks up a token by the hash of its secret, including revoked tokens.
+func (r *SQLiteRepository) FindAPITokenByHash(ctx context.Context, hash string) (domain.APIToken, error) {
+	row := r.readDB.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, hash)
+	t, err := scanAPIToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIToken{}, domain.ErrTokenNotFound
+	}
+	return t, err
+}
+
+// ListAPITokens returns every token a user has minted, newest first.
+func (r *SQLiteRepository) ListAPITokens(ctx context.Context, userID string) ([]domain.APIToken, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+apiTokenColumns+` FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanAPIToken)
+}
+
+// RevokeAPIToken marks a user's token as revoked. Revoking an already revoked token is a no-op.
+func (r *SQLiteRepository) RevokeAPIToken(ctx context.Context, userID, id string, at time.Time) error {
+	res, err := r.writeDB.ExecContext(ctx,
+		`UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ? AND user_id = ?`, at, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrTokenNotFound
+	}
+	return nil
+}
+
+// TouchAPIToken records when a token was last used.
+func (r *SQLiteRepository) TouchAPIToken(ctx context.Context, id string, at time.Time) error {
+	_, err := r.writeDB.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, at, id)
+	return err
+}
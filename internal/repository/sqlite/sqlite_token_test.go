@@ -0,0 +1,70 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokens_Lifecycle(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	older := domain.APIToken{ID: "t1", UserID: "u1", Name: "old", Prefix: "agb_aaaaaa", TokenHash: "h1",
+		Scopes: []domain.TokenScope{domain.ScopeListingsRead}, CreatedAt: now.Add(-time.Hour)}
+	newer := domain.APIToken{ID: "t2", UserID: "u1", Name: "new", Prefix: "agb_bbbbbb", TokenHash: "h2",
+		Scopes: []domain.TokenScope{domain.ScopeListingsRead, domain.ScopeListingsWrite}, CreatedAt: now}
+	other := domain.APIToken{ID: "t3", UserID: "u2", Name: "other", Prefix: "agb_cccccc", TokenHash: "h3",
+		Scopes: []domain.TokenScope{domain.ScopeListingsRead}, CreatedAt: now}
+	for _, tok := range []domain.APIToken{older, newer, other} {
+		require.NoError(t, repo.SaveAPIToken(ctx, tok))
+	}
+
+	found, err := repo.FindAPITokenByHash(ctx, "h2")
+	require.NoError(t, err)
+	assert.Equal(t, "t2", found.ID)
+	assert.Equal(t, newer.Scopes, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+	assert.False(t, found.IsRevoked())
+
+	_, err = repo.FindAPITokenByHash(ctx, "missing")
+	assert.ErrorIs(t, err, domain.ErrTokenNotFound)
+
+	list, err := repo.ListAPITokens(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "t2", list[0].ID, "newest first")
+
+	require.NoError(t, repo.TouchAPIToken(ctx, "t2", now))
+	found, _ = repo.FindAPITokenByHash(ctx, "h2")
+	require.NotNil(t, found.LastUsedAt)
+
+	assert.ErrorIs(t, repo.RevokeAPIToken(ctx, "u2", "t2", now), domain.ErrTokenNotFound, "cannot revoke another user's token")
+	require.NoError(t, repo.RevokeAPIToken(ctx, "u1", "t2", now))
+	found, _ = repo.FindAPITokenByHash(ctx, "h2")
+	assert.True(t, found.IsRevoked())
+
+	// Revoking twice keeps the original timestamp.
+	require.NoError(t, repo.RevokeAPIToken(ctx, "u1", "t2", now.Add(time.Hour)))
+	again, _ := repo.FindAPITokenByHash(ctx, "h2")
+	assert.True(t, again.RevokedAt.Equal(*found.RevokedAt))
+}
+
+func TestAPITokens_HashIsUnique(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+
+	tok := domain.APIToken{ID: "t1", UserID: "u1", Name: "a", Prefix: "agb_x", TokenHash: "dup",
+		Scopes: []domain.TokenScope{domain.ScopeListingsRead}, CreatedAt: time.Now()}
+	require.NoError(t, repo.SaveAPIToken(ctx, tok))
+	tok.ID = "t2"
+	assert.Error(t, repo.SaveAPIToken(ctx, tok))
+}
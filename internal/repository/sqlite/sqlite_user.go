@@ -12,16 +12,20 @@ import (
 func scanUser(s Scanner) (domain.User, error) {
 	var u domain.User
 	var createdAt time.Time
-	err := s.Scan(&u.ID, &u.GoogleID, &u.Email, &u.Name, &u.AvatarURL, &u.Role, &createdAt)
+	var suspendedUntil sql.NullTime
+	err := s.Scan(&u.ID, &u.GoogleID, &u.Email, &u.Name, &u.AvatarURL, &u.Role, &createdAt,
+		&u.Status, &suspendedUntil, &u.StatusReason)
 	if err == nil {
 		u.CreatedAt = createdAt
+		u.SuspendedUntil = suspendedUntil.Time
 	}
 	return u, err
 }
 
 // SaveUser inserts or updates a user.
 func (r *SQLiteRepository) SaveUser(ctx context.Context, u domain.User) error {
-	updateQuery := `UPDATE users SET google_id=?, email=?, name=?, avatar_url=?, role=? WHERE id=?`
+	// google_id is unique, so users without one store NULL rather than sharing ''.
+	updateQuery := `UPDATE users SET google_id=NULLIF(?, ''), email=?, name=?, avatar_url=?, role=? WHERE id=?`
 	res, err := r.writeDB.ExecContext(ctx, updateQuery,
 		u.GoogleID, u.Email, u.Name, u.AvatarURL, u.Role, u.ID,
 	)
@@ -40,7 +44,7 @@ func (r *SQLiteRepository) SaveUser(ctx context.Context, u domain.User) error {
 
 	insertQuery := `
 	INSERT INTO users (id, google_id, email, name, avatar_url, role, created_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?)
+	VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?)
 	ON CONFLICT(google_id) DO UPDATE SET
 		email = excluded.email,
 		name = excluded.name,
@@ -65,6 +69,56 @@ func (r *SQLiteRepository) FindUserByGoogleID(ctx context.Context, googleID stri
 	return u, err
 }
 
+// FindUserByIdentity retrieves the user a provider account is linked to.
+func (r *SQLiteRepository) FindUserByIdentity(ctx context.Context, provider, subject string) (domain.User, error) {
+	query := `SELECT ` + UserSelectionsSQL + ` FROM users
+	WHERE id = (SELECT user_id FROM user_identities WHERE provider = ? AND subject = ?)`
+	row := r.readDB.QueryRowContext(ctx, query, provider, subject)
+
+	u, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return u, err
+}
+
+// FindUserByIdentityEmail retrieves the user whose oldest sign-in identity has email.
+func (r *SQLiteRepository) FindUserByIdentityEmail(ctx context.Context, email string) (domain.User, error) {
+	query := `SELECT ` + UserSelectionsSQL + ` FROM users
+	WHERE id = (SELECT user_id FROM user_identities WHERE email = ? COLLATE NOCASE AND email <> ''
+		ORDER BY created_at ASC, rowid ASC LIMIT 1)`
+	row := r.readDB.QueryRowContext(ctx, query, email)
+
+	u, err := scanUser(row)
+	if err == sql.ErrNoRows {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return u, err
+}
+
+// SaveUserIdentity links a provider account to a user, refreshing the email of an
+// existing link. An account linked to someone else is left alone.
+func (r *SQLiteRepository) SaveUserIdentity(ctx context.Context, id domain.UserIdentity) error {
+	res, err := r.writeDB.ExecContext(ctx, `
+	INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(provider, subject) DO UPDATE SET email = excluded.email
+	WHERE user_identities.user_id = excluded.user_id`,
+		id.Provider, id.Subject, id.UserID, id.Email, id.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrIdentityLinked
+	}
+	return nil
+}
+
 // FindUserByID retrieves a user by their ID.
 func (r *SQLiteRepository) FindUserByID(ctx context.Context, id string) (domain.User, error) {
 	query := `SELECT ` + UserSelectionsSQL + ` FROM users WHERE id = ?`
@@ -93,3 +147,54 @@ func (r *SQLiteRepository) GetAllUsers(ctx context.Context, limit int, offset in
 	}
 	return scanAll(rows, scanUser)
 }
+
+// SetUserStatus changes whether a user may sign in. The end of a suspension is cleared
+// for any other status.
+func (r *SQLiteRepository) SetUserStatus(ctx context.Context, id string, status domain.UserStatus, until time.Time, reason string) error {
+	if status != domain.UserStatusSuspended {
+		until = time.Time{}
+	}
+	res, err := r.writeDB.ExecContext(ctx,
+		`UPDATE users SET status = ?, suspended_until = ?, status_reason = ? WHERE id = ?`,
+		status, nullTime(until), reason, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+const userEventColumns = `id, user_id, actor_id, actor_name, action, reason, until, listings_deactivated, created_at`
+
+func scanUserEvent(s Scanner) (domain.UserEvent, error) {
+	var e domain.UserEvent
+	var until sql.NullTime
+	err := s.Scan(&e.ID, &e.UserID, &e.ActorID, &e.ActorName, &e.Action, &e.Reason, &until, &e.ListingsDeactivated, &e.CreatedAt)
+	e.Until = until.Time
+	return e, err
+}
+
+// AppendUserEvent inserts an audit event for a user. Events are never updated or deleted.
+func (r *SQLiteRepository) AppendUserEvent(ctx context.Context, e domain.UserEvent) error {
+	_, err := r.writeDB.ExecContext(ctx,
+		`INSERT INTO user_events (`+userEventColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.UserID, e.ActorID, e.ActorName, e.Action, e.Reason, nullTime(e.Until), e.ListingsDeactivated, e.CreatedAt,
+	)
+	return err
+}
+
+// ListUserEvents returns every event recorded for a user, newest first.
+func (r *SQLiteRepository) ListUserEvents(ctx context.Context, userID string) ([]domain.UserEvent, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		`SELECT `+userEventColumns+` FROM user_events WHERE user_id = ? ORDER BY created_at DESC, rowid DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAll(rows, scanUserEvent)
+}
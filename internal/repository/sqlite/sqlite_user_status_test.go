@@ -0,0 +1,81 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserStatus(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	until := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Second)
+
+	require.NoError(t, repo.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g1", Name: "Ada", CreatedAt: time.Now()}))
+	u, err := repo.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusActive, u.Status, "new users are active")
+
+	require.NoError(t, repo.SetUserStatus(ctx, "u1", domain.UserStatusSuspended, until, "spam"))
+	// Signing in again re-saves the user without touching their status.
+	require.NoError(t, repo.SaveUser(ctx, domain.User{ID: "u1", GoogleID: "g1", Name: "Ada Lovelace", CreatedAt: time.Now()}))
+	u, err = repo.FindUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusSuspended, u.Status)
+	assert.True(t, u.SuspendedUntil.Equal(until))
+	assert.Equal(t, "spam", u.StatusReason)
+
+	require.NoError(t, repo.SetUserStatus(ctx, "u1", domain.UserStatusBanned, until, "more spam"))
+	u, _ = repo.FindUserByID(ctx, "u1")
+	assert.Equal(t, domain.UserStatusBanned, u.Status)
+	assert.True(t, u.SuspendedUntil.IsZero(), "only suspensions keep an end time")
+
+	assert.ErrorIs(t, repo.SetUserStatus(ctx, "missing", domain.UserStatusBanned, time.Time{}, "x"), domain.ErrUserNotFound)
+}
+
+func TestUserEvents(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.AppendUserEvent(ctx, domain.UserEvent{
+		ID: "e1", UserID: "u1", ActorID: "admin1", ActorName: "Admin", Action: domain.UserActionSuspend,
+		Reason: "spam", Until: now.Add(time.Hour), ListingsDeactivated: 3, CreatedAt: now,
+	}))
+	require.NoError(t, repo.AppendUserEvent(ctx, domain.UserEvent{
+		ID: "e2", UserID: "u1", ActorID: "admin1", Action: domain.UserActionReinstate, CreatedAt: now.Add(time.Minute),
+	}))
+	require.NoError(t, repo.AppendUserEvent(ctx, domain.UserEvent{ID: "e3", UserID: "u2", ActorID: "admin1", Action: domain.UserActionBan, CreatedAt: now}))
+
+	events, err := repo.ListUserEvents(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "e2", events[0].ID, "newest first")
+	assert.True(t, events[0].Until.IsZero())
+	assert.Equal(t, "spam", events[1].Reason)
+	assert.Equal(t, 3, events[1].ListingsDeactivated)
+	assert.True(t, events[1].Until.Equal(now.Add(time.Hour)))
+}
+
+func TestListFeedbackByUser(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	require.NoError(t, repo.SaveFeedback(ctx, domain.Feedback{ID: "f1", UserID: "u1", Type: domain.FeedbackTypeIssue, Content: "old", CreatedAt: now.Add(-time.Hour)}))
+	require.NoError(t, repo.SaveFeedback(ctx, domain.Feedback{ID: "f2", UserID: "u1", Type: domain.FeedbackTypeOther, Content: "new", CreatedAt: now}))
+	require.NoError(t, repo.SaveFeedback(ctx, domain.Feedback{ID: "f3", UserID: "u2", Type: domain.FeedbackTypeOther, Content: "other", CreatedAt: now}))
+
+	feedback, err := repo.ListFeedbackByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, feedback, 2)
+	assert.Equal(t, "new", feedback[0].Content)
+}
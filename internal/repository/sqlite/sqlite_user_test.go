@@ -140,3 +140,46 @@ func TestFindUser_NotFound(t *testing.T) {
 		t.Error("Expected error for missing google ID")
 	}
 }
+
+func TestUserIdentities(t *testing.T) {
+	t.Parallel()
+	repo, _ := testutil.SetupTestRepositoryUnique(t)
+	ctx := context.Background()
+
+	// Users without a Google ID must not collide on the unique google_id column.
+	_ = repo.SaveUser(ctx, domain.User{ID: "u1", Email: "e1", CreatedAt: time.Now()})
+	if err := repo.SaveUser(ctx, domain.User{ID: "u2", Email: "e2", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveUser without a Google ID failed: %v", err)
+	}
+
+	if _, err := repo.FindUserByIdentity(ctx, "acme", "sub-1"); err != domain.ErrUserNotFound {
+		t.Fatalf("Expected ErrUserNotFound before linking, got %v", err)
+	}
+	if err := repo.SaveUserIdentity(ctx, domain.UserIdentity{Provider: "acme", Subject: "sub-1", UserID: "u1", Email: "old", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveUserIdentity failed: %v", err)
+	}
+	if err := repo.SaveUserIdentity(ctx, domain.UserIdentity{Provider: "acme", Subject: "sub-1", UserID: "u1", Email: "new", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Relinking to the same user failed: %v", err)
+	}
+	u, err := repo.FindUserByIdentity(ctx, "acme", "sub-1")
+	if err != nil || u.ID != "u1" {
+		t.Fatalf("Expected u1, got %q (%v)", u.ID, err)
+	}
+
+	err = repo.SaveUserIdentity(ctx, domain.UserIdentity{Provider: "acme", Subject: "sub-1", UserID: "u2", CreatedAt: time.Now()})
+	if err != domain.ErrIdentityLinked {
+		t.Fatalf("Expected ErrIdentityLinked linking to another user, got %v", err)
+	}
+	if u, _ = repo.FindUserByIdentity(ctx, "acme", "sub-1"); u.ID != "u1" {
+		t.Errorf("Identity moved to %q", u.ID)
+	}
+
+	if u, err = repo.FindUserByIdentityEmail(ctx, "NEW"); err != nil || u.ID != "u1" {
+		t.Errorf("Expected u1 by identity email ignoring case, got %q (%v)", u.ID, err)
+	}
+	for _, email := range []string{"old", ""} {
+		if _, err = repo.FindUserByIdentityEmail(ctx, email); err != domain.ErrUserNotFound {
+			t.Errorf("Expected ErrUserNotFound for email %q, got %v", email, err)
+		}
+	}
+}
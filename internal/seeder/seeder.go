@@ -34,13 +34,13 @@ func SeedAll(ctx context.Context, repo domain.ListingStore) {
 
 // EnsureSeeded checks if the database is empty, and if so, seeds it.
 func EnsureSeeded(ctx context.Context, repo domain.ListingStore) {
-	listings, _, err := repo.FindAll(ctx, "", "", "", 0.0, 0.0, 0.0, "", "", true, 1, 0)
+	page, err := repo.Search(ctx, domain.ListingQuery{IncludeInactive: true, Limit: 1})
 	if err != nil {
 		slog.Error("Failed to check existing listings", "error", err)
 		return
 	}
 
-	if len(listings) == 0 {
+	if len(page.Listings) == 0 {
 		slog.Info("Database empty. Seeding data...")
 		SeedAll(ctx, repo)
 	}
@@ -18,7 +18,8 @@ func TestSeedAll(t *testing.T) {
 	seeder.SeedAll(context.Background(), repo)
 
 	// Verify some listings were saved
-	listings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", true, 100, 0)
+	page, err := repo.Search(context.Background(), domain.ListingQuery{IncludeInactive: true, Limit: 100})
+	listings := page.Listings
 	require.NoError(t, err)
 	assert.Greater(t, len(listings), 0, "Expected listings to be seeded")
 }
@@ -30,7 +31,8 @@ func TestEnsureSeeded_Empty(t *testing.T) {
 	seeder.EnsureSeeded(context.Background(), repo)
 
 	// Verify listings were saved
-	listings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", true, 1, 0)
+	page, err := repo.Search(context.Background(), domain.ListingQuery{IncludeInactive: true, Limit: 1})
+	listings := page.Listings
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(listings))
 }
@@ -45,11 +47,12 @@ func TestEnsureSeeded_NotEmpty(t *testing.T) {
 	seeder.EnsureSeeded(context.Background(), repo)
 
 	// Verify NO additional listings were saved (still just 1)
-	listings, _, err := repo.FindAll(context.Background(), "", "", "", 0.0, 0.0, 0.0, "", "", true, 100, 0)
+	page, err := repo.Search(context.Background(), domain.ListingQuery{IncludeInactive: true, Limit: 100})
+	listings := page.Listings
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(listings))
 }
 
-func TestEnsureSeeded_FindAllError(t *testing.T) {
+func TestEnsureSeeded_SearchError(t *testing.T) {
 	// Skipping as forcing error with SQLite is hard without mocks.
 }
@@ -2,6 +2,7 @@ package seeder
 
 import (
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"runtime"
 	"sync"
@@ -12,6 +13,23 @@ import (
 
 var origins []string
 
+// stressCityCentres are the cities stress listings are placed in. Each listing is
+// scattered within stressScatterMiles of its city's centre so radius searches have
+// realistic neighbours.
+var stressCityCentres = map[string][2]float64{
+	"Lagos":        {6.5244, 3.3792},
+	"Abuja":        {9.0765, 7.3986},
+	"Accra":        {5.6037, -0.1870},
+	"Dakar":        {14.7167, -17.4677},
+	"Nairobi":      {-1.2921, 36.8219},
+	"Johannesburg": {-26.2041, 28.0473},
+	"London":       {51.5074, -0.1278},
+	"New York":     {40.7128, -74.0060},
+	"Toronto":      {43.6532, -79.3832},
+}
+
+const stressScatterMiles = 30
+
 func init() {
 	for k := range domain.ValidOrigins {
 		origins = append(origins, k)
@@ -44,7 +62,10 @@ func GenerateStressListings(count int) []domain.Listing {
 		domain.Event,
 	}
 
-	cities := []string{"Lagos", "Abuja", "Accra", "Dakar", "Nairobi", "Johannesburg", "London", "New York", "Toronto"}
+	cities := make([]string, 0, len(stressCityCentres))
+	for city := range stressCityCentres {
+		cities = append(cities, city)
+	}
 
 	now := time.Now()
 
@@ -77,6 +98,9 @@ func generateSingleStressListing(i, workerID int, now time.Time, categories []do
 	// #nosec G404 - weak random is acceptable for non-security-critical stress testing
 	city := cities[rand.IntN(len(cities))]
 
+	// #nosec G404 - weak random is acceptable for non-security-critical stress testing
+	lat, lng := scatterAround(stressCityCentres[city], stressScatterMiles*math.Sqrt(rand.Float64()), 2*math.Pi*rand.Float64())
+
 	l := domain.Listing{
 		ID: fmt.Sprintf("lst_%d_%d_%d", now.UnixNano(), workerID, i),
 		// #nosec G404 - weak random is acceptable for non-security-critical stress testing
@@ -86,6 +110,8 @@ func generateSingleStressListing(i, workerID int, now time.Time, categories []do
 		Title:        fmt.Sprintf("Stress Test Listing %d", i),
 		Description:  "This is an automated listing generated for stress testing purposes.",
 		City:         city,
+		Latitude:     lat,
+		Longitude:    lng,
 		ContactEmail: fmt.Sprintf("test%d@example.com", i),
 		IsActive:     true,
 		Status:       domain.ListingStatusApproved,
@@ -118,3 +144,11 @@ func generateSingleStressListing(i, workerID int, now time.Time, categories []do
 
 	return l
 }
+
+// scatterAround returns the point miles from centre in the direction of bearing
+// (radians clockwise from north), close enough to flat over a city's extent.
+func scatterAround(centre [2]float64, miles, bearing float64) (float64, float64) {
+	dLat := miles * math.Cos(bearing) / domain.EarthRadiusMiles * 180 / math.Pi
+	dLng := miles * math.Sin(bearing) / (domain.EarthRadiusMiles * math.Cos(centre[0]*math.Pi/180)) * 180 / math.Pi
+	return centre[0] + dLat, centre[1] + dLng
+}
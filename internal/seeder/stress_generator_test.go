@@ -19,5 +19,8 @@ func TestGenerateStressListings(t *testing.T) {
 		if err := l.Validate(); err != nil {
 			t.Errorf("listing %d failed validation: %v\n%+v", i, err, l)
 		}
+		if l.Latitude == 0 && l.Longitude == 0 {
+			t.Errorf("listing %d in %s has no coordinates", i, l.City)
+		}
 	}
 }
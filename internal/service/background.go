@@ -12,15 +12,30 @@ type BackgroundService struct {
 	Repo           domain.ListingExpirer
 	Scraper        *ScraperJob
 	RatingEnricher *RatingEnricherJob
-	Interval       time.Duration
+	ExpiryReminder *ExpiryReminderJob
+	// Timezones is optional. When set, listings saved without a time zone are
+	// backfilled once on start.
+	Timezones domain.ListingTimezoneStore
+	// Notifications is optional. When set, owners are told their listings expired and
+	// queued mail is delivered every MailInterval.
+	Notifications *NotificationService
+	// GeocodeCache is optional. When set, its expired entries are pruned and its hit
+	// and miss counts reported every Interval.
+	GeocodeCache *CachingGeocodingService
+	Interval     time.Duration
+	MailInterval time.Duration
 }
 
+// outboxBatchSize bounds how many queued emails one delivery tick sends.
+const outboxBatchSize = 50
+
 func NewBackgroundService(repo domain.ListingExpirer, scraper *ScraperJob, ratingEnricher *RatingEnricherJob) *BackgroundService {
 	return &BackgroundService{
 		Repo:           repo,
 		Scraper:        scraper,
 		RatingEnricher: ratingEnricher,
 		Interval:       1 * time.Hour, // Default
+		MailInterval:   1 * time.Minute,
 	}
 }
 
@@ -29,20 +44,29 @@ func NewBackgroundService(repo domain.ListingExpirer, scraper *ScraperJob, ratin
 func (s *BackgroundService) StartTicker(ctx context.Context) {
 	ticker := time.NewTicker(s.Interval)
 	defer ticker.Stop()
+	mailTicker := time.NewTicker(s.MailInterval)
+	defer mailTicker.Stop()
 
 	slog.Info("[Background] Service started. Ticking every 1 hour.")
 
 	// Run once immediately on start
+	s.backfillTimezones(ctx)
+	s.remindExpiring(ctx)
 	s.expireListings(ctx)
 	s.enrichListings(ctx)
 	s.enrichRatings(ctx)
+	s.deliverMail(ctx)
 
 	for {
 		select {
 		case <-ticker.C:
+			s.remindExpiring(ctx)
 			s.expireListings(ctx)
 			s.enrichListings(ctx)
 			s.enrichRatings(ctx)
+			s.maintainGeocodeCache(ctx)
+		case <-mailTicker.C:
+			s.deliverMail(ctx)
 		case <-ctx.Done():
 			slog.Info("[Background] Service stopping...")
 			return
@@ -50,14 +74,82 @@ func (s *BackgroundService) StartTicker(ctx context.Context) {
 	}
 }
 
+func (s *BackgroundService) backfillTimezones(ctx context.Context) {
+	if s.Timezones == nil {
+		return
+	}
+	count, err := BackfillTimezones(ctx, s.Timezones)
+	if err != nil {
+		slog.Error("[Background] Error backfilling listing time zones", "error", err)
+	}
+	if count > 0 {
+		slog.Info("[Background] Backfilled listing time zones", "count", count)
+	}
+}
+
+func (s *BackgroundService) remindExpiring(ctx context.Context) {
+	if s.ExpiryReminder == nil {
+		return
+	}
+	count, err := s.ExpiryReminder.SendReminders(ctx)
+	if err != nil {
+		slog.Error("[Background] Error sending expiry reminders", "error", err)
+		return
+	}
+	if count > 0 {
+		slog.Info("[Background] Sent expiry reminders", "count", count)
+	}
+}
+
 func (s *BackgroundService) expireListings(ctx context.Context) {
-	count, err := s.Repo.ExpireListings(ctx)
+	expired, err := s.Repo.ExpireListings(ctx)
 	if err != nil {
 		slog.Error("[Background] Error expiring listings", "error", err)
 		return
 	}
+	if len(expired) > 0 {
+		slog.Info("[Background] Expired listings", "count", len(expired))
+	}
+	if s.Notifications == nil {
+		return
+	}
+	for _, l := range expired {
+		err := s.Notifications.NotifyUser(ctx, domain.NotificationListingExpired, l.OwnerID, map[string]interface{}{
+			"ListingID":    l.ID,
+			"ListingTitle": l.Title,
+			"ListingType":  string(l.Type),
+		})
+		if err != nil {
+			slog.Error("[Background] Error queuing expiry notification", "id", l.ID, "error", err)
+		}
+	}
+}
+
+func (s *BackgroundService) deliverMail(ctx context.Context) {
+	if s.Notifications == nil {
+		return
+	}
+	sent, err := s.Notifications.DeliverDue(ctx, outboxBatchSize)
+	if err != nil {
+		slog.Error("[Background] Error delivering email", "error", err)
+	}
+	if sent > 0 {
+		slog.Info("[Background] Delivered email", "count", sent)
+	}
+}
+
+func (s *BackgroundService) maintainGeocodeCache(ctx context.Context) {
+	if s.GeocodeCache == nil {
+		return
+	}
+	s.GeocodeCache.ReportMetrics(ctx)
+	count, err := s.GeocodeCache.Prune(ctx)
+	if err != nil {
+		slog.Error("[Background] Error pruning geocode cache", "error", err)
+		return
+	}
 	if count > 0 {
-		slog.Info("[Background] Expired listings", "count", count)
+		slog.Info("[Background] Pruned geocode cache", "count", count)
 	}
 }
 
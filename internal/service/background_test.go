@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jadecobra/agbalumo/internal/repository/sqlite"
 	"github.com/jadecobra/agbalumo/internal/testutil"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
@@ -81,3 +83,34 @@ func TestBackgroundService_StartTicker_Cancels(t *testing.T) {
 		t.Fatal("StartTicker did not exit after context cancellation")
 	}
 }
+
+func TestBackgroundService_NotifiesOwnersOfExpiredListings(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{}
+	notifications, store := newTestNotificationService(t, transport)
+	repo := store.(*sqlite.SQLiteRepository)
+	ctx := context.Background()
+	_ = repo.SaveUser(ctx, domain.User{ID: "owner1", Name: "Ada", Email: "ada@example.com"})
+	_ = repo.Save(ctx, domain.Listing{
+		ID:          "exp1",
+		Title:       "Lagos Night Market",
+		OwnerID:     "owner1",
+		Status:      domain.ListingStatusApproved,
+		IsActive:    true,
+		Deadline:    time.Now().Add(-24 * time.Hour),
+		OwnerOrigin: "Nigeria",
+		Type:        domain.Request,
+	})
+
+	service := NewBackgroundService(repo, nil, nil)
+	service.Notifications = notifications
+	service.expireListings(ctx)
+	service.deliverMail(ctx)
+
+	if len(transport.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(transport.sent))
+	}
+	if e := transport.sent[0]; e.To != "ada@example.com" || !strings.Contains(e.Subject, "Lagos Night Market") {
+		t.Errorf("unexpected email: %+v", e)
+	}
+}
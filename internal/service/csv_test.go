@@ -176,6 +176,10 @@ func (m *mockGeocodingService) Geocode(ctx context.Context, address string) (flo
 	return 0, 0, nil
 }
 
+func (m *mockGeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	return "", nil
+}
+
 func FuzzParseAndImport(f *testing.F) {
 	svc := NewCSVService()
 	ctx := context.Background()
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ExpiryReminderJob warns owners that a listing is about to expire. Requests get a
+// signed link that extends their deadline in one click.
+type ExpiryReminderJob struct {
+	repo          domain.ListingExpirer
+	notifications domain.NotificationService
+	links         *ExtensionLinks
+	now           func() time.Time
+	lead          time.Duration
+}
+
+func NewExpiryReminderJob(repo domain.ListingExpirer, notifications domain.NotificationService, links *ExtensionLinks, lead time.Duration) *ExpiryReminderJob {
+	return &ExpiryReminderJob{
+		repo:          repo,
+		notifications: notifications,
+		links:         links,
+		now:           time.Now,
+		lead:          lead,
+	}
+}
+
+// SendReminders queues a reminder for each listing expiring within the lead time and
+// returns how many were queued. Each expiry date is reminded about once.
+func (j *ExpiryReminderJob) SendReminders(ctx context.Context) (int, error) {
+	now := j.now()
+	listings, err := j.repo.ListExpiringListings(ctx, now, now.Add(j.lead))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, l := range listings {
+		data := map[string]interface{}{
+			"ListingID":    l.ID,
+			"ListingTitle": l.Title,
+			"ListingType":  string(l.Type),
+			"ExpiresOn":    l.ExpiresAt().Format("Monday, 2 January 2006"),
+		}
+		if l.Extendable() && j.links != nil {
+			data["ExtendURL"] = j.links.URL(l)
+		}
+		if err := j.notifications.NotifyUser(ctx, domain.NotificationListingExpiring, l.OwnerID, data); err != nil {
+			slog.Warn("[ExpiryReminderJob] Failed to queue reminder", slog.String("id", l.ID), slog.Any("error", err))
+			continue
+		}
+		if err := j.repo.MarkExpiryReminderSent(ctx, l.ID, now); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
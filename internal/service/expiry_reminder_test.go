@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryReminderJob_SendReminders(t *testing.T) {
+	t.Parallel()
+	repo := testutil.SetupTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "req", Title: "Need a tailor", OwnerID: "u1", Type: domain.Request, CreatedAt: now, Deadline: now.Add(24 * time.Hour), IsActive: true}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "evt", Title: "Owambe", OwnerID: "u1", Type: domain.Event, EventStart: now, EventEnd: now.Add(48 * time.Hour), IsActive: true}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "later", Title: "Later", OwnerID: "u1", Type: domain.Request, CreatedAt: now, Deadline: now.Add(20 * 24 * time.Hour), IsActive: true}))
+
+	notifications := &testutil.MockNotificationService{}
+	links := NewExtensionLinks("secret", "https://agbalumo.test")
+	job := NewExpiryReminderJob(repo, notifications, links, 3*24*time.Hour)
+
+	count, err := job.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, notifications.Sent, 2)
+
+	byID := map[string]testutil.SentNotification{}
+	for _, n := range notifications.Sent {
+		assert.Equal(t, domain.NotificationListingExpiring, n.Kind)
+		assert.Equal(t, "u1", n.To)
+		byID[n.Data["ListingID"].(string)] = n
+	}
+	assert.Contains(t, byID["req"].Data["ExtendURL"], "https://agbalumo.test/listings/req/extend?token=")
+	assert.NotContains(t, byID["evt"].Data, "ExtendURL")
+
+	// Each expiry is reminded about once.
+	count, err = job.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestExpiryReminderJob_RendersReminderEmail(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{}
+	notifications, store := newTestNotificationService(t, transport)
+	repo := testutil.SetupTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, store.SaveUser(ctx, domain.User{ID: "u1", Name: "Ada", Email: "ada@example.com"}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "req", Title: "Need a tailor", OwnerID: "u1", Type: domain.Request, CreatedAt: now, Deadline: now.Add(24 * time.Hour), IsActive: true}))
+
+	job := NewExpiryReminderJob(repo, notifications, NewExtensionLinks("secret", "https://agbalumo.test"), 3*24*time.Hour)
+	_, err := job.SendReminders(ctx)
+	require.NoError(t, err)
+	_, err = notifications.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+
+	require.Len(t, transport.sent, 1)
+	assert.Equal(t, "Your listing Need a tailor expires soon", transport.sent[0].Subject)
+	assert.Contains(t, transport.sent[0].Text, "https://agbalumo.test/listings/req/extend?token=")
+	assert.Contains(t, transport.sent[0].HTML, "Extend listing")
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// bundledGazetteer holds a small set of city centres and ZIP centroids so radius search
+// works with no network and no configured gazetteer.
+//
+//go:embed gazetteer/*.tsv
+var bundledGazetteer embed.FS
+
+// GazetteerPlace is a named point in a Gazetteer: a city centre or a postal code centroid.
+type GazetteerPlace struct {
+	Name string
+	// State is the first-level subdivision code, such as "TX" in the US.
+	State string
+	// Country is the ISO 3166-1 alpha-2 code.
+	Country    string
+	PostalCode string
+	Latitude   float64
+	Longitude  float64
+	Population int
+}
+
+// Gazetteer is an in-memory index of places loaded from GeoNames-style tab separated
+// files. Both the GeoNames cities format (19 columns) and its postal code format (12
+// columns) are read; lines starting with # are comments.
+type Gazetteer struct {
+	byName   map[string][]int
+	byPostal map[string]int
+	places   []GazetteerPlace
+}
+
+// NewGazetteer creates an empty Gazetteer.
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{byName: map[string][]int{}, byPostal: map[string]int{}}
+}
+
+// LoadGazetteer loads the files at paths, or the bundled gazetteer when there are none.
+func LoadGazetteer(paths ...string) (*Gazetteer, error) {
+	g := NewGazetteer()
+	if len(paths) == 0 {
+		entries, err := bundledGazetteer.ReadDir("gazetteer")
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			f, err := bundledGazetteer.Open("gazetteer/" + e.Name())
+			if err != nil {
+				return nil, err
+			}
+			err = g.Load(f)
+			_ = f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("bundled gazetteer %s: %w", e.Name(), err)
+			}
+		}
+		return g, nil
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path) // #nosec G304 - Path comes from operator configuration
+		if err != nil {
+			return nil, err
+		}
+		err = g.Load(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer %s: %w", path, err)
+		}
+	}
+	return g, nil
+}
+
+// Len returns the number of places loaded.
+func (g *Gazetteer) Len() int {
+	return len(g.places)
+}
+
+// Load adds the places read from r.
+func (g *Gazetteer) Load(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := sc.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cols := strings.Split(text, "\t")
+		var err error
+		switch {
+		case len(cols) >= 19:
+			err = g.addCity(cols)
+		case len(cols) >= 11:
+			err = g.addPostalCode(cols)
+		default:
+			err = fmt.Errorf("expected 12 or 19 columns, got %d", len(cols))
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return sc.Err()
+}
+
+// addCity adds a row of the GeoNames cities format: geonameid, name, asciiname,
+// alternatenames, latitude, longitude, feature class and code, country code, cc2,
+// admin1-4 codes, population, elevation, dem, timezone and modification date.
+func (g *Gazetteer) addCity(cols []string) error {
+	lat, lng, err := parseLatLng(cols[4], cols[5])
+	if err != nil {
+		return err
+	}
+	pop, _ := strconv.Atoi(cols[14])
+	idx := len(g.places)
+	g.places = append(g.places, GazetteerPlace{
+		Name:       cols[1],
+		State:      cols[10],
+		Country:    cols[8],
+		Latitude:   lat,
+		Longitude:  lng,
+		Population: pop,
+	})
+
+	names := append([]string{cols[1], cols[2]}, strings.Split(cols[3], ",")...)
+	seen := map[string]bool{}
+	for _, n := range names {
+		key := normalizePlaceName(n)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		g.byName[key] = append(g.byName[key], idx)
+	}
+	return nil
+}
+
+// addPostalCode adds a row of the GeoNames postal code format: country code, postal
+// code, place name, admin1-3 names and codes, latitude, longitude and accuracy.
+func (g *Gazetteer) addPostalCode(cols []string) error {
+	lat, lng, err := parseLatLng(cols[9], cols[10])
+	if err != nil {
+		return err
+	}
+	g.byPostal[normalizePostalCode(cols[1])] = len(g.places)
+	g.places = append(g.places, GazetteerPlace{
+		Name:       cols[2],
+		State:      cols[4],
+		Country:    cols[0],
+		PostalCode: cols[1],
+		Latitude:   lat,
+		Longitude:  lng,
+	})
+	return nil
+}
+
+func parseLatLng(latStr, lngStr string) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude %q", latStr)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude %q", lngStr)
+	}
+	return lat, lng, nil
+}
+
+// Lookup finds the place an address or search term names: a postal code when it has
+// one the gazetteer knows, otherwise a city, matched exactly or, failing that, by the
+// closest spelling. State and country names or codes elsewhere in the address pick
+// between cities that share a name; the most populous wins otherwise.
+func (g *Gazetteer) Lookup(address string) (GazetteerPlace, bool) {
+	parts := splitAddress(address)
+	if len(parts) == 0 {
+		return GazetteerPlace{}, false
+	}
+	if p, ok := g.lookupPostalCode(parts); ok {
+		return p, true
+	}
+
+	q := qualifiersOf(parts)
+	candidates := cityCandidates(parts)
+	for _, name := range candidates {
+		if idxs, ok := g.byName[name]; ok {
+			return g.best(idxs, q), true
+		}
+	}
+	for _, name := range candidates {
+		if idxs, ok := g.closest(name); ok {
+			return g.best(idxs, q), true
+		}
+	}
+	return GazetteerPlace{}, false
+}
+
+// Nearest returns the place closest to a point, if one lies within maxMiles of it.
+func (g *Gazetteer) Nearest(lat, lng, maxMiles float64) (GazetteerPlace, bool) {
+	best, bestDist := -1, maxMiles
+	for i, p := range g.places {
+		if d := domain.DistanceMiles(lat, lng, p.Latitude, p.Longitude); d <= bestDist {
+			best, bestDist = i, d
+		}
+	}
+	if best < 0 {
+		return GazetteerPlace{}, false
+	}
+	return g.places[best], true
+}
+
+// Label names the place for display: "Katy, TX" in the US, otherwise just its name.
+func (p GazetteerPlace) Label() string {
+	if p.Country == "US" && p.State != "" {
+		return p.Name + ", " + p.State
+	}
+	return p.Name
+}
+
+// lookupPostalCode matches a whole address part or any token with a digit in it, other
+// than the street number leading the address.
+func (g *Gazetteer) lookupPostalCode(parts [][]string) (GazetteerPlace, bool) {
+	for i, tokens := range parts {
+		if idx, ok := g.byPostal[normalizePostalCode(strings.Join(tokens, ""))]; ok {
+			return g.places[idx], true
+		}
+		for j, tok := range tokens {
+			if (i == 0 && j == 0 && len(tokens) > 1) || !strings.ContainsAny(tok, "0123456789") {
+				continue
+			}
+			if idx, ok := g.byPostal[normalizePostalCode(tok)]; ok {
+				return g.places[idx], true
+			}
+		}
+	}
+	return GazetteerPlace{}, false
+}
+
+// closest returns the places whose name is nearest to name within a small number of
+// edits that grows with the name's length. Short names are only matched exactly.
+func (g *Gazetteer) closest(name string) ([]int, bool) {
+	maxDist := len([]rune(name)) / 5
+	if maxDist == 0 {
+		return nil, false
+	}
+	if maxDist > 2 {
+		maxDist = 2
+	}
+	best, bestDist := []int(nil), maxDist+1
+	for key, idxs := range g.byName {
+		if d := len(key) - len(name); d > maxDist || -d > maxDist {
+			continue
+		}
+		d := editDistance(name, key)
+		if d > maxDist {
+			continue
+		}
+		if d < bestDist {
+			best, bestDist = append([]int(nil), idxs...), d
+		} else if d == bestDist {
+			best = append(best, idxs...)
+		}
+	}
+	sort.Ints(best) // map order is random; keep ties deterministic
+	return best, best != nil
+}
+
+// best picks the place agreeing with most of the address's qualifiers, then the most
+// populous.
+func (g *Gazetteer) best(idxs []int, q placeQualifiers) GazetteerPlace {
+	bestIdx, bestScore := idxs[0], -1
+	for _, idx := range idxs {
+		p := g.places[idx]
+		score := 0
+		countryMatch := q.countries[p.Country]
+		if countryMatch {
+			score += 2
+		}
+		if q.states[strings.ToUpper(p.State)] && (p.Country == "US" || countryMatch) {
+			score += 4
+		}
+		if score > bestScore || (score == bestScore && p.Population > g.places[bestIdx].Population) {
+			bestIdx, bestScore = idx, score
+		}
+	}
+	return g.places[bestIdx]
+}
+
+// placeQualifiers are the state and country codes an address mentions.
+type placeQualifiers struct {
+	states    map[string]bool
+	countries map[string]bool
+}
+
+func qualifiersOf(parts [][]string) placeQualifiers {
+	q := placeQualifiers{states: map[string]bool{}, countries: map[string]bool{}}
+	for _, tokens := range parts {
+		phrases := append([]string{strings.Join(tokens, " ")}, tokens...)
+		for _, s := range phrases {
+			if code, ok := qualifierState(s); ok {
+				q.states[code] = true
+				q.countries["US"] = true
+			} else if code, ok := countryCode(s); ok {
+				q.countries[code] = true
+			} else if len(s) == 2 || len(s) == 3 {
+				// Another country's subdivision code, such as "FC" or "ON".
+				q.states[strings.ToUpper(s)] = true
+			}
+		}
+	}
+	return q
+}
+
+// cityCandidates returns the names an address might give its city in, most likely
+// first: address parts from the last, each whole and then without postal codes and
+// state or country qualifiers. A leading street part is skipped, as are later parts
+// that are only a state or country; a leading one is kept, since "Washington" and
+// "New York" are cities too.
+func cityCandidates(parts [][]string) []string {
+	var out []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	for i := len(parts) - 1; i >= 0; i-- {
+		tokens := parts[i]
+		if len(parts) > 1 && i == 0 && strings.ContainsAny(tokens[0], "0123456789") {
+			continue
+		}
+		whole := strings.Join(tokens, " ")
+		if i > 0 && isQualifier(whole) {
+			continue
+		}
+		add(whole)
+
+		var kept []string
+		for _, tok := range tokens {
+			if !strings.ContainsAny(tok, "0123456789") {
+				kept = append(kept, tok)
+			}
+		}
+		for len(kept) > 1 && isQualifier(kept[len(kept)-1]) {
+			kept = kept[:len(kept)-1]
+		}
+		add(strings.Join(kept, " "))
+	}
+	return out
+}
+
+func isQualifier(s string) bool {
+	if _, ok := qualifierState(s); ok {
+		return true
+	}
+	_, ok := countryCode(s)
+	return ok
+}
+
+// qualifierState reads s as a US state code or full name.
+func qualifierState(s string) (string, bool) {
+	if len(s) == 3 {
+		return "", false
+	}
+	return domain.USStateCode(s)
+}
+
+// countryNames maps lower-cased country names to ISO 3166-1 alpha-2 codes.
+var countryNames = map[string]string{
+	"usa": "US", "united states": "US", "united states of america": "US", "america": "US",
+	"canada": "CA", "united kingdom": "GB", "uk": "GB", "england": "GB", "scotland": "GB", "wales": "GB",
+	"great britain": "GB", "ireland": "IE", "france": "FR", "germany": "DE",
+	"nigeria": "NG", "ghana": "GH", "senegal": "SN", "togo": "TG", "benin": "BJ",
+	"cote d'ivoire": "CI", "côte d'ivoire": "CI", "ivory coast": "CI", "sierra leone": "SL",
+	"liberia": "LR", "mali": "ML", "guinea": "GN", "gambia": "GM", "the gambia": "GM",
+	"kenya": "KE", "ethiopia": "ET", "uganda": "UG", "tanzania": "TZ", "rwanda": "RW",
+	"south africa": "ZA", "zimbabwe": "ZW", "zambia": "ZM", "angola": "AO",
+	"dr congo": "CD", "drc": "CD", "democratic republic of the congo": "CD", "cameroon": "CM",
+	"egypt": "EG", "morocco": "MA", "sudan": "SD", "somalia": "SO", "eritrea": "ER",
+}
+
+// countryCode reads s as a country name, or as a two-letter code that is not also a US
+// state code.
+func countryCode(s string) (string, bool) {
+	if code, ok := countryNames[s]; ok {
+		return code, true
+	}
+	if len(s) == 2 {
+		if _, isState := domain.USStateCode(s); !isState {
+			for _, code := range countryNames {
+				if strings.EqualFold(code, s) {
+					return code, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// splitAddress splits an address into comma separated parts of normalized tokens.
+func splitAddress(address string) [][]string {
+	var parts [][]string
+	for _, part := range strings.Split(address, ",") {
+		if tokens := strings.Fields(normalizePlaceName(part)); len(tokens) > 0 {
+			parts = append(parts, tokens)
+		}
+	}
+	return parts
+}
+
+// normalizePlaceName lower-cases a name and drops periods, so "St. Louis" and
+// "st louis" compare equal.
+func normalizePlaceName(s string) string {
+	s = strings.ToLower(strings.ReplaceAll(s, ".", ""))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func normalizePostalCode(s string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// gazetteerReverseMiles is how far from a point the nearest gazetteer place may be and
+// still name the area around it.
+const gazetteerReverseMiles = 30
+
+// GazetteerGeocodingService implements domain.GeocodingService from a Gazetteer, with
+// no network access. Results are city centres or postal code centroids, precise enough
+// for radius search but not for placing a street address.
+type GazetteerGeocodingService struct {
+	Gazetteer *Gazetteer
+}
+
+// NewGazetteerGeocodingService creates a geocoder backed by g.
+func NewGazetteerGeocodingService(g *Gazetteer) *GazetteerGeocodingService {
+	return &GazetteerGeocodingService{Gazetteer: g}
+}
+
+// GetCity returns the name of the place address is in.
+func (s *GazetteerGeocodingService) GetCity(_ context.Context, address string) (string, error) {
+	p, ok := s.Gazetteer.Lookup(address)
+	if !ok {
+		return "", domain.ErrLocationNotFound
+	}
+	return p.Name, nil
+}
+
+// Geocode returns the coordinates of the place address is in.
+func (s *GazetteerGeocodingService) Geocode(_ context.Context, address string) (float64, float64, error) {
+	p, ok := s.Gazetteer.Lookup(address)
+	if !ok {
+		return 0, 0, domain.ErrLocationNotFound
+	}
+	return p.Latitude, p.Longitude, nil
+}
+
+// ReverseGeocode names the nearest place to a point within a few tens of miles.
+func (s *GazetteerGeocodingService) ReverseGeocode(_ context.Context, lat, lng float64) (string, error) {
+	p, ok := s.Gazetteer.Nearest(lat, lng, gazetteerReverseMiles)
+	if !ok {
+		return "", domain.ErrLocationNotFound
+	}
+	return p.Label(), nil
+}
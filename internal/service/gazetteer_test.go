@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGazetteer_LookupBundled(t *testing.T) {
+	g, err := LoadGazetteer()
+	require.NoError(t, err)
+	require.Positive(t, g.Len())
+
+	tests := []struct {
+		address string
+		name    string
+		state   string
+		country string
+	}{
+		{address: "Houston", name: "Houston", state: "TX", country: "US"},
+		{address: "houston, tx", name: "Houston", state: "TX", country: "US"},
+		{address: "123 Main St, Dallas, Texas 75201", name: "Dallas", state: "TX", country: "US"},
+		{address: "77036", name: "Houston", state: "TX", country: "US"},
+		{address: "9000 Bellaire Blvd, Houston, TX 77036", name: "Houston", state: "TX", country: "US"},
+		{address: "NYC", name: "New York", state: "NY", country: "US"},
+		{address: "New York, NY", name: "New York", state: "NY", country: "US"},
+		{address: "St. Louis, MO", name: "Saint Louis", state: "MO", country: "US"},
+		{address: "Houstn", name: "Houston", state: "TX", country: "US"},
+		{address: "Philadelpia, PA", name: "Philadelphia", state: "PA", country: "US"},
+		{address: "Arlington, VA", name: "Arlington", state: "VA", country: "US"},
+		{address: "Arlington, Texas", name: "Arlington", state: "TX", country: "US"},
+		{address: "Birmingham, AL", name: "Birmingham", state: "AL", country: "US"},
+		{address: "Birmingham, UK", name: "Birmingham", country: "GB"},
+		{address: "Lagos, Nigeria", name: "Lagos", country: "NG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			p, ok := g.Lookup(tt.address)
+			require.True(t, ok)
+			assert.Equal(t, tt.name, p.Name)
+			assert.Equal(t, tt.country, p.Country)
+			if tt.state != "" {
+				assert.Equal(t, tt.state, p.State)
+			}
+		})
+	}
+
+	for _, address := range []string{"", "Atlantis", "Atlantica", "Dal", "99999"} {
+		_, ok := g.Lookup(address)
+		assert.False(t, ok, "expected no match for %q", address)
+	}
+}
+
+func TestGazetteer_LoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	cities := filepath.Join(dir, "cities.tsv")
+	row := []string{"1", "Ikeja", "Ikeja", "Ikeja GRA", "6.6018", "3.3515", "P", "PPLA", "NG", "", "05", "", "", "", "313196", "", "", "Africa/Lagos", ""}
+	require.NoError(t, os.WriteFile(cities, []byte("# comment\n"+strings.Join(row, "\t")+"\n"), 0o600))
+	zips := filepath.Join(dir, "zips.tsv")
+	zip := []string{"CA", "M5V 2T6", "Toronto", "Ontario", "ON", "", "", "", "", "43.6426", "-79.3871", "6"}
+	require.NoError(t, os.WriteFile(zips, []byte(strings.Join(zip, "\t")+"\n"), 0o600))
+
+	g, err := LoadGazetteer(cities, zips)
+	require.NoError(t, err)
+	assert.Equal(t, 2, g.Len())
+
+	p, ok := g.Lookup("ikeja gra")
+	require.True(t, ok)
+	assert.Equal(t, "Ikeja", p.Name)
+	assert.InDelta(t, 6.6018, p.Latitude, 1e-9)
+
+	p, ok = g.Lookup("m5v2t6")
+	require.True(t, ok)
+	assert.Equal(t, "Toronto", p.Name)
+	assert.Equal(t, "M5V 2T6", p.PostalCode)
+
+	_, ok = g.Lookup("Houston")
+	assert.False(t, ok, "files replace the bundled gazetteer")
+
+	_, err = LoadGazetteer(filepath.Join(dir, "missing.tsv"))
+	assert.Error(t, err)
+}
+
+func TestGazetteer_LoadRejectsMalformedRows(t *testing.T) {
+	err := NewGazetteer().Load(strings.NewReader("Houston\tTX\n"))
+	assert.ErrorContains(t, err, "line 1")
+
+	zip := []string{"US", "77002", "Houston", "Texas", "TX", "", "", "", "", "north", "-95.3", "4"}
+	err = NewGazetteer().Load(strings.NewReader("# header\n" + strings.Join(zip, "\t")))
+	assert.ErrorContains(t, err, "line 2: invalid latitude")
+}
+
+func TestGazetteerGeocodingService(t *testing.T) {
+	g, err := LoadGazetteer()
+	require.NoError(t, err)
+	svc := NewGazetteerGeocodingService(g)
+	ctx := context.Background()
+
+	city, err := svc.GetCity(ctx, "1 Main St, Houston, TX 77002")
+	require.NoError(t, err)
+	assert.Equal(t, "Houston", city)
+
+	lat, lng, err := svc.Geocode(ctx, "Houston, TX")
+	require.NoError(t, err)
+	assert.InDelta(t, 29.76, lat, 0.01)
+	assert.InDelta(t, -95.37, lng, 0.01)
+
+	_, err = svc.GetCity(ctx, "Atlantis")
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+	_, _, err = svc.Geocode(ctx, "Atlantis")
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+
+	label, err := svc.ReverseGeocode(ctx, 29.7858, -95.8245)
+	require.NoError(t, err)
+	assert.Equal(t, "Katy, TX", label)
+	label, err = svc.ReverseGeocode(ctx, 51.5, -0.12)
+	require.NoError(t, err)
+	assert.Equal(t, "London", label, "states are only shown for US places")
+	_, err = svc.ReverseGeocode(ctx, 0.5, -30)
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound, "mid-Atlantic is far from any place")
+}
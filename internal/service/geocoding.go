@@ -7,7 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 
 	"github.com/jadecobra/agbalumo/internal/domain"
 )
@@ -25,14 +25,17 @@ func NewGoogleGeocodingService(apiKey string) *GoogleGeocodingService {
 	}
 }
 
+type addressComponent struct {
+	LongName  string   `json:"long_name"`
+	ShortName string   `json:"short_name"`
+	Types     []string `json:"types"`
+}
+
 type geocodingResponse struct {
 	Status  string `json:"status"`
 	Results []struct {
-		AddressComponents []struct {
-			LongName string   `json:"long_name"`
-			Types    []string `json:"types"`
-		} `json:"address_components"`
-		Geometry struct {
+		AddressComponents []addressComponent `json:"address_components"`
+		Geometry          struct {
 			Location struct {
 				Lat float64 `json:"lat"`
 				Lng float64 `json:"lng"`
@@ -64,32 +67,40 @@ func (s *GoogleGeocodingService) Geocode(ctx context.Context, address string) (f
 	return loc.Lat, loc.Lng, nil
 }
 
+// ReverseGeocode names the locality containing a point, with its state or province
+// when Google reports one, such as "Katy, TX".
+func (s *GoogleGeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	latlng := strconv.FormatFloat(lat, 'f', -1, 64) + "," + strconv.FormatFloat(lng, 'f', -1, 64)
+	res, err := s.query(ctx, url.Values{"latlng": {latlng}})
+	if err != nil {
+		return "", err
+	}
+	if len(res.Results) == 0 {
+		return "", domain.ErrLocationNotFound
+	}
+	components := res.Results[0].AddressComponents
+	city := s.extractCity(components)
+	if city == "" {
+		return "", domain.ErrLocationNotFound
+	}
+	for _, component := range components {
+		if contains(component.Types, "administrative_area_level_1") && component.ShortName != "" {
+			return city + ", " + component.ShortName, nil
+		}
+	}
+	return city, nil
+}
+
 func (s *GoogleGeocodingService) fetchGeocode(ctx context.Context, address string) (*geocodingResponse, error) {
+	return s.query(ctx, url.Values{domain.FieldAddress: {address}})
+}
+
+func (s *GoogleGeocodingService) query(ctx context.Context, params url.Values) (*geocodingResponse, error) {
 	if s.APIKey == "" {
-		// Development Fallback: Hardcoded coordinates for common cities to allow demo without API key.
-		lowCity := strings.ToLower(address)
-		if strings.Contains(lowCity, "dallas") {
-			resp := &geocodingResponse{Status: "OK"}
-			resp.Results = make([]struct {
-				AddressComponents []struct {
-					LongName string   `json:"long_name"`
-					Types    []string `json:"types"`
-				} `json:"address_components"`
-				Geometry struct {
-					Location struct {
-						Lat float64 `json:"lat"`
-						Lng float64 `json:"lng"`
-					} `json:"location"`
-				} `json:"geometry"`
-			}, 1)
-			resp.Results[0].Geometry.Location.Lat = 32.7767
-			resp.Results[0].Geometry.Location.Lng = -96.7970
-			return resp, nil
-		}
 		return nil, fmt.Errorf("google maps api key is not configured")
 	}
 
-	apiURL, err := s.buildURL(address)
+	apiURL, err := s.buildURL(params)
 	if err != nil {
 		return nil, err
 	}
@@ -114,14 +125,16 @@ func (s *GoogleGeocodingService) fetchGeocode(ctx context.Context, address strin
 	return &res, nil
 }
 
-func (s *GoogleGeocodingService) buildURL(address string) (string, error) {
+func (s *GoogleGeocodingService) buildURL(params url.Values) (string, error) {
 	baseURL, err := url.Parse(s.BaseURL)
 	if err != nil {
 		return "", fmt.Errorf("invalid geocoding base url: %w", err)
 	}
 
 	q := baseURL.Query()
-	q.Set(domain.FieldAddress, address)
+	for k, v := range params {
+		q[k] = v
+	}
 	q.Set("key", s.APIKey)
 	baseURL.RawQuery = q.Encode()
 	return baseURL.String(), nil
@@ -147,10 +160,7 @@ func (s *GoogleGeocodingService) fetch(ctx context.Context, apiURL string) ([]by
 	return io.ReadAll(resp.Body)
 }
 
-func (s *GoogleGeocodingService) extractCity(components []struct {
-	LongName string   `json:"long_name"`
-	Types    []string `json:"types"`
-}) string {
+func (s *GoogleGeocodingService) extractCity(components []addressComponent) string {
 	var city string
 	for _, component := range components {
 		types := component.Types
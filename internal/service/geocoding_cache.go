@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// Metric event types reported by CachingGeocodingService.
+const (
+	MetricGeocodeCacheHit  = "geocode_cache_hit"
+	MetricGeocodeCacheMiss = "geocode_cache_miss"
+)
+
+const (
+	// DefaultGeocodeCacheTTL is how long a found location is reused.
+	DefaultGeocodeCacheTTL = 30 * 24 * time.Hour
+	// DefaultGeocodeNegativeTTL is how long a failed lookup is remembered, so an
+	// unknown city or an exhausted quota is not retried on every search.
+	DefaultGeocodeNegativeTTL = 15 * time.Minute
+)
+
+// CachingGeocodingService answers lookups from a GeocodeCacheStore and only asks Next
+// about addresses it has not seen recently. Concurrent lookups of the same address
+// share one call to Next.
+type CachingGeocodingService struct {
+	Next  domain.GeocodingService
+	Store domain.GeocodeCacheStore
+	// Metrics is optional. When set, ReportMetrics records hits and misses with it.
+	Metrics     domain.MetricsService
+	Now         func() time.Time
+	group       singleflight.Group
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	hits        atomic.Int64
+	misses      atomic.Int64
+	// reportedHits and reportedMisses are the counts already passed to Metrics.
+	reportedHits   atomic.Int64
+	reportedMisses atomic.Int64
+}
+
+// NewCachingGeocodingService wraps next with a cache kept in store.
+func NewCachingGeocodingService(next domain.GeocodingService, store domain.GeocodeCacheStore, metrics domain.MetricsService) *CachingGeocodingService {
+	return &CachingGeocodingService{
+		Next:        next,
+		Store:       store,
+		Metrics:     metrics,
+		Now:         time.Now,
+		TTL:         DefaultGeocodeCacheTTL,
+		NegativeTTL: DefaultGeocodeNegativeTTL,
+	}
+}
+
+// GetCity returns the city address is in. Addresses with no known city return
+// domain.ErrLocationNotFound.
+func (s *CachingGeocodingService) GetCity(ctx context.Context, address string) (string, error) {
+	e, err := s.lookup(ctx, normalizeAddress(address), domain.GeocodeLookupCity, func(e *domain.GeocodeCacheEntry) error {
+		city, err := s.Next.GetCity(ctx, address)
+		e.City, e.Found = city, city != ""
+		return err
+	})
+	return e.City, err
+}
+
+// Geocode returns the coordinates of address. Addresses with no known location return
+// domain.ErrLocationNotFound.
+func (s *CachingGeocodingService) Geocode(ctx context.Context, address string) (float64, float64, error) {
+	e, err := s.lookup(ctx, normalizeAddress(address), domain.GeocodeLookupCoords, func(e *domain.GeocodeCacheEntry) error {
+		lat, lng, err := s.Next.Geocode(ctx, address)
+		e.Latitude, e.Longitude, e.Found = lat, lng, lat != 0 || lng != 0
+		return err
+	})
+	return e.Latitude, e.Longitude, err
+}
+
+// ReverseGeocode names the area around a point. Points are cached to two decimal
+// places, within about half a mile, so neighbouring visitors share a lookup.
+func (s *CachingGeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	key := strconv.FormatFloat(lat, 'f', 2, 64) + "," + strconv.FormatFloat(lng, 'f', 2, 64)
+	e, err := s.lookup(ctx, key, domain.GeocodeLookupPlace, func(e *domain.GeocodeCacheEntry) error {
+		label, err := s.Next.ReverseGeocode(ctx, lat, lng)
+		e.City, e.Found = label, label != ""
+		return err
+	})
+	return e.City, err
+}
+
+// GeocodeCacheStats counts lookups answered from the cache and those passed on.
+type GeocodeCacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Stats returns the hit and miss counts since the service was created.
+func (s *CachingGeocodingService) Stats() GeocodeCacheStats {
+	return GeocodeCacheStats{Hits: s.hits.Load(), Misses: s.misses.Load()}
+}
+
+// ReportMetrics records the hits and misses counted since the last report.
+func (s *CachingGeocodingService) ReportMetrics(ctx context.Context) {
+	if s.Metrics == nil {
+		return
+	}
+	hits := s.hits.Load()
+	misses := s.misses.Load()
+	newHits := hits - s.reportedHits.Swap(hits)
+	newMisses := misses - s.reportedMisses.Swap(misses)
+	if newHits == 0 && newMisses == 0 {
+		return
+	}
+	s.Metrics.LogAndSave(ctx, MetricGeocodeCacheHit, float64(newHits), nil)
+	s.Metrics.LogAndSave(ctx, MetricGeocodeCacheMiss, float64(newMisses), nil)
+}
+
+// Prune removes expired entries from the store.
+func (s *CachingGeocodingService) Prune(ctx context.Context) (int64, error) {
+	return s.Store.DeleteExpiredGeocodeCacheEntries(ctx, s.Now())
+}
+
+// lookup returns the cached entry for key, or fills a new one with fetch and stores
+// it. A fetch that errors or finds nothing is stored as a negative entry, unless the
+// request was cancelled; the provider's error is returned this time and
+// domain.ErrLocationNotFound until the entry expires.
+func (s *CachingGeocodingService) lookup(ctx context.Context, key string, kind domain.GeocodeLookup, fetch func(*domain.GeocodeCacheEntry) error) (domain.GeocodeCacheEntry, error) {
+	if key == "" {
+		return domain.GeocodeCacheEntry{}, domain.ErrLocationNotFound
+	}
+
+	e, err := s.Store.GetGeocodeCacheEntry(ctx, key, kind, s.Now())
+	if err == nil {
+		s.hits.Add(1)
+		return cachedResult(e)
+	}
+	if !errors.Is(err, domain.ErrGeocodeNotCached) {
+		slog.Warn("geocode cache read failed", "address", key, "error", err)
+	}
+	s.misses.Add(1)
+
+	v, err, _ := s.group.Do(string(kind)+"|"+key, func() (interface{}, error) {
+		fresh := domain.GeocodeCacheEntry{Address: key, Lookup: kind, CreatedAt: s.Now()}
+		fetchErr := fetch(&fresh)
+		if fetchErr != nil && ctx.Err() != nil {
+			return fresh, fetchErr
+		}
+		if fetchErr != nil {
+			fresh.Found = false
+		}
+		ttl := s.TTL
+		if !fresh.Found {
+			ttl = s.NegativeTTL
+		}
+		fresh.ExpiresAt = fresh.CreatedAt.Add(ttl)
+		if saveErr := s.Store.SaveGeocodeCacheEntry(ctx, fresh); saveErr != nil {
+			slog.Warn("geocode cache write failed", "address", key, "error", saveErr)
+		}
+		return fresh, fetchErr
+	})
+	if err != nil {
+		return domain.GeocodeCacheEntry{}, err
+	}
+	return cachedResult(v.(domain.GeocodeCacheEntry))
+}
+
+// cachedResult returns e, or domain.ErrLocationNotFound for a negative entry.
+func cachedResult(e domain.GeocodeCacheEntry) (domain.GeocodeCacheEntry, error) {
+	if !e.Found {
+		return domain.GeocodeCacheEntry{}, domain.ErrLocationNotFound
+	}
+	return e, nil
+}
+
+// normalizeAddress is the cache key for an address: lower-cased, without periods and
+// with its comma separated parts trimmed, so "Houston,TX" and "houston, tx" share it.
+func normalizeAddress(address string) string {
+	var parts []string
+	for _, tokens := range splitAddress(address) {
+		parts = append(parts, strings.Join(tokens, " "))
+	}
+	return strings.Join(parts, ", ")
+}
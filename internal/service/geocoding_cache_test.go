@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGeocodeCache(t *testing.T, next domain.GeocodingService) (*CachingGeocodingService, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCachingGeocodingService(next, testutil.SetupTestRepository(t), nil)
+	cache.Now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestCachingGeocodingService_ReusesResults(t *testing.T) {
+	t.Parallel()
+	next := &stubGeocoder{city: "Houston", lat: 29.76, lng: -95.37}
+	cache, now := newTestGeocodeCache(t, next)
+	ctx := context.Background()
+
+	for _, address := range []string{"Houston, TX", "houston,tx", "  Houston ,  T.X. "} {
+		lat, lng, err := cache.Geocode(ctx, address)
+		require.NoError(t, err)
+		assert.Equal(t, 29.76, lat)
+		assert.Equal(t, -95.37, lng)
+	}
+	assert.EqualValues(t, 1, next.calls.Load(), "spellings of one address share an entry")
+
+	city, err := cache.GetCity(ctx, "Houston, TX")
+	require.NoError(t, err)
+	assert.Equal(t, "Houston", city)
+	assert.EqualValues(t, 2, next.calls.Load(), "city and coordinates are cached separately")
+	assert.Equal(t, GeocodeCacheStats{Hits: 2, Misses: 2}, cache.Stats())
+
+	*now = now.Add(DefaultGeocodeCacheTTL)
+	_, _, err = cache.Geocode(ctx, "Houston, TX")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, next.calls.Load(), "expired entries are looked up again")
+}
+
+func TestCachingGeocodingService_ReverseGeocodeSharesNearbyPoints(t *testing.T) {
+	t.Parallel()
+	next := &stubGeocoder{label: "Katy, TX"}
+	cache, _ := newTestGeocodeCache(t, next)
+	ctx := context.Background()
+
+	for _, p := range [][2]float64{{29.7858, -95.8245}, {29.7912, -95.8199}} {
+		label, err := cache.ReverseGeocode(ctx, p[0], p[1])
+		require.NoError(t, err)
+		assert.Equal(t, "Katy, TX", label)
+	}
+	assert.EqualValues(t, 1, next.calls.Load(), "points within the same rounded cell share an entry")
+
+	_, err := cache.ReverseGeocode(ctx, 29.76, -95.37)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, next.calls.Load())
+}
+
+func TestCachingGeocodingService_NegativeCaching(t *testing.T) {
+	t.Parallel()
+	quotaErr := errors.New("quota exceeded")
+	next := &stubGeocoder{err: quotaErr}
+	cache, now := newTestGeocodeCache(t, next)
+	ctx := context.Background()
+
+	_, _, err := cache.Geocode(ctx, "Atlantis")
+	assert.ErrorIs(t, err, quotaErr, "the provider's error is returned when it happens")
+	_, _, err = cache.Geocode(ctx, "Atlantis")
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound, "then the failure is remembered")
+	assert.EqualValues(t, 1, next.calls.Load())
+
+	next.err = nil
+	_, err = cache.GetCity(ctx, "Nowhere")
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound, "an empty result is not found")
+	_, err = cache.GetCity(ctx, "Nowhere")
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+	assert.EqualValues(t, 2, next.calls.Load())
+
+	*now = now.Add(DefaultGeocodeNegativeTTL)
+	next.lat, next.lng = 1, 2
+	lat, lng, err := cache.Geocode(ctx, "Atlantis")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, lat)
+	assert.Equal(t, 2.0, lng)
+
+	pruned, err := cache.Prune(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned, "the expired entry for Nowhere is removed")
+}
+
+func TestCachingGeocodingService_CancelledLookupIsNotCached(t *testing.T) {
+	t.Parallel()
+	next := &stubGeocoder{err: context.Canceled}
+	cache, _ := newTestGeocodeCache(t, next)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := cache.Geocode(ctx, "Houston")
+	require.ErrorIs(t, err, context.Canceled)
+
+	next.err, next.lat, next.lng = nil, 29.76, -95.37
+	lat, _, err := cache.Geocode(context.Background(), "Houston")
+	require.NoError(t, err)
+	assert.Equal(t, 29.76, lat)
+}
+
+func TestCachingGeocodingService_DedupesConcurrentLookups(t *testing.T) {
+	t.Parallel()
+	next := &stubGeocoder{lat: 29.76, lng: -95.37, release: make(chan struct{})}
+	cache, _ := newTestGeocodeCache(t, next)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]float64, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _, _ = cache.Geocode(context.Background(), "Houston")
+		}()
+	}
+	require.Eventually(t, func() bool { return cache.Stats().Misses == callers }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond) // let every caller join the lookup in flight
+	close(next.release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, next.calls.Load())
+	for _, lat := range results {
+		assert.Equal(t, 29.76, lat)
+	}
+}
+
+func TestCachingGeocodingService_ReportMetrics(t *testing.T) {
+	t.Parallel()
+	cache, _ := newTestGeocodeCache(t, &stubGeocoder{city: "Dallas"})
+	metrics := &testutil.MockMetricsService{}
+	cache.Metrics = metrics
+	ctx := context.Background()
+
+	for range 3 {
+		_, _ = cache.GetCity(ctx, "Dallas")
+	}
+	metrics.On("LogAndSave", testifyMock.Anything, MetricGeocodeCacheHit, 2.0, testifyMock.Anything).Once()
+	metrics.On("LogAndSave", testifyMock.Anything, MetricGeocodeCacheMiss, 1.0, testifyMock.Anything).Once()
+	cache.ReportMetrics(ctx)
+	metrics.AssertExpectations(t)
+
+	cache.ReportMetrics(ctx) // nothing new to report
+	metrics.AssertNumberOfCalls(t, "LogAndSave", 2)
+}
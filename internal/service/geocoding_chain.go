@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// Geocoding provider names accepted by NewGeocodingService.
+const (
+	GeocodingProviderGoogle    = "google"
+	GeocodingProviderGazetteer = "gazetteer"
+)
+
+// ChainGeocodingService asks each provider in turn, returning the first result found.
+type ChainGeocodingService struct {
+	Providers []domain.GeocodingService
+}
+
+// NewChainGeocodingService creates a geocoder that tries providers in order.
+func NewChainGeocodingService(providers ...domain.GeocodingService) *ChainGeocodingService {
+	return &ChainGeocodingService{Providers: providers}
+}
+
+// GetCity returns the first city a provider finds. When none does, it returns the last
+// provider's error.
+func (s *ChainGeocodingService) GetCity(ctx context.Context, address string) (string, error) {
+	err := error(domain.ErrLocationNotFound)
+	for _, p := range s.Providers {
+		city, pErr := p.GetCity(ctx, address)
+		if pErr == nil && city != "" {
+			return city, nil
+		}
+		if pErr != nil {
+			err = pErr
+		}
+	}
+	return "", err
+}
+
+// Geocode returns the first coordinates a provider finds. When none does, it returns
+// the last provider's error.
+func (s *ChainGeocodingService) Geocode(ctx context.Context, address string) (float64, float64, error) {
+	err := error(domain.ErrLocationNotFound)
+	for _, p := range s.Providers {
+		lat, lng, pErr := p.Geocode(ctx, address)
+		if pErr == nil && (lat != 0 || lng != 0) {
+			return lat, lng, nil
+		}
+		if pErr != nil {
+			err = pErr
+		}
+	}
+	return 0, 0, err
+}
+
+// ReverseGeocode returns the first name a provider gives the area around a point. When
+// none does, it returns the last provider's error.
+func (s *ChainGeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	err := error(domain.ErrLocationNotFound)
+	for _, p := range s.Providers {
+		label, pErr := p.ReverseGeocode(ctx, lat, lng)
+		if pErr == nil && label != "" {
+			return label, nil
+		}
+		if pErr != nil {
+			err = pErr
+		}
+	}
+	return "", err
+}
+
+// NewGeocodingService builds the geocoder for a list of provider names, tried in order.
+// Google is left out when there is no API key, so the default order still works offline.
+// The gazetteer loads the files at gazetteerPaths, or the bundled one when none are given.
+func NewGeocodingService(providers []string, googleAPIKey string, gazetteerPaths []string) (domain.GeocodingService, error) {
+	var chain []domain.GeocodingService
+	for _, name := range providers {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case GeocodingProviderGoogle:
+			if googleAPIKey != "" {
+				chain = append(chain, NewGoogleGeocodingService(googleAPIKey))
+			}
+		case GeocodingProviderGazetteer:
+			g, err := LoadGazetteer(gazetteerPaths...)
+			if err != nil {
+				return nil, fmt.Errorf("load gazetteer: %w", err)
+			}
+			chain = append(chain, NewGazetteerGeocodingService(g))
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown geocoding provider %q", name)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil, fmt.Errorf("no geocoding provider available from %q", strings.Join(providers, ","))
+	case 1:
+		return chain[0], nil
+	}
+	return NewChainGeocodingService(chain...), nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubGeocoder struct {
+	err error
+	// release, when set, holds every call until it is closed.
+	release chan struct{}
+	city    string
+	label   string
+	lat     float64
+	lng     float64
+	calls   atomic.Int32
+}
+
+func (s *stubGeocoder) GetCity(context.Context, string) (string, error) {
+	s.wait()
+	return s.city, s.err
+}
+
+func (s *stubGeocoder) Geocode(context.Context, string) (float64, float64, error) {
+	s.wait()
+	return s.lat, s.lng, s.err
+}
+
+func (s *stubGeocoder) ReverseGeocode(context.Context, float64, float64) (string, error) {
+	s.wait()
+	return s.label, s.err
+}
+
+func (s *stubGeocoder) wait() {
+	s.calls.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+}
+
+func TestChainGeocodingService(t *testing.T) {
+	ctx := context.Background()
+	quotaErr := errors.New("quota exceeded")
+
+	t.Run("falls through to the next provider", func(t *testing.T) {
+		first := &stubGeocoder{err: quotaErr}
+		second := &stubGeocoder{city: "Houston", lat: 29.76, lng: -95.37}
+		third := &stubGeocoder{city: "Dallas", lat: 32.78, lng: -96.80}
+		chain := NewChainGeocodingService(first, second, third)
+
+		city, err := chain.GetCity(ctx, "Houston")
+		require.NoError(t, err)
+		assert.Equal(t, "Houston", city)
+
+		lat, lng, err := chain.Geocode(ctx, "Houston")
+		require.NoError(t, err)
+		assert.Equal(t, 29.76, lat)
+		assert.Equal(t, -95.37, lng)
+		assert.EqualValues(t, 2, first.calls.Load())
+		assert.Zero(t, third.calls.Load())
+
+		second.label = "Katy, TX"
+		label, err := chain.ReverseGeocode(ctx, 29.79, -95.82)
+		require.NoError(t, err)
+		assert.Equal(t, "Katy, TX", label)
+	})
+
+	t.Run("empty results are not a match", func(t *testing.T) {
+		chain := NewChainGeocodingService(&stubGeocoder{}, &stubGeocoder{city: "Austin", lat: 30.27, lng: -97.74})
+		city, err := chain.GetCity(ctx, "Austin")
+		require.NoError(t, err)
+		assert.Equal(t, "Austin", city)
+		lat, _, err := chain.Geocode(ctx, "Austin")
+		require.NoError(t, err)
+		assert.Equal(t, 30.27, lat)
+	})
+
+	t.Run("returns the last error when nothing matches", func(t *testing.T) {
+		chain := NewChainGeocodingService(&stubGeocoder{err: quotaErr}, &stubGeocoder{err: domain.ErrLocationNotFound})
+		_, err := chain.GetCity(ctx, "Atlantis")
+		assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+		_, _, err = NewChainGeocodingService(&stubGeocoder{}).Geocode(ctx, "Atlantis")
+		assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+	})
+}
+
+func TestNewGeocodingService(t *testing.T) {
+	svc, err := NewGeocodingService([]string{"google", "gazetteer"}, "", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &GazetteerGeocodingService{}, svc, "google is skipped without an API key")
+
+	svc, err = NewGeocodingService([]string{" Gazetteer ", "google"}, "key", nil)
+	require.NoError(t, err)
+	chain, ok := svc.(*ChainGeocodingService)
+	require.True(t, ok)
+	require.Len(t, chain.Providers, 2)
+	assert.IsType(t, &GazetteerGeocodingService{}, chain.Providers[0])
+	assert.IsType(t, &GoogleGeocodingService{}, chain.Providers[1])
+
+	_, err = NewGeocodingService([]string{"bing"}, "key", nil)
+	assert.ErrorContains(t, err, `unknown geocoding provider "bing"`)
+
+	_, err = NewGeocodingService([]string{"google"}, "", nil)
+	assert.ErrorContains(t, err, "no geocoding provider")
+
+	_, err = NewGeocodingService([]string{"gazetteer"}, "", []string{"does-not-exist.tsv"})
+	assert.ErrorContains(t, err, "load gazetteer")
+}
@@ -7,7 +7,9 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/jadecobra/agbalumo/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type geocodingTestCase struct {
@@ -123,6 +125,30 @@ func TestGoogleGeocodingService_GetCity(t *testing.T) {
 	}
 }
 
+func TestGoogleGeocodingService_ReverseGeocode(t *testing.T) {
+	var latlng string
+	body := geocodeResponse("OK",
+		comp("Katy", "locality", "political"),
+		`{"long_name": "Texas", "short_name": "TX", "types": ["administrative_area_level_1", "political"]}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		latlng = r.URL.Query().Get("latlng")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	svc := NewGoogleGeocodingService("valid-key")
+	svc.BaseURL = server.URL
+
+	label, err := svc.ReverseGeocode(context.Background(), 29.7858, -95.8245)
+	require.NoError(t, err)
+	assert.Equal(t, "Katy, TX", label)
+	assert.Equal(t, "29.7858,-95.8245", latlng)
+
+	body = geocodeResponse("ZERO_RESULTS")
+	_, err = svc.ReverseGeocode(context.Background(), 0.5, -30)
+	assert.ErrorIs(t, err, domain.ErrLocationNotFound)
+}
+
 func geocodeResponse(status string, components ...string) string {
 	comps := ""
 	for i, c := range components {
@@ -6,32 +6,108 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
 )
 
-// ComputeIsOpen determines if a listing is currently open based on its unstructured hours text.
-func ComputeIsOpen(hoursText string, structuredHours string, currentTime time.Time) bool {
+// ComputeIsOpen reports whether l is open at now, read on the listing's own wall clock
+// so that hours are honored wherever the server runs. Special hours override the
+// regular ones, and a permanently closed listing is never open.
+func ComputeIsOpen(l domain.Listing, now time.Time) bool {
+	open, _ := evaluateListing(l, l.LocalTime(now))
+	return open
+}
+
+// ComputeOpenState reports whether l is open at now and whether that changes within
+// domain.OpenSoonWindow.
+func ComputeOpenState(l domain.Listing, now time.Time) domain.OpenState {
+	local := l.LocalTime(now)
+	if l.IsClosedForGood(now) {
+		return domain.OpenStateClosed
+	}
+	open, known := evaluateListing(l, local)
+	if !known {
+		return domain.OpenStateUnknown
+	}
+	later, _ := evaluateListing(l, local.Add(domain.OpenSoonWindow))
+	switch {
+	case open && !later:
+		return domain.OpenStateClosesSoon
+	case open:
+		return domain.OpenStateOpen
+	case later:
+		return domain.OpenStateOpensSoon
+	}
+	return domain.OpenStateClosed
+}
+
+// ApplyOpenState sets IsCurrentlyOpen and OpenState on each listing for now.
+func ApplyOpenState(listings []domain.Listing, now time.Time) {
+	for i := range listings {
+		listings[i].OpenState = ComputeOpenState(listings[i], now)
+		listings[i].IsCurrentlyOpen = listings[i].OpenState.IsOpen()
+	}
+}
+
+// evaluateListing evaluates l's hours at local, a time on the listing's wall clock.
+func evaluateListing(l domain.Listing, local time.Time) (open bool, known bool) {
+	if l.PermanentlyClosed {
+		return false, true
+	}
+	return evaluateHours(l.HoursOfOperation, withHoursExceptions(l.StructuredHours, l.HoursExceptionList(), local), local)
+}
+
+// withHoursExceptions returns structured hours with date keys added for the exceptions
+// covering t's date and the day before, whose overnight ranges may still be running.
+// Free-text hours are still used for the days no exception covers.
+func withHoursExceptions(structured string, exceptions []domain.HoursException, t time.Time) string {
+	if len(exceptions) == 0 {
+		return structured
+	}
+	schedule := map[string][]string{}
+	if structured != "" {
+		_ = json.Unmarshal([]byte(structured), &schedule) // malformed hours fall back to the text
+	}
+	applied := false
+	for _, day := range []time.Time{t.AddDate(0, 0, -1), t} {
+		if e, ok := domain.HoursExceptionCovering(exceptions, day.Format(time.DateOnly)); ok {
+			schedule[day.Format(time.DateOnly)] = e.Ranges()
+			applied = true
+		}
+	}
+	if !applied {
+		return structured
+	}
+	b, _ := json.Marshal(schedule)
+	return string(b)
+}
+
+// evaluateHours reports whether a listing with these hours is open at currentTime,
+// which must already be on the listing's wall clock. known is false when there are
+// no hours to evaluate.
+func evaluateHours(hoursText string, structuredHours string, currentTime time.Time) (open bool, known bool) {
 	if isOpen, evaluated := evaluateStructuredHours(structuredHours, currentTime); evaluated {
-		return isOpen
+		return isOpen, true
 	}
 
 	hoursText = strings.ToLower(strings.TrimSpace(hoursText))
 	if hoursText == "" {
-		return false
+		return false, false
 	}
 
 	if strings.Contains(hoursText, "open 24 hours") {
-		return true
+		return true, true
 	}
 
 	if isClosedToday(hoursText, currentTime) {
-		return false
+		return false, true
 	}
 
 	if !isDayMatch(hoursText, currentTime) {
-		return false
+		return false, true
 	}
 
-	return isTimeOpen(hoursText, currentTime)
+	return isTimeOpen(hoursText, currentTime), true
 }
 
 func isClosedToday(hoursText string, currentTime time.Time) bool {
@@ -165,6 +241,10 @@ func parseTimeToMinutes(hourStr, minStr, ampmStr string) (int, bool) {
 	return hour*60 + min, true
 }
 
+// evaluateStructuredHours evaluates structured hours JSON such as
+// {"mon":["09:00-17:00"],"2026-12-25":[]}. A date key overrides the weekday key for that
+// date, which is how holiday closures and special hours are recorded. A range whose
+// close is before its open runs past midnight into the next day.
 func evaluateStructuredHours(structured string, currentTime time.Time) (bool, bool) {
 	if structured == "" {
 		return false, false
@@ -175,46 +255,66 @@ func evaluateStructuredHours(structured string, currentTime time.Time) (bool, bo
 		return false, false // invalid JSON, fallback to regex
 	}
 
-	dayNames := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
-	weekday := strings.ToLower(dayNames[currentTime.Weekday()])
+	currentMinutes := currentTime.Hour()*60 + currentTime.Minute()
 
-	ranges, ok := schedule[weekday]
-	if !ok {
-		return false, false // day not present in JSON, fallback to regex
+	// Yesterday's overnight ranges may still be running.
+	if ranges, ok := rangesForDate(schedule, currentTime.AddDate(0, 0, -1)); ok {
+		for _, r := range ranges {
+			openMin, closeMin, ok := parseTimeRange(r)
+			if ok && closeMin < openMin && currentMinutes < closeMin {
+				return true, true
+			}
+		}
 	}
 
-	if len(ranges) == 0 {
-		return false, true // explicitly closed today
+	ranges, ok := rangesForDate(schedule, currentTime)
+	if !ok {
+		return false, false // day not present in JSON, fallback to regex
 	}
 
-	currentMinutes := currentTime.Hour()*60 + currentTime.Minute()
-
 	for _, r := range ranges {
 		if isTimeInRange(r, currentMinutes) {
 			return true, true
 		}
 	}
 
-	return false, true // was evaluated and determined to be closed
+	return false, true // was evaluated and determined to be closed; an empty list means closed all day
 }
 
-func isTimeInRange(timeRange string, currentMinutes int) bool {
-	parts := strings.Split(timeRange, "-")
-	if len(parts) != 2 {
-		return false
+// rangesForDate returns the ranges recorded for t's date, or else for its weekday.
+func rangesForDate(schedule map[string][]string, t time.Time) ([]string, bool) {
+	if ranges, ok := schedule[t.Format(time.DateOnly)]; ok {
+		return ranges, true
 	}
-	openMin, ok1 := parseTimeStr(parts[0])
-	closeMin, ok2 := parseTimeStr(parts[1])
-	if !ok1 || !ok2 {
+	dayNames := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	ranges, ok := schedule[dayNames[t.Weekday()]]
+	return ranges, ok
+}
+
+// isTimeInRange reports whether currentMinutes falls in the part of timeRange that is
+// on the day it is listed under; an overnight range covers from its open to midnight.
+func isTimeInRange(timeRange string, currentMinutes int) bool {
+	openMin, closeMin, ok := parseTimeRange(timeRange)
+	if !ok {
 		return false
 	}
 
 	if closeMin < openMin { // overlaps midnight
-		return currentMinutes >= openMin || currentMinutes < closeMin
+		return currentMinutes >= openMin
 	}
 	return currentMinutes >= openMin && currentMinutes < closeMin
 }
 
+func parseTimeRange(timeRange string) (int, int, bool) {
+	parts := strings.Split(timeRange, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	openMin, ok1 := parseTimeStr(parts[0])
+	closeMin, ok2 := parseTimeStr(parts[1])
+	return openMin, closeMin, ok1 && ok2
+}
+
 func parseTimeStr(t string) (int, bool) {
 	parts := strings.Split(strings.TrimSpace(t), ":")
 	if len(parts) != 2 {
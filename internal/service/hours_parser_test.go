@@ -3,6 +3,8 @@ package service
 import (
 	"testing"
 	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
 )
 
 func TestComputeIsOpen(t *testing.T) {
@@ -73,7 +75,7 @@ func TestComputeIsOpen(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ComputeIsOpen(tt.hoursText, tt.structuredHours, tt.currentTime)
+			got := ComputeIsOpen(domain.Listing{HoursOfOperation: tt.hoursText, StructuredHours: tt.structuredHours}, tt.currentTime)
 			if got != tt.want {
 				t.Errorf("ComputeIsOpen(%q, %q, %v) = %v, want %v", tt.hoursText, tt.structuredHours, tt.currentTime, got, tt.want)
 			}
@@ -81,3 +83,132 @@ func TestComputeIsOpen(t *testing.T) {
 	}
 
 }
+
+func TestComputeIsOpen_ListingTimezone(t *testing.T) {
+	// 2026-04-27 15:00 UTC is Monday 10:00 in Houston.
+	now := time.Date(2026, 4, 27, 15, 0, 0, 0, time.UTC)
+	l := domain.Listing{StructuredHours: `{"mon": ["09:00-11:00"]}`, Timezone: "America/Chicago"}
+	if !ComputeIsOpen(l, now) {
+		t.Errorf("expected listing to be open at 10:00 Houston time")
+	}
+
+	l.Timezone = ""
+	if ComputeIsOpen(l, now) {
+		t.Errorf("expected listing without a zone to be evaluated on the given clock")
+	}
+
+	l.State = "TX"
+	if !ComputeIsOpen(l, now) {
+		t.Errorf("expected zone to be resolved from the listing's state")
+	}
+}
+
+func TestComputeIsOpen_StructuredOvernightAndDates(t *testing.T) {
+	tests := []struct {
+		currentTime time.Time
+		name        string
+		structured  string
+		want        bool
+	}{
+		{
+			name:        "Friday late range still open early Saturday",
+			structured:  `{"fri": ["18:00-02:00"], "sat": []}`,
+			currentTime: time.Date(2026, 5, 2, 1, 30, 0, 0, time.UTC),
+			want:        true,
+		},
+		{
+			name:        "Overnight range does not cover the morning of its own day",
+			structured:  `{"thu": [], "fri": ["18:00-02:00"]}`,
+			currentTime: time.Date(2026, 5, 1, 1, 30, 0, 0, time.UTC),
+			want:        false,
+		},
+		{
+			name:        "Date closes a normally open day",
+			structured:  `{"fri": ["09:00-17:00"], "2026-05-01": []}`,
+			currentTime: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+			want:        false,
+		},
+		{
+			name:        "Date sets special hours",
+			structured:  `{"fri": ["09:00-17:00"], "2026-05-01": ["18:00-20:00"]}`,
+			currentTime: time.Date(2026, 5, 1, 19, 0, 0, 0, time.UTC),
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeIsOpen(domain.Listing{StructuredHours: tt.structured}, tt.currentTime); got != tt.want {
+				t.Errorf("ComputeIsOpen(%q, %v) = %v, want %v", tt.structured, tt.currentTime, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeOpenState(t *testing.T) {
+	l := domain.Listing{StructuredHours: `{"mon": ["09:00-17:00"]}`}
+	monday := func(h, m int) time.Time { return time.Date(2026, 4, 27, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		at   time.Time
+		want domain.OpenState
+	}{
+		{monday(8, 0), domain.OpenStateClosed},
+		{monday(8, 40), domain.OpenStateOpensSoon},
+		{monday(12, 0), domain.OpenStateOpen},
+		{monday(16, 45), domain.OpenStateClosesSoon},
+		{monday(17, 0), domain.OpenStateClosed},
+	}
+	for _, tt := range tests {
+		if got := ComputeOpenState(l, tt.at); got != tt.want {
+			t.Errorf("ComputeOpenState at %s = %q, want %q", tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+
+	if got := ComputeOpenState(domain.Listing{}, monday(12, 0)); got != domain.OpenStateUnknown {
+		t.Errorf("expected unknown state without hours, got %q", got)
+	}
+}
+
+func TestComputeIsOpen_HoursExceptions(t *testing.T) {
+	weekly := `{"fri": ["09:00-17:00"], "sat": ["09:00-17:00"]}`
+	friday := func(h, m int) time.Time { return time.Date(2026, 5, 1, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		at         time.Time
+		name       string
+		hoursText  string
+		structured string
+		exceptions string
+		want       bool
+	}{
+		{name: "no exception", structured: weekly, at: friday(12, 0), want: true},
+		{name: "closed for the day", structured: weekly, exceptions: `[{"start":"2026-05-01","kind":"closed"}]`, at: friday(12, 0), want: false},
+		{name: "closed range", structured: weekly, exceptions: `[{"start":"2026-04-28","end":"2026-05-03","kind":"closed"}]`, at: friday(12, 0), want: false},
+		{name: "closure over", structured: weekly, exceptions: `[{"start":"2026-04-28","end":"2026-04-30","kind":"closed"}]`, at: friday(12, 0), want: true},
+		{name: "custom hours replace weekly", structured: weekly, exceptions: `[{"start":"2026-05-01","kind":"custom","hours":["18:00-23:00"]}]`, at: friday(12, 0), want: false},
+		{name: "custom hours open", structured: weekly, exceptions: `[{"start":"2026-05-01","kind":"custom","hours":["18:00-23:00"]}]`, at: friday(19, 0), want: true},
+		{name: "custom overnight runs into the next day", structured: weekly, exceptions: `[{"start":"2026-05-01","kind":"custom","hours":["20:00-02:00"]}]`, at: time.Date(2026, 5, 2, 1, 0, 0, 0, time.UTC), want: true},
+		{name: "custom day inside a closure", structured: weekly, exceptions: `[{"start":"2026-04-28","end":"2026-05-03","kind":"closed"},{"start":"2026-05-01","kind":"custom","hours":["10:00-14:00"]}]`, at: friday(12, 0), want: true},
+		{name: "permanently closed", structured: weekly, exceptions: `[{"start":"2026-04-01","kind":"permanently_closed"}]`, at: friday(12, 0), want: false},
+		{name: "closure overrides free-text hours", hoursText: "Mon-Sun 9am-5pm", exceptions: `[{"start":"2026-05-01","kind":"closed"}]`, at: friday(12, 0), want: false},
+		{name: "free-text hours on other days", hoursText: "Mon-Sun 9am-5pm", exceptions: `[{"start":"2026-05-02","kind":"closed"}]`, at: friday(12, 0), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := domain.Listing{HoursOfOperation: tt.hoursText, StructuredHours: tt.structured, HoursExceptions: tt.exceptions}
+			if got := ComputeIsOpen(l, tt.at); got != tt.want {
+				t.Errorf("ComputeIsOpen at %v = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+
+	flagged := domain.Listing{StructuredHours: weekly, PermanentlyClosed: true}
+	if ComputeIsOpen(flagged, friday(12, 0)) {
+		t.Errorf("expected a permanently closed listing to be closed")
+	}
+	if got := ComputeOpenState(domain.Listing{PermanentlyClosed: true}, friday(12, 0)); got != domain.OpenStateClosed {
+		t.Errorf("expected a permanently closed listing without hours to be closed, got %q", got)
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// ErrHoursNotRecognized is returned when no opening hours can be read from a text.
+var ErrHoursNotRecognized = errors.New("no opening hours recognized")
+
+// HoursParse is the result of reading opening hours with rules.
+type HoursParse struct {
+	// Schedule maps "mon".."sun" to "HH:MM-HH:MM" ranges; an empty list means closed.
+	Schedule map[string][]string
+	// Confidence estimates, from 0 to 1, how faithfully Schedule reflects the text.
+	// It falls when parts of the text go unread or AM/PM had to be guessed.
+	Confidence float64
+}
+
+// JSON encodes Schedule in the structured hours format stored on listings.
+func (p HoursParse) JSON() string {
+	b, _ := json.Marshal(p.Schedule)
+	return string(b)
+}
+
+// RuleHoursExtractor reads common opening-hours formats, such as "Mon-Fri 9am-5pm,
+// Sat 10-2, Sun closed", "Daily 11:00–22:00", split shifts and "24/7", without any
+// network calls. It implements domain.HoursExtractor.
+type RuleHoursExtractor struct{}
+
+// NewRuleHoursExtractor creates a new RuleHoursExtractor.
+func NewRuleHoursExtractor() *RuleHoursExtractor {
+	return &RuleHoursExtractor{}
+}
+
+// ExtractHours returns the structured hours JSON for rawHours, or
+// ErrHoursNotRecognized when nothing in it could be read.
+func (e *RuleHoursExtractor) ExtractHours(_ context.Context, rawHours string) (string, error) {
+	p := e.Parse(rawHours)
+	if p.Confidence == 0 {
+		return "", ErrHoursNotRecognized
+	}
+	return p.JSON(), nil
+}
+
+// EscalatingHoursExtractor reads hours with rules and asks Fallback, typically
+// GeminiHoursExtractor, only when the rules are less than MinConfidence sure.
+type EscalatingHoursExtractor struct {
+	Rules         *RuleHoursExtractor
+	Fallback      domain.HoursExtractor
+	MinConfidence float64
+}
+
+// DefaultHoursConfidence is the rule confidence above which no fallback is consulted.
+const DefaultHoursConfidence = 0.8
+
+// NewEscalatingHoursExtractor creates an extractor that escalates to fallback below
+// DefaultHoursConfidence. A nil fallback means the rules' answer is always used.
+func NewEscalatingHoursExtractor(fallback domain.HoursExtractor) *EscalatingHoursExtractor {
+	return &EscalatingHoursExtractor{
+		Rules:         NewRuleHoursExtractor(),
+		Fallback:      fallback,
+		MinConfidence: DefaultHoursConfidence,
+	}
+}
+
+// ExtractHours implements domain.HoursExtractor. When the fallback fails, a low
+// confidence rule result is still better than none and is returned instead.
+func (e *EscalatingHoursExtractor) ExtractHours(ctx context.Context, rawHours string) (string, error) {
+	p := e.Rules.Parse(rawHours)
+	if p.Confidence >= e.MinConfidence || e.Fallback == nil {
+		if p.Confidence == 0 {
+			return "", ErrHoursNotRecognized
+		}
+		return p.JSON(), nil
+	}
+
+	structured, err := e.Fallback.ExtractHours(ctx, rawHours)
+	if err == nil {
+		return structured, nil
+	}
+	if p.Confidence > 0 {
+		return p.JSON(), nil
+	}
+	return "", err
+}
+
+var hoursDayKeys = [7]string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+const (
+	hoursTimePattern = `(\d{1,2})(?:[:.](\d{2}))?\s*(?:(am|pm|a|p)\b)?`
+	hoursDayPattern  = `(mon(?:day)?s?|tue(?:s|sday)?s?|wed(?:nesday)?s?|thu(?:r|rs|rsday)?s?|fri(?:day)?s?|sat(?:urday)?s?|sun(?:day)?s?)\b\.?`
+)
+
+// hoursTokenRe recognizes, in order of preference: round-the-clock phrases, every-day
+// phrases, weekdays, weekends, day ranges, single days, time ranges, "closed",
+// separators and filler words. Anything else is left unread and lowers confidence.
+var hoursTokenRe = regexp.MustCompile(
+	`(24\s*/\s*7|24\s*h(?:ou)?rs?|all\s+day|around\s+the\s+clock)` + // 1: all day
+		`|(daily|every\s*day|7\s*days(?:\s*a\s*week)?|seven\s*days(?:\s*a\s*week)?|all\s*week|mon\w*\s*-\s*sun\w*)` + // 2: every day
+		`|(weekdays?)` + // 3
+		`|(weekends?)` + // 4
+		`|` + hoursDayPattern + `\s*-\s*` + hoursDayPattern + // 5, 6: day range
+		`|` + hoursDayPattern + // 7: day
+		`|` + hoursTimePattern + `\s*-\s*` + hoursTimePattern + // 8-13: time range
+		`|(closed)` + // 14
+		`|([,;|\n]|\.\s)` + // 15: separator
+		`|(open|opens|only|hours?|hrs|from|and|&|a\s*week)`, // 16: filler
+)
+
+var hoursNormalizer = strings.NewReplacer(
+	"–", "-", "—", "-", "‒", "-", "−", "-",
+	" to ", " - ", " thru ", " - ", " through ", " - ", " until ", " - ", " till ", " - ", " til ",
+	" - ",
+	"a.m.", "am", "p.m.", "pm", "a.m", "am", "p.m", "pm",
+	"12 noon", "12pm", "12:00 noon", "12pm", "noon", "12pm",
+	"12 midnight", "12am", "midnight", "12am",
+)
+
+type hoursTokenKind int
+
+const (
+	tokenDays hoursTokenKind = iota
+	tokenTimes
+	tokenClosed
+	tokenSeparator
+)
+
+type hoursGroup struct {
+	days       map[int]bool
+	ranges     []string
+	closed     bool
+	timesFirst bool
+	lastKind   hoursTokenKind
+	sealed     bool
+	allWeek    bool
+}
+
+func (g *hoursGroup) complete() bool {
+	return len(g.days) > 0 && (len(g.ranges) > 0 || g.closed)
+}
+
+// Parse reads rawHours. A text it cannot read at all yields zero Confidence.
+func (e *RuleHoursExtractor) Parse(rawHours string) HoursParse {
+	text := " " + strings.ToLower(strings.TrimSpace(rawHours)) + " "
+	text = hoursNormalizer.Replace(text)
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return HoursParse{}
+	}
+
+	var groups []*hoursGroup
+	current := func() *hoursGroup {
+		if len(groups) == 0 || groups[len(groups)-1].sealed {
+			groups = append(groups, &hoursGroup{days: map[int]bool{}, lastKind: tokenSeparator})
+		}
+		return groups[len(groups)-1]
+	}
+	startGroup := func() *hoursGroup {
+		groups = append(groups, &hoursGroup{days: map[int]bool{}, lastKind: tokenSeparator})
+		return groups[len(groups)-1]
+	}
+
+	addDays := func(days []int, allWeek bool) {
+		g := current()
+		if g.complete() && g.lastKind != tokenDays {
+			g = startGroup()
+		}
+		if len(g.days) == 0 && len(g.ranges) > 0 {
+			g.timesFirst = true
+		}
+		for _, d := range days {
+			g.days[d] = true
+		}
+		g.allWeek = g.allWeek || allWeek
+		g.lastKind = tokenDays
+	}
+	addRange := func(r string) {
+		g := current()
+		if g.complete() && (g.closed || (g.timesFirst && g.lastKind == tokenDays)) {
+			g = startGroup()
+		}
+		g.ranges = append(g.ranges, r)
+		g.lastKind = tokenTimes
+	}
+
+	read, guessed := 0, false
+	for _, m := range hoursTokenRe.FindAllStringSubmatchIndex(text, -1) {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return text[m[2*i]:m[2*i+1]]
+		}
+		matched := true
+
+		switch {
+		case group(1) != "":
+			addRange("00:00-24:00")
+		case group(2) != "":
+			addDays([]int{0, 1, 2, 3, 4, 5, 6}, true)
+		case group(3) != "":
+			addDays([]int{0, 1, 2, 3, 4}, false)
+		case group(4) != "":
+			addDays([]int{5, 6}, false)
+		case group(5) != "":
+			addDays(dayRange(dayIndex(group(5)), dayIndex(group(6))), false)
+		case group(7) != "":
+			addDays([]int{dayIndex(group(7))}, false)
+		case group(8) != "":
+			r, ambiguous, ok := timeRange(group(8), group(9), group(10), group(11), group(12), group(13))
+			if !ok {
+				matched = false
+				break
+			}
+			guessed = guessed || ambiguous
+			addRange(r)
+		case group(14) != "":
+			g := current()
+			if len(g.ranges) > 0 {
+				g = startGroup()
+			}
+			g.closed = true
+			g.lastKind = tokenClosed
+		case group(15) != "":
+			// A complete group ends here, as do continuation times such as the
+			// "5-10pm" of "Mon-Fri 11-2pm, 5-10pm; Sat ...".
+			if g := current(); g.complete() || len(g.days) == 0 && len(g.ranges) > 0 && len(groups) > 1 {
+				g.sealed = true
+			}
+		}
+
+		if matched {
+			read += alnumCount(text[m[0]:m[1]])
+		}
+	}
+
+	total := alnumCount(text)
+	if total == 0 {
+		return HoursParse{}
+	}
+	schedule, explicitDays, dayless := buildSchedule(groups)
+	if len(schedule) == 0 {
+		return HoursParse{}
+	}
+
+	confidence := float64(read) / float64(total)
+	if guessed {
+		confidence *= 0.85
+	}
+	if dayless && !allDayOnly(schedule) {
+		// Times with no days at all, e.g. "9am-5pm", are assumed to apply daily.
+		confidence *= 0.7
+	}
+	for _, g := range groups {
+		if len(g.days) > 0 && len(g.ranges) == 0 && !g.closed {
+			// Days were named but never given hours.
+			confidence *= 0.7
+		}
+	}
+
+	if explicitDays {
+		for _, day := range hoursDayKeys {
+			if _, ok := schedule[day]; !ok {
+				schedule[day] = []string{}
+			}
+		}
+	}
+	return HoursParse{Schedule: schedule, Confidence: math.Round(confidence*100) / 100}
+}
+
+// buildSchedule applies groups in order, later groups overriding earlier ones for the
+// days they name. A group of times without days continues the previous group, which is
+// how split shifts after a separator read, or applies daily when it comes first.
+func buildSchedule(groups []*hoursGroup) (schedule map[string][]string, explicitDays, dayless bool) {
+	schedule = map[string][]string{}
+	var previous *hoursGroup
+	for _, g := range groups {
+		if len(g.ranges) == 0 && !g.closed {
+			continue
+		}
+		days := sortedDays(g.days)
+		appendRanges := false
+		if len(days) == 0 {
+			if previous != nil {
+				days, appendRanges = sortedDays(previous.days), true
+			} else {
+				days, dayless = []int{0, 1, 2, 3, 4, 5, 6}, true
+			}
+		} else {
+			previous = g
+			explicitDays = explicitDays || !g.allWeek
+		}
+
+		for _, d := range days {
+			key := hoursDayKeys[d]
+			switch {
+			case g.closed && len(g.ranges) == 0:
+				schedule[key] = []string{}
+			case appendRanges:
+				schedule[key] = dedupe(append(schedule[key], g.ranges...))
+			default:
+				schedule[key] = dedupe(append([]string{}, g.ranges...))
+			}
+		}
+	}
+	return schedule, explicitDays, dayless
+}
+
+func allDayOnly(schedule map[string][]string) bool {
+	for _, ranges := range schedule {
+		if len(ranges) != 1 || ranges[0] != "00:00-24:00" {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedDays(days map[int]bool) []int {
+	out := make([]int, 0, len(days))
+	for d := range days {
+		out = append(out, d)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func dedupe(ranges []string) []string {
+	seen := make(map[string]bool, len(ranges))
+	out := ranges[:0]
+	for _, r := range ranges {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// dayIndex maps a day token to 0 (Monday) through 6 (Sunday).
+func dayIndex(token string) int {
+	for i, key := range hoursDayKeys {
+		if strings.HasPrefix(token, key) {
+			return i
+		}
+	}
+	return 0
+}
+
+// dayRange lists the days from start to end inclusive, wrapping past Sunday.
+func dayRange(start, end int) []int {
+	var days []int
+	for d := start; ; d = (d + 1) % 7 {
+		days = append(days, d)
+		if d == end {
+			return days
+		}
+	}
+}
+
+// timeRange converts two clock readings into "HH:MM-HH:MM". ambiguous is set when
+// neither reading carried AM/PM and the text was not plainly on a 24-hour clock.
+func timeRange(h1, m1, s1, h2, m2, s2 string) (string, bool, bool) {
+	start, ok1 := clockMinutes(h1, m1)
+	end, ok2 := clockMinutes(h2, m2)
+	if !ok1 || !ok2 {
+		return "", false, false
+	}
+	s1, s2 = meridiem(s1), meridiem(s2)
+
+	ambiguous := false
+	switch {
+	case s1 != "" && s2 != "":
+		start, end = applyMeridiem(start, s1), applyMeridiem(end, s2)
+	case s2 != "":
+		// "9-5pm" and "11-2pm" start in the morning, "6-10pm" in the evening;
+		// "5-2am" runs overnight.
+		end = applyMeridiem(end, s2)
+		am, pm := applyMeridiem(start, "am"), applyMeridiem(start, "pm")
+		if s2 == "pm" && pm <= end || s2 == "am" && am >= end {
+			start = pm
+		} else {
+			start = am
+		}
+	case s1 != "":
+		// "9am-5" closes in the afternoon; "10pm-2" runs overnight.
+		start = applyMeridiem(start, s1)
+		end = applyMeridiem(end, "am")
+		if pm := applyMeridiem(end, "pm"); end <= start && pm > start {
+			end = pm
+		}
+	case start > 12*60 || end > 12*60 || m1 != "" && strings.HasPrefix(h1, "0"):
+		// A 24-hour clock, e.g. "11:00-22:00" or "07:30-15:00".
+	default:
+		ambiguous = true
+		if end <= start {
+			end += 12 * 60 // "10-2", "9-5"
+		} else if start < 7*60 && end <= 12*60 {
+			start, end = start+12*60, end+12*60 // "5-11" is an evening
+		}
+	}
+
+	if end == start {
+		return "00:00-24:00", ambiguous, true
+	}
+	return fmt.Sprintf("%s-%s", clock(start), clock(end)), ambiguous, true
+}
+
+func clockMinutes(h, m string) (int, bool) {
+	hour, err := strconv.Atoi(h)
+	if err != nil || hour > 24 {
+		return 0, false
+	}
+	minute := 0
+	if m != "" {
+		if minute, err = strconv.Atoi(m); err != nil || minute > 59 {
+			return 0, false
+		}
+	}
+	if hour == 24 && minute > 0 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+func meridiem(s string) string {
+	switch s {
+	case "a", "am":
+		return "am"
+	case "p", "pm":
+		return "pm"
+	}
+	return ""
+}
+
+func applyMeridiem(minutes int, s string) int {
+	hour := minutes / 60 % 12
+	if s == "pm" {
+		hour += 12
+	}
+	return hour*60 + minutes%60
+}
+
+func clock(minutes int) string {
+	if minutes >= 24*60 {
+		minutes = 24 * 60
+	}
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func alnumCount(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schedule builds an expected schedule from a compact spec such as
+// "mon-fri=09:00-17:00;sat=10:00-14:00,15:00-18:00;sun=". Days that are not named
+// are left out, so specs for explicit-day texts name every day.
+func schedule(spec string) map[string][]string {
+	out := map[string][]string{}
+	for _, part := range strings.Split(spec, ";") {
+		daysSpec, rangesSpec, _ := strings.Cut(part, "=")
+		ranges := []string{}
+		if rangesSpec != "" {
+			ranges = strings.Split(rangesSpec, ",")
+		}
+		from, to, isRange := strings.Cut(daysSpec, "-")
+		if !isRange {
+			to = from
+		}
+		for _, d := range dayRange(dayIndex(from), dayIndex(to)) {
+			out[hoursDayKeys[d]] = ranges
+		}
+	}
+	return out
+}
+
+func TestRuleHoursExtractor_Corpus(t *testing.T) {
+	t.Parallel()
+	const nineToFive = "09:00-17:00"
+
+	tests := []struct {
+		text          string
+		want          string
+		minConfidence float64
+	}{
+		// Day ranges and explicit closures
+		{"Mon-Fri 9am-5pm, Sat 10-2, Sun closed", "mon-fri=" + nineToFive + ";sat=10:00-14:00;sun=", 0.8},
+		{"Mon - Fri: 9:00 AM - 5:00 PM", "mon-fri=" + nineToFive + ";sat-sun=", 1},
+		{"Monday to Friday 8am to 6pm; Saturday 9am to 1pm", "mon-fri=08:00-18:00;sat=09:00-13:00;sun=", 1},
+		{"Mon-Fri 9:30AM-6PM | Sat 10AM-4PM | Sun Closed", "mon-fri=09:30-18:00;sat=10:00-16:00;sun=", 1},
+		{"Mon-Fri 9am-5pm closed weekends", "mon-fri=" + nineToFive + ";sat-sun=", 1},
+		{"Closed Sunday, Mon-Sat 9-5", "mon-sat=" + nineToFive + ";sun=", 0.8},
+		{"Tuesday–Sunday: 12:00 – 21:00 (Closed Mondays)", "tue-sun=12:00-21:00;mon=", 1},
+		{"Sun closed, Mon-Sat 8am-8pm", "mon-sat=08:00-20:00;sun=", 1},
+		{"MON-THU 11AM-9PM, FRI-SAT 11AM-10PM, SUN 12PM-8PM", "mon-thu=11:00-21:00;fri-sat=11:00-22:00;sun=12:00-20:00", 1},
+		{"Mon–Thu 11am–10pm Fri–Sat 11am–12am Sun 12pm–9pm", "mon-thu=11:00-22:00;fri-sat=11:00-00:00;sun=12:00-21:00", 1},
+		{"Mon 9-5 Tue 10-2", "mon=" + nineToFive + ";tue=10:00-14:00;wed-sun=", 0.8},
+		{"Mon, Wed, Fri 10am - 7pm", "mon=10:00-19:00;wed=10:00-19:00;fri=10:00-19:00;tue=;thu=;sat-sun=", 1},
+		{"Fri-Mon 10am-6pm", "fri-mon=10:00-18:00;tue-thu=", 1},
+		{"Tues-Sat 11a-7p", "tue-sat=11:00-19:00;sun-mon=", 1},
+		{"Thurs-Sun 4pm-10pm", "thu-sun=16:00-22:00;mon-wed=", 1},
+		{"Mon-Fri 10a-6p", "mon-fri=10:00-18:00;sat-sun=", 1},
+
+		// Every day
+		{"Daily 11:00–22:00", "mon-sun=11:00-22:00", 1},
+		{"Open daily 7am - 3pm", "mon-sun=07:00-15:00", 1},
+		{"Every day 10am-10pm", "mon-sun=10:00-22:00", 1},
+		{"Everyday 8:00-20:00", "mon-sun=08:00-20:00", 1},
+		{"Open 7 days a week 10am-10pm", "mon-sun=10:00-22:00", 1},
+		{"Mon-Sun 10:00 - 22:00", "mon-sun=10:00-22:00", 1},
+		{"Daily 10am-midnight", "mon-sun=10:00-00:00", 1},
+		{"11am-3pm & 5pm-10pm daily", "mon-sun=11:00-15:00,17:00-22:00", 1},
+
+		// Weekdays and weekends
+		{"Weekdays 7:30-15:00, Weekends 9-14", "mon-fri=07:30-15:00;sat-sun=09:00-14:00", 1},
+		{"Weekdays 6am-9pm, weekends 8am-9pm", "mon-fri=06:00-21:00;sat-sun=08:00-21:00", 1},
+		{"Weekends only 9am-1pm", "sat-sun=09:00-13:00;mon-fri=", 0.8},
+
+		// Split shifts
+		{"Mon-Fri 9-12, 1-5", "mon-fri=09:00-12:00,13:00-17:00;sat-sun=", 0.8},
+		{"Mon 10-2 and 4-8", "mon=10:00-14:00,16:00-20:00;tue-sun=", 0.8},
+		{"Tue-Sat 11:30-14:30, 17:30-22:00; Sun 12:00-21:00; Mon closed", "tue-sat=11:30-14:30,17:30-22:00;sun=12:00-21:00;mon=", 1},
+		{"Mon-Fri 11-2pm, 5-10pm; Sat 5pm-11pm", "mon-fri=11:00-14:00,17:00-22:00;sat=17:00-23:00;sun=", 1},
+		{"Lunch Mon-Fri 11am-2pm", "mon-fri=11:00-14:00;sat-sun=", 0.5},
+
+		// Times before days
+		{"9am-5pm Mon-Fri, 10am-2pm Sat", "mon-fri=" + nineToFive + ";sat=10:00-14:00;sun=", 1},
+		{"10am-6pm Tue-Sat", "tue-sat=10:00-18:00;sun-mon=", 1},
+
+		// Around the clock
+		{"24/7", "mon-sun=00:00-24:00", 1},
+		{"Open 24 hours", "mon-sun=00:00-24:00", 1},
+		{"Open 24 Hours", "mon-sun=00:00-24:00", 1},
+		{"24 hrs", "mon-sun=00:00-24:00", 1},
+		{"Mon-Fri 24 hours, Sat-Sun 8am-8pm", "mon-fri=00:00-24:00;sat-sun=08:00-20:00", 1},
+
+		// Overnight and noon/midnight words
+		{"Fri-Sat 6pm-2am", "fri-sat=18:00-02:00;sun-thu=", 1},
+		{"Thu-Sat 10pm - 4am", "thu-sat=22:00-04:00;sun-wed=", 1},
+		{"Mon-Sat 11 a.m. to 9 p.m., Sunday noon to 6 p.m.", "mon-sat=11:00-21:00;sun=12:00-18:00", 1},
+		{"Daily noon-midnight", "mon-sun=12:00-00:00", 1},
+
+		// Guessed AM/PM
+		{"Tue-Sun 5-11", "tue-sun=17:00-23:00;mon=", 0.8},
+		{"Mon-Sat 9-5pm", "mon-sat=" + nineToFive + ";sun=", 1},
+		{"Daily 6-10pm", "mon-sun=18:00-22:00", 1},
+		{"Mon-Fri 11-2pm", "mon-fri=11:00-14:00;sat-sun=", 1},
+		{"Mon-Sun 9am-9", "mon-sun=09:00-21:00", 1},
+		{"Mon-Fri 12-8", "mon-fri=12:00-20:00;sat-sun=", 0.8},
+
+		// Low confidence: escalate
+		{"9am-5pm", "mon-sun=" + nineToFive, 0.5},
+		{"Mon-Fri 9-5 except public holidays", "mon-fri=" + nineToFive + ";sat-sun=", 0.2},
+	}
+
+	e := NewRuleHoursExtractor()
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+			got := e.Parse(tt.text)
+			assert.Equal(t, schedule(tt.want), got.Schedule)
+			assert.GreaterOrEqual(t, got.Confidence, tt.minConfidence)
+		})
+	}
+}
+
+func TestRuleHoursExtractor_LowConfidence(t *testing.T) {
+	t.Parallel()
+	e := NewRuleHoursExtractor()
+
+	for _, text := range []string{
+		"9am-5pm",
+		"Mon-Fri 9-5 except public holidays",
+		"Lunch Mon-Fri 11am-2pm, dinner by reservation",
+	} {
+		assert.Less(t, e.Parse(text).Confidence, DefaultHoursConfidence, text)
+	}
+
+	for _, text := range []string{"", "By appointment only", "Call for hours", "See website"} {
+		got := e.Parse(text)
+		assert.Zero(t, got.Confidence, text)
+		assert.Empty(t, got.Schedule, text)
+		_, err := e.ExtractHours(context.Background(), text)
+		assert.ErrorIs(t, err, ErrHoursNotRecognized, text)
+	}
+}
+
+func TestRuleHoursExtractor_MatchesComputeIsOpen(t *testing.T) {
+	t.Parallel()
+	structured, err := NewRuleHoursExtractor().ExtractHours(context.Background(), "Mon-Fri 9am-5pm, Sat 10-2, Sun closed")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"mon":["09:00-17:00"],"tue":["09:00-17:00"],"wed":["09:00-17:00"],"thu":["09:00-17:00"],"fri":["09:00-17:00"],"sat":["10:00-14:00"],"sun":[]}`, structured)
+}
+
+type stubHoursFallback struct {
+	err    error
+	result string
+	calls  int
+}
+
+func (s *stubHoursFallback) ExtractHours(context.Context, string) (string, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestEscalatingHoursExtractor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("confident rules skip the fallback", func(t *testing.T) {
+		t.Parallel()
+		fallback := &stubHoursFallback{result: `{"mon":["01:00-02:00"]}`}
+		got, err := NewEscalatingHoursExtractor(fallback).ExtractHours(ctx, "Daily 11:00-22:00")
+		require.NoError(t, err)
+		assert.Contains(t, got, `"mon":["11:00-22:00"]`)
+		assert.Zero(t, fallback.calls)
+	})
+
+	t.Run("unsure rules escalate", func(t *testing.T) {
+		t.Parallel()
+		fallback := &stubHoursFallback{result: `{"mon":["01:00-02:00"]}`}
+		got, err := NewEscalatingHoursExtractor(fallback).ExtractHours(ctx, "9am-5pm")
+		require.NoError(t, err)
+		assert.Equal(t, fallback.result, got)
+		assert.Equal(t, 1, fallback.calls)
+	})
+
+	t.Run("failed fallback keeps the rule result", func(t *testing.T) {
+		t.Parallel()
+		fallback := &stubHoursFallback{err: errors.New("quota exceeded")}
+		got, err := NewEscalatingHoursExtractor(fallback).ExtractHours(ctx, "9am-5pm")
+		require.NoError(t, err)
+		assert.Contains(t, got, `"sun":["09:00-17:00"]`)
+	})
+
+	t.Run("nothing readable and a failed fallback", func(t *testing.T) {
+		t.Parallel()
+		fallback := &stubHoursFallback{err: errors.New("quota exceeded")}
+		_, err := NewEscalatingHoursExtractor(fallback).ExtractHours(ctx, "Call for hours")
+		assert.EqualError(t, err, "quota exceeded")
+	})
+
+	t.Run("no fallback", func(t *testing.T) {
+		t.Parallel()
+		got, err := NewEscalatingHoursExtractor(nil).ExtractHours(ctx, "9am-5pm")
+		require.NoError(t, err)
+		assert.Contains(t, got, `"mon":["09:00-17:00"]`)
+	})
+}
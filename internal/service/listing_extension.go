@@ -0,0 +1,73 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// DefaultExtensionLinkTTL is how long an extension link in a reminder stays valid.
+const DefaultExtensionLinkTTL = 14 * 24 * time.Hour
+
+// ExtensionLinks signs the links in expiry reminders that let an owner extend a
+// listing without signing in. A token is bound to the listing's current deadline, so
+// it stops working once the listing has been extended with it.
+type ExtensionLinks struct {
+	Now     func() time.Time
+	BaseURL string
+	key     []byte
+	TTL     time.Duration
+}
+
+// NewExtensionLinks creates ExtensionLinks whose signing key is derived from secret,
+// so the same secret can safely sign other things too.
+func NewExtensionLinks(secret, baseURL string) *ExtensionLinks {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("listing-extension"))
+	return &ExtensionLinks{
+		Now:     time.Now,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		key:     mac.Sum(nil),
+		TTL:     DefaultExtensionLinkTTL,
+	}
+}
+
+// Token returns a signed token for extending l.
+func (x *ExtensionLinks) Token(l domain.Listing) string {
+	expires := strconv.FormatInt(x.Now().Add(x.TTL).Unix(), 10)
+	return expires + "." + x.sign(l, expires)
+}
+
+// URL returns the link that opens the extension page for l.
+func (x *ExtensionLinks) URL(l domain.Listing) string {
+	return x.BaseURL + domain.PathListings + "/" + url.PathEscape(l.ID) + "/extend?" +
+		url.Values{domain.ParamToken: {x.Token(l)}}.Encode()
+}
+
+// Verify checks that token was issued for l as it is now and has not expired.
+func (x *ExtensionLinks) Verify(l domain.Listing, token string) error {
+	expires, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return domain.ErrInvalidExtensionLink
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || x.Now().After(time.Unix(unix, 0)) {
+		return domain.ErrInvalidExtensionLink
+	}
+	if !hmac.Equal([]byte(sig), []byte(x.sign(l, expires))) {
+		return domain.ErrInvalidExtensionLink
+	}
+	return nil
+}
+
+func (x *ExtensionLinks) sign(l domain.Listing, expires string) string {
+	mac := hmac.New(sha256.New, x.key)
+	mac.Write([]byte(l.ID + "|" + strconv.FormatInt(l.Deadline.UnixNano(), 10) + "|" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
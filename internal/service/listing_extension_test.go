@@ -0,0 +1,52 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionLinks(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	links := NewExtensionLinks("secret", "https://agbalumo.test/")
+	links.Now = func() time.Time { return now }
+	l := domain.Listing{ID: "req 1", Type: domain.Request, Deadline: now.Add(48 * time.Hour)}
+
+	link, err := url.Parse(links.URL(l))
+	require.NoError(t, err)
+	assert.Equal(t, "/listings/req 1/extend", link.Path)
+	token := link.Query().Get(domain.ParamToken)
+	assert.NoError(t, links.Verify(l, token))
+
+	t.Run("Tampered", func(t *testing.T) {
+		assert.ErrorIs(t, links.Verify(l, token+"x"), domain.ErrInvalidExtensionLink)
+		assert.ErrorIs(t, links.Verify(l, "not-a-token"), domain.ErrInvalidExtensionLink)
+		expires, sig, _ := strings.Cut(token, ".")
+		assert.ErrorIs(t, links.Verify(l, expires+"9."+sig), domain.ErrInvalidExtensionLink)
+	})
+
+	t.Run("OtherListingOrSecret", func(t *testing.T) {
+		other := l
+		other.ID = "req-2"
+		assert.ErrorIs(t, links.Verify(other, token), domain.ErrInvalidExtensionLink)
+		assert.ErrorIs(t, NewExtensionLinks("other", "").Verify(l, token), domain.ErrInvalidExtensionLink)
+	})
+
+	t.Run("UsedAfterExtension", func(t *testing.T) {
+		extended := l
+		extended.Deadline = l.Deadline.Add(domain.ListingExtensionPeriod)
+		assert.ErrorIs(t, links.Verify(extended, token), domain.ErrInvalidExtensionLink)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		later := *links
+		later.Now = func() time.Time { return now.Add(DefaultExtensionLinkTTL + time.Second) }
+		assert.ErrorIs(t, later.Verify(l, token), domain.ErrInvalidExtensionLink)
+	})
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// DefaultEmailTemplateGlob matches the email templates shipped with the app.
+const DefaultEmailTemplateGlob = "ui/templates/email/*.html"
+
+const (
+	defaultMaxOutboxAttempts = 6
+	outboxBaseBackoff        = time.Minute
+	outboxMaxBackoff         = 6 * time.Hour
+)
+
+// EmailBrand carries the brand palette into email templates. Mail clients ignore
+// stylesheets, so templates inline these values instead of referencing Tailwind classes.
+type EmailBrand struct {
+	Primary    string
+	EarthDark  string
+	EarthClay  string
+	EarthOchre string
+	EarthSand  string
+	EarthCream string
+	Secondary  string
+	Accent     string
+}
+
+var defaultEmailBrand = EmailBrand{
+	Primary:    domain.BrandColorPrimary,
+	EarthDark:  domain.BrandColorEarthDark,
+	EarthClay:  domain.BrandColorEarthClay,
+	EarthOchre: domain.BrandColorEarthOchre,
+	EarthSand:  domain.BrandColorEarthSand,
+	EarthCream: domain.BrandColorEarthCream,
+	Secondary:  domain.BrandColorSecondary,
+	Accent:     domain.BrandColorAccent,
+}
+
+// EmailTemplates renders notification emails. Each kind defines three templates:
+// "<kind>.subject", "<kind>.html" and "<kind>.text". The same files are parsed twice,
+// so the HTML part is contextually escaped while the subject and plain-text part are not.
+type EmailTemplates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// LoadEmailTemplates parses the email templates matching pattern.
+func LoadEmailTemplates(pattern string) (*EmailTemplates, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no email templates match %s", pattern)
+	}
+
+	html, err := htmltemplate.New("email").Funcs(htmltemplate.FuncMap{"dict": emailDict}).ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("parse email templates: %w", err)
+	}
+	text, err := texttemplate.New("email").Funcs(texttemplate.FuncMap{"dict": emailDict}).ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("parse email templates: %w", err)
+	}
+	return &EmailTemplates{html: html, text: text}, nil
+}
+
+// Render builds the email for kind addressed to to.
+func (t *EmailTemplates) Render(kind domain.NotificationKind, to string, data map[string]interface{}) (domain.Email, error) {
+	name := string(kind)
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return domain.Email{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := t.text.ExecuteTemplate(&text, name+".text", data); err != nil {
+		return domain.Email{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return domain.Email{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+	return domain.Email{
+		To: to,
+		// A subject spans one header line; user-supplied values must not add others.
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		HTML:    strings.TrimSpace(html.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
+
+func emailDict(values ...interface{}) (map[string]interface{}, error) {
+	if len(values)%2 != 0 {
+		return nil, errors.New("invalid dict call")
+	}
+	d := make(map[string]interface{}, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		key, ok := values[i].(string)
+		if !ok {
+			return nil, errors.New("dict keys must be strings")
+		}
+		d[key] = values[i+1]
+	}
+	return d, nil
+}
+
+// NotificationStore is the persistence NotificationService needs.
+type NotificationStore interface {
+	domain.OutboxStore
+	domain.UserStore
+}
+
+// NotificationService renders notifications and queues them in the outbox. DeliverDue
+// hands queued mail to the transport, retrying failures with exponential backoff until
+// MaxAttempts is reached.
+type NotificationService struct {
+	Store      NotificationStore
+	Transport  domain.Notifier
+	Templates  *EmailTemplates
+	Now        func() time.Time
+	BaseURL    string
+	AdminEmail string
+	// MaxAttempts is how many delivery attempts a message gets before it is marked failed.
+	MaxAttempts int
+}
+
+// NewNotificationService creates a new NotificationService.
+func NewNotificationService(store NotificationStore, transport domain.Notifier, templates *EmailTemplates, baseURL, adminEmail string) *NotificationService {
+	return &NotificationService{
+		Store:       store,
+		Transport:   transport,
+		Templates:   templates,
+		Now:         time.Now,
+		BaseURL:     strings.TrimRight(baseURL, "/"),
+		AdminEmail:  adminEmail,
+		MaxAttempts: defaultMaxOutboxAttempts,
+	}
+}
+
+// Notify renders kind with data and queues it for delivery to to. Templates also
+// receive Brand, BaseURL, ProfileURL and AdminURL, and ListingURL when data has a
+// ListingID.
+func (s *NotificationService) Notify(ctx context.Context, kind domain.NotificationKind, to string, data map[string]interface{}) error {
+	if to == "" {
+		return nil
+	}
+	email, err := s.Templates.Render(kind, to, s.templateData(data))
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	return s.Store.SaveOutboxMessage(ctx, domain.OutboxMessage{
+		ID:            uuid.New().String(),
+		Kind:          kind,
+		Status:        domain.OutboxStatusPending,
+		Email:         email,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	})
+}
+
+// NotifyUser queues kind for the user's email address. UserName defaults to the
+// user's name. Users without an address are skipped.
+func (s *NotificationService) NotifyUser(ctx context.Context, kind domain.NotificationKind, userID string, data map[string]interface{}) error {
+	if userID == "" {
+		return nil
+	}
+	u, err := s.Store.FindUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find notification recipient %s: %w", userID, err)
+	}
+	merged := map[string]interface{}{"UserName": u.Name}
+	for k, v := range data {
+		merged[k] = v
+	}
+	return s.Notify(ctx, kind, u.Email, merged)
+}
+
+// NotifyAdmins queues kind for the configured admin address. Without one, admin
+// notifications are dropped.
+func (s *NotificationService) NotifyAdmins(ctx context.Context, kind domain.NotificationKind, data map[string]interface{}) error {
+	return s.Notify(ctx, kind, s.AdminEmail, data)
+}
+
+func (s *NotificationService) templateData(data map[string]interface{}) map[string]interface{} {
+	d := map[string]interface{}{
+		"Brand":      defaultEmailBrand,
+		"BaseURL":    s.BaseURL,
+		"ProfileURL": s.BaseURL + domain.PathProfile,
+		"AdminURL":   s.BaseURL + domain.PathAdmin,
+	}
+	if id, ok := data["ListingID"].(string); ok && id != "" {
+		d["ListingURL"] = s.BaseURL + domain.PathListings + "/" + id
+	}
+	for k, v := range data {
+		d[k] = v
+	}
+	return d
+}
+
+// DeliverDue sends up to limit queued messages whose next attempt is due and returns
+// how many were sent. A failed send is rescheduled rather than returned as an error.
+// A message carrying a secret is given up on once the secret expires, and its body
+// is erased as soon as it is sent or given up on, so the outbox never keeps the secret.
+func (s *NotificationService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	msgs, err := s.Store.ListDueOutboxMessages(ctx, s.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, m := range msgs {
+		ttl := m.Kind.SecretTTL()
+		expiresAt := m.CreatedAt.Add(ttl)
+		if ttl > 0 && !s.Now().Before(expiresAt) {
+			m.Status = domain.OutboxStatusFailed
+			m.LastError = "expired before delivery"
+			slog.Warn("[Notifications] Email expired before delivery", "id", m.ID, "kind", m.Kind, "attempts", m.Attempts)
+		} else if err := s.deliver(ctx, &m); err == nil {
+			sent++
+		} else if ttl > 0 && m.NextAttemptAt.After(expiresAt) {
+			// Retrying after expiry would only send a dead link.
+			m.NextAttemptAt = expiresAt
+		}
+		if ttl > 0 && m.Status != domain.OutboxStatusPending {
+			m.Email.HTML, m.Email.Text = "", ""
+		}
+		if err := s.Store.UpdateOutboxMessage(ctx, m); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// deliver makes one delivery attempt for m and records its outcome on m.
+func (s *NotificationService) deliver(ctx context.Context, m *domain.OutboxMessage) error {
+	m.Attempts++
+	err := s.Transport.Send(ctx, m.Email)
+	if err != nil {
+		m.LastError = err.Error()
+		if m.Attempts >= s.MaxAttempts {
+			m.Status = domain.OutboxStatusFailed
+			slog.Error("[Notifications] Giving up on email", "id", m.ID, "kind", m.Kind, "attempts", m.Attempts, "error", err)
+		} else {
+			m.NextAttemptAt = s.Now().Add(outboxBackoff(m.Attempts))
+			slog.Warn("[Notifications] Email delivery failed, will retry", "id", m.ID, "kind", m.Kind, "attempts", m.Attempts, "error", err)
+		}
+		return err
+	}
+	m.Status = domain.OutboxStatusSent
+	m.SentAt = s.Now()
+	m.LastError = ""
+	return nil
+}
+
+// outboxBackoff doubles the wait after each failed attempt, capped at outboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseBackoff
+	for i := 1; i < attempts && d < outboxMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > outboxMaxBackoff {
+		d = outboxMaxBackoff
+	}
+	return d
+}
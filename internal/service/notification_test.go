@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEmailTemplateGlob = "../../ui/templates/email/*.html"
+
+type recordingNotifier struct {
+	err  error
+	sent []domain.Email
+}
+
+func (n *recordingNotifier) Send(_ context.Context, e domain.Email) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, e)
+	return nil
+}
+
+func newTestNotificationService(t *testing.T, transport domain.Notifier) (*NotificationService, NotificationStore) {
+	t.Helper()
+	templates, err := LoadEmailTemplates(testEmailTemplateGlob)
+	require.NoError(t, err)
+	repo := testutil.SetupTestRepository(t)
+	return NewNotificationService(repo, transport, templates, "https://agbalumo.test/", "admin@agbalumo.test"), repo
+}
+
+func TestEmailTemplates_RenderEveryKind(t *testing.T) {
+	t.Parallel()
+	templates, err := LoadEmailTemplates(testEmailTemplateGlob)
+	require.NoError(t, err)
+	svc := &NotificationService{BaseURL: "https://agbalumo.test"}
+
+	kinds := []domain.NotificationKind{
+		domain.NotificationClaimApproved,
+		domain.NotificationClaimRejected,
+		domain.NotificationListingRejected,
+		domain.NotificationListingExpired,
+		domain.NotificationFeedbackReceived,
+		domain.NotificationLoginLink,
+	}
+	for _, kind := range kinds {
+		t.Run(string(kind), func(t *testing.T) {
+			data := svc.templateData(map[string]interface{}{
+				"UserName":         "Ada",
+				"ListingID":        "l1",
+				"ListingTitle":     "Suya Spot",
+				"ListingType":      "Event",
+				"FeedbackType":     "Bug",
+				"Content":          "It broke",
+				"LoginURL":         "https://agbalumo.test/auth/email/verify?token=t",
+				"ExpiresInMinutes": 15,
+			})
+			e, err := templates.Render(kind, "ada@example.com", data)
+			require.NoError(t, err)
+			assert.NotEmpty(t, e.Subject)
+			assert.NotContains(t, e.Subject, "\n")
+			assert.NotEmpty(t, e.Text)
+			assert.Contains(t, e.HTML, domain.BrandColorPrimary)
+			assert.NotContains(t, e.HTML, "ZgotmplZ")
+		})
+	}
+}
+
+func TestEmailTemplates_EscapesHTMLOnly(t *testing.T) {
+	t.Parallel()
+	templates, err := LoadEmailTemplates(testEmailTemplateGlob)
+	require.NoError(t, err)
+	svc := &NotificationService{}
+
+	e, err := templates.Render(domain.NotificationFeedbackReceived, "admin@example.com", svc.templateData(map[string]interface{}{
+		"UserName":     "Ada",
+		"FeedbackType": "Bug",
+		"Content":      "<script>alert(1)</script>\r\nBcc: x@example.com",
+	}))
+	require.NoError(t, err)
+	assert.NotContains(t, e.HTML, "<script>")
+	assert.Contains(t, e.Text, "<script>alert(1)</script>")
+	assert.Equal(t, "New Bug feedback", e.Subject)
+}
+
+func TestNotificationService_NotifyUserQueuesEmail(t *testing.T) {
+	t.Parallel()
+	svc, store := newTestNotificationService(t, &recordingNotifier{})
+	ctx := context.Background()
+	require.NoError(t, store.SaveUser(ctx, domain.User{ID: "u1", Name: "Ada", Email: "ada@example.com"}))
+
+	err := svc.NotifyUser(ctx, domain.NotificationListingRejected, "u1", map[string]interface{}{"ListingID": "l1", "ListingTitle": "Suya Spot"})
+	require.NoError(t, err)
+
+	due, err := store.ListDueOutboxMessages(ctx, time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "ada@example.com", due[0].Email.To)
+	assert.Equal(t, domain.NotificationListingRejected, due[0].Kind)
+	assert.Contains(t, due[0].Email.Text, "Hi Ada,")
+	assert.Contains(t, due[0].Email.Text, "https://agbalumo.test/profile")
+}
+
+func TestNotificationService_SkipsMissingRecipients(t *testing.T) {
+	t.Parallel()
+	svc, store := newTestNotificationService(t, &recordingNotifier{})
+	svc.AdminEmail = ""
+	ctx := context.Background()
+	require.NoError(t, store.SaveUser(ctx, domain.User{ID: "u1", Name: "No Mail"}))
+
+	require.NoError(t, svc.NotifyUser(ctx, domain.NotificationListingExpired, "u1", nil))
+	require.NoError(t, svc.NotifyUser(ctx, domain.NotificationListingExpired, "", nil))
+	require.NoError(t, svc.NotifyAdmins(ctx, domain.NotificationFeedbackReceived, nil))
+
+	due, err := store.ListDueOutboxMessages(ctx, time.Now().Add(time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestNotificationService_DeliverDue(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{}
+	svc, store := newTestNotificationService(t, transport)
+	ctx := context.Background()
+	require.NoError(t, svc.NotifyAdmins(ctx, domain.NotificationFeedbackReceived, map[string]interface{}{"UserName": "Ada", "FeedbackType": "Bug", "Content": "x"}))
+
+	sent, err := svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, transport.sent, 1)
+	assert.Equal(t, "admin@agbalumo.test", transport.sent[0].To)
+
+	// Sent messages are not delivered twice.
+	sent, err = svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	due, err := store.ListDueOutboxMessages(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestNotificationService_DeliverDueRetriesWithBackoff(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{err: errors.New("connection refused")}
+	svc, store := newTestNotificationService(t, transport)
+	svc.MaxAttempts = 2
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	ctx := context.Background()
+	require.NoError(t, svc.Notify(ctx, domain.NotificationClaimApproved, "ada@example.com", map[string]interface{}{"UserName": "Ada", "ListingID": "l1", "ListingTitle": "Suya Spot"}))
+
+	sent, err := svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// Not due again until the backoff has passed.
+	due, err := store.ListDueOutboxMessages(ctx, now.Add(outboxBaseBackoff-time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = store.ListDueOutboxMessages(ctx, now.Add(outboxBaseBackoff), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, 1, due[0].Attempts)
+	assert.Equal(t, "connection refused", due[0].LastError)
+
+	// The final attempt marks the message failed, so it is never retried.
+	now = now.Add(outboxBaseBackoff)
+	_, err = svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	due, err = store.ListDueOutboxMessages(ctx, now.Add(24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+// updateRecordingStore records every outbox update, including those to messages that
+// are no longer due.
+type updateRecordingStore struct {
+	NotificationStore
+	updated []domain.OutboxMessage
+}
+
+func (s *updateRecordingStore) UpdateOutboxMessage(ctx context.Context, m domain.OutboxMessage) error {
+	s.updated = append(s.updated, m)
+	return s.NotificationStore.UpdateOutboxMessage(ctx, m)
+}
+
+func TestNotificationService_DeliverDueErasesSentSecrets(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{}
+	svc, repo := newTestNotificationService(t, transport)
+	store := &updateRecordingStore{NotificationStore: repo}
+	svc.Store = store
+	ctx := context.Background()
+	loginURL := "https://agbalumo.test/auth/email/verify?token=SECRET"
+	require.NoError(t, svc.Notify(ctx, domain.NotificationLoginLink, "ada@example.com", map[string]interface{}{"LoginURL": loginURL, "ExpiresInMinutes": 15}))
+
+	sent, err := svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, transport.sent, 1)
+	assert.Contains(t, transport.sent[0].Text, "SECRET")
+
+	require.Len(t, store.updated, 1)
+	assert.Equal(t, domain.OutboxStatusSent, store.updated[0].Status)
+	assert.Empty(t, store.updated[0].Email.HTML)
+	assert.Empty(t, store.updated[0].Email.Text)
+	assert.Equal(t, "ada@example.com", store.updated[0].Email.To)
+}
+
+func TestNotificationService_DeliverDueExpiresUndeliveredSecrets(t *testing.T) {
+	t.Parallel()
+	transport := &recordingNotifier{err: errors.New("connection refused")}
+	svc, repo := newTestNotificationService(t, transport)
+	store := &updateRecordingStore{NotificationStore: repo}
+	svc.Store = store
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	ctx := context.Background()
+	require.NoError(t, svc.Notify(ctx, domain.NotificationLoginLink, "ada@example.com", map[string]interface{}{"LoginURL": "https://agbalumo.test/auth/email/verify?token=SECRET", "ExpiresInMinutes": 15}))
+	expiresAt := now.Add(domain.LoginLinkTTL)
+
+	// Fail until the backoff would pass the link's expiry: the retry is pulled back to it.
+	for i := 0; i < 4; i++ {
+		_, err := svc.DeliverDue(ctx, 10)
+		require.NoError(t, err)
+		last := store.updated[len(store.updated)-1]
+		require.Equal(t, domain.OutboxStatusPending, last.Status)
+		assert.Contains(t, last.Email.Text, "SECRET", "a pending message keeps its body")
+		assert.False(t, last.NextAttemptAt.After(expiresAt))
+		now = last.NextAttemptAt
+	}
+	require.Equal(t, expiresAt, now)
+
+	transport.err = nil
+	sent, err := svc.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, sent, "an expired link is not sent")
+	assert.Empty(t, transport.sent)
+	last := store.updated[len(store.updated)-1]
+	assert.Equal(t, domain.OutboxStatusFailed, last.Status)
+	assert.Empty(t, last.Email.HTML)
+	assert.Empty(t, last.Email.Text)
+
+	due, err := repo.ListDueOutboxMessages(ctx, now.Add(24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, time.Minute, outboxBackoff(1))
+	assert.Equal(t, 2*time.Minute, outboxBackoff(2))
+	assert.Equal(t, 8*time.Minute, outboxBackoff(4))
+	assert.Equal(t, outboxMaxBackoff, outboxBackoff(20))
+}
+
+func TestLoadEmailTemplates_NoMatches(t *testing.T) {
+	t.Parallel()
+	_, err := LoadEmailTemplates(t.TempDir() + "/*.html")
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "no email templates"))
+}
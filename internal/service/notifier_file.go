@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// FileNotifier is the development transport. It logs each email and, when Dir is set,
+// writes it there as an .eml file that any mail client can open.
+type FileNotifier struct {
+	Now  func() time.Time
+	Dir  string
+	From string
+}
+
+// NewFileNotifier creates a new FileNotifier.
+func NewFileNotifier(dir, from string) *FileNotifier {
+	return &FileNotifier{Now: time.Now, Dir: dir, From: from}
+}
+
+// Send logs e and writes it to Dir.
+func (n *FileNotifier) Send(_ context.Context, e domain.Email) error {
+	slog.Info("[Notifications] Email", "to", e.To, "subject", e.Subject)
+	if n.Dir == "" {
+		return nil
+	}
+
+	from, err := mail.ParseAddress(n.From)
+	if err != nil {
+		return fmt.Errorf("parse sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(e.To)
+	if err != nil {
+		return fmt.Errorf("parse recipient address: %w", err)
+	}
+	now := n.Now()
+	msg, err := buildMIMEMessage(from, to, e, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(n.Dir, 0o750); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), uuid.New().String()[:8])
+	return os.WriteFile(filepath.Join(n.Dir, name), msg, 0o600)
+}
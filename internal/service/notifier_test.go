@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEmail = domain.Email{
+	To:      "Ada <ada@example.com>",
+	Subject: "Your claim for Suya Spot was approved",
+	HTML:    "<p>Approved</p>",
+	Text:    "Approved\n",
+}
+
+func TestSMTPNotifier_Send(t *testing.T) {
+	t.Parallel()
+	n := NewSMTPNotifier("smtp.example.com", 587, "user", "secret", "agbalumo <no-reply@agbalumo.com>")
+	n.Now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	var addr, from string
+	var to []string
+	var msg []byte
+	var auth smtp.Auth
+	n.SendMail = func(a string, au smtp.Auth, f string, t []string, m []byte) error {
+		addr, auth, from, to, msg = a, au, f, t, m
+		return nil
+	}
+
+	require.NoError(t, n.Send(context.Background(), testEmail))
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, "no-reply@agbalumo.com", from)
+	assert.Equal(t, []string{"ada@example.com"}, to)
+
+	body := string(msg)
+	assert.Contains(t, body, "Subject: Your claim for Suya Spot was approved\r\n")
+	assert.Contains(t, body, "Content-Type: multipart/alternative;")
+	assert.Contains(t, body, "text/plain; charset=utf-8")
+	assert.Contains(t, body, "text/html; charset=utf-8")
+	assert.Contains(t, body, "<p>Approved</p>")
+}
+
+func TestSMTPNotifier_Errors(t *testing.T) {
+	t.Parallel()
+	n := NewSMTPNotifier("smtp.example.com", 25, "", "", "no-reply@agbalumo.com")
+	n.SendMail = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("451 try later") }
+
+	err := n.Send(context.Background(), testEmail)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "451 try later")
+
+	err = n.Send(context.Background(), domain.Email{To: "not an address"})
+	assert.Error(t, err)
+}
+
+func TestFileNotifier_WritesEML(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	n := NewFileNotifier(dir, "no-reply@agbalumo.com")
+
+	require.NoError(t, n.Send(context.Background(), testEmail))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	raw, err := os.ReadFile(files[0]) // #nosec G304 - path comes from the test's temp dir
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), "From: <no-reply@agbalumo.com>\r\n"))
+}
+
+func TestFileNotifier_LogOnly(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, NewFileNotifier("", "").Send(context.Background(), testEmail))
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// twilioTimeout bounds a send, so a slow Twilio API cannot hold a claim request open.
+const twilioTimeout = 10 * time.Second
+
+// TwilioSMSSender sends text messages through Twilio's Messages API.
+type TwilioSMSSender struct {
+	Client     *http.Client
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+// NewTwilioSMSSender creates a new TwilioSMSSender that sends from the given number or
+// messaging service SID.
+func NewTwilioSMSSender(accountSID, authToken, from string) *TwilioSMSSender {
+	return &TwilioSMSSender{
+		Client:     &http.Client{Timeout: twilioTimeout},
+		BaseURL:    "https://api.twilio.com",
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		From:       from,
+	}
+}
+
+// SendSMS sends body to the phone number to.
+func (s *TwilioSMSSender) SendSMS(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "Body": {body}}
+	if strings.HasPrefix(s.From, "MG") {
+		form.Set("MessagingServiceSid", s.From)
+	} else {
+		form.Set("From", s.From)
+	}
+
+	endpoint := s.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(s.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.AccountSID, s.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send text message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var apiErr struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&apiErr)
+		return fmt.Errorf("send text message: twilio returned %d: %s (code %d)", resp.StatusCode, apiErr.Message, apiErr.Code)
+	}
+	return nil
+}
+
+// LogSMSSender is the development transport. It logs each text message, including its
+// body, so it must never be used where the log is readable by anyone but the developer.
+type LogSMSSender struct{}
+
+// SendSMS logs the message instead of sending it.
+func (LogSMSSender) SendSMS(_ context.Context, to, body string) error {
+	slog.Info("[SMS] Not sending text message in development", "to", to, "body", body)
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSMSSender_SendSMS(t *testing.T) {
+	t.Parallel()
+	var path, user, pass string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		user, pass, _ = r.BasicAuth()
+		_ = r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := NewTwilioSMSSender("AC123", "token", "+15550001111")
+	assert.Equal(t, twilioTimeout, s.Client.Timeout)
+	s.BaseURL = srv.URL
+	require.NoError(t, s.SendSMS(context.Background(), "+2348000000000", "Your code is 123456"))
+
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", path)
+	assert.Equal(t, "AC123", user)
+	assert.Equal(t, "token", pass)
+	assert.Equal(t, "+2348000000000", form.Get("To"))
+	assert.Equal(t, "+15550001111", form.Get("From"))
+	assert.Equal(t, "Your code is 123456", form.Get("Body"))
+}
+
+func TestTwilioSMSSender_Errors(t *testing.T) {
+	t.Parallel()
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code": 21211, "message": "Invalid 'To' Phone Number"}`))
+	}))
+	defer srv.Close()
+
+	s := NewTwilioSMSSender("AC123", "token", "MG456")
+	s.BaseURL = srv.URL
+	err := s.SendSMS(context.Background(), "not-a-number", "hi")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "21211")
+	assert.Equal(t, "MG456", form.Get("MessagingServiceSid"))
+	assert.Empty(t, form.Get("From"))
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+)
+
+// timezoneBackfillBatch is how many listings BackfillTimezones loads at a time.
+const timezoneBackfillBatch = 200
+
+// BackfillTimezones resolves and stores the time zone of listings saved without one,
+// returning how many it updated. Listings whose zone cannot be resolved are left empty
+// and evaluated on the server's clock.
+func BackfillTimezones(ctx context.Context, store domain.ListingTimezoneStore) (int, error) {
+	updated := 0
+	afterID := ""
+	for {
+		batch, err := store.FindListingsWithoutTimezone(ctx, afterID, timezoneBackfillBatch)
+		if err != nil {
+			return updated, err
+		}
+		for _, l := range batch {
+			l.RefreshTimezone()
+			if l.Timezone == "" {
+				continue
+			}
+			if err := store.SetListingTimezone(ctx, l.ID, l.Timezone); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		if len(batch) < timezoneBackfillBatch {
+			return updated, nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jadecobra/agbalumo/internal/domain"
+	"github.com/jadecobra/agbalumo/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillTimezones(t *testing.T) {
+	t.Parallel()
+	repo := testutil.SetupTestRepository(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "houston", Title: "Suya Spot", Type: domain.Food, State: "TX", Country: "USA", CreatedAt: now, IsActive: true}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "lagos", Title: "Buka", Type: domain.Food, Country: "Nigeria", CreatedAt: now, IsActive: true}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "nowhere", Title: "Mystery", Type: domain.Food, Country: "Atlantis", CreatedAt: now, IsActive: true}))
+	require.NoError(t, repo.Save(ctx, domain.Listing{ID: "set", Title: "Already Set", Type: domain.Food, State: "TX", Timezone: "America/Denver", CreatedAt: now, IsActive: true}))
+
+	count, err := BackfillTimezones(ctx, repo)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	for id, want := range map[string]string{"houston": "America/Chicago", "lagos": "Africa/Lagos", "nowhere": "", "set": "America/Denver"} {
+		l, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, l.Timezone, id)
+	}
+
+	count, err = BackfillTimezones(ctx, repo)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
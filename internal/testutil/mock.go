@@ -24,6 +24,11 @@ func (m *MockGeocodingService) Geocode(ctx context.Context, address string) (flo
 	return args.Get(0).(float64), args.Get(1).(float64), args.Error(2)
 }
 
+func (m *MockGeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (string, error) {
+	args := m.Called(ctx, lat, lng)
+	return args.String(0), args.Error(1)
+}
+
 type MockImageService struct {
 	testifyMock.Mock
 }
@@ -58,7 +63,7 @@ func (m *MockCSVService) ParseAndImport(ctx context.Context, r io.Reader, repo d
 
 type MockListingService struct{}
 
-func (m *MockListingService) ClaimListing(ctx context.Context, user domain.User, listingID string) (domain.ClaimRequest, error) {
+func (m *MockListingService) ClaimListing(ctx context.Context, user domain.User, listingID string, evidence domain.ClaimEvidence) (domain.ClaimRequest, error) {
 	return domain.ClaimRequest{}, nil
 }
 
@@ -79,3 +84,31 @@ type MockMetricsService struct {
 func (m *MockMetricsService) LogAndSave(ctx context.Context, eventType string, value float64, metadata map[string]interface{}) {
 	m.Called(ctx, eventType, value, metadata)
 }
+
+// SentNotification is a notification captured by MockNotificationService.
+type SentNotification struct {
+	Data map[string]interface{}
+	Kind domain.NotificationKind
+	// To is the address for Notify, the user ID for NotifyUser and empty for NotifyAdmins.
+	To string
+}
+
+// MockNotificationService records notifications instead of queuing them.
+type MockNotificationService struct {
+	Sent []SentNotification
+}
+
+func (m *MockNotificationService) Notify(ctx context.Context, kind domain.NotificationKind, to string, data map[string]interface{}) error {
+	m.Sent = append(m.Sent, SentNotification{Kind: kind, To: to, Data: data})
+	return nil
+}
+
+func (m *MockNotificationService) NotifyUser(ctx context.Context, kind domain.NotificationKind, userID string, data map[string]interface{}) error {
+	m.Sent = append(m.Sent, SentNotification{Kind: kind, To: userID, Data: data})
+	return nil
+}
+
+func (m *MockNotificationService) NotifyAdmins(ctx context.Context, kind domain.NotificationKind, data map[string]interface{}) error {
+	m.Sent = append(m.Sent, SentNotification{Kind: kind, Data: data})
+	return nil
+}
@@ -8,7 +8,7 @@ function setupAuthActions() {
                 const modal = document.getElementById(modalId);
                 if (modal) modal.showModal();
             } else {
-                window.location.href = '/auth/google/login';
+                window.location.href = '/auth/login';
             }
         }
     });
@@ -10,7 +10,11 @@ if (!window.filterState) {
     window.filterState = {
         type: urlParams.get('type') || 'Food',
         city: urlParams.get('city') || '',
-        radius: urlParams.get('radius') || '25'
+        radius: urlParams.get('radius') || '25',
+        openNow: urlParams.get('open_now') === 'true',
+        openAt: urlParams.get('open_at') || '',
+        lat: urlParams.get('lat') || '',
+        lng: urlParams.get('lng') || ''
     };
 }
 
@@ -151,9 +155,48 @@ function setupFilterButtons() {
     document.addEventListener('input', (e) => {
         if (e.target.id === 'filter-city') {
             window.filterState.city = e.target.value;
+            // A typed city replaces a "near me" search.
+            window.filterState.lat = '';
+            window.filterState.lng = '';
         }
     });
 
+    // "Near Me": search around the visitor's location from the Geolocation API.
+    document.addEventListener('click', (e) => {
+        const btn = e.target.closest('[data-near-me]');
+        if (!btn || !navigator.geolocation) return;
+
+        btn.disabled = true;
+        navigator.geolocation.getCurrentPosition((pos) => {
+            btn.disabled = false;
+            window.filterState.lat = pos.coords.latitude.toFixed(5);
+            window.filterState.lng = pos.coords.longitude.toFixed(5);
+            window.filterState.city = '';
+            const cityInput = document.getElementById('filter-city');
+            if (cityInput) cityInput.value = '';
+
+            if (window.htmx) {
+                window.htmx.ajax('GET', '/listings/fragment', {
+                    target: '#listings-container',
+                    indicator: '#listings-loading'
+                });
+            }
+        }, () => {
+            btn.disabled = false;
+            if (window.showToast) window.showToast('Location unavailable. Try entering a city instead.', 'error');
+        }, { maximumAge: 300000, timeout: 10000 });
+    });
+
+    // Opening hours filters. Captured so the state is updated before HTMX sends the
+    // request the same change triggers.
+    document.addEventListener('change', (e) => {
+        if (e.target.id === 'filter-open-now') {
+            window.filterState.openNow = e.target.checked;
+        } else if (e.target.id === 'filter-open-at') {
+            window.filterState.openAt = e.target.value;
+        }
+    }, true);
+
     // Inject filter state into all HTMX requests to /listings/fragment
     document.body.addEventListener('htmx:configRequest', (evt) => {
         if (evt.detail.path === '/listings/fragment') {
@@ -161,6 +204,14 @@ function setupFilterButtons() {
             if (state.type) evt.detail.parameters['type'] = state.type;
             if (state.city) evt.detail.parameters['city'] = state.city;
             if (state.radius) evt.detail.parameters['radius'] = state.radius;
+            if (state.openNow) evt.detail.parameters['open_now'] = 'true';
+            else delete evt.detail.parameters['open_now'];
+            if (state.openAt) evt.detail.parameters['open_at'] = state.openAt;
+            else delete evt.detail.parameters['open_at'];
+            if (state.lat && state.lng && !evt.detail.parameters['city']) {
+                evt.detail.parameters['lat'] = state.lat;
+                evt.detail.parameters['lng'] = state.lng;
+            }
         }
     });
 }
@@ -0,0 +1,155 @@
+/**
+ * Listings Map View
+ * Toggles the home page between listing cards and a map of the same search.
+ * Markers come from /listings/map as GeoJSON, clustered by the server when zoomed out.
+ * CSP-compliant: Standard DOM listeners, no inline handlers.
+ */
+
+const ACTIVE_VIEW_CLASSES = ['bg-earth-ochre', 'text-earth-dark', 'border-earth-ochre'];
+const INACTIVE_VIEW_CLASSES = ['text-earth-cream', 'hover:bg-white/10', 'border-white/20'];
+
+let listingsMap = null;
+let listingsMapMarkers = [];
+let listingsMapRequest = null;
+
+function listingsMapFilters() {
+    const state = window.filterState || {};
+    const params = new URLSearchParams();
+    if (state.type) params.set('type', state.type);
+    const search = document.getElementById('search');
+    if (search && search.value) params.set('q', search.value);
+    if (state.openNow) params.set('open_now', 'true');
+    if (state.openAt) params.set('open_at', state.openAt);
+    return params;
+}
+
+function refreshListingsMap() {
+    if (!listingsMap || !listingsMap.getBounds()) return;
+
+    const bounds = listingsMap.getBounds();
+    const sw = bounds.getSouthWest();
+    const ne = bounds.getNorthEast();
+    const params = listingsMapFilters();
+    params.set('bbox', [sw.lng(), sw.lat(), ne.lng(), ne.lat()].map(v => v.toFixed(5)).join(','));
+    params.set('zoom', String(listingsMap.getZoom()));
+
+    // Only the latest viewport matters while the user pans.
+    if (listingsMapRequest) listingsMapRequest.abort();
+    listingsMapRequest = new AbortController();
+    fetch('/listings/map?' + params.toString(), { signal: listingsMapRequest.signal })
+        .then(res => res.ok ? res.json() : Promise.reject(new Error('map request failed: ' + res.status)))
+        .then(renderListingsMap)
+        .catch(err => {
+            if (err.name !== 'AbortError') console.error(err);
+        });
+}
+
+function renderListingsMap(collection) {
+    listingsMapMarkers.forEach(m => m.setMap(null));
+    listingsMapMarkers = (collection.features || []).map(feature => {
+        const [lng, lat] = feature.geometry.coordinates;
+        const props = feature.properties || {};
+        const position = { lat, lng };
+
+        if (props.cluster) {
+            const marker = new google.maps.Marker({
+                position,
+                map: listingsMap,
+                label: { text: String(props.point_count), color: '#ffffff', fontWeight: 'bold', fontSize: '12px' },
+                icon: {
+                    path: google.maps.SymbolPath.CIRCLE,
+                    scale: 14 + Math.min(props.point_count, 100) / 8,
+                    fillColor: '#CC7722',
+                    fillOpacity: 0.9,
+                    strokeColor: '#ffffff',
+                    strokeWeight: 2
+                },
+                title: props.point_count + ' listings'
+            });
+            marker.addListener('click', () => {
+                listingsMap.setCenter(position);
+                listingsMap.setZoom(listingsMap.getZoom() + 2);
+            });
+            return marker;
+        }
+
+        const marker = new google.maps.Marker({ position, map: listingsMap, title: props.title });
+        marker.addListener('click', () => {
+            if (window.htmx && props.id) {
+                window.htmx.ajax('GET', '/listings/' + encodeURIComponent(props.id), { target: 'body', swap: 'beforeend' });
+            }
+        });
+        return marker;
+    });
+}
+
+function initListingsMap() {
+    const el = document.getElementById('listings-map');
+    if (listingsMap || !el || typeof google === 'undefined' || !google.maps) return;
+
+    const lat = parseFloat(el.dataset.centerLat || (window.filterState && window.filterState.lat));
+    const lng = parseFloat(el.dataset.centerLng || (window.filterState && window.filterState.lng));
+    const hasCenter = !isNaN(lat) && !isNaN(lng);
+
+    listingsMap = new google.maps.Map(el, {
+        center: hasCenter ? { lat, lng } : { lat: 39.8, lng: -98.6 },
+        zoom: hasCenter ? 11 : 4,
+        streetViewControl: false,
+        mapTypeControl: false
+    });
+    listingsMap.addListener('idle', refreshListingsMap);
+}
+
+function showListingsView(view) {
+    const mapEl = document.getElementById('listings-map');
+    if (!mapEl) return;
+    const showMap = view === 'map';
+
+    mapEl.classList.toggle('hidden', !showMap);
+    ['listings-container', 'pagination'].forEach(id => {
+        const el = document.getElementById(id);
+        if (el) el.classList.toggle('hidden', showMap);
+    });
+    document.querySelectorAll('[data-listings-view]').forEach(btn => {
+        const active = btn.getAttribute('data-listings-view') === view;
+        btn.setAttribute('aria-pressed', String(active));
+        ACTIVE_VIEW_CLASSES.forEach(c => btn.classList.toggle(c, active));
+        INACTIVE_VIEW_CLASSES.forEach(c => btn.classList.toggle(c, !active));
+    });
+
+    if (!showMap) return;
+    if (typeof google !== 'undefined' && google.maps) {
+        initListingsMap();
+    } else {
+        loadGoogleMapsApi(mapEl.dataset.googleMapsKey);
+    }
+}
+
+function setupListingsMapView() {
+    if (window._listingsMapViewInitialized) return;
+    window._listingsMapViewInitialized = true;
+
+    document.addEventListener('click', (e) => {
+        const btn = e.target.closest('[data-listings-view]');
+        if (btn) showListingsView(btn.getAttribute('data-listings-view'));
+    });
+
+    document.addEventListener('google-maps-loaded', () => {
+        const mapEl = document.getElementById('listings-map');
+        if (mapEl && !mapEl.classList.contains('hidden')) initListingsMap();
+    });
+
+    // Filters re-render the list; keep the map showing the same search.
+    document.body.addEventListener('htmx:afterSwap', (evt) => {
+        if (evt.detail.target && evt.detail.target.id === 'listings-container') {
+            const mapEl = document.getElementById('listings-map');
+            if (mapEl && !mapEl.classList.contains('hidden')) {
+                const pagination = document.getElementById('pagination');
+                if (pagination) pagination.classList.add('hidden');
+                refreshListingsMap();
+            }
+        }
+    });
+}
+
+document.addEventListener('DOMContentLoaded', setupListingsMapView);
@@ -15,6 +15,9 @@ function loadGoogleMapsApi(apiKey) {
 
 // Google Maps Init (Global scope needed for callback)
 window.initGoogleMaps = function () {
+    // Let other components, such as the listings map, know the API is ready.
+    document.dispatchEvent(new Event('google-maps-loaded'));
+
     const inputs = document.querySelectorAll('[name="address"][data-google-maps-key]');
     if (inputs.length === 0) return;
 